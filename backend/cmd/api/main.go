@@ -6,17 +6,32 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	// Event time zones are IANA names; embed the database so they resolve
+	// even on hosts without one installed.
+	_ "time/tzdata"
 
+	asistenciahandler "project/backend/internal/asistencia/handler"
 	authhandler "project/backend/internal/auth/handler"
+	calendariohandler "project/backend/internal/calendario/handler"
+	certificadoshandler "project/backend/internal/certificados/handler"
+	encuestascron "project/backend/internal/encuestas/cron"
+	encuestashandler "project/backend/internal/encuestas/handler"
+	entradashandler "project/backend/internal/entradas/handler"
+	eventcron "project/backend/internal/events/cron"
 	eventhandler "project/backend/internal/events/handler"
+	gafetescron "project/backend/internal/gafetes/cron"
+	gafeteshandler "project/backend/internal/gafetes/handler"
 	inscripcioneshandler "project/backend/internal/inscripciones/handler"
 	paishandler "project/backend/internal/pais/handler"
 	permissionhandler "project/backend/internal/permissions/handler"
 	registrationhandler "project/backend/internal/registrations/handler"
+	rolecron "project/backend/internal/roles/cron"
 	rolehandler "project/backend/internal/roles/handler"
 	roles "project/backend/internal/roles/service"
+	sedeshandler "project/backend/internal/sedes/handler"
 	sesioneshandler "project/backend/internal/sesiones/handler"
 	smtphandler "project/backend/internal/shared/smtp"
+	"project/backend/internal/shared/storage"
 	userhandler "project/backend/internal/users/handler"
 	userrepo "project/backend/internal/users/repo"
 
@@ -73,16 +88,30 @@ func main() {
 	eventsHandler := eventhandler.New(prismaClient)
 	inscriptionsHandler := inscripcioneshandler.New(prismaClient)
 	paisesHandler := paishandler.New(prismaClient)
+	sedesHandler := sedeshandler.New(prismaClient)
+	entradasHandler := entradashandler.New(prismaClient)
+	encuestasHandler := encuestashandler.New(prismaClient)
+	certificadosHandler := certificadoshandler.New(prismaClient)
+	asistenciaHandler := asistenciahandler.New(prismaClient)
+	gafetesHandler := gafeteshandler.New(prismaClient)
+	calendarioHandler := calendariohandler.New(prismaClient)
 	fechasOcupadasHandler := eventhandler.GetFechasOcupadasHandler(eventsHandler.(*eventhandler.Handler).Svc())
+	historialEstadosHandler := eventhandler.GetHistorialEstadosHandler(eventsHandler.(*eventhandler.Handler).Svc())
+	conflictosHandler := eventhandler.GetConflictosHandler(eventsHandler.(*eventhandler.Handler).Svc())
 	registrationsHandler := registrationhandler.New(prismaClient)
 	notificationHandler := notificationhandler.New(prismaClient)
 	notificationcron.StartCierreInscripcionesScheduler(prismaClient)
+	rolecron.StartExpiracionRolesScheduler(prismaClient)
+	eventcron.StartEstadosEventoScheduler(prismaClient)
+	encuestascron.StartEnvioEncuestasScheduler(prismaClient)
+	gafetescron.StartGeneracionGafetesScheduler(prismaClient)
 	sesionesHandler := sesioneshandler.New(prismaClient)
 	rolesHandler := rolehandler.New(prismaClient)
 	permissionsHandler := permissionhandler.New(prismaClient)
 
 	http.HandleFunc("/api/user/assign-role", userHandler.UpdateUserRoleHandler)
 	http.HandleFunc("/api/user/assign-roles", userHandler.UpdateUserRolesHandler)
+	http.HandleFunc("/api/user/grant-role", userHandler.GrantUserRoleHandler)
 
 	http.HandleFunc("/api/auth/register", authHandler.RegisterHandler)
 	http.HandleFunc("/api/auth/register/request-key", authHandler.RequestRegisterTemporaryKeyHandler)
@@ -107,6 +136,17 @@ func main() {
 
 	http.Handle("/api/eventos", eventsHandler)
 	http.HandleFunc("/api/eventos/fechas-ocupadas", fechasOcupadasHandler)
+	http.HandleFunc("/api/eventos/historial-estados", historialEstadosHandler)
+	http.HandleFunc("/api/eventos/conflictos", conflictosHandler)
+	http.HandleFunc("/api/eventos/series", eventsHandler.(*eventhandler.Handler).SeriesHandler)
+	http.HandleFunc("/api/eventos/buscar", eventsHandler.(*eventhandler.Handler).BuscarHandler)
+	http.HandleFunc("/api/eventos/portada", eventsHandler.(*eventhandler.Handler).PortadaHandler)
+	http.HandleFunc("/api/eventos/versiones", eventsHandler.(*eventhandler.Handler).VersionesHandler)
+	http.HandleFunc("/api/eventos/reprogramaciones", eventsHandler.(*eventhandler.Handler).ReprogramacionesHandler)
+	http.HandleFunc(eventhandler.RespuestaReprogramacionPath, eventsHandler.(*eventhandler.Handler).RespuestaReprogramacionHandler)
+	http.HandleFunc("/api/eventos/resumen", eventsHandler.(*eventhandler.Handler).ResumenHandler)
+	http.HandleFunc("/api/eventos/importar", eventsHandler.(*eventhandler.Handler).ImportarHandler)
+	http.HandleFunc("/api/eventos/dashboard", eventsHandler.(*eventhandler.Handler).DashboardHandler)
 	http.Handle("/api/inscripciones", inscriptionsHandler)
 	http.HandleFunc("/api/inscripciones/status", inscriptionsHandler.UpdateEstadoHandler)
 	http.HandleFunc("/api/inscripciones/historial", inscriptionsHandler.HistorialHandler)
@@ -120,8 +160,35 @@ func main() {
 	http.Handle("/api/notifications", notificationHandler)
 	http.Handle("/api/notifications/", notificationHandler)
 	http.Handle("/api/paises", paisesHandler)
+	http.Handle("/api/sedes", sedesHandler)
+	http.HandleFunc("/api/sedes/salas", sedesHandler.(*sedeshandler.Handler).SalasHandler)
+	http.Handle("/api/entradas", entradasHandler)
+	http.HandleFunc("/api/entradas/codigos", entradasHandler.CodigosHandler)
+	http.HandleFunc("/api/entradas/codigos/lote", entradasHandler.LoteCodigosHandler)
+	http.HandleFunc("/api/entradas/codigos/canjes", entradasHandler.CanjesHandler)
+	http.Handle("/api/encuestas", encuestasHandler)
+	http.HandleFunc("/api/encuestas/resultados", encuestasHandler.ResultadosHandler)
+	http.HandleFunc(encuestashandler.RespuestaEncuestaPath, encuestasHandler.RespuestaHandler)
+	http.Handle("/api/certificados", certificadosHandler)
+	http.HandleFunc("/api/certificados/emitidos", certificadosHandler.EmitidosHandler)
+	http.HandleFunc("/api/certificados/pdf", certificadosHandler.PDFHandler)
+	http.HandleFunc(certificadoshandler.VerificarCertificadoPath, certificadosHandler.VerificarHandler)
+	http.HandleFunc("/api/asistencia/checkin", asistenciaHandler.CheckInHandler)
+	http.HandleFunc("/api/asistencia/sincronizar", asistenciaHandler.SincronizarHandler)
+	http.HandleFunc("/api/asistencia/resumen", asistenciaHandler.ResumenHandler)
+	http.Handle("/api/gafetes", gafetesHandler)
 	http.Handle("/api/sesiones", sesionesHandler)
 	http.Handle("/api/sesiones/", sesionesHandler)
+	http.HandleFunc("/api/calendario/evento", calendarioHandler.EventoHandler)
+	http.HandleFunc("/api/calendario/publico", calendarioHandler.PublicoHandler)
+	http.HandleFunc("/api/calendario/token", calendarioHandler.TokenHandler)
+	http.HandleFunc(calendariohandler.AgendaPath, calendarioHandler.AgendaHandler)
+
+	// Uploaded files are served from here unless STORAGE_BASE_URL points to
+	// another host.
+	if uploads := storage.NewLocalFromEnv(); strings.HasPrefix(uploads.BaseURL, "/") {
+		http.Handle(uploads.BaseURL+"/", uploads.Handler())
+	}
 
 	if paisHandler, ok := paisesHandler.(*paishandler.Handler); ok {
 		http.HandleFunc("/api/ciudades", paisHandler.ListCiudadesByPaisHandler)
@@ -14,4 +14,5 @@ require (
 	go.mongodb.org/mongo-driver/v2 v2.0.1 // indirect
 	golang.org/x/crypto v0.31.0
 	golang.org/x/text v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,28 @@
+package dto
+
+// MaxEscaneosSincronizacion caps the scans a device can upload at once.
+const MaxEscaneosSincronizacion = 500
+
+// CheckInRequest is a ticket scanned at an event. The scanner is set up
+// for IDEvento, and tickets for other events are refused. With IDSesion
+// the attendee is checked into that session, and into the event too if
+// they had not been yet.
+type CheckInRequest struct {
+	IDEvento int    `json:"id_evento"`
+	IDSesion *int   `json:"id_sesion"`
+	Payload  string `json:"payload"`
+}
+
+// SincronizacionRequest uploads the scans a device made while offline.
+type SincronizacionRequest struct {
+	IDEvento int              `json:"id_evento"`
+	Escaneos []EscaneoOffline `json:"escaneos"`
+}
+
+// EscaneoOffline is a scan made offline. RegistradoEn is when the device
+// scanned the ticket, in RFC 3339.
+type EscaneoOffline struct {
+	IDSesion     *int   `json:"id_sesion"`
+	Payload      string `json:"payload"`
+	RegistradoEn string `json:"registrado_en"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+// Outcomes of a scan.
+const (
+	ResultadoRegistrado = "registrado"
+	ResultadoDuplicado  = "duplicado"
+	ResultadoRechazado  = "rechazado"
+)
+
+// CheckInResponse is the outcome of a scan. For a duplicate, RegistradoEn
+// and IDOperador tell when and by whom the attendee was first checked in.
+type CheckInResponse struct {
+	Resultado     string `json:"resultado"`
+	IDInscripcion int    `json:"id_inscripcion"`
+	IDSesion      *int   `json:"id_sesion"`
+	Participante  string `json:"participante"`
+	RegistradoEn  string `json:"registrado_en"`
+	IDOperador    *int   `json:"id_operador"`
+}
+
+// SincronizacionResponse reports an offline upload scan by scan, in the
+// order they were sent.
+type SincronizacionResponse struct {
+	Total       int                   `json:"total"`
+	Registrados int                   `json:"registrados"`
+	Duplicados  int                   `json:"duplicados"`
+	Rechazados  int                   `json:"rechazados"`
+	Escaneos    []EscaneoSincronizado `json:"escaneos"`
+}
+
+// EscaneoSincronizado is the outcome of one offline scan. Error says why a
+// rejected scan was refused.
+type EscaneoSincronizado struct {
+	Indice int    `json:"indice"`
+	Error  string `json:"error,omitempty"`
+	CheckInResponse
+}
+
+// ResumenAsistenciaResponse is the attendance of an event as it stands.
+type ResumenAsistenciaResponse struct {
+	IDEvento  int                        `json:"id_evento"`
+	Aprobados int                        `json:"aprobados"`
+	Presentes int                        `json:"presentes"`
+	Sesiones  []AsistenciaSesionResponse `json:"sesiones"`
+}
+
+type AsistenciaSesionResponse struct {
+	IDSesion    int    `json:"id_sesion"`
+	Titulo      string `json:"titulo"`
+	FechaInicio string `json:"fecha_inicio"`
+	Presentes   int    `json:"presentes"`
+}
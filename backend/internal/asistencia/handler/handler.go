@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project/backend/internal/asistencia/dto"
+	"project/backend/internal/asistencia/repo"
+	"project/backend/internal/asistencia/service"
+	"project/backend/internal/asistencia/validation"
+	"project/backend/internal/policy"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+	"project/backend/prisma/db"
+)
+
+type Handler struct {
+	svc         *service.Service
+	roleService roles.UserRoleService
+}
+
+func New(client *db.PrismaClient) *Handler {
+	return &Handler{
+		svc:         service.New(repo.New(client)),
+		roleService: roles.NewUserRoleService(client),
+	}
+}
+
+// CheckInHandler serves POST /api/asistencia/checkin for staff scanning
+// tickets: {id_evento, payload, id_sesion?}. A ticket that was already
+// checked in answers 409 with when it was first scanned.
+func (h *Handler) CheckInHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req dto.CheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateCheckIn(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	res, err := h.svc.CheckIn(ctx, req, policy.SubjectFromRequest(r).UserID)
+	if writeAsistenciaError(w, err) {
+		return
+	}
+	if res.Resultado == dto.ResultadoDuplicado {
+		writeJSON(w, http.StatusConflict, res)
+		return
+	}
+	writeJSON(w, http.StatusCreated, res)
+}
+
+// SincronizarHandler serves POST /api/asistencia/sincronizar, where scanning
+// devices upload the scans they made offline. It answers with the outcome
+// of every scan.
+func (h *Handler) SincronizarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req dto.SincronizacionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateSincronizacion(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	res, err := h.svc.Sincronizar(ctx, req, policy.SubjectFromRequest(r).UserID)
+	if writeAsistenciaError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// ResumenHandler serves GET /api/asistencia/resumen?id_evento=N: how many
+// attendees came in so far, in total and per session.
+func (h *Handler) ResumenHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	res, err := h.svc.Resumen(ctx, eventoID)
+	if writeAsistenciaError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// msgSinPermisos answers callers without events.management.
+const msgSinPermisos = "no tienes permisos para registrar asistencia"
+
+func writeAsistenciaError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrEventoNotFound), errors.Is(err, service.ErrInscripcionNotFound), errors.Is(err, service.ErrSesionNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrEntradaInvalida), errors.Is(err, service.ErrFechaInvalida):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrOtroEvento), errors.Is(err, service.ErrNoAprobada), errors.Is(err, service.ErrEventoCancelado):
+		httperror.WriteJSON(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, service.ErrSinClave):
+		httperror.WriteJSON(w, http.StatusInternalServerError, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,183 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"project/backend/prisma/db"
+)
+
+// EstadoAprobado is the only inscription status whose ticket lets the
+// attendee in.
+const EstadoAprobado = "Aprobado"
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+type EventoRow struct {
+	IDEvento    int    `json:"id_evento"`
+	Estado      string `json:"estado"`
+	ZonaHoraria string `json:"zona_horaria"`
+}
+
+// InscripcionRow is what a scan needs to know about the ticket's holder.
+type InscripcionRow struct {
+	IDInscripcion int    `json:"id_inscripcion"`
+	IDEvento      int    `json:"id_evento"`
+	Estado        string `json:"estado"`
+	Participante  string `json:"participante"`
+}
+
+type SesionRow struct {
+	IDSesion  int  `json:"id_sesion"`
+	IDEvento  int  `json:"id_evento"`
+	Cancelado bool `json:"cancelado"`
+}
+
+// NuevaAsistencia is a check-in to record. IDSesion nil is the event's
+// door; IDOperador is the staff member who scanned the ticket.
+type NuevaAsistencia struct {
+	IDEvento      int
+	IDInscripcion int
+	IDSesion      *int
+	RegistradoEn  time.Time
+	IDOperador    *int
+	SinConexion   bool
+}
+
+// RegistroRow is the check-in that stands after a scan: the new one when
+// Nuevo is set, otherwise the one recorded before.
+type RegistroRow struct {
+	Nuevo        bool      `json:"nuevo"`
+	RegistradoEn time.Time `json:"registrado_en"`
+	IDOperador   *int      `json:"id_operador"`
+}
+
+type SesionResumenRow struct {
+	IDSesion    int       `json:"id_sesion"`
+	Titulo      string    `json:"titulo"`
+	FechaInicio time.Time `json:"fecha_inicio"`
+	Presentes   int       `json:"presentes"`
+}
+
+// FindEvento returns the event, or db.ErrNotFound.
+func (r *Repository) FindEvento(ctx context.Context, id int) (EventoRow, error) {
+	query := `SELECT "id_evento", "estado", "zona_horaria" FROM "Evento" WHERE "id_evento" = $1::int`
+	var rows []EventoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return EventoRow{}, err
+	}
+	if len(rows) == 0 {
+		return EventoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// FindInscripcion returns the inscription, or db.ErrNotFound.
+func (r *Repository) FindInscripcion(ctx context.Context, id int) (InscripcionRow, error) {
+	query := `SELECT "id_inscripcion", "id_evento", "estado", btrim("nombre_participante") AS "participante"
+		FROM "Inscripcion" WHERE "id_inscripcion" = $1::int`
+	var rows []InscripcionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return InscripcionRow{}, err
+	}
+	if len(rows) == 0 {
+		return InscripcionRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// FindSesion returns the session, or db.ErrNotFound.
+func (r *Repository) FindSesion(ctx context.Context, id int) (SesionRow, error) {
+	query := `SELECT "id_sesion", "id_evento", "cancelado" FROM "Sesion" WHERE "id_sesion" = $1::int`
+	var rows []SesionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return SesionRow{}, err
+	}
+	if len(rows) == 0 {
+		return SesionRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// Registrar records a check-in in a single statement, so two devices
+// scanning the same ticket cannot both record it. A session check-in also
+// checks the attendee into the event if they had not been.
+func (r *Repository) Registrar(ctx context.Context, a NuevaAsistencia) (RegistroRow, error) {
+	entrada := `INSERT INTO "Asistencia" ("id_evento", "id_inscripcion", "registrado_en", "id_operador", "sin_conexion", "createdAt")
+			VALUES ($1::int, $2::int, ($3::timestamptz AT TIME ZONE 'UTC'), $4::int, $5::boolean, NOW())
+			ON CONFLICT ("id_inscripcion") DO NOTHING`
+	params := []interface{}{a.IDEvento, a.IDInscripcion, a.RegistradoEn.UTC(), a.IDOperador, a.SinConexion}
+
+	var query string
+	if a.IDSesion == nil {
+		query = `WITH "nueva" AS (` + entrada + ` RETURNING "registrado_en", "id_operador")
+		SELECT true AS "nuevo", n."registrado_en", n."id_operador" FROM "nueva" n
+		UNION ALL
+		SELECT false, a."registrado_en", a."id_operador" FROM "Asistencia" a
+		WHERE a."id_inscripcion" = $2::int AND NOT EXISTS (SELECT 1 FROM "nueva")`
+	} else {
+		query = `WITH "entrada" AS (` + entrada + `),
+		"nueva" AS (
+			INSERT INTO "AsistenciaSesion" ("id_sesion", "id_inscripcion", "registrado_en", "id_operador", "sin_conexion", "createdAt")
+			VALUES ($6::int, $2::int, ($3::timestamptz AT TIME ZONE 'UTC'), $4::int, $5::boolean, NOW())
+			ON CONFLICT ("id_sesion", "id_inscripcion") DO NOTHING
+			RETURNING "registrado_en", "id_operador"
+		)
+		SELECT true AS "nuevo", n."registrado_en", n."id_operador" FROM "nueva" n
+		UNION ALL
+		SELECT false, a."registrado_en", a."id_operador" FROM "AsistenciaSesion" a
+		WHERE a."id_sesion" = $6::int AND a."id_inscripcion" = $2::int AND NOT EXISTS (SELECT 1 FROM "nueva")`
+		params = append(params, *a.IDSesion)
+	}
+
+	var rows []RegistroRow
+	err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows)
+	if err != nil {
+		return RegistroRow{}, err
+	}
+	if len(rows) == 0 {
+		return RegistroRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// Conteos returns how many inscriptions of an event are approved and how
+// many attendees have been checked in.
+func (r *Repository) Conteos(ctx context.Context, eventoID int) (aprobados, presentes int, err error) {
+	query := `SELECT
+			(SELECT COUNT(*) FROM "Inscripcion" WHERE "id_evento" = $1::int AND "estado" = '` + EstadoAprobado + `')::int AS "aprobados",
+			(SELECT COUNT(*) FROM "Asistencia" WHERE "id_evento" = $1::int)::int AS "presentes"`
+	var rows []struct {
+		Aprobados int `json:"aprobados"`
+		Presentes int `json:"presentes"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return 0, 0, err
+	}
+	if len(rows) == 0 {
+		return 0, 0, nil
+	}
+	return rows[0].Aprobados, rows[0].Presentes, nil
+}
+
+// ListSesiones returns the sessions of an event that were not cancelled,
+// in schedule order, with how many attendees were checked into each.
+func (r *Repository) ListSesiones(ctx context.Context, eventoID int) ([]SesionResumenRow, error) {
+	query := `SELECT s."id_sesion", s."titulo", s."fecha_inicio", COUNT(a."id_asistencia_sesion")::int AS "presentes"
+		FROM "Sesion" s
+		LEFT JOIN "AsistenciaSesion" a ON a."id_sesion" = s."id_sesion"
+		WHERE s."id_evento" = $1::int AND NOT s."cancelado"
+		GROUP BY s."id_sesion"
+		ORDER BY s."fecha_inicio", s."id_sesion"`
+	var rows []SesionResumenRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
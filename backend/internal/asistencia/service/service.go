@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"project/backend/internal/asistencia/dto"
+	"project/backend/internal/asistencia/repo"
+	"project/backend/internal/asistencia/ticket"
+	"project/backend/internal/events/domain"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrEventoNotFound      = errors.New("evento no encontrado")
+	ErrEventoCancelado     = errors.New("el evento fue cancelado")
+	ErrEntradaInvalida     = errors.New("la entrada no es válida")
+	ErrOtroEvento          = errors.New("la entrada es de otro evento")
+	ErrInscripcionNotFound = errors.New("inscripción no encontrada")
+	ErrNoAprobada          = errors.New("la inscripción no está aprobada")
+	ErrSesionNotFound      = errors.New("sesión no encontrada")
+	ErrFechaInvalida       = errors.New("fecha de escaneo inválida")
+	ErrSinClave            = errors.New("las entradas no están configuradas")
+	ErrDB                  = errors.New("db error")
+)
+
+// margenReloj is how far in the future an offline scan may claim to be,
+// to allow for devices whose clock runs a little fast.
+const margenReloj = 5 * time.Minute
+
+type Service struct {
+	repo *repo.Repository
+	now  func() time.Time
+}
+
+func New(r *repo.Repository) *Service {
+	return &Service{repo: r, now: time.Now}
+}
+
+// escaneo is a scan about to be recorded.
+type escaneo struct {
+	payload      string
+	idSesion     *int
+	registradoEn time.Time
+	sinConexion  bool
+}
+
+// CheckIn records a ticket scanned now at the event's door or at one of
+// its sessions. A ticket already checked in there is not an error: the
+// response says it is a duplicate and when it was first scanned.
+func (s *Service) CheckIn(ctx context.Context, req dto.CheckInRequest, operadorID int) (dto.CheckInResponse, error) {
+	evento, err := s.evento(ctx, req.IDEvento)
+	if err != nil {
+		return dto.CheckInResponse{}, err
+	}
+	return s.registrar(ctx, evento, escaneo{payload: req.Payload, idSesion: req.IDSesion, registradoEn: s.now()}, operadorID)
+}
+
+// Sincronizar records the scans a device made while offline, each with the
+// time it was scanned. A scan that is refused does not stop the others. If
+// the database fails midway the device can send the batch again: the scans
+// already recorded come back as duplicates.
+func (s *Service) Sincronizar(ctx context.Context, req dto.SincronizacionRequest, operadorID int) (dto.SincronizacionResponse, error) {
+	evento, err := s.evento(ctx, req.IDEvento)
+	if err != nil {
+		return dto.SincronizacionResponse{}, err
+	}
+
+	now := s.now()
+	res := dto.SincronizacionResponse{Total: len(req.Escaneos), Escaneos: make([]dto.EscaneoSincronizado, 0, len(req.Escaneos))}
+	for i, e := range req.Escaneos {
+		item := dto.EscaneoSincronizado{Indice: i}
+		registradoEn, err := parseRegistradoEn(e.RegistradoEn, now)
+		if err == nil {
+			item.CheckInResponse, err = s.registrar(ctx, evento, escaneo{
+				payload:      e.Payload,
+				idSesion:     e.IDSesion,
+				registradoEn: registradoEn,
+				sinConexion:  true,
+			}, operadorID)
+		}
+		if errors.Is(err, ErrDB) || errors.Is(err, ErrSinClave) {
+			return dto.SincronizacionResponse{}, err
+		}
+		if err != nil {
+			item.Resultado = dto.ResultadoRechazado
+			item.Error = err.Error()
+			item.IDSesion = e.IDSesion
+		}
+
+		switch item.Resultado {
+		case dto.ResultadoRegistrado:
+			res.Registrados++
+		case dto.ResultadoDuplicado:
+			res.Duplicados++
+		default:
+			res.Rechazados++
+		}
+		res.Escaneos = append(res.Escaneos, item)
+	}
+	return res, nil
+}
+
+// Resumen returns the live attendance of an event: how many approved
+// attendees there are, how many came in, and how many went to each session.
+func (s *Service) Resumen(ctx context.Context, eventoID int) (dto.ResumenAsistenciaResponse, error) {
+	evento, err := s.evento(ctx, eventoID)
+	if err != nil && !errors.Is(err, ErrEventoCancelado) {
+		return dto.ResumenAsistenciaResponse{}, err
+	}
+	aprobados, presentes, err := s.repo.Conteos(ctx, eventoID)
+	if err != nil {
+		return dto.ResumenAsistenciaResponse{}, ErrDB
+	}
+	sesiones, err := s.repo.ListSesiones(ctx, eventoID)
+	if err != nil {
+		return dto.ResumenAsistenciaResponse{}, ErrDB
+	}
+
+	res := dto.ResumenAsistenciaResponse{
+		IDEvento:  eventoID,
+		Aprobados: aprobados,
+		Presentes: presentes,
+		Sesiones:  make([]dto.AsistenciaSesionResponse, 0, len(sesiones)),
+	}
+	for _, row := range sesiones {
+		res.Sesiones = append(res.Sesiones, dto.AsistenciaSesionResponse{
+			IDSesion:    row.IDSesion,
+			Titulo:      row.Titulo,
+			FechaInicio: domain.FormatoLocal(row.FechaInicio, evento.ZonaHoraria),
+			Presentes:   row.Presentes,
+		})
+	}
+	return res, nil
+}
+
+// evento returns the event scans are for. A cancelled event is returned
+// along with ErrEventoCancelado.
+func (s *Service) evento(ctx context.Context, eventoID int) (repo.EventoRow, error) {
+	evento, err := s.repo.FindEvento(ctx, eventoID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.EventoRow{}, ErrEventoNotFound
+		}
+		return repo.EventoRow{}, ErrDB
+	}
+	if evento.Estado == domain.EstadoCancelado {
+		return evento, ErrEventoCancelado
+	}
+	return evento, nil
+}
+
+func (s *Service) registrar(ctx context.Context, evento repo.EventoRow, e escaneo, operadorID int) (dto.CheckInResponse, error) {
+	t, err := ticket.Verificar(e.payload)
+	if err != nil {
+		if errors.Is(err, ticket.ErrSinClave) {
+			return dto.CheckInResponse{}, ErrSinClave
+		}
+		return dto.CheckInResponse{}, ErrEntradaInvalida
+	}
+	if t.IDEvento != evento.IDEvento {
+		return dto.CheckInResponse{}, ErrOtroEvento
+	}
+
+	inscripcion, err := s.repo.FindInscripcion(ctx, t.IDInscripcion)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.CheckInResponse{}, ErrInscripcionNotFound
+		}
+		return dto.CheckInResponse{}, ErrDB
+	}
+	if err := validarInscripcion(inscripcion, evento.IDEvento); err != nil {
+		return dto.CheckInResponse{}, err
+	}
+
+	if e.idSesion != nil {
+		sesion, err := s.repo.FindSesion(ctx, *e.idSesion)
+		if err != nil && !errors.Is(err, db.ErrNotFound) {
+			return dto.CheckInResponse{}, ErrDB
+		}
+		if err != nil || sesion.IDEvento != evento.IDEvento || sesion.Cancelado {
+			return dto.CheckInResponse{}, ErrSesionNotFound
+		}
+	}
+
+	var operador *int
+	if operadorID > 0 {
+		operador = &operadorID
+	}
+	registro, err := s.repo.Registrar(ctx, repo.NuevaAsistencia{
+		IDEvento:      evento.IDEvento,
+		IDInscripcion: inscripcion.IDInscripcion,
+		IDSesion:      e.idSesion,
+		RegistradoEn:  e.registradoEn,
+		IDOperador:    operador,
+		SinConexion:   e.sinConexion,
+	})
+	if err != nil {
+		return dto.CheckInResponse{}, ErrDB
+	}
+
+	res := dto.CheckInResponse{
+		Resultado:     dto.ResultadoRegistrado,
+		IDInscripcion: inscripcion.IDInscripcion,
+		IDSesion:      e.idSesion,
+		Participante:  inscripcion.Participante,
+		RegistradoEn:  domain.FormatoLocal(registro.RegistradoEn, evento.ZonaHoraria),
+		IDOperador:    registro.IDOperador,
+	}
+	if !registro.Nuevo {
+		res.Resultado = dto.ResultadoDuplicado
+	}
+	return res, nil
+}
+
+// validarInscripcion checks that a ticket's inscription still lets its
+// holder in: a cancelled or rejected inscription keeps a valid signature.
+func validarInscripcion(i repo.InscripcionRow, eventoID int) error {
+	if i.IDEvento != eventoID {
+		return ErrOtroEvento
+	}
+	if i.Estado != repo.EstadoAprobado {
+		return ErrNoAprobada
+	}
+	return nil
+}
+
+// parseRegistradoEn reads the time of an offline scan, which cannot be in
+// the future.
+func parseRegistradoEn(valor string, now time.Time) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(valor))
+	if err != nil || t.After(now.Add(margenReloj)) {
+		return time.Time{}, ErrFechaInvalida
+	}
+	return t, nil
+}
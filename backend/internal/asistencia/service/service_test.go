@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"project/backend/internal/asistencia/repo"
+)
+
+func TestValidarInscripcion(t *testing.T) {
+	cases := []struct {
+		name string
+		row  repo.InscripcionRow
+		want error
+	}{
+		{"aprobada", repo.InscripcionRow{IDEvento: 7, Estado: "Aprobado"}, nil},
+		{"otro evento", repo.InscripcionRow{IDEvento: 8, Estado: "Aprobado"}, ErrOtroEvento},
+		{"cancelada", repo.InscripcionRow{IDEvento: 7, Estado: "Cancelado"}, ErrNoAprobada},
+		{"pendiente", repo.InscripcionRow{IDEvento: 7, Estado: "Pendiente"}, ErrNoAprobada},
+	}
+	for _, c := range cases {
+		if got := validarInscripcion(c.row, 7); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseRegistradoEn(t *testing.T) {
+	now := time.Date(2026, 5, 10, 15, 0, 0, 0, time.UTC)
+
+	got, err := parseRegistradoEn("2026-05-10T09:30:00-04:00", now)
+	if err != nil || !got.Equal(time.Date(2026, 5, 10, 13, 30, 0, 0, time.UTC)) {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if _, err := parseRegistradoEn("2026-05-10T15:04:00Z", now); err != nil {
+		t.Errorf("a clock slightly ahead should be accepted: %v", err)
+	}
+	for _, valor := range []string{"", "10/05/2026 09:30", "2026-05-10T16:00:00Z"} {
+		if _, err := parseRegistradoEn(valor, now); err != ErrFechaInvalida {
+			t.Errorf("%q: got %v, want ErrFechaInvalida", valor, err)
+		}
+	}
+}
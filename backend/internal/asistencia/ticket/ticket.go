@@ -0,0 +1,82 @@
+// Package ticket signs the payload printed as a QR code on the ticket of an
+// approved inscription, so staff can tell a genuine ticket from a forged
+// one before looking anything up. The key is TICKET_SECRET, kept apart from
+// JWT_SECRET so rotating one never invalidates the other.
+package ticket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// prefijo starts every payload and versions its format.
+const prefijo = "INS1"
+
+var (
+	ErrSinClave = errors.New("TICKET_SECRET not set")
+	ErrInvalido = errors.New("entrada inválida")
+)
+
+// Ticket is what a valid payload vouches for.
+type Ticket struct {
+	IDInscripcion int
+	IDEvento      int
+}
+
+// Firmar returns the payload of an inscription's ticket:
+// INS1.<id_inscripcion>.<id_evento>.<firma>. It is the same every time, so
+// it can be printed again without being stored.
+func Firmar(inscripcionID, eventoID int) (string, error) {
+	clave, err := claveFirma()
+	if err != nil {
+		return "", err
+	}
+	cuerpo := prefijo + "." + strconv.Itoa(inscripcionID) + "." + strconv.Itoa(eventoID)
+	return cuerpo + "." + firma(clave, cuerpo), nil
+}
+
+// Verificar checks a scanned payload and returns the ticket it carries.
+func Verificar(payload string) (Ticket, error) {
+	clave, err := claveFirma()
+	if err != nil {
+		return Ticket{}, err
+	}
+	partes := strings.Split(strings.TrimSpace(payload), ".")
+	if len(partes) != 4 || partes[0] != prefijo {
+		return Ticket{}, ErrInvalido
+	}
+	cuerpo := strings.Join(partes[:3], ".")
+	if !hmac.Equal([]byte(partes[3]), []byte(firma(clave, cuerpo))) {
+		return Ticket{}, ErrInvalido
+	}
+	inscripcionID, err := strconv.Atoi(partes[1])
+	if err != nil || inscripcionID <= 0 {
+		return Ticket{}, ErrInvalido
+	}
+	eventoID, err := strconv.Atoi(partes[2])
+	if err != nil || eventoID <= 0 {
+		return Ticket{}, ErrInvalido
+	}
+	return Ticket{IDInscripcion: inscripcionID, IDEvento: eventoID}, nil
+}
+
+func claveFirma() ([]byte, error) {
+	clave := strings.TrimSpace(os.Getenv("TICKET_SECRET"))
+	if clave == "" {
+		return nil, ErrSinClave
+	}
+	return []byte(clave), nil
+}
+
+// firma is the first 128 bits of the HMAC-SHA256 of cuerpo, which keeps
+// the QR code small.
+func firma(clave []byte, cuerpo string) string {
+	mac := hmac.New(sha256.New, clave)
+	mac.Write([]byte(cuerpo))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
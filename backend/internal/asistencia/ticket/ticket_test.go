@@ -0,0 +1,50 @@
+package ticket
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFirmarVerificar(t *testing.T) {
+	t.Setenv("TICKET_SECRET", "test-secret")
+
+	payload, err := Firmar(42, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(payload, "INS1.42.7.") {
+		t.Fatalf("unexpected payload %q", payload)
+	}
+	got, err := Verificar(" " + payload + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (Ticket{IDInscripcion: 42, IDEvento: 7}) {
+		t.Fatalf("got %+v", got)
+	}
+
+	for _, forjado := range []string{
+		strings.Replace(payload, ".42.", ".43.", 1),
+		strings.Replace(payload, "INS1.", "INS2.", 1),
+		payload[:len(payload)-1],
+		"INS1.42.7",
+		"",
+	} {
+		if _, err := Verificar(forjado); err != ErrInvalido {
+			t.Errorf("Verificar(%q) = %v, want ErrInvalido", forjado, err)
+		}
+	}
+
+	t.Setenv("TICKET_SECRET", "otra")
+	if _, err := Verificar(payload); err != ErrInvalido {
+		t.Errorf("payload signed with another key was accepted")
+	}
+}
+
+func TestClaveFirma(t *testing.T) {
+	t.Setenv("TICKET_SECRET", "")
+	t.Setenv("JWT_SECRET", "jwt")
+	if _, err := Firmar(1, 1); err != ErrSinClave {
+		t.Fatalf("got %v, want ErrSinClave without falling back to JWT_SECRET", err)
+	}
+}
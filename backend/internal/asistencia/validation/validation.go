@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"project/backend/internal/asistencia/dto"
+)
+
+func ValidateCheckIn(req dto.CheckInRequest) error {
+	if req.IDEvento <= 0 {
+		return errors.New("id_evento es requerido")
+	}
+	return validateEscaneo(req.Payload, req.IDSesion)
+}
+
+func ValidateSincronizacion(req dto.SincronizacionRequest) error {
+	if req.IDEvento <= 0 {
+		return errors.New("id_evento es requerido")
+	}
+	if len(req.Escaneos) == 0 {
+		return errors.New("no hay escaneos para sincronizar")
+	}
+	if len(req.Escaneos) > dto.MaxEscaneosSincronizacion {
+		return fmt.Errorf("se pueden sincronizar como máximo %d escaneos a la vez", dto.MaxEscaneosSincronizacion)
+	}
+	for i, e := range req.Escaneos {
+		if err := validateEscaneo(e.Payload, e.IDSesion); err != nil {
+			return fmt.Errorf("escaneo %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateEscaneo(payload string, idSesion *int) error {
+	if strings.TrimSpace(payload) == "" {
+		return errors.New("payload es requerido")
+	}
+	if idSesion != nil && *idSesion <= 0 {
+		return errors.New("id_sesion inválido")
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"testing"
+
+	"project/backend/internal/asistencia/dto"
+)
+
+func TestValidateSincronizacion(t *testing.T) {
+	cero := 0
+	base := func() dto.SincronizacionRequest {
+		return dto.SincronizacionRequest{
+			IDEvento: 7,
+			Escaneos: []dto.EscaneoOffline{{Payload: "INS1.1.7.x", RegistradoEn: "2026-05-10T09:30:00Z"}},
+		}
+	}
+	cases := []struct {
+		name    string
+		mutate  func(*dto.SincronizacionRequest)
+		wantErr bool
+	}{
+		{"valida", func(*dto.SincronizacionRequest) {}, false},
+		{"sin evento", func(r *dto.SincronizacionRequest) { r.IDEvento = 0 }, true},
+		{"sin escaneos", func(r *dto.SincronizacionRequest) { r.Escaneos = nil }, true},
+		{"demasiados", func(r *dto.SincronizacionRequest) {
+			r.Escaneos = make([]dto.EscaneoOffline, dto.MaxEscaneosSincronizacion+1)
+			for i := range r.Escaneos {
+				r.Escaneos[i].Payload = "x"
+			}
+		}, true},
+		{"payload vacio", func(r *dto.SincronizacionRequest) { r.Escaneos[0].Payload = " " }, true},
+		{"sesion invalida", func(r *dto.SincronizacionRequest) { r.Escaneos[0].IDSesion = &cero }, true},
+	}
+
+	for _, c := range cases {
+		req := base()
+		c.mutate(&req)
+		err := ValidateSincronizacion(req)
+		if c.wantErr && err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
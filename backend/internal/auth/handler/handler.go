@@ -261,12 +261,12 @@ func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	primaryRole := ""
-	if len(roles) > 0 {
-		primaryRole = roles[0].NombreRol
+	roleNames := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleNames = append(roleNames, role.NombreRol)
 	}
 
-	token, err := service.CreateJWT(user.IDUsuario, user.Email, primaryRole)
+	token, err := service.CreateJWT(user.IDUsuario, user.Email, roleNames)
 	if err != nil {
 		log.Printf("create jwt error: %v", err)
 		response.WriteError(w, http.StatusInternalServerError, response.ErrTokenCreation)
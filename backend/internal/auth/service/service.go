@@ -19,16 +19,31 @@ func CheckPasswordHash(hash, password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
 
-func CreateJWT(userID int, email, roleName string) (string, error) {
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenClaims is the identity carried by a token issued by CreateJWT.
+type TokenClaims struct {
+	UserID int
+	Email  string
+	Roles  []string
+}
+
+func CreateJWT(userID int, email string, roleNames []string) (string, error) {
 	secret := strings.TrimSpace(os.Getenv("JWT_SECRET"))
 	if secret == "" {
 		return "", errors.New("JWT_SECRET not set")
 	}
 
+	primaryRole := ""
+	if len(roleNames) > 0 {
+		primaryRole = roleNames[0]
+	}
+
 	claims := jwt.MapClaims{
 		"sub":   userID,
 		"email": email,
-		"role":  roleName,
+		"role":  primaryRole,
+		"roles": roleNames,
 		"exp":   time.Now().Add(24 * time.Hour).Unix(),
 		"iat":   time.Now().Unix(),
 	}
@@ -36,3 +51,45 @@ func CreateJWT(userID int, email, roleName string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
+
+// ParseJWT validates a token signed with JWT_SECRET and returns its claims.
+// Tokens issued before the roles claim existed fall back to the single role.
+func ParseJWT(tokenString string) (*TokenClaims, error) {
+	secret := strings.TrimSpace(os.Getenv("JWT_SECRET"))
+	if secret == "" {
+		return nil, errors.New("JWT_SECRET not set")
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	sub, ok := mapClaims["sub"].(float64)
+	if !ok || sub <= 0 {
+		return nil, ErrInvalidToken
+	}
+
+	claims := &TokenClaims{UserID: int(sub), Roles: []string{}}
+	claims.Email, _ = mapClaims["email"].(string)
+	if rawRoles, ok := mapClaims["roles"].([]interface{}); ok {
+		for _, raw := range rawRoles {
+			if name, ok := raw.(string); ok && strings.TrimSpace(name) != "" {
+				claims.Roles = append(claims.Roles, strings.TrimSpace(name))
+			}
+		}
+	}
+	if len(claims.Roles) == 0 {
+		if role, ok := mapClaims["role"].(string); ok && strings.TrimSpace(role) != "" {
+			claims.Roles = append(claims.Roles, strings.TrimSpace(role))
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"project/backend/internal/calendario/repo"
+	"project/backend/internal/calendario/service"
+	"project/backend/internal/policy"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+	"project/backend/internal/shared/ics"
+	"project/backend/internal/shared/publicurl"
+	"project/backend/prisma/db"
+)
+
+// AgendaPath is the prefix of personal feed URLs: AgendaPath + token + ".ics".
+const AgendaPath = "/api/calendario/agenda/"
+
+type Handler struct {
+	svc         *service.Service
+	roleService roles.UserRoleService
+}
+
+func New(client *db.PrismaClient) *Handler {
+	return &Handler{
+		svc:         service.New(repo.New(client)),
+		roleService: roles.NewUserRoleService(client),
+	}
+}
+
+// EventoHandler serves GET /api/calendario/evento?id=N, the event and its
+// sessions.
+func (h *Handler) EventoHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	cal, err := h.svc.CalendarioEvento(ctx, id, roles.CanManageEvents(ctx, h.roleService, r))
+	if writeCalendarioError(w, err) {
+		return
+	}
+	writeICS(w, cal, fmt.Sprintf("evento-%d.ics", id))
+}
+
+// PublicoHandler serves GET /api/calendario/publico, the feed of published
+// events.
+func (h *Handler) PublicoHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	cal, err := h.svc.CalendarioPublico(ctx, time.Now())
+	if writeCalendarioError(w, err) {
+		return
+	}
+	writeICS(w, cal, "eventos.ics")
+}
+
+// AgendaHandler serves GET /api/calendario/agenda/<token>.ics. The token is
+// the only credential, so calendar clients can subscribe without logging in.
+func (h *Handler) AgendaHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, AgendaPath), ".ics")
+	if token == "" || strings.Contains(token, "/") {
+		httperror.WriteJSON(w, http.StatusNotFound, service.ErrTokenNotFound.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	cal, err := h.svc.CalendarioAgenda(ctx, token, time.Now())
+	if writeCalendarioError(w, err) {
+		return
+	}
+	writeICS(w, cal, "agenda.ics")
+}
+
+// TokenHandler manages the caller's personal feed: GET reports whether it
+// exists, POST issues a new URL (revoking the previous one) and DELETE
+// disables it.
+func (h *Handler) TokenHandler(w http.ResponseWriter, r *http.Request) {
+	usuarioID := policy.SubjectFromRequest(r).UserID
+	if usuarioID <= 0 {
+		httperror.WriteJSON(w, http.StatusUnauthorized, "no autenticado")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		creado, err := h.svc.TokenCreado(ctx, usuarioID)
+		if writeCalendarioError(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"creado": creado})
+	case http.MethodPost:
+		token, err := h.svc.GenerarToken(ctx, usuarioID)
+		if writeCalendarioError(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]string{
+			"token": token,
+			"url":   publicurl.Base() + AgendaPath + token + ".ics",
+		})
+	case http.MethodDelete:
+		if writeCalendarioError(w, h.svc.RevocarToken(ctx, usuarioID)) {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeCalendarioError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrTokenNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
+
+func writeICS(w http.ResponseWriter, cal ics.Calendar, filename string) {
+	w.Header().Set("Content-Type", ics.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write(ics.Encode(cal, time.Now()))
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
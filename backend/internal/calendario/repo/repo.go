@@ -0,0 +1,173 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	inscripciones "project/backend/internal/inscripciones/validation"
+	"project/backend/prisma/db"
+)
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+// EventoRow is an event as published in calendars. EstadoInscripcion is
+// only set in a user's agenda.
+type EventoRow struct {
+	IDEvento          int       `json:"id_evento"`
+	Nombre            string    `json:"nombre"`
+	Descripcion       string    `json:"descripcion"`
+	Ubicacion         string    `json:"ubicacion"`
+	SedeNombre        *string   `json:"sede_nombre"`
+	FechaInicio       time.Time `json:"fecha_inicio"`
+	FechaFin          time.Time `json:"fecha_fin"`
+	Estado            string    `json:"estado"`
+	Cancelado         bool      `json:"cancelado"`
+	Secuencia         int       `json:"secuencia"`
+	CreatedAt         time.Time `json:"createdAt"`
+	ActualizadoEn     time.Time `json:"actualizado_en"`
+	EstadoInscripcion *string   `json:"estado_inscripcion"`
+}
+
+// SesionRow is a session as published in calendars. A session of a
+// cancelled event counts as cancelled, with one more sequence number so
+// clients pick the change up.
+type SesionRow struct {
+	IDSesion      int       `json:"id_sesion"`
+	IDEvento      int       `json:"id_evento"`
+	EventoNombre  string    `json:"evento_nombre"`
+	Titulo        string    `json:"titulo"`
+	Descripcion   string    `json:"descripcion"`
+	Ubicacion     string    `json:"ubicacion"`
+	FechaInicio   time.Time `json:"fecha_inicio"`
+	FechaFin      time.Time `json:"fecha_fin"`
+	Cancelado     bool      `json:"cancelado"`
+	Secuencia     int       `json:"secuencia"`
+	CreatedAt     time.Time `json:"createdAt"`
+	ActualizadoEn time.Time `json:"actualizado_en"`
+}
+
+const eventoSelect = `SELECT e."id_evento", e."nombre", e."descripcion", e."ubicacion", s."nombre" AS "sede_nombre",
+		e."fecha_inicio", e."fecha_fin", e."estado", e."cancelado", e."secuencia", e."createdAt", e."actualizado_en"`
+
+const eventoFrom = ` FROM "Evento" e
+		LEFT JOIN "Sede" s ON s."id_sede" = e."id_sede"`
+
+const sesionSelect = `SELECT se."id_sesion", se."id_evento", e."nombre" AS "evento_nombre", se."titulo", se."descripcion", se."ubicacion",
+		se."fecha_inicio", se."fecha_fin", (se."cancelado" OR e."cancelado") AS "cancelado",
+		se."secuencia" + CASE WHEN e."cancelado" AND NOT se."cancelado" THEN 1 ELSE 0 END AS "secuencia",
+		se."createdAt", GREATEST(se."actualizado_en", CASE WHEN e."cancelado" THEN e."actualizado_en" END) AS "actualizado_en"
+		FROM "Sesion" se
+		JOIN "Evento" e ON e."id_evento" = se."id_evento"`
+
+func (r *Repository) FindEvento(ctx context.Context, id int) (EventoRow, error) {
+	var rows []EventoRow
+	if err := r.client.Prisma.Raw.QueryRaw(eventoSelect+eventoFrom+` WHERE e."id_evento" = $1`, id).Exec(ctx, &rows); err != nil {
+		return EventoRow{}, err
+	}
+	if len(rows) == 0 {
+		return EventoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// FindSesiones lists every session of an event, cancelled ones included so
+// calendars can remove them.
+func (r *Repository) FindSesiones(ctx context.Context, eventoID int) ([]SesionRow, error) {
+	var rows []SesionRow
+	query := sesionSelect + ` WHERE se."id_evento" = $1 ORDER BY se."fecha_inicio", se."id_sesion"`
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindPublicados lists the events that are not drafts and end after desde.
+func (r *Repository) FindPublicados(ctx context.Context, desde time.Time) ([]EventoRow, error) {
+	var rows []EventoRow
+	query := eventoSelect + eventoFrom + ` WHERE e."estado" <> 'Borrador' AND e."fecha_fin" >= ($1::timestamptz AT TIME ZONE 'UTC')
+		ORDER BY e."fecha_inicio", e."id_evento"`
+	if err := r.client.Prisma.Raw.QueryRaw(query, desde).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindInscritos lists the events the user holds an inscription for, with
+// its status, ending after desde. Rejected, cancelled and expired
+// inscriptions are left out.
+func (r *Repository) FindInscritos(ctx context.Context, usuarioID int, desde time.Time) ([]EventoRow, error) {
+	var rows []EventoRow
+	query := eventoSelect + `, i."estado" AS "estado_inscripcion"` + eventoFrom + `
+		JOIN "Inscripcion" i ON i."id_evento" = e."id_evento"
+		WHERE i."id_usuario" = $1::int AND i."estado" NOT IN ` + inscripciones.EstadosLiberados + `
+			AND e."estado" <> 'Borrador' AND e."fecha_fin" >= ($2::timestamptz AT TIME ZONE 'UTC')
+		ORDER BY e."fecha_inicio", e."id_evento"`
+	if err := r.client.Prisma.Raw.QueryRaw(query, usuarioID, desde).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindSesionesPonente lists the sessions the user speaks at, ending after
+// desde, in events that are not drafts.
+func (r *Repository) FindSesionesPonente(ctx context.Context, usuarioID int, desde time.Time) ([]SesionRow, error) {
+	var rows []SesionRow
+	query := sesionSelect + `
+		JOIN "SesionPonente" sp ON sp."id_sesion" = se."id_sesion"
+		WHERE sp."id_usuario" = $1::int AND e."estado" <> 'Borrador' AND se."fecha_fin" >= ($2::timestamptz AT TIME ZONE 'UTC')
+		ORDER BY se."fecha_inicio", se."id_sesion"`
+	if err := r.client.Prisma.Raw.QueryRaw(query, usuarioID, desde).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SetToken stores the hash of the user's feed secret, replacing any
+// previous one.
+func (r *Repository) SetToken(ctx context.Context, usuarioID int, hash string) error {
+	query := `INSERT INTO "CalendarioToken" ("id_usuario", "token_hash", "createdAt") VALUES ($1::int, $2::text, NOW())
+		ON CONFLICT ("id_usuario") DO UPDATE SET "token_hash" = EXCLUDED."token_hash", "createdAt" = NOW()`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, usuarioID, hash).Exec(ctx)
+	return err
+}
+
+func (r *Repository) DeleteToken(ctx context.Context, usuarioID int) error {
+	query := `DELETE FROM "CalendarioToken" WHERE "id_usuario" = $1::int`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, usuarioID).Exec(ctx)
+	return err
+}
+
+// FindTokenCreado returns when the user's current feed secret was created.
+func (r *Repository) FindTokenCreado(ctx context.Context, usuarioID int) (time.Time, error) {
+	var rows []struct {
+		CreatedAt time.Time `json:"createdAt"`
+	}
+	query := `SELECT "createdAt" FROM "CalendarioToken" WHERE "id_usuario" = $1::int`
+	if err := r.client.Prisma.Raw.QueryRaw(query, usuarioID).Exec(ctx, &rows); err != nil {
+		return time.Time{}, err
+	}
+	if len(rows) == 0 {
+		return time.Time{}, db.ErrNotFound
+	}
+	return rows[0].CreatedAt, nil
+}
+
+func (r *Repository) FindUsuarioPorToken(ctx context.Context, hash string) (int, error) {
+	var rows []struct {
+		IDUsuario int `json:"id_usuario"`
+	}
+	query := `SELECT "id_usuario" FROM "CalendarioToken" WHERE "token_hash" = $1::text`
+	if err := r.client.Prisma.Raw.QueryRaw(query, hash).Exec(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, db.ErrNotFound
+	}
+	return rows[0].IDUsuario, nil
+}
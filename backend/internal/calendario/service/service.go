@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project/backend/internal/calendario/repo"
+	"project/backend/internal/shared/ics"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrNotFound      = errors.New("evento no encontrado")
+	ErrTokenNotFound = errors.New("calendario no encontrado")
+	ErrDB            = errors.New("db error")
+)
+
+const (
+	// Feeds keep recently finished events so clients do not drop them
+	// right after they end.
+	ventanaPasado = 90 * 24 * time.Hour
+	// refrescoFeed is the polling interval suggested to subscribed clients.
+	refrescoFeed = 6 * time.Hour
+)
+
+type Service struct {
+	repo *repo.Repository
+}
+
+func New(r *repo.Repository) *Service {
+	return &Service{repo: r}
+}
+
+// CalendarioEvento returns the event with its sessions. Drafts are only
+// visible to managers.
+func (s *Service) CalendarioEvento(ctx context.Context, id int, incluirBorradores bool) (ics.Calendar, error) {
+	evento, err := s.repo.FindEvento(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ics.Calendar{}, ErrNotFound
+		}
+		return ics.Calendar{}, ErrDB
+	}
+	if evento.Estado == "Borrador" && !incluirBorradores {
+		return ics.Calendar{}, ErrNotFound
+	}
+	sesiones, err := s.repo.FindSesiones(ctx, id)
+	if err != nil {
+		return ics.Calendar{}, ErrDB
+	}
+
+	cal := ics.Calendar{Name: evento.Nombre, Events: []ics.Event{eventoICS(evento)}}
+	for _, sesion := range sesiones {
+		cal.Events = append(cal.Events, sesionICS(sesion))
+	}
+	return cal, nil
+}
+
+// CalendarioPublico returns the published events that have not ended more
+// than 90 days before now.
+func (s *Service) CalendarioPublico(ctx context.Context, now time.Time) (ics.Calendar, error) {
+	eventos, err := s.repo.FindPublicados(ctx, now.Add(-ventanaPasado))
+	if err != nil {
+		return ics.Calendar{}, ErrDB
+	}
+	cal := ics.Calendar{Name: "Eventos", Refresh: refrescoFeed}
+	for _, evento := range eventos {
+		cal.Events = append(cal.Events, eventoICS(evento))
+	}
+	return cal, nil
+}
+
+// CalendarioAgenda returns the personal agenda behind a feed token: the
+// events the user is inscribed in and the sessions they speak at.
+func (s *Service) CalendarioAgenda(ctx context.Context, token string, now time.Time) (ics.Calendar, error) {
+	usuarioID, err := s.repo.FindUsuarioPorToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ics.Calendar{}, ErrTokenNotFound
+		}
+		return ics.Calendar{}, ErrDB
+	}
+
+	desde := now.Add(-ventanaPasado)
+	eventos, err := s.repo.FindInscritos(ctx, usuarioID, desde)
+	if err != nil {
+		return ics.Calendar{}, ErrDB
+	}
+	sesiones, err := s.repo.FindSesionesPonente(ctx, usuarioID, desde)
+	if err != nil {
+		return ics.Calendar{}, ErrDB
+	}
+
+	cal := ics.Calendar{Name: "Mi agenda", Refresh: refrescoFeed}
+	for _, evento := range eventos {
+		cal.Events = append(cal.Events, eventoICS(evento))
+	}
+	for _, sesion := range sesiones {
+		cal.Events = append(cal.Events, sesionICS(sesion))
+	}
+	return cal, nil
+}
+
+// GenerarToken creates a new feed token for the user, invalidating the
+// previous one. Only its hash is stored, so the token is shown once.
+func (s *Service) GenerarToken(ctx context.Context, usuarioID int) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", ErrDB
+	}
+	token := hex.EncodeToString(raw)
+	if err := s.repo.SetToken(ctx, usuarioID, hashToken(token)); err != nil {
+		return "", ErrDB
+	}
+	return token, nil
+}
+
+// RevocarToken disables the user's feed.
+func (s *Service) RevocarToken(ctx context.Context, usuarioID int) error {
+	if err := s.repo.DeleteToken(ctx, usuarioID); err != nil {
+		return ErrDB
+	}
+	return nil
+}
+
+// TokenCreado reports when the user's feed token was generated.
+func (s *Service) TokenCreado(ctx context.Context, usuarioID int) (time.Time, error) {
+	creado, err := s.repo.FindTokenCreado(ctx, usuarioID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return time.Time{}, ErrTokenNotFound
+		}
+		return time.Time{}, ErrDB
+	}
+	return creado, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func eventoICS(e repo.EventoRow) ics.Event {
+	return ics.Event{
+		UID:          fmt.Sprintf("evento-%d@eventos", e.IDEvento),
+		Summary:      e.Nombre,
+		Description:  e.Descripcion,
+		Location:     lugar(e.SedeNombre, e.Ubicacion),
+		Start:        e.FechaInicio,
+		End:          e.FechaFin,
+		Created:      e.CreatedAt,
+		LastModified: e.ActualizadoEn,
+		Sequence:     e.Secuencia,
+		Status:       estadoEvento(e),
+	}
+}
+
+func sesionICS(s repo.SesionRow) ics.Event {
+	status := ics.StatusConfirmed
+	if s.Cancelado {
+		status = ics.StatusCancelled
+	}
+	return ics.Event{
+		UID:          fmt.Sprintf("sesion-%d@eventos", s.IDSesion),
+		Summary:      s.Titulo + " · " + s.EventoNombre,
+		Description:  s.Descripcion,
+		Location:     s.Ubicacion,
+		Start:        s.FechaInicio,
+		End:          s.FechaFin,
+		Created:      s.CreatedAt,
+		LastModified: s.ActualizadoEn,
+		Sequence:     s.Secuencia,
+		Status:       status,
+	}
+}
+
+// estadoEvento maps the event, and the user's inscription when present, to
+// a VEVENT status: inscriptions still waiting for a place are tentative.
+func estadoEvento(e repo.EventoRow) string {
+	if e.Cancelado {
+		return ics.StatusCancelled
+	}
+	if e.EstadoInscripcion != nil {
+		switch *e.EstadoInscripcion {
+		case "Pendiente", "En espera":
+			return ics.StatusTentative
+		}
+	}
+	return ics.StatusConfirmed
+}
+
+func lugar(sede *string, ubicacion string) string {
+	partes := []string{}
+	if sede != nil && strings.TrimSpace(*sede) != "" {
+		partes = append(partes, strings.TrimSpace(*sede))
+	}
+	if u := strings.TrimSpace(ubicacion); u != "" {
+		partes = append(partes, u)
+	}
+	return strings.Join(partes, ", ")
+}
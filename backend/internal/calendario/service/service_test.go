@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"project/backend/internal/calendario/repo"
+	"project/backend/internal/shared/ics"
+)
+
+func TestEstadoEvento(t *testing.T) {
+	estado := func(s string) *string { return &s }
+	cases := []struct {
+		name string
+		row  repo.EventoRow
+		want string
+	}{
+		{"publicado", repo.EventoRow{}, ics.StatusConfirmed},
+		{"cancelado", repo.EventoRow{Cancelado: true, EstadoInscripcion: estado("Aprobado")}, ics.StatusCancelled},
+		{"inscripcion aprobada", repo.EventoRow{EstadoInscripcion: estado("Aprobado")}, ics.StatusConfirmed},
+		{"inscripcion pendiente", repo.EventoRow{EstadoInscripcion: estado("Pendiente")}, ics.StatusTentative},
+		{"en espera", repo.EventoRow{EstadoInscripcion: estado("En espera")}, ics.StatusTentative},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := estadoEvento(tc.row); got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSesionICS(t *testing.T) {
+	ev := sesionICS(repo.SesionRow{IDSesion: 4, Titulo: "Apertura", EventoNombre: "Congreso", Cancelado: true, Secuencia: 2})
+	if ev.UID != "sesion-4@eventos" || ev.Status != ics.StatusCancelled || ev.Sequence != 2 {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	if hashToken("a") == hashToken("b") || len(hashToken("a")) != 64 {
+		t.Fatalf("unexpected hash")
+	}
+}
@@ -0,0 +1,35 @@
+package dto
+
+// PlantillaRequest represents the payload to configure the certificate of
+// an event. Texto may use the placeholders in Marcadores, written between
+// braces, and must name the participant.
+type PlantillaRequest struct {
+	Titulo string  `json:"titulo"`
+	Texto  string  `json:"texto"`
+	Firmas []Firma `json:"firmas"`
+}
+
+// Firma is a signature line printed at the foot of the certificate.
+type Firma struct {
+	Nombre string `json:"nombre"`
+	Cargo  string `json:"cargo"`
+}
+
+// Placeholders a certificate text can use.
+const (
+	MarcadorParticipante = "participante"
+	MarcadorAfiliacion   = "afiliacion"
+	MarcadorEvento       = "evento"
+	MarcadorFechaInicio  = "fecha_inicio"
+	MarcadorFechaFin     = "fecha_fin"
+	MarcadorUbicacion    = "ubicacion"
+)
+
+var Marcadores = []string{
+	MarcadorParticipante,
+	MarcadorAfiliacion,
+	MarcadorEvento,
+	MarcadorFechaInicio,
+	MarcadorFechaFin,
+	MarcadorUbicacion,
+}
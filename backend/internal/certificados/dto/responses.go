@@ -0,0 +1,40 @@
+package dto
+
+type PlantillaResponse struct {
+	IDPlantilla int     `json:"id_plantilla"`
+	IDEvento    int     `json:"id_evento"`
+	Titulo      string  `json:"titulo"`
+	Texto       string  `json:"texto"`
+	Firmas      []Firma `json:"firmas"`
+}
+
+// CertificadoResponse is an issued certificate. Texto is the template text
+// with the placeholders already filled in when it was issued.
+type CertificadoResponse struct {
+	IDCertificado int     `json:"id_certificado"`
+	IDInscripcion int     `json:"id_inscripcion"`
+	Codigo        string  `json:"codigo"`
+	Participante  string  `json:"participante"`
+	Titulo        string  `json:"titulo"`
+	Texto         string  `json:"texto"`
+	Firmas        []Firma `json:"firmas"`
+	EmitidoEn     string  `json:"emitido_en"`
+}
+
+// EmisionResponse reports an issuing run: Emitidos were issued now, Total
+// is how many the event has in all.
+type EmisionResponse struct {
+	Emitidos int `json:"emitidos"`
+	Total    int `json:"total"`
+}
+
+// VerificacionResponse is what the public check of a code shows. Only
+// Valido and Codigo are set when the code does not exist.
+type VerificacionResponse struct {
+	Valido       bool   `json:"valido"`
+	Codigo       string `json:"codigo"`
+	Participante string `json:"participante,omitempty"`
+	Evento       string `json:"evento,omitempty"`
+	Titulo       string `json:"titulo,omitempty"`
+	EmitidoEn    string `json:"emitido_en,omitempty"`
+}
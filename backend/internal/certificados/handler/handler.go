@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project/backend/internal/certificados/dto"
+	"project/backend/internal/certificados/repo"
+	"project/backend/internal/certificados/service"
+	"project/backend/internal/certificados/validation"
+	"project/backend/internal/policy"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+	"project/backend/internal/shared/pdf"
+	"project/backend/internal/shared/publicurl"
+	"project/backend/prisma/db"
+)
+
+// VerificarCertificadoPath is the public endpoint printed on every
+// certificate: VerificarCertificadoPath + "?codigo=" + code.
+const VerificarCertificadoPath = "/api/certificados/verificar"
+
+type Handler struct {
+	svc         *service.Service
+	roleService roles.UserRoleService
+}
+
+func New(client *db.PrismaClient) *Handler {
+	return &Handler{
+		svc:         service.New(repo.New(client)),
+		roleService: roles.NewUserRoleService(client),
+	}
+}
+
+// ServeHTTP serves /api/certificados?id_evento=N for organizers. GET returns
+// the certificate template of the event and PUT creates or replaces it.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		defer cancel()
+
+		plantilla, err := h.svc.GetPlantilla(ctx, eventoID)
+		if writeCertificadoError(w, err) {
+			return
+		}
+		writeJSON(w, plantilla)
+		return
+	}
+
+	var req dto.PlantillaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidatePlantilla(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	plantilla, err := h.svc.GuardarPlantilla(ctx, eventoID, req)
+	if writeCertificadoError(w, err) {
+		return
+	}
+	writeJSON(w, plantilla)
+}
+
+// EmitidosHandler serves /api/certificados/emitidos?id_evento=N for
+// organizers. GET lists the certificates issued for the event and POST
+// issues the missing ones once the event has finished.
+func (h *Handler) EmitidosHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	if r.Method == http.MethodGet {
+		certificados, err := h.svc.ListCertificados(ctx, eventoID)
+		if writeCertificadoError(w, err) {
+			return
+		}
+		writeJSON(w, certificados)
+		return
+	}
+
+	res, err := h.svc.EmitirCertificados(ctx, eventoID)
+	if writeCertificadoError(w, err) {
+		return
+	}
+	writeJSON(w, res)
+}
+
+// PDFHandler serves GET /api/certificados/pdf?id_inscripcion=N: the
+// certificate as a PDF, for the attendee it belongs to or an organizer.
+func (h *Handler) PDFHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	inscripcionID, err := strconv.Atoi(r.URL.Query().Get("id_inscripcion"))
+	if err != nil || inscripcionID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_inscripcion inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	subject := policy.SubjectFromRequest(r)
+	gestor, err := roles.AuthorizeSubject(ctx, h.roleService, subject, "events.management")
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusInternalServerError, "error verificando permisos")
+		return
+	}
+
+	enlace := publicurl.Base() + VerificarCertificadoPath + "?codigo="
+	contenido, codigo, err := h.svc.PDFCertificado(ctx, inscripcionID, subject.UserID, gestor, enlace)
+	if writeCertificadoError(w, err) {
+		return
+	}
+	w.Header().Set("Content-Type", pdf.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"certificado-%s.pdf\"", codigo))
+	_, _ = w.Write(contenido)
+}
+
+// VerificarHandler serves GET VerificarCertificadoPath?codigo=C to anyone:
+// whether the code belongs to an issued certificate, and whose it is.
+func (h *Handler) VerificarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	res, err := h.svc.VerificarCertificado(ctx, r.URL.Query().Get("codigo"))
+	if writeCertificadoError(w, err) {
+		return
+	}
+	writeJSON(w, res)
+}
+
+// msgSinPermisos answers callers without events.management.
+const msgSinPermisos = "no tienes permisos para gestionar certificados"
+
+func writeCertificadoError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrPlantillaNotFound), errors.Is(err, service.ErrEventoNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrNoAutorizado):
+		httperror.WriteJSON(w, http.StatusForbidden, "no tienes acceso a este certificado")
+	case errors.Is(err, service.ErrEventoNoFinalizado):
+		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(payload)
+}
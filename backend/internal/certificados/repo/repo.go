@@ -0,0 +1,209 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"project/backend/internal/certificados/dto"
+	"project/backend/prisma/db"
+)
+
+// estadoElegible is the inscription status that earns a certificate;
+// attendees checked in at the door earn one too.
+const estadoElegible = "Aprobado"
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+// EventoRow holds what a certificate prints about its event.
+type EventoRow struct {
+	IDEvento    int       `json:"id_evento"`
+	Nombre      string    `json:"nombre"`
+	Estado      string    `json:"estado"`
+	FechaInicio time.Time `json:"fecha_inicio"`
+	FechaFin    time.Time `json:"fecha_fin"`
+	Ubicacion   string    `json:"ubicacion"`
+	ZonaHoraria string    `json:"zona_horaria"`
+}
+
+// PlantillaRow is the certificate template of an event. Firmas is JSON.
+type PlantillaRow struct {
+	IDPlantilla int    `json:"id_plantilla"`
+	IDEvento    int    `json:"id_evento"`
+	Titulo      string `json:"titulo"`
+	Texto       string `json:"texto"`
+	Firmas      string `json:"firmas"`
+}
+
+// ElegibleRow is an inscription that earned a certificate and has none yet.
+type ElegibleRow struct {
+	IDInscripcion int    `json:"id_inscripcion"`
+	Participante  string `json:"participante"`
+	Afiliacion    string `json:"afiliacion"`
+}
+
+// NuevoCertificado is a certificate ready to be stored, with its text
+// already filled in.
+type NuevoCertificado struct {
+	IDInscripcion int         `json:"id_inscripcion"`
+	Codigo        string      `json:"codigo"`
+	Participante  string      `json:"participante"`
+	Titulo        string      `json:"titulo"`
+	Texto         string      `json:"texto"`
+	Firmas        []dto.Firma `json:"firmas"`
+}
+
+// CertificadoRow is an issued certificate with the user it belongs to and
+// the time zone of its event. Firmas is JSON.
+type CertificadoRow struct {
+	IDCertificado int       `json:"id_certificado"`
+	IDEvento      int       `json:"id_evento"`
+	IDInscripcion int       `json:"id_inscripcion"`
+	IDUsuario     int       `json:"id_usuario"`
+	Codigo        string    `json:"codigo"`
+	Participante  string    `json:"participante"`
+	NombreEvento  string    `json:"nombre_evento"`
+	Titulo        string    `json:"titulo"`
+	Texto         string    `json:"texto"`
+	Firmas        string    `json:"firmas"`
+	EmitidoEn     time.Time `json:"emitido_en"`
+	ZonaHoraria   string    `json:"zona_horaria"`
+}
+
+const certificadoSelect = `SELECT c."id_certificado", c."id_evento", c."id_inscripcion", i."id_usuario", c."codigo",
+		c."participante", c."nombre_evento", c."titulo", c."texto", c."firmas"::text AS "firmas", c."emitido_en",
+		e."zona_horaria"
+	FROM "Certificado" c
+	JOIN "Inscripcion" i ON i."id_inscripcion" = c."id_inscripcion"
+	JOIN "Evento" e ON e."id_evento" = c."id_evento"`
+
+// FindEvento returns the event, or db.ErrNotFound.
+func (r *Repository) FindEvento(ctx context.Context, id int) (EventoRow, error) {
+	query := `SELECT "id_evento", "nombre", "estado", "fecha_inicio", "fecha_fin", "ubicacion", "zona_horaria"
+		FROM "Evento" WHERE "id_evento" = $1::int`
+	var rows []EventoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return EventoRow{}, err
+	}
+	if len(rows) == 0 {
+		return EventoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// FindPlantilla returns the certificate template of an event, or
+// db.ErrNotFound.
+func (r *Repository) FindPlantilla(ctx context.Context, eventoID int) (PlantillaRow, error) {
+	query := `SELECT "id_plantilla", "id_evento", "titulo", "texto", "firmas"::text AS "firmas"
+		FROM "PlantillaCertificado" WHERE "id_evento" = $1::int`
+	var rows []PlantillaRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return PlantillaRow{}, err
+	}
+	if len(rows) == 0 {
+		return PlantillaRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// GuardarPlantilla creates the certificate template of an event or replaces
+// it. Certificates already issued keep the text they were issued with.
+func (r *Repository) GuardarPlantilla(ctx context.Context, eventoID int, titulo, texto string, firmas []dto.Firma) error {
+	if firmas == nil {
+		firmas = []dto.Firma{}
+	}
+	payload, err := json.Marshal(firmas)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO "PlantillaCertificado" ("id_evento", "titulo", "texto", "firmas", "createdAt", "updatedAt")
+		VALUES ($1::int, $2::text, $3::text, $4::jsonb, NOW(), NOW())
+		ON CONFLICT ("id_evento") DO UPDATE SET "titulo" = EXCLUDED."titulo", "texto" = EXCLUDED."texto",
+			"firmas" = EXCLUDED."firmas", "updatedAt" = NOW()`
+	_, err = r.client.Prisma.Raw.ExecuteRaw(query, eventoID, strings.TrimSpace(titulo), strings.TrimSpace(texto), string(payload)).Exec(ctx)
+	return err
+}
+
+// ListElegibles returns the inscriptions of an event that were approved or
+// checked in and have no certificate yet.
+func (r *Repository) ListElegibles(ctx context.Context, eventoID int) ([]ElegibleRow, error) {
+	query := `SELECT i."id_inscripcion", btrim(i."nombre_participante") AS "participante", btrim(i."afiliacion") AS "afiliacion"
+		FROM "Inscripcion" i
+		WHERE i."id_evento" = $1::int
+			AND (i."estado" = '` + estadoElegible + `' OR EXISTS (SELECT 1 FROM "Asistencia" a WHERE a."id_inscripcion" = i."id_inscripcion"))
+			AND NOT EXISTS (SELECT 1 FROM "Certificado" c WHERE c."id_inscripcion" = i."id_inscripcion")
+		ORDER BY i."id_inscripcion"`
+	var rows []ElegibleRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Emitir stores the certificates of an event in a single statement.
+// Inscriptions that got one in the meantime are skipped. It returns how
+// many were stored.
+func (r *Repository) Emitir(ctx context.Context, eventoID int, nombreEvento string, certificados []NuevoCertificado) (int, error) {
+	if len(certificados) == 0 {
+		return 0, nil
+	}
+	for i := range certificados {
+		if certificados[i].Firmas == nil {
+			certificados[i].Firmas = []dto.Firma{}
+		}
+	}
+	payload, err := json.Marshal(certificados)
+	if err != nil {
+		return 0, err
+	}
+	query := `INSERT INTO "Certificado" ("id_evento", "id_inscripcion", "codigo", "participante", "nombre_evento", "titulo", "texto", "firmas", "emitido_en")
+		SELECT $1::int, f."id_inscripcion", f."codigo", f."participante", $2::text, f."titulo", f."texto", f."firmas", NOW()
+		FROM jsonb_to_recordset($3::jsonb) AS f("id_inscripcion" int, "codigo" text, "participante" text, "titulo" text, "texto" text, "firmas" jsonb)
+		ON CONFLICT ("id_inscripcion") DO NOTHING`
+	count, err := r.client.Prisma.Raw.ExecuteRaw(query, eventoID, nombreEvento, string(payload)).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return count.Count, nil
+}
+
+// ListCertificados returns the certificates of an event in the order they
+// were issued.
+func (r *Repository) ListCertificados(ctx context.Context, eventoID int) ([]CertificadoRow, error) {
+	var rows []CertificadoRow
+	query := certificadoSelect + ` WHERE c."id_evento" = $1::int ORDER BY c."id_certificado"`
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindByInscripcion returns the certificate of an inscription, or
+// db.ErrNotFound.
+func (r *Repository) FindByInscripcion(ctx context.Context, inscripcionID int) (CertificadoRow, error) {
+	return r.findOne(ctx, certificadoSelect+` WHERE c."id_inscripcion" = $1::int`, inscripcionID)
+}
+
+// FindByCodigo returns the certificate with a verification code, or
+// db.ErrNotFound.
+func (r *Repository) FindByCodigo(ctx context.Context, codigo string) (CertificadoRow, error) {
+	return r.findOne(ctx, certificadoSelect+` WHERE c."codigo" = $1::text`, codigo)
+}
+
+func (r *Repository) findOne(ctx context.Context, query string, param interface{}) (CertificadoRow, error) {
+	var rows []CertificadoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, param).Exec(ctx, &rows); err != nil {
+		return CertificadoRow{}, err
+	}
+	if len(rows) == 0 {
+		return CertificadoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
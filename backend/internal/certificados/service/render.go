@@ -0,0 +1,46 @@
+package service
+
+import (
+	"project/backend/internal/certificados/dto"
+	"project/backend/internal/certificados/repo"
+	"project/backend/internal/events/domain"
+	"project/backend/internal/shared/pdf"
+)
+
+// renderCertificado lays out a certificate on a landscape A4 page: a
+// framed title, the participant's name, the text of the template, the
+// signature lines, and a footer with the code and where to verify it.
+func renderCertificado(c repo.CertificadoRow, firmas []dto.Firma, verificacion string) []byte {
+	size := pdf.A4.Landscape()
+	doc := pdf.New(size)
+	page := doc.AddPage()
+	centro := size.Width / 2
+
+	page.SetColor(0.35, 0.35, 0.35)
+	page.Rect(24, 24, size.Width-48, size.Height-48, 2)
+	page.Rect(32, 32, size.Width-64, size.Height-64, 0.5)
+
+	page.SetColor(0.1, 0.1, 0.1)
+	page.Paragraph(80, 120, size.Width-160, pdf.HelveticaBold, 30, 36, pdf.AlignCenter, c.Titulo)
+	page.Text(centro, 200, pdf.HelveticaBold, 24, pdf.AlignCenter, c.Participante)
+	page.Line(centro-180, 212, centro+180, 212, 0.75)
+	page.Paragraph(120, 250, size.Width-240, pdf.Helvetica, 14, 20, pdf.AlignCenter, c.Texto)
+
+	if len(firmas) > 0 {
+		ancho := (size.Width - 160) / float64(len(firmas))
+		for i, f := range firmas {
+			x := 80 + ancho*float64(i) + ancho/2
+			page.Line(x-90, 470, x+90, 470, 0.75)
+			page.Text(x, 486, pdf.HelveticaBold, 11, pdf.AlignCenter, f.Nombre)
+			if f.Cargo != "" {
+				page.Text(x, 501, pdf.HelveticaOblique, 10, pdf.AlignCenter, f.Cargo)
+			}
+		}
+	}
+
+	page.SetColor(0.35, 0.35, 0.35)
+	emitido := c.EmitidoEn.In(domain.Zona(c.ZonaHoraria)).Format(formatoFecha)
+	page.Text(centro, 538, pdf.Helvetica, 9, pdf.AlignCenter, "Emitido el "+emitido+" · Código de verificación: "+c.Codigo)
+	page.Text(centro, 551, pdf.Helvetica, 9, pdf.AlignCenter, "Verifica su autenticidad en "+verificacion)
+	return doc.Bytes()
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"project/backend/internal/certificados/dto"
+	"project/backend/internal/certificados/repo"
+	"project/backend/internal/events/domain"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrNotFound           = errors.New("certificado no encontrado")
+	ErrPlantillaNotFound  = errors.New("el evento no tiene plantilla de certificado")
+	ErrEventoNotFound     = errors.New("evento no encontrado")
+	ErrEventoNoFinalizado = errors.New("los certificados se emiten cuando el evento finaliza")
+	ErrNoAutorizado       = errors.New("no autorizado")
+	ErrDB                 = errors.New("db error")
+)
+
+// formatoFecha is how dates are written in certificate texts.
+const formatoFecha = "02/01/2006"
+
+type Service struct {
+	repo *repo.Repository
+}
+
+func New(r *repo.Repository) *Service {
+	return &Service{repo: r}
+}
+
+func (s *Service) GetPlantilla(ctx context.Context, eventoID int) (dto.PlantillaResponse, error) {
+	row, err := s.repo.FindPlantilla(ctx, eventoID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.PlantillaResponse{}, ErrPlantillaNotFound
+		}
+		return dto.PlantillaResponse{}, ErrDB
+	}
+	return dto.PlantillaResponse{
+		IDPlantilla: row.IDPlantilla,
+		IDEvento:    row.IDEvento,
+		Titulo:      row.Titulo,
+		Texto:       row.Texto,
+		Firmas:      parseFirmas(row.Firmas),
+	}, nil
+}
+
+// GuardarPlantilla creates or replaces the certificate template of an
+// event. Certificates already issued are not changed.
+func (s *Service) GuardarPlantilla(ctx context.Context, eventoID int, req dto.PlantillaRequest) (dto.PlantillaResponse, error) {
+	if _, err := s.evento(ctx, eventoID); err != nil {
+		return dto.PlantillaResponse{}, err
+	}
+	firmas := make([]dto.Firma, 0, len(req.Firmas))
+	for _, f := range req.Firmas {
+		firmas = append(firmas, dto.Firma{Nombre: strings.TrimSpace(f.Nombre), Cargo: strings.TrimSpace(f.Cargo)})
+	}
+	if err := s.repo.GuardarPlantilla(ctx, eventoID, req.Titulo, req.Texto, firmas); err != nil {
+		return dto.PlantillaResponse{}, ErrDB
+	}
+	return s.GetPlantilla(ctx, eventoID)
+}
+
+// EmitirCertificados issues a certificate to every attendee of a finished
+// event whose inscription was approved or who was checked in and who has
+// none yet, each with its own verification code. Running it again only
+// issues the missing ones.
+func (s *Service) EmitirCertificados(ctx context.Context, eventoID int) (dto.EmisionResponse, error) {
+	evento, err := s.evento(ctx, eventoID)
+	if err != nil {
+		return dto.EmisionResponse{}, err
+	}
+	if evento.Estado != domain.EstadoFinalizado {
+		return dto.EmisionResponse{}, ErrEventoNoFinalizado
+	}
+	plantilla, err := s.GetPlantilla(ctx, eventoID)
+	if err != nil {
+		return dto.EmisionResponse{}, err
+	}
+	elegibles, err := s.repo.ListElegibles(ctx, eventoID)
+	if err != nil {
+		return dto.EmisionResponse{}, ErrDB
+	}
+
+	nuevos := make([]repo.NuevoCertificado, 0, len(elegibles))
+	for _, e := range elegibles {
+		codigo, err := nuevoCodigo()
+		if err != nil {
+			return dto.EmisionResponse{}, ErrDB
+		}
+		nuevos = append(nuevos, repo.NuevoCertificado{
+			IDInscripcion: e.IDInscripcion,
+			Codigo:        codigo,
+			Participante:  e.Participante,
+			Titulo:        plantilla.Titulo,
+			Texto:         aplicarPlantilla(plantilla.Texto, valoresCertificado(evento, e)),
+			Firmas:        plantilla.Firmas,
+		})
+	}
+	emitidos, err := s.repo.Emitir(ctx, eventoID, evento.Nombre, nuevos)
+	if err != nil {
+		return dto.EmisionResponse{}, ErrDB
+	}
+	todos, err := s.repo.ListCertificados(ctx, eventoID)
+	if err != nil {
+		return dto.EmisionResponse{}, ErrDB
+	}
+	return dto.EmisionResponse{Emitidos: emitidos, Total: len(todos)}, nil
+}
+
+func (s *Service) ListCertificados(ctx context.Context, eventoID int) ([]dto.CertificadoResponse, error) {
+	if _, err := s.evento(ctx, eventoID); err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListCertificados(ctx, eventoID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make([]dto.CertificadoResponse, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, certificadoResponse(row))
+	}
+	return res, nil
+}
+
+// PDFCertificado renders the certificate of an inscription. Only its owner
+// can download it unless gestor is set. enlace is the verification URL
+// printed on it, to which the code is appended. It returns the code too.
+func (s *Service) PDFCertificado(ctx context.Context, inscripcionID, usuarioID int, gestor bool, enlace string) ([]byte, string, error) {
+	row, err := s.repo.FindByInscripcion(ctx, inscripcionID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, "", ErrNotFound
+		}
+		return nil, "", ErrDB
+	}
+	if !gestor && (usuarioID <= 0 || row.IDUsuario != usuarioID) {
+		return nil, "", ErrNoAutorizado
+	}
+	return renderCertificado(row, parseFirmas(row.Firmas), enlace+row.Codigo), row.Codigo, nil
+}
+
+// VerificarCertificado looks up a verification code. An unknown code is
+// not an error: the response says it is not valid.
+func (s *Service) VerificarCertificado(ctx context.Context, codigo string) (dto.VerificacionResponse, error) {
+	codigo = NormalizarCodigo(codigo)
+	res := dto.VerificacionResponse{Codigo: codigo}
+	if codigo == "" {
+		return res, nil
+	}
+	row, err := s.repo.FindByCodigo(ctx, codigo)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return res, nil
+		}
+		return dto.VerificacionResponse{}, ErrDB
+	}
+	res.Valido = true
+	res.Participante = row.Participante
+	res.Evento = row.NombreEvento
+	res.Titulo = row.Titulo
+	res.EmitidoEn = row.EmitidoEn.In(domain.Zona(row.ZonaHoraria)).Format(formatoFecha)
+	return res, nil
+}
+
+func (s *Service) evento(ctx context.Context, eventoID int) (repo.EventoRow, error) {
+	evento, err := s.repo.FindEvento(ctx, eventoID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.EventoRow{}, ErrEventoNotFound
+		}
+		return repo.EventoRow{}, ErrDB
+	}
+	return evento, nil
+}
+
+// valoresCertificado fills the placeholders for one attendee. Dates are
+// written in the event's time zone.
+func valoresCertificado(evento repo.EventoRow, e repo.ElegibleRow) map[string]string {
+	loc := domain.Zona(evento.ZonaHoraria)
+	return map[string]string{
+		dto.MarcadorParticipante: e.Participante,
+		dto.MarcadorAfiliacion:   e.Afiliacion,
+		dto.MarcadorEvento:       evento.Nombre,
+		dto.MarcadorFechaInicio:  evento.FechaInicio.In(loc).Format(formatoFecha),
+		dto.MarcadorFechaFin:     evento.FechaFin.In(loc).Format(formatoFecha),
+		dto.MarcadorUbicacion:    evento.Ubicacion,
+	}
+}
+
+// aplicarPlantilla replaces every {marcador} in texto with its value.
+func aplicarPlantilla(texto string, valores map[string]string) string {
+	pares := make([]string, 0, 2*len(valores))
+	for clave, valor := range valores {
+		pares = append(pares, "{"+clave+"}", valor)
+	}
+	return strings.NewReplacer(pares...).Replace(strings.TrimSpace(texto))
+}
+
+// alfabetoCodigo leaves out letters and digits that are easy to confuse
+// when a code is typed from paper.
+const alfabetoCodigo = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// nuevoCodigo returns a random verification code such as 7KQ2-MX9D-H4TP.
+func nuevoCodigo() (string, error) {
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i, v := range raw {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(alfabetoCodigo[int(v)%len(alfabetoCodigo)])
+	}
+	return b.String(), nil
+}
+
+// NormalizarCodigo accepts a code as typed, in any case and with or without
+// separators, and returns it as stored. Anything that cannot be a code
+// comes back empty.
+func NormalizarCodigo(codigo string) string {
+	var limpio []byte
+	for _, r := range strings.ToUpper(codigo) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case strings.ContainsRune(alfabetoCodigo, r):
+			limpio = append(limpio, byte(r))
+		default:
+			return ""
+		}
+	}
+	if len(limpio) != 12 {
+		return ""
+	}
+	return string(limpio[0:4]) + "-" + string(limpio[4:8]) + "-" + string(limpio[8:12])
+}
+
+func parseFirmas(raw string) []dto.Firma {
+	firmas := []dto.Firma{}
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &firmas)
+	}
+	return firmas
+}
+
+func certificadoResponse(row repo.CertificadoRow) dto.CertificadoResponse {
+	return dto.CertificadoResponse{
+		IDCertificado: row.IDCertificado,
+		IDInscripcion: row.IDInscripcion,
+		Codigo:        row.Codigo,
+		Participante:  row.Participante,
+		Titulo:        row.Titulo,
+		Texto:         row.Texto,
+		Firmas:        parseFirmas(row.Firmas),
+		EmitidoEn:     domain.FormatoLocal(row.EmitidoEn, row.ZonaHoraria),
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"project/backend/internal/certificados/dto"
+	"project/backend/internal/certificados/repo"
+)
+
+func TestAplicarPlantilla(t *testing.T) {
+	got := aplicarPlantilla(" {participante} asistió a {evento}; {participante} aprobó. ", map[string]string{
+		dto.MarcadorParticipante: "Ana",
+		dto.MarcadorEvento:       "Congreso {2026}",
+	})
+	if want := "Ana asistió a Congreso {2026}; Ana aprobó."; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestNuevoCodigo(t *testing.T) {
+	vistos := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		codigo, err := nuevoCodigo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(codigo) != 14 || NormalizarCodigo(codigo) != codigo {
+			t.Fatalf("malformed code %q", codigo)
+		}
+		if vistos[codigo] {
+			t.Fatalf("repeated code %q", codigo)
+		}
+		vistos[codigo] = true
+	}
+}
+
+func TestNormalizarCodigo(t *testing.T) {
+	cases := map[string]string{
+		"7KQ2-MX9D-H4TP":   "7KQ2-MX9D-H4TP",
+		" 7kq2 mx9d h4tp ": "7KQ2-MX9D-H4TP",
+		"7KQ2MX9DH4TP":     "7KQ2-MX9D-H4TP",
+		"7KQ2-MX9D":        "",
+		"7KQ2-MX9D-H4T0":   "",
+		"":                 "",
+	}
+	for in, want := range cases {
+		if got := NormalizarCodigo(in); got != want {
+			t.Errorf("NormalizarCodigo(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderCertificado(t *testing.T) {
+	row := repo.CertificadoRow{
+		Codigo:       "7KQ2-MX9D-H4TP",
+		Participante: "María Núñez",
+		Titulo:       "Certificado de asistencia",
+		Texto:        "Se certifica que María Núñez asistió al Congreso (edición 2026).",
+		EmitidoEn:    time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC),
+		ZonaHoraria:  "America/Bogota",
+	}
+	out := renderCertificado(row, []dto.Firma{{Nombre: "Ana Pérez", Cargo: "Coordinadora"}}, "https://example.org/v?codigo=7KQ2-MX9D-H4TP")
+
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatal("missing PDF header")
+	}
+	for _, want := range []string{"/MediaBox [0 0 841.89 595.28]", "(Certificado de asistencia)", "\\(edici", "Ana P\xe9rez", "15/04/2026", "7KQ2-MX9D-H4TP"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"project/backend/internal/certificados/dto"
+)
+
+const maxFirmas = 3
+
+var marcadorPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+func ValidatePlantilla(req dto.PlantillaRequest) error {
+	titulo := strings.TrimSpace(req.Titulo)
+	if utf8.RuneCountInString(titulo) < 3 || utf8.RuneCountInString(titulo) > 120 {
+		return errors.New("El título del certificado debe tener entre 3 y 120 caracteres.")
+	}
+	texto := strings.TrimSpace(req.Texto)
+	if utf8.RuneCountInString(texto) < 10 || utf8.RuneCountInString(texto) > 1500 {
+		return errors.New("El texto del certificado debe tener entre 10 y 1500 caracteres.")
+	}
+	if err := ValidateMarcadores(texto); err != nil {
+		return err
+	}
+	if len(req.Firmas) > maxFirmas {
+		return fmt.Errorf("El certificado admite como máximo %d firmas.", maxFirmas)
+	}
+	for i, f := range req.Firmas {
+		nombre := strings.TrimSpace(f.Nombre)
+		if utf8.RuneCountInString(nombre) < 3 || utf8.RuneCountInString(nombre) > 100 {
+			return fmt.Errorf("Firma %d: el nombre debe tener entre 3 y 100 caracteres.", i+1)
+		}
+		if utf8.RuneCountInString(strings.TrimSpace(f.Cargo)) > 100 {
+			return fmt.Errorf("Firma %d: el cargo no puede superar 100 caracteres.", i+1)
+		}
+	}
+	return nil
+}
+
+// ValidateMarcadores checks that every placeholder in texto is known and
+// that the participant is named.
+func ValidateMarcadores(texto string) error {
+	conocidos := make(map[string]bool, len(dto.Marcadores))
+	for _, m := range dto.Marcadores {
+		conocidos[m] = true
+	}
+	participante := false
+	for _, m := range marcadorPattern.FindAllStringSubmatch(texto, -1) {
+		if !conocidos[m[1]] {
+			return fmt.Errorf("Marcador desconocido {%s}; se admiten: {%s}.", m[1], strings.Join(dto.Marcadores, "}, {"))
+		}
+		participante = participante || m[1] == dto.MarcadorParticipante
+	}
+	if !participante {
+		return errors.New("El texto del certificado debe incluir {participante}.")
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"testing"
+
+	"project/backend/internal/certificados/dto"
+)
+
+func TestValidatePlantilla(t *testing.T) {
+	base := func() dto.PlantillaRequest {
+		return dto.PlantillaRequest{
+			Titulo: "Certificado de asistencia",
+			Texto:  "Se certifica que {participante} asistió a {evento} el {fecha_inicio}.",
+			Firmas: []dto.Firma{{Nombre: "Ana Pérez", Cargo: "Coordinadora"}},
+		}
+	}
+	cases := []struct {
+		name    string
+		mutate  func(*dto.PlantillaRequest)
+		wantErr bool
+	}{
+		{"valida", func(*dto.PlantillaRequest) {}, false},
+		{"sin firmas", func(r *dto.PlantillaRequest) { r.Firmas = nil }, false},
+		{"titulo corto", func(r *dto.PlantillaRequest) { r.Titulo = "ok" }, true},
+		{"texto corto", func(r *dto.PlantillaRequest) { r.Texto = "{evento}" }, true},
+		{"sin participante", func(r *dto.PlantillaRequest) { r.Texto = "Asistencia a {evento} completa." }, true},
+		{"marcador desconocido", func(r *dto.PlantillaRequest) { r.Texto += " {nota}" }, true},
+		{"demasiadas firmas", func(r *dto.PlantillaRequest) {
+			r.Firmas = append(r.Firmas, r.Firmas[0], r.Firmas[0], r.Firmas[0])
+		}, true},
+		{"firma sin nombre", func(r *dto.PlantillaRequest) { r.Firmas[0].Nombre = " " }, true},
+	}
+
+	for _, c := range cases {
+		req := base()
+		c.mutate(&req)
+		err := ValidatePlantilla(req)
+		if c.wantErr && err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
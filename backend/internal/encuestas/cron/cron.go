@@ -0,0 +1,43 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"project/backend/internal/encuestas/repo"
+	"project/backend/internal/encuestas/service"
+	notificationsrepo "project/backend/internal/notifications/repo"
+	notificationsrv "project/backend/internal/notifications/service"
+	"project/backend/prisma/db"
+
+	"github.com/robfig/cron/v3"
+)
+
+func StartEnvioEncuestasScheduler(prismaClient *db.PrismaClient) {
+	encuestaService := service.New(repo.New(prismaClient), notificationsrv.NewNotificationServiceFromClient(prismaClient))
+	jobExecutionRepo := notificationsrepo.NewJobExecutionRepository(prismaClient)
+	jobName := "envio_encuestas"
+
+	runEnvio(encuestaService, jobExecutionRepo, jobName)
+
+	c := cron.New()
+	c.AddFunc("@every 5m", func() {
+		runEnvio(encuestaService, jobExecutionRepo, jobName)
+	})
+	c.Start()
+}
+
+func runEnvio(encuestaService *service.Service, jobExecutionRepo *notificationsrepo.JobExecutionRepository, jobName string) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	enviadas, err := encuestaService.EnviarPendientes(ctx)
+	if err != nil {
+		log.Println("[Encuestas] Error al enviar encuestas:", err)
+		return
+	}
+	if enviadas > 0 {
+		log.Println("[Encuestas] Encuestas enviadas:", enviadas)
+	}
+	_ = jobExecutionRepo.UpsertLastRun(ctx, jobName, now)
+}
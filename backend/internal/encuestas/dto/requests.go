@@ -0,0 +1,48 @@
+package dto
+
+// Question types of a survey.
+const (
+	PreguntaCalificacion = "calificacion"
+	PreguntaUnica        = "opcion_unica"
+	PreguntaMultiple     = "opcion_multiple"
+	PreguntaTexto        = "texto"
+)
+
+// CalificacionMaxima is the top of the rating scale; ratings go from 1 to
+// CalificacionMaxima.
+const CalificacionMaxima = 5
+
+// EncuestaRequest represents the payload to configure the survey of an
+// event. Saving it replaces every question. Anonima keeps responses apart
+// from who gave them.
+type EncuestaRequest struct {
+	Titulo      string            `json:"titulo"`
+	Descripcion string            `json:"descripcion"`
+	Anonima     bool              `json:"anonima"`
+	Preguntas   []PreguntaRequest `json:"preguntas"`
+}
+
+// PreguntaRequest is a question of a survey. Opciones are required by the
+// choice types and ignored by the others.
+type PreguntaRequest struct {
+	Tipo        string   `json:"tipo"`
+	Enunciado   string   `json:"enunciado"`
+	Opciones    []string `json:"opciones"`
+	Obligatoria bool     `json:"obligatoria"`
+}
+
+// RespuestaEncuestaRequest is an attendee's answer to a survey, sent with
+// the token of the link they were notified with.
+type RespuestaEncuestaRequest struct {
+	Token      string              `json:"token"`
+	Respuestas []RespuestaPregunta `json:"respuestas"`
+}
+
+// RespuestaPregunta answers one question: Valor for ratings, Opciones for
+// choices and Texto for free text.
+type RespuestaPregunta struct {
+	IDPregunta int      `json:"id_pregunta"`
+	Valor      *int     `json:"valor"`
+	Opciones   []string `json:"opciones"`
+	Texto      string   `json:"texto"`
+}
@@ -0,0 +1,87 @@
+package dto
+
+// EncuestaResponse is the configuration of a survey. EnviadaEn is empty
+// until the invitations go out; Invitados and Respuestas count them and
+// their answers.
+type EncuestaResponse struct {
+	IDEncuesta  int                `json:"id_encuesta"`
+	IDEvento    int                `json:"id_evento"`
+	Titulo      string             `json:"titulo"`
+	Descripcion string             `json:"descripcion"`
+	Anonima     bool               `json:"anonima"`
+	Activa      bool               `json:"activa"`
+	EnviadaEn   string             `json:"enviada_en,omitempty"`
+	Invitados   int                `json:"invitados"`
+	Respuestas  int                `json:"respuestas"`
+	Preguntas   []PreguntaResponse `json:"preguntas"`
+}
+
+type PreguntaResponse struct {
+	IDPregunta  int      `json:"id_pregunta"`
+	Orden       int      `json:"orden"`
+	Tipo        string   `json:"tipo"`
+	Enunciado   string   `json:"enunciado"`
+	Opciones    []string `json:"opciones"`
+	Obligatoria bool     `json:"obligatoria"`
+}
+
+// FormularioEncuestaResponse is what an attendee sees behind a survey link.
+type FormularioEncuestaResponse struct {
+	Evento         string             `json:"evento"`
+	Titulo         string             `json:"titulo"`
+	Descripcion    string             `json:"descripcion"`
+	Anonima        bool               `json:"anonima"`
+	Respondida     bool               `json:"respondida"`
+	PuedeResponder bool               `json:"puede_responder"`
+	Preguntas      []PreguntaResponse `json:"preguntas"`
+}
+
+// ResultadosEncuestaResponse aggregates the answers of a survey question by
+// question.
+type ResultadosEncuestaResponse struct {
+	IDEncuesta    int                 `json:"id_encuesta"`
+	IDEvento      int                 `json:"id_evento"`
+	Titulo        string              `json:"titulo"`
+	Anonima       bool                `json:"anonima"`
+	Invitados     int                 `json:"invitados"`
+	Respuestas    int                 `json:"respuestas"`
+	TasaRespuesta float64             `json:"tasa_respuesta"`
+	Preguntas     []ResultadoPregunta `json:"preguntas"`
+}
+
+// ResultadoPregunta is the aggregate of one question. Ratings fill Promedio
+// and Opciones with one entry per score; choices fill Opciones; free text
+// fills Textos.
+type ResultadoPregunta struct {
+	IDPregunta int            `json:"id_pregunta"`
+	Tipo       string         `json:"tipo"`
+	Enunciado  string         `json:"enunciado"`
+	Respuestas int            `json:"respuestas"`
+	Promedio   *float64       `json:"promedio,omitempty"`
+	Opciones   []ConteoOpcion `json:"opciones,omitempty"`
+	Textos     []string       `json:"textos,omitempty"`
+}
+
+type ConteoOpcion struct {
+	Opcion string `json:"opcion"`
+	Total  int    `json:"total"`
+}
+
+// ExportacionEncuesta holds every response of a survey, in the order they
+// arrived, for the CSV export.
+type ExportacionEncuesta struct {
+	Anonima    bool
+	Preguntas  []PreguntaResponse
+	Respuestas []RespuestaExportada
+}
+
+// RespuestaExportada is one response. Valores has the answer of each
+// question by id, as text; Participante and Email are empty for anonymous
+// surveys.
+type RespuestaExportada struct {
+	IDRespuesta  int
+	Fecha        string
+	Participante string
+	Email        string
+	Valores      map[int]string
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project/backend/internal/encuestas/dto"
+	"project/backend/internal/encuestas/repo"
+	"project/backend/internal/encuestas/service"
+	"project/backend/internal/encuestas/validation"
+	notificationsrv "project/backend/internal/notifications/service"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+	"project/backend/internal/shared/publicurl"
+	"project/backend/prisma/db"
+)
+
+// RespuestaEncuestaPath is where the links sent with a survey point to:
+// RespuestaEncuestaPath + "?token=" + secret.
+const RespuestaEncuestaPath = "/api/encuestas/respuesta"
+
+type Handler struct {
+	svc         *service.Service
+	roleService roles.UserRoleService
+}
+
+func New(client *db.PrismaClient) *Handler {
+	return &Handler{
+		svc:         service.New(repo.New(client), notificationsrv.NewNotificationServiceFromClient(client)),
+		roleService: roles.NewUserRoleService(client),
+	}
+}
+
+// ServeHTTP serves /api/encuestas?id_evento=N for organizers. GET returns
+// the survey of the event, PUT creates or replaces it until it is sent, and
+// PATCH opens or closes it with {"activa": bool}.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodPut, http.MethodPatch:
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getEncuesta(w, r, eventoID)
+	case http.MethodPut:
+		h.guardarEncuesta(w, r, eventoID)
+	case http.MethodPatch:
+		h.setActiva(w, r, eventoID)
+	}
+}
+
+func (h *Handler) getEncuesta(w http.ResponseWriter, r *http.Request, eventoID int) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	encuesta, err := h.svc.GetEncuesta(ctx, eventoID)
+	if writeEncuestaError(w, err) {
+		return
+	}
+	writeJSON(w, encuesta)
+}
+
+func (h *Handler) guardarEncuesta(w http.ResponseWriter, r *http.Request, eventoID int) {
+	var req dto.EncuestaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateEncuesta(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	enlace := publicurl.Base() + RespuestaEncuestaPath + "?token="
+	encuesta, err := h.svc.GuardarEncuesta(ctx, eventoID, req, enlace)
+	if writeEncuestaError(w, err) {
+		return
+	}
+	writeJSON(w, encuesta)
+}
+
+func (h *Handler) setActiva(w http.ResponseWriter, r *http.Request, eventoID int) {
+	var req struct {
+		Activa *bool `json:"activa"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Activa == nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "activa es requerido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	encuesta, err := h.svc.SetActiva(ctx, eventoID, *req.Activa)
+	if writeEncuestaError(w, err) {
+		return
+	}
+	writeJSON(w, encuesta)
+}
+
+// ResultadosHandler serves GET /api/encuestas/resultados?id_evento=N for
+// organizers: the answers aggregated by question, or every response as a
+// CSV file with &formato=csv.
+func (h *Handler) ResultadosHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if r.URL.Query().Get("formato") != "csv" {
+		res, err := h.svc.ResultadosEncuesta(ctx, eventoID)
+		if writeEncuestaError(w, err) {
+			return
+		}
+		writeJSON(w, res)
+		return
+	}
+
+	exportacion, err := h.svc.ExportarRespuestas(ctx, eventoID)
+	if writeEncuestaError(w, err) {
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"encuesta-evento-%d.csv\"", eventoID))
+	_ = escribirCSV(w, exportacion)
+}
+
+// RespuestaHandler serves the link attendees get when a survey is sent. The
+// token is the only credential: GET ?token=T shows the survey and whether
+// it was answered, POST {token, respuestas} answers it once.
+func (h *Handler) RespuestaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		res, err := h.svc.ConsultarEncuesta(ctx, r.URL.Query().Get("token"))
+		if writeEncuestaError(w, err) {
+			return
+		}
+		writeJSON(w, res)
+	case http.MethodPost:
+		var req dto.RespuestaEncuestaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+			return
+		}
+		if writeEncuestaError(w, h.svc.ResponderEncuesta(ctx, req)) {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// escribirCSV writes one row per response and one column per question.
+// Identified surveys add the participant's name and email.
+func escribirCSV(w http.ResponseWriter, e dto.ExportacionEncuesta) error {
+	out := csv.NewWriter(w)
+	cabecera := []string{"id_respuesta", "fecha"}
+	if !e.Anonima {
+		cabecera = append(cabecera, "participante", "email")
+	}
+	for _, p := range e.Preguntas {
+		cabecera = append(cabecera, p.Enunciado)
+	}
+	if err := out.Write(cabecera); err != nil {
+		return err
+	}
+	for _, res := range e.Respuestas {
+		fila := []string{strconv.Itoa(res.IDRespuesta), res.Fecha}
+		if !e.Anonima {
+			fila = append(fila, res.Participante, res.Email)
+		}
+		for _, p := range e.Preguntas {
+			fila = append(fila, res.Valores[p.IDPregunta])
+		}
+		if err := out.Write(fila); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// msgSinPermisos answers callers without events.management.
+const msgSinPermisos = "no tienes permisos para gestionar encuestas"
+
+func writeEncuestaError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrEventoNotFound), errors.Is(err, service.ErrEnlaceNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrRespuestaInvalida):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrEventoCancelado), errors.Is(err, service.ErrEnviada),
+		errors.Is(err, service.ErrCerrada), errors.Is(err, service.ErrRespondida):
+		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,370 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/prisma/db"
+)
+
+// estadoInvitado is the inscription status that gets the survey: only
+// attendees whose inscription was approved are asked.
+const estadoInvitado = "Aprobado"
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+// EventoRow is the event a survey belongs to.
+type EventoRow struct {
+	IDEvento int    `json:"id_evento"`
+	Nombre   string `json:"nombre"`
+	Estado   string `json:"estado"`
+}
+
+// EncuestaRow is the survey of an event, with the time zone of the event,
+// how many attendees were invited and how many answered.
+type EncuestaRow struct {
+	IDEncuesta  int        `json:"id_encuesta"`
+	IDEvento    int        `json:"id_evento"`
+	Titulo      string     `json:"titulo"`
+	Descripcion string     `json:"descripcion"`
+	Anonima     bool       `json:"anonima"`
+	Activa      bool       `json:"activa"`
+	EnviadaEn   *time.Time `json:"enviada_en"`
+	ZonaHoraria string     `json:"zona_horaria"`
+	Invitados   int        `json:"invitados"`
+	Respuestas  int        `json:"respuestas"`
+}
+
+type PreguntaRow struct {
+	IDPregunta  int      `json:"id_pregunta"`
+	Orden       int      `json:"orden"`
+	Tipo        string   `json:"tipo"`
+	Enunciado   string   `json:"enunciado"`
+	Opciones    []string `json:"opciones"`
+	Obligatoria bool     `json:"obligatoria"`
+}
+
+// Encuesta holds what is written when the survey of an event is saved.
+// Enlace is the prefix of the links sent with the invitations, to which
+// the token is appended.
+type Encuesta struct {
+	Titulo      string
+	Descripcion string
+	Anonima     bool
+	Enlace      string
+	Preguntas   []NuevaPregunta
+}
+
+type NuevaPregunta struct {
+	Orden       int      `json:"orden"`
+	Tipo        string   `json:"tipo"`
+	Enunciado   string   `json:"enunciado"`
+	Opciones    []string `json:"opciones"`
+	Obligatoria bool     `json:"obligatoria"`
+}
+
+// PorEnviarRow is an active survey of a finished event whose invitations
+// have not gone out yet.
+type PorEnviarRow struct {
+	IDEncuesta int    `json:"id_encuesta"`
+	IDEvento   int    `json:"id_evento"`
+	Evento     string `json:"evento"`
+	Enlace     string `json:"enlace"`
+}
+
+// InvitadoRow is an attendee that was sent a survey.
+type InvitadoRow struct {
+	IDInscripcion int `json:"id_inscripcion"`
+	IDUsuario     int `json:"id_usuario"`
+}
+
+// InvitacionRow is the survey behind a link token.
+type InvitacionRow struct {
+	IDInvitacion  int    `json:"id_invitacion"`
+	IDEncuesta    int    `json:"id_encuesta"`
+	IDInscripcion int    `json:"id_inscripcion"`
+	Respondida    bool   `json:"respondida"`
+	Evento        string `json:"evento"`
+	Titulo        string `json:"titulo"`
+	Descripcion   string `json:"descripcion"`
+	Anonima       bool   `json:"anonima"`
+	Activa        bool   `json:"activa"`
+}
+
+// NuevoItem is the answer to one question.
+type NuevoItem struct {
+	IDPregunta int      `json:"id_pregunta"`
+	Valor      *int     `json:"valor"`
+	Opciones   []string `json:"opciones"`
+	Texto      string   `json:"texto"`
+}
+
+// RespuestaItemRow is one answered question of a response, or a response
+// with no answered question when IDPregunta is nil. Participante and Email
+// are nil for anonymous responses.
+type RespuestaItemRow struct {
+	IDRespuesta  int       `json:"id_respuesta"`
+	Fecha        time.Time `json:"fecha"`
+	Participante *string   `json:"participante"`
+	Email        *string   `json:"email"`
+	IDPregunta   *int      `json:"id_pregunta"`
+	Valor        *int      `json:"valor"`
+	Opciones     []string  `json:"opciones"`
+	Texto        *string   `json:"texto"`
+}
+
+// FindEvento returns the event, or db.ErrNotFound.
+func (r *Repository) FindEvento(ctx context.Context, id int) (EventoRow, error) {
+	var rows []EventoRow
+	query := `SELECT "id_evento", "nombre", "estado" FROM "Evento" WHERE "id_evento" = $1::int`
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return EventoRow{}, err
+	}
+	if len(rows) == 0 {
+		return EventoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// FindByEvento returns the survey of an event, or db.ErrNotFound.
+func (r *Repository) FindByEvento(ctx context.Context, eventoID int) (EncuestaRow, error) {
+	query := `SELECT e."id_encuesta", e."id_evento", e."titulo", e."descripcion", e."anonima", e."activa", e."enviada_en",
+			ev."zona_horaria",
+			(SELECT COUNT(*) FROM "EncuestaInvitacion" i WHERE i."id_encuesta" = e."id_encuesta")::int AS "invitados",
+			(SELECT COUNT(*) FROM "EncuestaRespuesta" r WHERE r."id_encuesta" = e."id_encuesta")::int AS "respuestas"
+		FROM "Encuesta" e
+		JOIN "Evento" ev ON ev."id_evento" = e."id_evento"
+		WHERE e."id_evento" = $1::int`
+	var rows []EncuestaRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return EncuestaRow{}, err
+	}
+	if len(rows) == 0 {
+		return EncuestaRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+func (r *Repository) ListPreguntas(ctx context.Context, encuestaID int) ([]PreguntaRow, error) {
+	query := `SELECT "id_pregunta", "orden", "tipo", "enunciado", "opciones", "obligatoria"
+		FROM "EncuestaPregunta"
+		WHERE "id_encuesta" = $1::int
+		ORDER BY "orden", "id_pregunta"`
+	var rows []PreguntaRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, encuestaID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Guardar creates the survey of an event or replaces its configuration and
+// questions, in a single statement. A survey whose invitations were already
+// sent is left untouched and ok is false.
+func (r *Repository) Guardar(ctx context.Context, eventoID int, e Encuesta) (bool, error) {
+	preguntas := e.Preguntas
+	if preguntas == nil {
+		preguntas = []NuevaPregunta{}
+	}
+	for i := range preguntas {
+		if preguntas[i].Opciones == nil {
+			preguntas[i].Opciones = []string{}
+		}
+	}
+	payload, err := json.Marshal(preguntas)
+	if err != nil {
+		return false, err
+	}
+	query := `WITH "encuesta" AS (
+			INSERT INTO "Encuesta" ("id_evento", "titulo", "descripcion", "anonima", "enlace", "createdAt", "updatedAt")
+			VALUES ($1::int, $2::text, $3::text, $4::boolean, $5::text, NOW(), NOW())
+			ON CONFLICT ("id_evento") DO UPDATE SET "titulo" = EXCLUDED."titulo", "descripcion" = EXCLUDED."descripcion",
+				"anonima" = EXCLUDED."anonima", "enlace" = EXCLUDED."enlace", "updatedAt" = NOW()
+			WHERE "Encuesta"."enviada_en" IS NULL
+			RETURNING "id_encuesta"
+		), "borradas" AS (
+			DELETE FROM "EncuestaPregunta" WHERE "id_encuesta" IN (SELECT "id_encuesta" FROM "encuesta")
+		), "nuevas" AS (
+			INSERT INTO "EncuestaPregunta" ("id_encuesta", "orden", "tipo", "enunciado", "opciones", "obligatoria")
+			SELECT e."id_encuesta", p."orden", p."tipo", p."enunciado", p."opciones", p."obligatoria"
+			FROM "encuesta" e
+			CROSS JOIN jsonb_to_recordset($6::jsonb) AS p("orden" int, "tipo" text, "enunciado" text, "opciones" text[], "obligatoria" boolean)
+		)
+		SELECT "id_encuesta" FROM "encuesta"`
+	var rows []struct {
+		IDEncuesta int `json:"id_encuesta"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID, strings.TrimSpace(e.Titulo), strings.TrimSpace(e.Descripcion),
+		e.Anonima, e.Enlace, string(payload)).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// SetActiva opens or closes the survey of an event. A closed survey is not
+// sent and takes no more answers. ok is false when the event has no survey.
+func (r *Repository) SetActiva(ctx context.Context, eventoID int, activa bool) (bool, error) {
+	query := `UPDATE "Encuesta" SET "activa" = $2::boolean, "updatedAt" = NOW() WHERE "id_evento" = $1::int`
+	count, err := r.client.Prisma.Raw.ExecuteRaw(query, eventoID, activa).Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	return count.Count > 0, nil
+}
+
+// FindPorEnviar lists the active surveys with questions whose event has
+// finished and that were not sent yet.
+func (r *Repository) FindPorEnviar(ctx context.Context) ([]PorEnviarRow, error) {
+	query := `SELECT e."id_encuesta", e."id_evento", ev."nombre" AS "evento", e."enlace"
+		FROM "Encuesta" e
+		JOIN "Evento" ev ON ev."id_evento" = e."id_evento"
+		WHERE e."activa" AND e."enviada_en" IS NULL AND ev."estado" = '` + domain.EstadoFinalizado + `'
+			AND EXISTS (SELECT 1 FROM "EncuestaPregunta" p WHERE p."id_encuesta" = e."id_encuesta")
+		ORDER BY e."id_encuesta"`
+	var rows []PorEnviarRow
+	if err := r.client.Prisma.Raw.QueryRaw(query).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListInvitables returns the approved inscriptions of an event.
+func (r *Repository) ListInvitables(ctx context.Context, eventoID int) ([]int, error) {
+	query := `SELECT "id_inscripcion" FROM "Inscripcion"
+		WHERE "id_evento" = $1::int AND "estado" = '` + estadoInvitado + `'
+		ORDER BY "id_inscripcion"`
+	var rows []struct {
+		IDInscripcion int `json:"id_inscripcion"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.IDInscripcion)
+	}
+	return ids, nil
+}
+
+// Enviar marks a survey as sent and stores one invitation per inscription
+// with the hash of its token, in a single statement. Inscriptions that are
+// no longer approved are skipped. A survey already sent is left untouched,
+// so two runs never invite twice. It returns who was invited.
+func (r *Repository) Enviar(ctx context.Context, encuestaID int, inscripciones []int, hashes []string) ([]InvitadoRow, error) {
+	if inscripciones == nil {
+		inscripciones = []int{}
+	}
+	if hashes == nil {
+		hashes = []string{}
+	}
+	query := `WITH "enviada" AS (
+			UPDATE "Encuesta" SET "enviada_en" = NOW(), "updatedAt" = NOW()
+			WHERE "id_encuesta" = $1::int AND "enviada_en" IS NULL
+			RETURNING "id_encuesta", "id_evento"
+		), "invitados" AS (
+			INSERT INTO "EncuestaInvitacion" ("id_encuesta", "id_inscripcion", "token_hash")
+			SELECT e."id_encuesta", t."id_inscripcion", t."token_hash"
+			FROM "enviada" e
+			CROSS JOIN unnest($2::int[], $3::text[]) AS t("id_inscripcion", "token_hash")
+			JOIN "Inscripcion" i ON i."id_inscripcion" = t."id_inscripcion"
+			WHERE i."id_evento" = e."id_evento" AND i."estado" = '` + estadoInvitado + `'
+			RETURNING "id_inscripcion"
+		)
+		SELECT i."id_inscripcion", i."id_usuario"
+		FROM "invitados" inv
+		JOIN "Inscripcion" i ON i."id_inscripcion" = inv."id_inscripcion"
+		ORDER BY i."id_inscripcion"`
+	var rows []InvitadoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, encuestaID, inscripciones, hashes).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindInvitacion returns the invitation with the given token hash, or
+// db.ErrNotFound.
+func (r *Repository) FindInvitacion(ctx context.Context, tokenHash string) (InvitacionRow, error) {
+	query := `SELECT inv."id_invitacion", inv."id_encuesta", inv."id_inscripcion", inv."respondida",
+			ev."nombre" AS "evento", e."titulo", e."descripcion", e."anonima", e."activa"
+		FROM "EncuestaInvitacion" inv
+		JOIN "Encuesta" e ON e."id_encuesta" = inv."id_encuesta"
+		JOIN "Evento" ev ON ev."id_evento" = e."id_evento"
+		WHERE inv."token_hash" = $1::text`
+	var rows []InvitacionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, tokenHash).Exec(ctx, &rows); err != nil {
+		return InvitacionRow{}, err
+	}
+	if len(rows) == 0 {
+		return InvitacionRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// Responder stores the answer to an invitation in a single statement. An
+// anonymous answer is not linked to the inscription and its date is kept
+// to the day, so it cannot be matched to the invitation it came from. ok
+// is false when the invitation was already answered.
+func (r *Repository) Responder(ctx context.Context, invitacionID int, anonima bool, items []NuevoItem) (bool, error) {
+	if items == nil {
+		items = []NuevoItem{}
+	}
+	for i := range items {
+		if items[i].Opciones == nil {
+			items[i].Opciones = []string{}
+		}
+	}
+	payload, err := json.Marshal(items)
+	if err != nil {
+		return false, err
+	}
+	query := `WITH "invitacion" AS (
+			UPDATE "EncuestaInvitacion" SET "respondida" = true
+			WHERE "id_invitacion" = $1::int AND NOT "respondida"
+			RETURNING "id_encuesta", "id_inscripcion"
+		), "respuesta" AS (
+			INSERT INTO "EncuestaRespuesta" ("id_encuesta", "id_inscripcion", "fecha")
+			SELECT i."id_encuesta",
+				CASE WHEN $2::boolean THEN NULL ELSE i."id_inscripcion" END,
+				CASE WHEN $2::boolean THEN date_trunc('day', NOW()) ELSE NOW() END
+			FROM "invitacion" i
+			RETURNING "id_respuesta"
+		), "items" AS (
+			INSERT INTO "EncuestaRespuestaItem" ("id_respuesta", "id_pregunta", "valor", "opciones", "texto")
+			SELECT r."id_respuesta", f."id_pregunta", f."valor", f."opciones", NULLIF(btrim(f."texto"), '')
+			FROM "respuesta" r
+			CROSS JOIN jsonb_to_recordset($3::jsonb) AS f("id_pregunta" int, "valor" int, "opciones" text[], "texto" text)
+		)
+		SELECT "id_respuesta" FROM "respuesta"`
+	var rows []struct {
+		IDRespuesta int `json:"id_respuesta"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, invitacionID, anonima, string(payload)).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// ListRespuestas returns every answered question of a survey, response by
+// response in the order they arrived.
+func (r *Repository) ListRespuestas(ctx context.Context, encuestaID int) ([]RespuestaItemRow, error) {
+	query := `SELECT r."id_respuesta", r."fecha", i."nombre_participante" AS "participante", i."email",
+			it."id_pregunta", it."valor", COALESCE(it."opciones", ARRAY[]::text[]) AS "opciones", it."texto"
+		FROM "EncuestaRespuesta" r
+		LEFT JOIN "Inscripcion" i ON i."id_inscripcion" = r."id_inscripcion"
+		LEFT JOIN "EncuestaRespuestaItem" it ON it."id_respuesta" = r."id_respuesta"
+		WHERE r."id_encuesta" = $1::int
+		ORDER BY r."id_respuesta", it."id_item"`
+	var rows []RespuestaItemRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, encuestaID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"project/backend/internal/encuestas/dto"
+	"project/backend/internal/encuestas/repo"
+	"project/backend/internal/encuestas/validation"
+	"project/backend/internal/events/domain"
+	notificationdto "project/backend/internal/notifications/dto"
+	notificationsrv "project/backend/internal/notifications/service"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrNotFound          = errors.New("el evento no tiene encuesta")
+	ErrEventoNotFound    = errors.New("evento no encontrado")
+	ErrEventoCancelado   = errors.New("el evento fue cancelado")
+	ErrEnviada           = errors.New("la encuesta ya fue enviada y no se puede modificar")
+	ErrEnlaceNotFound    = errors.New("enlace de encuesta no encontrado")
+	ErrCerrada           = errors.New("la encuesta está cerrada")
+	ErrRespondida        = errors.New("la encuesta ya fue respondida")
+	ErrRespuestaInvalida = errors.New("respuesta inválida")
+	ErrDB                = errors.New("db error")
+)
+
+type Service struct {
+	repo                *repo.Repository
+	notificationService notificationsrv.NotificationService
+}
+
+func New(r *repo.Repository, notificationService notificationsrv.NotificationService) *Service {
+	return &Service{repo: r, notificationService: notificationService}
+}
+
+func (s *Service) GetEncuesta(ctx context.Context, eventoID int) (dto.EncuestaResponse, error) {
+	encuesta, err := s.encuesta(ctx, eventoID)
+	if err != nil {
+		return dto.EncuestaResponse{}, err
+	}
+	preguntas, err := s.repo.ListPreguntas(ctx, encuesta.IDEncuesta)
+	if err != nil {
+		return dto.EncuestaResponse{}, ErrDB
+	}
+	return encuestaResponse(encuesta, preguntas), nil
+}
+
+// GuardarEncuesta creates the survey of an event or replaces it, questions
+// included, as long as it was not sent yet. enlace is the prefix of the
+// links the invitations will carry.
+func (s *Service) GuardarEncuesta(ctx context.Context, eventoID int, req dto.EncuestaRequest, enlace string) (dto.EncuestaResponse, error) {
+	evento, err := s.repo.FindEvento(ctx, eventoID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.EncuestaResponse{}, ErrEventoNotFound
+		}
+		return dto.EncuestaResponse{}, ErrDB
+	}
+	if evento.Estado == domain.EstadoCancelado {
+		return dto.EncuestaResponse{}, ErrEventoCancelado
+	}
+
+	nueva := repo.Encuesta{
+		Titulo:      req.Titulo,
+		Descripcion: req.Descripcion,
+		Anonima:     req.Anonima,
+		Enlace:      enlace,
+		Preguntas:   make([]repo.NuevaPregunta, 0, len(req.Preguntas)),
+	}
+	for i, p := range req.Preguntas {
+		pregunta := repo.NuevaPregunta{
+			Orden:       i + 1,
+			Tipo:        p.Tipo,
+			Enunciado:   strings.TrimSpace(p.Enunciado),
+			Obligatoria: p.Obligatoria,
+		}
+		if p.Tipo == dto.PreguntaUnica || p.Tipo == dto.PreguntaMultiple {
+			for _, opcion := range p.Opciones {
+				pregunta.Opciones = append(pregunta.Opciones, strings.TrimSpace(opcion))
+			}
+		}
+		nueva.Preguntas = append(nueva.Preguntas, pregunta)
+	}
+	ok, err := s.repo.Guardar(ctx, eventoID, nueva)
+	if err != nil {
+		return dto.EncuestaResponse{}, ErrDB
+	}
+	if !ok {
+		return dto.EncuestaResponse{}, ErrEnviada
+	}
+	return s.GetEncuesta(ctx, eventoID)
+}
+
+// SetActiva opens or closes the survey of an event. A closed survey is not
+// sent when the event finishes and takes no more answers.
+func (s *Service) SetActiva(ctx context.Context, eventoID int, activa bool) (dto.EncuestaResponse, error) {
+	ok, err := s.repo.SetActiva(ctx, eventoID, activa)
+	if err != nil {
+		return dto.EncuestaResponse{}, ErrDB
+	}
+	if !ok {
+		return dto.EncuestaResponse{}, ErrNotFound
+	}
+	return s.GetEncuesta(ctx, eventoID)
+}
+
+// EnviarPendientes sends the surveys of the events that finished: every
+// attendee with an approved inscription is notified with a personal link.
+// Each survey is sent once. It returns how many surveys were sent.
+func (s *Service) EnviarPendientes(ctx context.Context) (int, error) {
+	encuestas, err := s.repo.FindPorEnviar(ctx)
+	if err != nil {
+		return 0, ErrDB
+	}
+	enviadas := 0
+	for _, e := range encuestas {
+		inscripciones, err := s.repo.ListInvitables(ctx, e.IDEvento)
+		if err != nil {
+			return enviadas, ErrDB
+		}
+		tokens := make(map[int]string, len(inscripciones))
+		hashes := make([]string, 0, len(inscripciones))
+		for _, id := range inscripciones {
+			token, err := nuevoToken()
+			if err != nil {
+				return enviadas, ErrDB
+			}
+			tokens[id] = token
+			hashes = append(hashes, hashToken(token))
+		}
+		invitados, err := s.repo.Enviar(ctx, e.IDEncuesta, inscripciones, hashes)
+		if err != nil {
+			return enviadas, ErrDB
+		}
+		enviadas++
+
+		eventoID := e.IDEvento
+		for _, inv := range invitados {
+			_, notifErr := s.notificationService.CreateNotification(ctx, notificationdto.CreateNotificationRequest{
+				UserID:  inv.IDUsuario,
+				EventID: &eventoID,
+				Type:    notificationdto.NotificationTypeEncuestaEvento,
+				Message: fmt.Sprintf(notificationdto.MsgEncuestaEvento, e.Evento, e.Enlace+tokens[inv.IDInscripcion]),
+			})
+			if notifErr != nil {
+				fmt.Println("[Encuestas] Error notificando al usuario", inv.IDUsuario, ":", notifErr)
+			}
+		}
+	}
+	return enviadas, nil
+}
+
+// ConsultarEncuesta returns the survey behind a link token.
+func (s *Service) ConsultarEncuesta(ctx context.Context, token string) (dto.FormularioEncuestaResponse, error) {
+	inv, err := s.invitacion(ctx, token)
+	if err != nil {
+		return dto.FormularioEncuestaResponse{}, err
+	}
+	preguntas, err := s.repo.ListPreguntas(ctx, inv.IDEncuesta)
+	if err != nil {
+		return dto.FormularioEncuestaResponse{}, ErrDB
+	}
+	return dto.FormularioEncuestaResponse{
+		Evento:         inv.Evento,
+		Titulo:         inv.Titulo,
+		Descripcion:    inv.Descripcion,
+		Anonima:        inv.Anonima,
+		Respondida:     inv.Respondida,
+		PuedeResponder: inv.Activa && !inv.Respondida,
+		Preguntas:      preguntasResponse(preguntas),
+	}, nil
+}
+
+// ResponderEncuesta stores the answer sent with a link token. Each link
+// answers once.
+func (s *Service) ResponderEncuesta(ctx context.Context, req dto.RespuestaEncuestaRequest) error {
+	inv, err := s.invitacion(ctx, req.Token)
+	if err != nil {
+		return err
+	}
+	if !inv.Activa {
+		return ErrCerrada
+	}
+	if inv.Respondida {
+		return ErrRespondida
+	}
+	preguntas, err := s.repo.ListPreguntas(ctx, inv.IDEncuesta)
+	if err != nil {
+		return ErrDB
+	}
+	if err := validation.ValidateRespuestas(preguntasResponse(preguntas), req.Respuestas); err != nil {
+		return fmt.Errorf("%w: %s", ErrRespuestaInvalida, err.Error())
+	}
+
+	tipos := make(map[int]string, len(preguntas))
+	for _, p := range preguntas {
+		tipos[p.IDPregunta] = p.Tipo
+	}
+	items := make([]repo.NuevoItem, 0, len(req.Respuestas))
+	for _, r := range req.Respuestas {
+		if item, ok := nuevoItem(tipos[r.IDPregunta], r); ok {
+			items = append(items, item)
+		}
+	}
+	ok, err := s.repo.Responder(ctx, inv.IDInvitacion, inv.Anonima, items)
+	if err != nil {
+		return ErrDB
+	}
+	if !ok {
+		return ErrRespondida
+	}
+	return nil
+}
+
+// ResultadosEncuesta aggregates the answers of the survey of an event.
+func (s *Service) ResultadosEncuesta(ctx context.Context, eventoID int) (dto.ResultadosEncuestaResponse, error) {
+	encuesta, err := s.encuesta(ctx, eventoID)
+	if err != nil {
+		return dto.ResultadosEncuestaResponse{}, err
+	}
+	preguntas, err := s.repo.ListPreguntas(ctx, encuesta.IDEncuesta)
+	if err != nil {
+		return dto.ResultadosEncuestaResponse{}, ErrDB
+	}
+	items, err := s.repo.ListRespuestas(ctx, encuesta.IDEncuesta)
+	if err != nil {
+		return dto.ResultadosEncuestaResponse{}, ErrDB
+	}
+	res := dto.ResultadosEncuestaResponse{
+		IDEncuesta: encuesta.IDEncuesta,
+		IDEvento:   encuesta.IDEvento,
+		Titulo:     encuesta.Titulo,
+		Anonima:    encuesta.Anonima,
+		Invitados:  encuesta.Invitados,
+		Respuestas: encuesta.Respuestas,
+		Preguntas:  agregar(preguntas, items),
+	}
+	if encuesta.Invitados > 0 {
+		res.TasaRespuesta = math.Round(float64(encuesta.Respuestas)*1000/float64(encuesta.Invitados)) / 10
+	}
+	return res, nil
+}
+
+// ExportarRespuestas returns every response of the survey of an event, one
+// value per question. Dates are in the event's time zone.
+func (s *Service) ExportarRespuestas(ctx context.Context, eventoID int) (dto.ExportacionEncuesta, error) {
+	encuesta, err := s.encuesta(ctx, eventoID)
+	if err != nil {
+		return dto.ExportacionEncuesta{}, err
+	}
+	preguntas, err := s.repo.ListPreguntas(ctx, encuesta.IDEncuesta)
+	if err != nil {
+		return dto.ExportacionEncuesta{}, ErrDB
+	}
+	items, err := s.repo.ListRespuestas(ctx, encuesta.IDEncuesta)
+	if err != nil {
+		return dto.ExportacionEncuesta{}, ErrDB
+	}
+
+	res := dto.ExportacionEncuesta{Anonima: encuesta.Anonima, Preguntas: preguntasResponse(preguntas)}
+	for _, item := range items {
+		n := len(res.Respuestas)
+		if n == 0 || res.Respuestas[n-1].IDRespuesta != item.IDRespuesta {
+			respuesta := dto.RespuestaExportada{
+				IDRespuesta: item.IDRespuesta,
+				Fecha:       domain.FormatoLocal(item.Fecha, encuesta.ZonaHoraria),
+				Valores:     map[int]string{},
+			}
+			if item.Participante != nil {
+				respuesta.Participante = *item.Participante
+			}
+			if item.Email != nil {
+				respuesta.Email = *item.Email
+			}
+			res.Respuestas = append(res.Respuestas, respuesta)
+			n++
+		}
+		if item.IDPregunta != nil {
+			res.Respuestas[n-1].Valores[*item.IDPregunta] = valorTexto(item)
+		}
+	}
+	return res, nil
+}
+
+func (s *Service) encuesta(ctx context.Context, eventoID int) (repo.EncuestaRow, error) {
+	encuesta, err := s.repo.FindByEvento(ctx, eventoID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.EncuestaRow{}, ErrNotFound
+		}
+		return repo.EncuestaRow{}, ErrDB
+	}
+	return encuesta, nil
+}
+
+func (s *Service) invitacion(ctx context.Context, token string) (repo.InvitacionRow, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return repo.InvitacionRow{}, ErrEnlaceNotFound
+	}
+	inv, err := s.repo.FindInvitacion(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.InvitacionRow{}, ErrEnlaceNotFound
+		}
+		return repo.InvitacionRow{}, ErrDB
+	}
+	return inv, nil
+}
+
+// agregar sums up the answers of each question: ratings get their average
+// and a count per score, choices a count per option in the order they were
+// configured, and free text every answer in the order it arrived.
+func agregar(preguntas []repo.PreguntaRow, items []repo.RespuestaItemRow) []dto.ResultadoPregunta {
+	res := make([]dto.ResultadoPregunta, 0, len(preguntas))
+	indice := make(map[int]int, len(preguntas))
+	conteos := make([]map[string]int, len(preguntas))
+	sumas := make([]int, len(preguntas))
+	for i, p := range preguntas {
+		indice[p.IDPregunta] = i
+		conteos[i] = map[string]int{}
+		res = append(res, dto.ResultadoPregunta{IDPregunta: p.IDPregunta, Tipo: p.Tipo, Enunciado: p.Enunciado})
+	}
+
+	for _, item := range items {
+		if item.IDPregunta == nil {
+			continue
+		}
+		i, ok := indice[*item.IDPregunta]
+		if !ok {
+			continue
+		}
+		res[i].Respuestas++
+		switch {
+		case item.Valor != nil:
+			sumas[i] += *item.Valor
+			conteos[i][strconv.Itoa(*item.Valor)]++
+		case item.Texto != nil:
+			res[i].Textos = append(res[i].Textos, *item.Texto)
+		default:
+			for _, opcion := range item.Opciones {
+				conteos[i][opcion]++
+			}
+		}
+	}
+
+	for i, p := range preguntas {
+		switch p.Tipo {
+		case dto.PreguntaCalificacion:
+			for v := 1; v <= dto.CalificacionMaxima; v++ {
+				opcion := strconv.Itoa(v)
+				res[i].Opciones = append(res[i].Opciones, dto.ConteoOpcion{Opcion: opcion, Total: conteos[i][opcion]})
+			}
+			if res[i].Respuestas > 0 {
+				promedio := float64(sumas[i]) / float64(res[i].Respuestas)
+				res[i].Promedio = &promedio
+			}
+		case dto.PreguntaUnica, dto.PreguntaMultiple:
+			for _, opcion := range p.Opciones {
+				res[i].Opciones = append(res[i].Opciones, dto.ConteoOpcion{Opcion: opcion, Total: conteos[i][opcion]})
+			}
+		}
+	}
+	return res
+}
+
+// nuevoItem keeps the part of an answer that fits the type of its question.
+// ok is false when the question was left blank.
+func nuevoItem(tipo string, r dto.RespuestaPregunta) (repo.NuevoItem, bool) {
+	item := repo.NuevoItem{IDPregunta: r.IDPregunta}
+	switch tipo {
+	case dto.PreguntaCalificacion:
+		item.Valor = r.Valor
+		return item, r.Valor != nil
+	case dto.PreguntaTexto:
+		item.Texto = strings.TrimSpace(r.Texto)
+		return item, item.Texto != ""
+	default:
+		item.Opciones = r.Opciones
+		return item, len(r.Opciones) > 0
+	}
+}
+
+func valorTexto(item repo.RespuestaItemRow) string {
+	switch {
+	case item.Valor != nil:
+		return strconv.Itoa(*item.Valor)
+	case item.Texto != nil:
+		return *item.Texto
+	default:
+		return strings.Join(item.Opciones, " | ")
+	}
+}
+
+func encuestaResponse(e repo.EncuestaRow, preguntas []repo.PreguntaRow) dto.EncuestaResponse {
+	res := dto.EncuestaResponse{
+		IDEncuesta:  e.IDEncuesta,
+		IDEvento:    e.IDEvento,
+		Titulo:      e.Titulo,
+		Descripcion: e.Descripcion,
+		Anonima:     e.Anonima,
+		Activa:      e.Activa,
+		Invitados:   e.Invitados,
+		Respuestas:  e.Respuestas,
+		Preguntas:   preguntasResponse(preguntas),
+	}
+	if e.EnviadaEn != nil {
+		res.EnviadaEn = domain.FormatoLocal(*e.EnviadaEn, e.ZonaHoraria)
+	}
+	return res
+}
+
+func preguntasResponse(rows []repo.PreguntaRow) []dto.PreguntaResponse {
+	res := make([]dto.PreguntaResponse, 0, len(rows))
+	for _, p := range rows {
+		opciones := p.Opciones
+		if opciones == nil {
+			opciones = []string{}
+		}
+		res = append(res, dto.PreguntaResponse{
+			IDPregunta:  p.IDPregunta,
+			Orden:       p.Orden,
+			Tipo:        p.Tipo,
+			Enunciado:   p.Enunciado,
+			Opciones:    opciones,
+			Obligatoria: p.Obligatoria,
+		})
+	}
+	return res
+}
+
+// nuevoToken returns a random link secret. Only its hash is stored.
+func nuevoToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+
+	"project/backend/internal/encuestas/dto"
+	"project/backend/internal/encuestas/repo"
+)
+
+func TestAgregar(t *testing.T) {
+	entero := func(v int) *int { return &v }
+	texto := func(v string) *string { return &v }
+	preguntas := []repo.PreguntaRow{
+		{IDPregunta: 1, Tipo: dto.PreguntaCalificacion, Enunciado: "General"},
+		{IDPregunta: 2, Tipo: dto.PreguntaMultiple, Enunciado: "Temas", Opciones: []string{"Go", "SQL", "Web"}},
+		{IDPregunta: 3, Tipo: dto.PreguntaTexto, Enunciado: "Comentarios"},
+	}
+	items := []repo.RespuestaItemRow{
+		{IDRespuesta: 1, IDPregunta: entero(1), Valor: entero(5)},
+		{IDRespuesta: 1, IDPregunta: entero(2), Opciones: []string{"Go", "SQL"}},
+		{IDRespuesta: 1, IDPregunta: entero(3), Texto: texto("Excelente")},
+		{IDRespuesta: 2, IDPregunta: entero(1), Valor: entero(4)},
+		{IDRespuesta: 2, IDPregunta: entero(2), Opciones: []string{"Go"}},
+		{IDRespuesta: 3},
+	}
+
+	res := agregar(preguntas, items)
+	if len(res) != 3 {
+		t.Fatalf("expected 3 questions, got %d", len(res))
+	}
+
+	general := res[0]
+	if general.Respuestas != 2 || general.Promedio == nil || *general.Promedio != 4.5 {
+		t.Fatalf("unexpected rating aggregate: %+v", general)
+	}
+	if len(general.Opciones) != dto.CalificacionMaxima || general.Opciones[3].Total != 1 || general.Opciones[4].Total != 1 || general.Opciones[0].Total != 0 {
+		t.Fatalf("unexpected rating distribution: %+v", general.Opciones)
+	}
+
+	temas := res[1]
+	want := []dto.ConteoOpcion{{Opcion: "Go", Total: 2}, {Opcion: "SQL", Total: 1}, {Opcion: "Web", Total: 0}}
+	if temas.Respuestas != 2 || len(temas.Opciones) != len(want) {
+		t.Fatalf("unexpected choice aggregate: %+v", temas)
+	}
+	for i := range want {
+		if temas.Opciones[i] != want[i] {
+			t.Fatalf("option %d: expected %+v, got %+v", i, want[i], temas.Opciones[i])
+		}
+	}
+
+	comentarios := res[2]
+	if comentarios.Respuestas != 1 || len(comentarios.Textos) != 1 || comentarios.Textos[0] != "Excelente" || comentarios.Promedio != nil {
+		t.Fatalf("unexpected text aggregate: %+v", comentarios)
+	}
+}
+
+func TestNuevoItem(t *testing.T) {
+	valor := 3
+	r := dto.RespuestaPregunta{IDPregunta: 7, Valor: &valor, Opciones: []string{"Go"}, Texto: " hola "}
+
+	item, ok := nuevoItem(dto.PreguntaCalificacion, r)
+	if !ok || item.Valor == nil || *item.Valor != 3 || item.Opciones != nil || item.Texto != "" {
+		t.Fatalf("rating: unexpected item %+v", item)
+	}
+	item, ok = nuevoItem(dto.PreguntaTexto, r)
+	if !ok || item.Valor != nil || item.Texto != "hola" {
+		t.Fatalf("text: unexpected item %+v", item)
+	}
+	item, ok = nuevoItem(dto.PreguntaUnica, r)
+	if !ok || item.Valor != nil || len(item.Opciones) != 1 {
+		t.Fatalf("choice: unexpected item %+v", item)
+	}
+	if _, ok := nuevoItem(dto.PreguntaTexto, dto.RespuestaPregunta{IDPregunta: 7, Texto: "  "}); ok {
+		t.Fatalf("blank text should be skipped")
+	}
+}
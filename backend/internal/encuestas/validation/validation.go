@@ -0,0 +1,144 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"project/backend/internal/encuestas/dto"
+)
+
+const (
+	maxPreguntas = 30
+	maxOpciones  = 20
+	maxTexto     = 2000
+)
+
+func ValidateEncuesta(req dto.EncuestaRequest) error {
+	titulo := strings.TrimSpace(req.Titulo)
+	if utf8.RuneCountInString(titulo) < 3 || utf8.RuneCountInString(titulo) > 150 {
+		return errors.New("El título de la encuesta debe tener entre 3 y 150 caracteres.")
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(req.Descripcion)) > 1000 {
+		return errors.New("La descripción de la encuesta no puede superar 1000 caracteres.")
+	}
+	if len(req.Preguntas) == 0 {
+		return errors.New("La encuesta debe tener al menos una pregunta.")
+	}
+	if len(req.Preguntas) > maxPreguntas {
+		return fmt.Errorf("La encuesta no puede tener más de %d preguntas.", maxPreguntas)
+	}
+	for i, p := range req.Preguntas {
+		if err := validatePregunta(p); err != nil {
+			return fmt.Errorf("Pregunta %d: %s", i+1, err.Error())
+		}
+	}
+	return nil
+}
+
+func validatePregunta(p dto.PreguntaRequest) error {
+	enunciado := strings.TrimSpace(p.Enunciado)
+	if utf8.RuneCountInString(enunciado) < 3 || utf8.RuneCountInString(enunciado) > 300 {
+		return errors.New("el enunciado debe tener entre 3 y 300 caracteres")
+	}
+	switch p.Tipo {
+	case dto.PreguntaCalificacion, dto.PreguntaTexto:
+		return nil
+	case dto.PreguntaUnica, dto.PreguntaMultiple:
+	default:
+		return errors.New("tipo de pregunta inválido")
+	}
+	if len(p.Opciones) < 2 || len(p.Opciones) > maxOpciones {
+		return fmt.Errorf("debe tener entre 2 y %d opciones", maxOpciones)
+	}
+	vistas := make(map[string]bool, len(p.Opciones))
+	for _, opcion := range p.Opciones {
+		opcion = strings.TrimSpace(opcion)
+		if opcion == "" || utf8.RuneCountInString(opcion) > 100 {
+			return errors.New("cada opción debe tener entre 1 y 100 caracteres")
+		}
+		if vistas[strings.ToLower(opcion)] {
+			return fmt.Errorf("la opción %q está repetida", opcion)
+		}
+		vistas[strings.ToLower(opcion)] = true
+	}
+	return nil
+}
+
+// ValidateRespuestas checks an answer against the questions of the survey:
+// every answered question must belong to it and be answered once with a
+// value its type accepts, and every required question must be answered.
+func ValidateRespuestas(preguntas []dto.PreguntaResponse, respuestas []dto.RespuestaPregunta) error {
+	porID := make(map[int]dto.PreguntaResponse, len(preguntas))
+	for _, p := range preguntas {
+		porID[p.IDPregunta] = p
+	}
+	respondidas := make(map[int]bool, len(respuestas))
+	for _, r := range respuestas {
+		p, ok := porID[r.IDPregunta]
+		if !ok {
+			return fmt.Errorf("la pregunta %d no pertenece a la encuesta", r.IDPregunta)
+		}
+		if respondidas[r.IDPregunta] {
+			return fmt.Errorf("la pregunta %q está respondida más de una vez", p.Enunciado)
+		}
+		if vacia(p.Tipo, r) {
+			continue
+		}
+		if err := validateRespuesta(p, r); err != nil {
+			return fmt.Errorf("%q: %s", p.Enunciado, err.Error())
+		}
+		respondidas[r.IDPregunta] = true
+	}
+	for _, p := range preguntas {
+		if p.Obligatoria && !respondidas[p.IDPregunta] {
+			return fmt.Errorf("la pregunta %q es obligatoria", p.Enunciado)
+		}
+	}
+	return nil
+}
+
+// vacia reports whether r leaves a question of type tipo unanswered.
+func vacia(tipo string, r dto.RespuestaPregunta) bool {
+	switch tipo {
+	case dto.PreguntaCalificacion:
+		return r.Valor == nil
+	case dto.PreguntaTexto:
+		return strings.TrimSpace(r.Texto) == ""
+	default:
+		return len(r.Opciones) == 0
+	}
+}
+
+func validateRespuesta(p dto.PreguntaResponse, r dto.RespuestaPregunta) error {
+	switch p.Tipo {
+	case dto.PreguntaCalificacion:
+		if *r.Valor < 1 || *r.Valor > dto.CalificacionMaxima {
+			return fmt.Errorf("la calificación debe estar entre 1 y %d", dto.CalificacionMaxima)
+		}
+	case dto.PreguntaTexto:
+		if utf8.RuneCountInString(strings.TrimSpace(r.Texto)) > maxTexto {
+			return fmt.Errorf("la respuesta no puede superar %d caracteres", maxTexto)
+		}
+	case dto.PreguntaUnica, dto.PreguntaMultiple:
+		if p.Tipo == dto.PreguntaUnica && len(r.Opciones) > 1 {
+			return errors.New("solo se puede elegir una opción")
+		}
+		validas := make(map[string]bool, len(p.Opciones))
+		for _, opcion := range p.Opciones {
+			validas[opcion] = true
+		}
+		elegidas := make(map[string]bool, len(r.Opciones))
+		for _, opcion := range r.Opciones {
+			if !validas[opcion] {
+				return fmt.Errorf("la opción %q no existe", opcion)
+			}
+			if elegidas[opcion] {
+				return fmt.Errorf("la opción %q está repetida", opcion)
+			}
+			elegidas[opcion] = true
+		}
+	}
+	return nil
+}
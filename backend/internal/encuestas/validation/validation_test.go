@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"testing"
+
+	"project/backend/internal/encuestas/dto"
+)
+
+func TestValidateEncuesta(t *testing.T) {
+	base := func() dto.EncuestaRequest {
+		return dto.EncuestaRequest{
+			Titulo: "¿Qué te pareció el evento?",
+			Preguntas: []dto.PreguntaRequest{
+				{Tipo: dto.PreguntaCalificacion, Enunciado: "Calificación general", Obligatoria: true},
+				{Tipo: dto.PreguntaUnica, Enunciado: "¿Volverías?", Opciones: []string{"Sí", "No"}},
+				{Tipo: dto.PreguntaTexto, Enunciado: "Comentarios"},
+			},
+		}
+	}
+	cases := []struct {
+		name    string
+		mutate  func(*dto.EncuestaRequest)
+		wantErr bool
+	}{
+		{"valida", func(*dto.EncuestaRequest) {}, false},
+		{"titulo corto", func(r *dto.EncuestaRequest) { r.Titulo = "ok" }, true},
+		{"sin preguntas", func(r *dto.EncuestaRequest) { r.Preguntas = nil }, true},
+		{"tipo desconocido", func(r *dto.EncuestaRequest) { r.Preguntas[0].Tipo = "escala" }, true},
+		{"enunciado vacio", func(r *dto.EncuestaRequest) { r.Preguntas[2].Enunciado = "  " }, true},
+		{"una sola opcion", func(r *dto.EncuestaRequest) { r.Preguntas[1].Opciones = []string{"Sí"} }, true},
+		{"opcion vacia", func(r *dto.EncuestaRequest) { r.Preguntas[1].Opciones = []string{"Sí", " "} }, true},
+		{"opcion repetida", func(r *dto.EncuestaRequest) { r.Preguntas[1].Opciones = []string{"Sí", "sí "} }, true},
+		{"texto ignora opciones", func(r *dto.EncuestaRequest) { r.Preguntas[2].Opciones = []string{"x"} }, false},
+	}
+
+	for _, c := range cases {
+		req := base()
+		c.mutate(&req)
+		err := ValidateEncuesta(req)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
+
+func TestValidateRespuestas(t *testing.T) {
+	preguntas := []dto.PreguntaResponse{
+		{IDPregunta: 1, Tipo: dto.PreguntaCalificacion, Enunciado: "General", Obligatoria: true},
+		{IDPregunta: 2, Tipo: dto.PreguntaUnica, Enunciado: "¿Volverías?", Opciones: []string{"Sí", "No"}},
+		{IDPregunta: 3, Tipo: dto.PreguntaMultiple, Enunciado: "Temas", Opciones: []string{"Go", "SQL", "Web"}},
+		{IDPregunta: 4, Tipo: dto.PreguntaTexto, Enunciado: "Comentarios"},
+	}
+	valor := func(v int) *int { return &v }
+	cases := []struct {
+		name       string
+		respuestas []dto.RespuestaPregunta
+		wantErr    bool
+	}{
+		{"solo obligatoria", []dto.RespuestaPregunta{{IDPregunta: 1, Valor: valor(4)}}, false},
+		{"completa", []dto.RespuestaPregunta{
+			{IDPregunta: 1, Valor: valor(5)},
+			{IDPregunta: 2, Opciones: []string{"Sí"}},
+			{IDPregunta: 3, Opciones: []string{"Go", "SQL"}},
+			{IDPregunta: 4, Texto: "Muy bueno"},
+		}, false},
+		{"falta obligatoria", []dto.RespuestaPregunta{{IDPregunta: 4, Texto: "Bien"}}, true},
+		{"obligatoria en blanco", []dto.RespuestaPregunta{{IDPregunta: 1}}, true},
+		{"calificacion fuera de rango", []dto.RespuestaPregunta{{IDPregunta: 1, Valor: valor(6)}}, true},
+		{"calificacion cero", []dto.RespuestaPregunta{{IDPregunta: 1, Valor: valor(0)}}, true},
+		{"pregunta ajena", []dto.RespuestaPregunta{{IDPregunta: 1, Valor: valor(3)}, {IDPregunta: 9, Texto: "x"}}, true},
+		{"pregunta repetida", []dto.RespuestaPregunta{{IDPregunta: 1, Valor: valor(3)}, {IDPregunta: 1, Valor: valor(4)}}, true},
+		{"dos opciones en unica", []dto.RespuestaPregunta{{IDPregunta: 1, Valor: valor(3)}, {IDPregunta: 2, Opciones: []string{"Sí", "No"}}}, true},
+		{"opcion inexistente", []dto.RespuestaPregunta{{IDPregunta: 1, Valor: valor(3)}, {IDPregunta: 3, Opciones: []string{"Rust"}}}, true},
+		{"opcion repetida", []dto.RespuestaPregunta{{IDPregunta: 1, Valor: valor(3)}, {IDPregunta: 3, Opciones: []string{"Go", "Go"}}}, true},
+	}
+
+	for _, c := range cases {
+		err := ValidateRespuestas(preguntas, c.respuestas)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+const (
+	DescuentoPorcentaje = "porcentaje"
+	DescuentoFijo       = "fijo"
+)
+
+// TipoEntradaRequest represents the payload to create or update a ticket type
+// of an event. Dates are read in the event's time zone. PrecioAnticipado is
+// the early-bird price, charged until AnticipadoHasta.
+type TipoEntradaRequest struct {
+	IDEvento         int              `json:"id_evento"`
+	Nombre           string           `json:"nombre"`
+	Descripcion      string           `json:"descripcion"`
+	Precio           decimal.Decimal  `json:"precio"`
+	Moneda           string           `json:"moneda"`
+	Cantidad         *int             `json:"cantidad"`
+	VentaDesde       string           `json:"venta_desde"`
+	VentaHasta       string           `json:"venta_hasta"`
+	PrecioAnticipado *decimal.Decimal `json:"precio_anticipado"`
+	AnticipadoHasta  string           `json:"anticipado_hasta"`
+	SoloPonentes     bool             `json:"solo_ponentes"`
+}
+
+// CodigoPromocionalRequest represents the payload to create or update a
+// promo code of an event. TipoDescuento is "porcentaje" or "fijo"; a fixed
+// discount is an amount in Moneda and only applies to tickets sold in it.
+// Dates are read in the event's time zone, and an empty TiposEntrada
+// accepts every ticket type.
+type CodigoPromocionalRequest struct {
+	IDEvento       int             `json:"id_evento"`
+	Codigo         string          `json:"codigo"`
+	TipoDescuento  string          `json:"tipo_descuento"`
+	Valor          decimal.Decimal `json:"valor"`
+	Moneda         string          `json:"moneda"`
+	UsosMaximos    *int            `json:"usos_maximos"`
+	UsosPorUsuario *int            `json:"usos_por_usuario"`
+	ValidoDesde    string          `json:"valido_desde"`
+	ValidoHasta    string          `json:"valido_hasta"`
+	TiposEntrada   []int           `json:"tipos_entrada"`
+}
+
+// LoteCodigosRequest generates Cantidad single-use codes that start with
+// Prefijo and share the discount rules. Codigo, UsosMaximos and
+// UsosPorUsuario are ignored.
+type LoteCodigosRequest struct {
+	Prefijo  string `json:"prefijo"`
+	Cantidad int    `json:"cantidad"`
+	CodigoPromocionalRequest
+}
@@ -0,0 +1,81 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// TipoEntradaResponse represents a ticket type of an event. PrecioActual is
+// the price charged right now, early-bird included; Disponibles is nil when
+// the type has no quantity limit.
+type TipoEntradaResponse struct {
+	ID               int              `json:"id_tipo_entrada"`
+	IDEvento         int              `json:"id_evento"`
+	Nombre           string           `json:"nombre"`
+	Descripcion      string           `json:"descripcion"`
+	Precio           decimal.Decimal  `json:"precio"`
+	PrecioAnticipado *decimal.Decimal `json:"precio_anticipado"`
+	AnticipadoHasta  *string          `json:"anticipado_hasta"`
+	PrecioActual     decimal.Decimal  `json:"precio_actual"`
+	Moneda           string           `json:"moneda"`
+	Cantidad         *int             `json:"cantidad"`
+	Vendidas         int              `json:"vendidas"`
+	Disponibles      *int             `json:"disponibles"`
+	VentaDesde       *string          `json:"venta_desde"`
+	VentaHasta       *string          `json:"venta_hasta"`
+	EnVenta          bool             `json:"en_venta"`
+	SoloPonentes     bool             `json:"solo_ponentes"`
+	Activo           bool             `json:"activo"`
+}
+
+// CodigoPromocionalResponse represents a promo code. Usos counts the
+// inscriptions that redeemed it and were not rejected or cancelled.
+type CodigoPromocionalResponse struct {
+	ID             int             `json:"id_codigo"`
+	IDEvento       int             `json:"id_evento"`
+	Codigo         string          `json:"codigo"`
+	TipoDescuento  string          `json:"tipo_descuento"`
+	Valor          decimal.Decimal `json:"valor"`
+	Moneda         *string         `json:"moneda"`
+	UsosMaximos    *int            `json:"usos_maximos"`
+	UsosPorUsuario *int            `json:"usos_por_usuario"`
+	Usos           int             `json:"usos"`
+	ValidoDesde    *string         `json:"valido_desde"`
+	ValidoHasta    *string         `json:"valido_hasta"`
+	TiposEntrada   []int           `json:"tipos_entrada"`
+	Lote           *string         `json:"lote"`
+	Activo         bool            `json:"activo"`
+}
+
+// LoteCodigosResponse lists the codes generated in one batch.
+type LoteCodigosResponse struct {
+	Lote    string   `json:"lote"`
+	Codigos []string `json:"codigos"`
+}
+
+// CanjeResponse is an inscription that redeemed a promo code. Monto is the
+// amount due after the discount.
+type CanjeResponse struct {
+	IDInscripcion int             `json:"id_inscripcion"`
+	Codigo        string          `json:"codigo"`
+	Lote          *string         `json:"lote"`
+	IDUsuario     int             `json:"id_usuario"`
+	Participante  string          `json:"participante"`
+	TipoEntrada   *string         `json:"tipo_entrada"`
+	Descuento     decimal.Decimal `json:"descuento"`
+	Monto         decimal.Decimal `json:"monto"`
+	Moneda        string          `json:"moneda"`
+	Estado        string          `json:"estado"`
+	Fecha         string          `json:"fecha"`
+}
+
+// ResumenCodigoResponse sums the redemptions of a code in one currency.
+type ResumenCodigoResponse struct {
+	Codigo    string          `json:"codigo"`
+	Canjes    int             `json:"canjes"`
+	Descuento decimal.Decimal `json:"descuento"`
+	Moneda    string          `json:"moneda"`
+}
+
+// CanjesResponse is the redemptions report of an event.
+type CanjesResponse struct {
+	Resumen []ResumenCodigoResponse `json:"resumen"`
+	Canjes  []CanjeResponse         `json:"canjes"`
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project/backend/internal/entradas/dto"
+	"project/backend/internal/entradas/service"
+	"project/backend/internal/entradas/validation"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+)
+
+// CodigosHandler manages the promo codes of an event. Every method requires
+// management permission, since listing reveals redeemable codes.
+func (h *Handler) CodigosHandler(w http.ResponseWriter, r *http.Request) {
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		h.listCodigos(w, r)
+	case http.MethodPost:
+		h.createCodigo(w, r)
+	case http.MethodPut:
+		h.updateCodigo(w, r)
+	case http.MethodPatch:
+		h.setCodigoActivo(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// LoteCodigosHandler generates a batch of single-use codes.
+func (h *Handler) LoteCodigosHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	var req dto.LoteCodigosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateLoteCodigos(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	lote, err := h.svc.GenerarLote(ctx, req, time.Now())
+	if writeCodigoError(w, err) {
+		return
+	}
+	writeJSON(w, lote)
+}
+
+// CanjesHandler reports the promo code redemptions of ?id_evento.
+func (h *Handler) CanjesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	canjes, err := h.svc.Canjes(ctx, eventoID)
+	if writeCodigoError(w, err) {
+		return
+	}
+	writeJSON(w, canjes)
+}
+
+func (h *Handler) listCodigos(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	if idStr := r.URL.Query().Get("id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil || id <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+			return
+		}
+		codigo, err := h.svc.GetCodigo(ctx, id)
+		if writeCodigoError(w, err) {
+			return
+		}
+		writeJSON(w, codigo)
+		return
+	}
+
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+	codigos, err := h.svc.ListCodigos(ctx, eventoID, r.URL.Query().Get("lote"))
+	if writeCodigoError(w, err) {
+		return
+	}
+	writeJSON(w, codigos)
+}
+
+func (h *Handler) createCodigo(w http.ResponseWriter, r *http.Request) {
+	var req dto.CodigoPromocionalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateCodigoPromocional(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	created, err := h.svc.CreateCodigo(ctx, req)
+	if writeCodigoError(w, err) {
+		return
+	}
+	writeJSON(w, created)
+}
+
+func (h *Handler) updateCodigo(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+	var req dto.CodigoPromocionalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateCodigoPromocional(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	updated, err := h.svc.UpdateCodigo(ctx, id, req)
+	if writeCodigoError(w, err) {
+		return
+	}
+	writeJSON(w, updated)
+}
+
+func (h *Handler) setCodigoActivo(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+	var req struct {
+		Activo *bool `json:"activo"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Activo == nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "activo es requerido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	updated, err := h.svc.SetCodigoActivo(ctx, id, *req.Activo)
+	if writeCodigoError(w, err) {
+		return
+	}
+	writeJSON(w, updated)
+}
+
+func writeCodigoError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrCodigoNotFound), errors.Is(err, service.ErrEventoNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrVigenciaInvalida), errors.Is(err, service.ErrTiposInvalidos):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrCodigoExists):
+		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project/backend/internal/entradas/dto"
+	"project/backend/internal/entradas/repo"
+	"project/backend/internal/entradas/service"
+	"project/backend/internal/entradas/validation"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+	"project/backend/prisma/db"
+)
+
+type Handler struct {
+	svc         *service.Service
+	roleService roles.UserRoleService
+}
+
+func New(client *db.PrismaClient) *Handler {
+	return &Handler{
+		svc:         service.New(repo.New(client)),
+		roleService: roles.NewUserRoleService(client),
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listTipos(w, r)
+	case http.MethodPost:
+		h.createTipo(w, r)
+	case http.MethodPut:
+		h.updateTipo(w, r)
+	case http.MethodPatch:
+		h.setActivo(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listTipos returns one ticket type with ?id, or those of an event with
+// ?id_evento. Withdrawn types are listed with todos=true, which requires
+// management permission.
+func (h *Handler) listTipos(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	if idStr := r.URL.Query().Get("id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil || id <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+			return
+		}
+		tipo, err := h.svc.GetTipoEntrada(ctx, id, time.Now())
+		if writeEntradaError(w, err) {
+			return
+		}
+		writeJSON(w, tipo)
+		return
+	}
+
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+	todos := r.URL.Query().Get("todos") == "true"
+	if todos && !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	tipos, err := h.svc.ListTiposEntrada(ctx, eventoID, todos, time.Now())
+	if writeEntradaError(w, err) {
+		return
+	}
+	writeJSON(w, tipos)
+}
+
+func (h *Handler) createTipo(w http.ResponseWriter, r *http.Request) {
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	var req dto.TipoEntradaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateTipoEntrada(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	created, err := h.svc.CreateTipoEntrada(ctx, req, time.Now())
+	if writeEntradaError(w, err) {
+		return
+	}
+	writeJSON(w, created)
+}
+
+func (h *Handler) updateTipo(w http.ResponseWriter, r *http.Request) {
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+	var req dto.TipoEntradaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateTipoEntrada(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	updated, err := h.svc.UpdateTipoEntrada(ctx, id, req, time.Now())
+	if writeEntradaError(w, err) {
+		return
+	}
+	writeJSON(w, updated)
+}
+
+// setActivo withdraws a ticket type from sale, or puts it back, with
+// {"activo": bool}.
+func (h *Handler) setActivo(w http.ResponseWriter, r *http.Request) {
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+	var req struct {
+		Activo *bool `json:"activo"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Activo == nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "activo es requerido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	updated, err := h.svc.SetActivo(ctx, id, *req.Activo, time.Now())
+	if writeEntradaError(w, err) {
+		return
+	}
+	writeJSON(w, updated)
+}
+
+// msgSinPermisos answers callers without events.management.
+const msgSinPermisos = "no tienes permisos para gestionar entradas"
+
+func writeEntradaError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrEventoNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrFechasInvalidas):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrNameExists), errors.Is(err, service.ErrCantidad):
+		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,197 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	inscripciones "project/backend/internal/inscripciones/validation"
+	"project/backend/prisma/db"
+
+	"github.com/shopspring/decimal"
+)
+
+// CodigoRow is a promo code with the redemptions that still count against
+// its limits.
+type CodigoRow struct {
+	IDCodigo       int        `json:"id_codigo"`
+	IDEvento       int        `json:"id_evento"`
+	Codigo         string     `json:"codigo"`
+	TipoDescuento  string     `json:"tipo_descuento"`
+	Valor          string     `json:"valor"`
+	Moneda         *string    `json:"moneda"`
+	UsosMaximos    *int       `json:"usos_maximos"`
+	UsosPorUsuario *int       `json:"usos_por_usuario"`
+	ValidoDesde    *time.Time `json:"valido_desde"`
+	ValidoHasta    *time.Time `json:"valido_hasta"`
+	TiposEntrada   []int      `json:"tipos_entrada"`
+	Lote           *string    `json:"lote"`
+	Activo         bool       `json:"activo"`
+	Usos           int        `json:"usos"`
+}
+
+// CodigoPromocional holds the columns written when promo codes are created
+// or updated. Codes are stored in upper case; Moneda is empty for
+// percentage discounts.
+type CodigoPromocional struct {
+	TipoDescuento  string
+	Valor          decimal.Decimal
+	Moneda         string
+	UsosMaximos    *int
+	UsosPorUsuario *int
+	ValidoDesde    *time.Time
+	ValidoHasta    *time.Time
+	TiposEntrada   []int
+}
+
+// CanjeRow is an inscription that redeemed a promo code.
+type CanjeRow struct {
+	IDInscripcion int       `json:"id_inscripcion"`
+	Codigo        string    `json:"codigo"`
+	Lote          *string   `json:"lote"`
+	IDUsuario     int       `json:"id_usuario"`
+	Participante  string    `json:"participante"`
+	TipoEntrada   *string   `json:"tipo_entrada"`
+	Descuento     string    `json:"descuento"`
+	Monto         string    `json:"monto"`
+	Moneda        string    `json:"moneda"`
+	Estado        string    `json:"estado"`
+	Fecha         time.Time `json:"fecha"`
+}
+
+const codigoSelect = `SELECT p."id_codigo", p."id_evento", p."codigo", p."tipo_descuento", p."valor"::text AS "valor", p."moneda",
+		p."usos_maximos", p."usos_por_usuario", p."valido_desde", p."valido_hasta", p."tipos_entrada", p."lote", p."activo",
+		(SELECT COUNT(*) FROM "Inscripcion" i
+			WHERE i."id_codigo" = p."id_codigo" AND i."estado" NOT IN ` + inscripciones.EstadosLiberados + `)::int AS "usos"
+		FROM "CodigoPromocional" p`
+
+// ListCodigos returns the promo codes of an event, optionally only those of
+// one batch.
+func (r *Repository) ListCodigos(ctx context.Context, eventoID int, lote string) ([]CodigoRow, error) {
+	query := codigoSelect + ` WHERE p."id_evento" = $1::int AND ($2::text = '' OR p."lote" = $2::text) ORDER BY p."codigo"`
+	var rows []CodigoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID, strings.TrimSpace(lote)).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *Repository) FindCodigo(ctx context.Context, id int) (CodigoRow, error) {
+	return r.findCodigo(ctx, codigoSelect+` WHERE p."id_codigo" = $1::int`, id)
+}
+
+// FindCodigoPorTexto looks a code up within an event, ignoring case.
+func (r *Repository) FindCodigoPorTexto(ctx context.Context, eventoID int, codigo string) (CodigoRow, error) {
+	query := codigoSelect + ` WHERE p."id_evento" = $1::int AND p."codigo" = upper($2::text)`
+	return r.findCodigo(ctx, query, eventoID, strings.TrimSpace(codigo))
+}
+
+func (r *Repository) findCodigo(ctx context.Context, query string, params ...interface{}) (CodigoRow, error) {
+	var rows []CodigoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return CodigoRow{}, err
+	}
+	if len(rows) == 0 {
+		return CodigoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// UsosUsuario counts the redemptions of a code by one user.
+func (r *Repository) UsosUsuario(ctx context.Context, codigoID, usuarioID int) (int, error) {
+	query := `SELECT COUNT(*)::int AS "usos" FROM "Inscripcion"
+		WHERE "id_codigo" = $1::int AND "id_usuario" = $2::int AND "estado" NOT IN ` + inscripciones.EstadosLiberados
+	var rows []struct {
+		Usos int `json:"usos"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, codigoID, usuarioID).Exec(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Usos, nil
+}
+
+// CreateCodigos inserts one promo code per entry of codigos with the same
+// rules. Codes the event already has are skipped; the ids and codes that were
+// actually inserted are returned.
+func (r *Repository) CreateCodigos(ctx context.Context, eventoID int, codigos []string, datos CodigoPromocional, lote string) ([]CodigoRow, error) {
+	query := `INSERT INTO "CodigoPromocional" ("id_evento", "codigo", "tipo_descuento", "valor", "moneda", "usos_maximos", "usos_por_usuario",
+		"valido_desde", "valido_hasta", "tipos_entrada", "lote", "activo", "createdAt", "updatedAt")
+		SELECT $1::int, upper(c), $3::text, $4::numeric, NULLIF($5::text, ''), $6::int, $7::int,
+			$8::timestamptz AT TIME ZONE 'UTC', $9::timestamptz AT TIME ZONE 'UTC', $10::int[], NULLIF($11::text, ''), true, NOW(), NOW()
+		FROM unnest($2::text[]) AS c
+		ON CONFLICT ("id_evento", "codigo") DO NOTHING
+		RETURNING "id_codigo", "codigo"`
+	params := []interface{}{eventoID, codigos}
+	params = append(params, codigoParams(datos)...)
+	params = append(params, strings.TrimSpace(lote))
+	var rows []CodigoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// UpdateCodigo replaces the text and rules of a code. It returns false when
+// another code of the event already uses the new text.
+func (r *Repository) UpdateCodigo(ctx context.Context, id int, codigo string, datos CodigoPromocional) (bool, error) {
+	query := `UPDATE "CodigoPromocional" p SET "codigo" = upper($2::text), "tipo_descuento" = $3::text, "valor" = $4::numeric,
+		"moneda" = NULLIF($5::text, ''), "usos_maximos" = $6::int, "usos_por_usuario" = $7::int,
+		"valido_desde" = $8::timestamptz AT TIME ZONE 'UTC', "valido_hasta" = $9::timestamptz AT TIME ZONE 'UTC',
+		"tipos_entrada" = $10::int[], "updatedAt" = NOW()
+		WHERE p."id_codigo" = $1::int AND NOT EXISTS (
+			SELECT 1 FROM "CodigoPromocional" o
+			WHERE o."id_evento" = p."id_evento" AND o."codigo" = upper($2::text) AND o."id_codigo" <> p."id_codigo"
+		)`
+	params := append([]interface{}{id, strings.TrimSpace(codigo)}, codigoParams(datos)...)
+	count, err := r.client.Prisma.Raw.ExecuteRaw(query, params...).Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	return count.Count > 0, nil
+}
+
+func (r *Repository) SetCodigoActivo(ctx context.Context, id int, activo bool) error {
+	query := `UPDATE "CodigoPromocional" SET "activo" = $2::boolean, "updatedAt" = NOW() WHERE "id_codigo" = $1::int`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, id, activo).Exec(ctx)
+	return err
+}
+
+// ListCanjes returns every inscription of the event that redeemed a code,
+// newest first.
+func (r *Repository) ListCanjes(ctx context.Context, eventoID int) ([]CanjeRow, error) {
+	query := `SELECT i."id_inscripcion", p."codigo", p."lote", i."id_usuario",
+		COALESCE(NULLIF(i."nombre_participante", ''), u."nombre") AS "participante", t."nombre" AS "tipo_entrada",
+		COALESCE(i."descuento", 0)::text AS "descuento", COALESCE(i."monto", 0)::text AS "monto", COALESCE(i."moneda", '') AS "moneda",
+		i."estado", i."fecha_inscripcion" AS "fecha"
+		FROM "Inscripcion" i
+		JOIN "CodigoPromocional" p ON p."id_codigo" = i."id_codigo"
+		JOIN "Usuario" u ON u."id_usuario" = i."id_usuario"
+		LEFT JOIN "TipoEntrada" t ON t."id_tipo_entrada" = i."id_tipo_entrada"
+		WHERE p."id_evento" = $1::int
+		ORDER BY i."fecha_inscripcion" DESC, i."id_inscripcion" DESC`
+	var rows []CanjeRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func codigoParams(datos CodigoPromocional) []interface{} {
+	tipos := datos.TiposEntrada
+	if tipos == nil {
+		tipos = []int{}
+	}
+	return []interface{}{
+		datos.TipoDescuento,
+		datos.Valor.String(),
+		strings.ToUpper(strings.TrimSpace(datos.Moneda)),
+		datos.UsosMaximos,
+		datos.UsosPorUsuario,
+		datos.ValidoDesde,
+		datos.ValidoHasta,
+		tipos,
+	}
+}
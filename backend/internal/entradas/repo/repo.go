@@ -0,0 +1,190 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	inscripciones "project/backend/internal/inscripciones/validation"
+	"project/backend/prisma/db"
+
+	"github.com/shopspring/decimal"
+)
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+// TipoEntradaRow is a ticket type with the number of tickets taken. Prices
+// are read as text to keep their exact decimal value.
+type TipoEntradaRow struct {
+	IDTipoEntrada    int        `json:"id_tipo_entrada"`
+	IDEvento         int        `json:"id_evento"`
+	Nombre           string     `json:"nombre"`
+	Descripcion      string     `json:"descripcion"`
+	Precio           string     `json:"precio"`
+	Moneda           string     `json:"moneda"`
+	Cantidad         *int       `json:"cantidad"`
+	VentaDesde       *time.Time `json:"venta_desde"`
+	VentaHasta       *time.Time `json:"venta_hasta"`
+	PrecioAnticipado *string    `json:"precio_anticipado"`
+	AnticipadoHasta  *time.Time `json:"anticipado_hasta"`
+	SoloPonentes     bool       `json:"solo_ponentes"`
+	Activo           bool       `json:"activo"`
+	Vendidas         int        `json:"vendidas"`
+}
+
+// TipoEntrada holds the columns written when a ticket type is created or
+// updated. Dates are instants; nil means no limit.
+type TipoEntrada struct {
+	Nombre           string
+	Descripcion      string
+	Precio           decimal.Decimal
+	Moneda           string
+	Cantidad         *int
+	VentaDesde       *time.Time
+	VentaHasta       *time.Time
+	PrecioAnticipado *decimal.Decimal
+	AnticipadoHasta  *time.Time
+	SoloPonentes     bool
+}
+
+const tipoSelect = `SELECT t."id_tipo_entrada", t."id_evento", t."nombre", t."descripcion", t."precio"::text AS "precio", t."moneda",
+		t."cantidad", t."venta_desde", t."venta_hasta", t."precio_anticipado"::text AS "precio_anticipado", t."anticipado_hasta",
+		t."solo_ponentes", t."activo",
+		(SELECT COUNT(*) FROM "Inscripcion" i
+			WHERE i."id_tipo_entrada" = t."id_tipo_entrada" AND i."estado" NOT IN ` + inscripciones.EstadosLiberados + `)::int AS "vendidas"
+		FROM "TipoEntrada" t`
+
+// List returns the ticket types of an event, cheapest first. Inactive types
+// are only included when todos is set.
+func (r *Repository) List(ctx context.Context, eventoID int, todos bool) ([]TipoEntradaRow, error) {
+	query := tipoSelect + ` WHERE t."id_evento" = $1::int`
+	if !todos {
+		query += ` AND t."activo"`
+	}
+	query += ` ORDER BY t."precio" ASC, t."nombre" ASC`
+	var rows []TipoEntradaRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int) (TipoEntradaRow, error) {
+	var rows []TipoEntradaRow
+	if err := r.client.Prisma.Raw.QueryRaw(tipoSelect+` WHERE t."id_tipo_entrada" = $1::int`, id).Exec(ctx, &rows); err != nil {
+		return TipoEntradaRow{}, err
+	}
+	if len(rows) == 0 {
+		return TipoEntradaRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// ExistsNombre reports whether another ticket type of the event already uses
+// nombre.
+func (r *Repository) ExistsNombre(ctx context.Context, eventoID int, nombre string, excluirID int) (bool, error) {
+	query := `SELECT "id_tipo_entrada" FROM "TipoEntrada"
+		WHERE "id_evento" = $1::int AND lower("nombre") = lower($2::text) AND "id_tipo_entrada" <> $3::int LIMIT 1`
+	var rows []struct {
+		IDTipoEntrada int `json:"id_tipo_entrada"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID, strings.TrimSpace(nombre), excluirID).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (r *Repository) Create(ctx context.Context, eventoID int, tipo TipoEntrada) (int, error) {
+	query := `INSERT INTO "TipoEntrada" ("id_evento", "nombre", "descripcion", "precio", "moneda", "cantidad", "venta_desde", "venta_hasta",
+		"precio_anticipado", "anticipado_hasta", "solo_ponentes", "activo", "createdAt", "updatedAt")
+		VALUES ($1::int, $2::text, $3::text, $4::numeric, $5::text, $6::int,
+			$7::timestamptz AT TIME ZONE 'UTC', $8::timestamptz AT TIME ZONE 'UTC',
+			$9::numeric, $10::timestamptz AT TIME ZONE 'UTC', $11::boolean, true, NOW(), NOW())
+		RETURNING "id_tipo_entrada"`
+	params := append([]interface{}{eventoID}, tipoParams(tipo)...)
+	var rows []struct {
+		IDTipoEntrada int `json:"id_tipo_entrada"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no rows")
+	}
+	return rows[0].IDTipoEntrada, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id int, tipo TipoEntrada) error {
+	query := `UPDATE "TipoEntrada" SET "nombre" = $2::text, "descripcion" = $3::text, "precio" = $4::numeric, "moneda" = $5::text,
+		"cantidad" = $6::int, "venta_desde" = $7::timestamptz AT TIME ZONE 'UTC', "venta_hasta" = $8::timestamptz AT TIME ZONE 'UTC',
+		"precio_anticipado" = $9::numeric, "anticipado_hasta" = $10::timestamptz AT TIME ZONE 'UTC',
+		"solo_ponentes" = $11::boolean, "updatedAt" = NOW()
+		WHERE "id_tipo_entrada" = $1::int`
+	params := append([]interface{}{id}, tipoParams(tipo)...)
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, params...).Exec(ctx)
+	return err
+}
+
+// SetActivo withdraws a ticket type from sale or puts it back. Types are
+// never deleted so inscriptions keep the type they paid for.
+func (r *Repository) SetActivo(ctx context.Context, id int, activo bool) error {
+	query := `UPDATE "TipoEntrada" SET "activo" = $2::boolean, "updatedAt" = NOW() WHERE "id_tipo_entrada" = $1::int`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, id, activo).Exec(ctx)
+	return err
+}
+
+// FindZonaEvento returns the time zone of an event, or db.ErrNotFound when
+// the event does not exist.
+func (r *Repository) FindZonaEvento(ctx context.Context, eventoID int) (string, error) {
+	var rows []struct {
+		ZonaHoraria string `json:"zona_horaria"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(`SELECT "zona_horaria" FROM "Evento" WHERE "id_evento" = $1::int`, eventoID).Exec(ctx, &rows); err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", db.ErrNotFound
+	}
+	return rows[0].ZonaHoraria, nil
+}
+
+// EsPonente reports whether the user speaks at any session of the event.
+func (r *Repository) EsPonente(ctx context.Context, eventoID, usuarioID int) (bool, error) {
+	query := `SELECT sp."id_usuario" FROM "SesionPonente" sp
+		JOIN "Sesion" s ON s."id_sesion" = sp."id_sesion"
+		WHERE s."id_evento" = $1::int AND sp."id_usuario" = $2::int AND NOT s."cancelado"
+		LIMIT 1`
+	var rows []struct {
+		IDUsuario int `json:"id_usuario"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID, usuarioID).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func tipoParams(tipo TipoEntrada) []interface{} {
+	var anticipado interface{}
+	if tipo.PrecioAnticipado != nil {
+		anticipado = tipo.PrecioAnticipado.String()
+	}
+	return []interface{}{
+		strings.TrimSpace(tipo.Nombre),
+		strings.TrimSpace(tipo.Descripcion),
+		tipo.Precio.String(),
+		strings.ToUpper(strings.TrimSpace(tipo.Moneda)),
+		tipo.Cantidad,
+		tipo.VentaDesde,
+		tipo.VentaHasta,
+		anticipado,
+		tipo.AnticipadoHasta,
+		tipo.SoloPonentes,
+	}
+}
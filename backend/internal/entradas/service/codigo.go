@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"project/backend/internal/entradas/dto"
+	"project/backend/internal/entradas/repo"
+	"project/backend/internal/entradas/validation"
+	"project/backend/internal/events/domain"
+	"project/backend/prisma/db"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrCodigoNotFound   = errors.New("código promocional no encontrado")
+	ErrCodigoExists     = errors.New("ya existe un código promocional con ese texto en el evento")
+	ErrCodigoVencido    = errors.New("el código promocional no está vigente")
+	ErrCodigoNoAplica   = errors.New("el código promocional no aplica a este tipo de entrada")
+	ErrCodigoAgotado    = errors.New("el código promocional ya no tiene usos disponibles")
+	ErrTiposInvalidos   = errors.New("los tipos de entrada no pertenecen al evento")
+	ErrVigenciaInvalida = errors.New("vigencia inválida")
+)
+
+// alfabetoCodigos leaves out characters that are easy to misread, such as
+// O and 0 or I and 1.
+const alfabetoCodigos = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const largoCodigoLote = 8
+
+func (s *Service) ListCodigos(ctx context.Context, eventoID int, lote string) ([]dto.CodigoPromocionalResponse, error) {
+	zona, err := s.zonaEvento(ctx, eventoID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListCodigos(ctx, eventoID, lote)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make([]dto.CodigoPromocionalResponse, 0, len(rows))
+	for _, row := range rows {
+		item, err := codigoResponse(row, zona)
+		if err != nil {
+			return nil, ErrDB
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+func (s *Service) GetCodigo(ctx context.Context, id int) (dto.CodigoPromocionalResponse, error) {
+	row, err := s.findCodigo(ctx, id)
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, err
+	}
+	zona, err := s.zonaEvento(ctx, row.IDEvento)
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, err
+	}
+	res, err := codigoResponse(row, zona)
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, ErrDB
+	}
+	return res, nil
+}
+
+func (s *Service) CreateCodigo(ctx context.Context, req dto.CodigoPromocionalRequest) (dto.CodigoPromocionalResponse, error) {
+	datos, err := s.prepararCodigo(ctx, req)
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, err
+	}
+	created, err := s.repo.CreateCodigos(ctx, req.IDEvento, []string{req.Codigo}, datos, "")
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, ErrDB
+	}
+	if len(created) == 0 {
+		return dto.CodigoPromocionalResponse{}, ErrCodigoExists
+	}
+	return s.GetCodigo(ctx, created[0].IDCodigo)
+}
+
+// UpdateCodigo replaces the text and rules of a code. Its event cannot
+// change, and inscriptions that already redeemed it keep their discount.
+func (s *Service) UpdateCodigo(ctx context.Context, id int, req dto.CodigoPromocionalRequest) (dto.CodigoPromocionalResponse, error) {
+	actual, err := s.findCodigo(ctx, id)
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, err
+	}
+	if actual.IDEvento != req.IDEvento {
+		return dto.CodigoPromocionalResponse{}, ErrCodigoNotFound
+	}
+	datos, err := s.prepararCodigo(ctx, req)
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, err
+	}
+	updated, err := s.repo.UpdateCodigo(ctx, id, req.Codigo, datos)
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, ErrDB
+	}
+	if !updated {
+		return dto.CodigoPromocionalResponse{}, ErrCodigoExists
+	}
+	return s.GetCodigo(ctx, id)
+}
+
+func (s *Service) SetCodigoActivo(ctx context.Context, id int, activo bool) (dto.CodigoPromocionalResponse, error) {
+	if _, err := s.findCodigo(ctx, id); err != nil {
+		return dto.CodigoPromocionalResponse{}, err
+	}
+	if err := s.repo.SetCodigoActivo(ctx, id, activo); err != nil {
+		return dto.CodigoPromocionalResponse{}, ErrDB
+	}
+	return s.GetCodigo(ctx, id)
+}
+
+// GenerarLote creates req.Cantidad random single-use codes that share the
+// discount rules. Codes that collide with existing ones are drawn again.
+func (s *Service) GenerarLote(ctx context.Context, req dto.LoteCodigosRequest, now time.Time) (dto.LoteCodigosResponse, error) {
+	base := req.CodigoPromocionalRequest
+	unUso := 1
+	base.UsosMaximos = &unUso
+	base.UsosPorUsuario = &unUso
+	datos, err := s.prepararCodigo(ctx, base)
+	if err != nil {
+		return dto.LoteCodigosResponse{}, err
+	}
+
+	prefijo := strings.ToUpper(strings.TrimSpace(req.Prefijo))
+	lote := now.UTC().Format("20060102150405")
+	if prefijo != "" {
+		lote = prefijo + "-" + lote
+	}
+
+	res := dto.LoteCodigosResponse{Lote: lote, Codigos: make([]string, 0, req.Cantidad)}
+	for intento := 0; intento < 3 && len(res.Codigos) < req.Cantidad; intento++ {
+		codigos, err := codigosAleatorios(prefijo, req.Cantidad-len(res.Codigos))
+		if err != nil {
+			return dto.LoteCodigosResponse{}, ErrDB
+		}
+		created, err := s.repo.CreateCodigos(ctx, req.IDEvento, codigos, datos, lote)
+		if err != nil {
+			return dto.LoteCodigosResponse{}, ErrDB
+		}
+		for _, c := range created {
+			res.Codigos = append(res.Codigos, c.Codigo)
+		}
+	}
+	if len(res.Codigos) < req.Cantidad {
+		fmt.Println("[Entradas] Lote", lote, "incompleto:", len(res.Codigos), "de", req.Cantidad)
+		return dto.LoteCodigosResponse{}, ErrDB
+	}
+	return res, nil
+}
+
+// Canjes reports every redemption of the event's promo codes and the
+// discount granted by each code.
+func (s *Service) Canjes(ctx context.Context, eventoID int) (dto.CanjesResponse, error) {
+	zona, err := s.zonaEvento(ctx, eventoID)
+	if err != nil {
+		return dto.CanjesResponse{}, err
+	}
+	rows, err := s.repo.ListCanjes(ctx, eventoID)
+	if err != nil {
+		return dto.CanjesResponse{}, ErrDB
+	}
+
+	res := dto.CanjesResponse{Resumen: []dto.ResumenCodigoResponse{}, Canjes: make([]dto.CanjeResponse, 0, len(rows))}
+	resumen := map[string]int{}
+	for _, row := range rows {
+		descuento, err := decimal.NewFromString(row.Descuento)
+		if err != nil {
+			return dto.CanjesResponse{}, ErrDB
+		}
+		monto, err := decimal.NewFromString(row.Monto)
+		if err != nil {
+			return dto.CanjesResponse{}, ErrDB
+		}
+		res.Canjes = append(res.Canjes, dto.CanjeResponse{
+			IDInscripcion: row.IDInscripcion,
+			Codigo:        row.Codigo,
+			Lote:          row.Lote,
+			IDUsuario:     row.IDUsuario,
+			Participante:  row.Participante,
+			TipoEntrada:   row.TipoEntrada,
+			Descuento:     descuento,
+			Monto:         monto,
+			Moneda:        row.Moneda,
+			Estado:        row.Estado,
+			Fecha:         domain.FormatoLocal(row.Fecha, zona),
+		})
+		if row.Estado == "Rechazado" || row.Estado == "Cancelado" || row.Estado == "Expirado" {
+			continue
+		}
+		clave := row.Codigo + "|" + row.Moneda
+		i, ok := resumen[clave]
+		if !ok {
+			i = len(res.Resumen)
+			resumen[clave] = i
+			res.Resumen = append(res.Resumen, dto.ResumenCodigoResponse{Codigo: row.Codigo, Moneda: row.Moneda})
+		}
+		res.Resumen[i].Canjes++
+		res.Resumen[i].Descuento = res.Resumen[i].Descuento.Add(descuento)
+	}
+	return res, nil
+}
+
+// aplicarCodigo discounts cot with the event's promo code texto. The limits
+// are checked here for a friendly error and again when the inscription is
+// stored, under the event lock.
+func (s *Service) aplicarCodigo(ctx context.Context, eventoID int, texto string, usuarioID int, cot Cotizacion, now time.Time) (Cotizacion, error) {
+	if cot.IDTipoEntrada == nil || cot.Monto == nil {
+		return Cotizacion{}, ErrCodigoNoAplica
+	}
+	codigo, err := s.repo.FindCodigoPorTexto(ctx, eventoID, texto)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return Cotizacion{}, ErrCodigoNotFound
+		}
+		return Cotizacion{}, ErrDB
+	}
+	if !codigoVigente(codigo, now) {
+		return Cotizacion{}, ErrCodigoVencido
+	}
+	if !codigoAplica(codigo, *cot.IDTipoEntrada, cot.Moneda) {
+		return Cotizacion{}, ErrCodigoNoAplica
+	}
+	if codigo.UsosMaximos != nil && codigo.Usos >= *codigo.UsosMaximos {
+		return Cotizacion{}, ErrCodigoAgotado
+	}
+	if codigo.UsosPorUsuario != nil {
+		usos, err := s.repo.UsosUsuario(ctx, codigo.IDCodigo, usuarioID)
+		if err != nil {
+			return Cotizacion{}, ErrDB
+		}
+		if usos >= *codigo.UsosPorUsuario {
+			return Cotizacion{}, ErrCodigoAgotado
+		}
+	}
+
+	descuento, err := descuentoDe(codigo, *cot.Monto)
+	if err != nil {
+		return Cotizacion{}, ErrDB
+	}
+	monto := cot.Monto.Sub(descuento)
+	id := codigo.IDCodigo
+	cot.IDCodigo = &id
+	cot.Descuento = &descuento
+	cot.Monto = &monto
+	return cot, nil
+}
+
+func (s *Service) prepararCodigo(ctx context.Context, req dto.CodigoPromocionalRequest) (repo.CodigoPromocional, error) {
+	zona, err := s.zonaEvento(ctx, req.IDEvento)
+	if err != nil {
+		return repo.CodigoPromocional{}, err
+	}
+	desde, hasta, err := validation.ParseVigenciaCodigo(req, domain.Zona(zona))
+	if err != nil {
+		return repo.CodigoPromocional{}, fmt.Errorf("%w: %s", ErrVigenciaInvalida, err.Error())
+	}
+	if len(req.TiposEntrada) > 0 {
+		tipos, err := s.repo.List(ctx, req.IDEvento, true)
+		if err != nil {
+			return repo.CodigoPromocional{}, ErrDB
+		}
+		delEvento := make(map[int]bool, len(tipos))
+		for _, t := range tipos {
+			delEvento[t.IDTipoEntrada] = true
+		}
+		for _, id := range req.TiposEntrada {
+			if !delEvento[id] {
+				return repo.CodigoPromocional{}, ErrTiposInvalidos
+			}
+		}
+	}
+	moneda := ""
+	if req.TipoDescuento == dto.DescuentoFijo {
+		moneda = req.Moneda
+	}
+	return repo.CodigoPromocional{
+		TipoDescuento:  req.TipoDescuento,
+		Valor:          req.Valor,
+		Moneda:         moneda,
+		UsosMaximos:    req.UsosMaximos,
+		UsosPorUsuario: req.UsosPorUsuario,
+		ValidoDesde:    desde,
+		ValidoHasta:    hasta,
+		TiposEntrada:   req.TiposEntrada,
+	}, nil
+}
+
+func (s *Service) findCodigo(ctx context.Context, id int) (repo.CodigoRow, error) {
+	row, err := s.repo.FindCodigo(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.CodigoRow{}, ErrCodigoNotFound
+		}
+		return repo.CodigoRow{}, ErrDB
+	}
+	return row, nil
+}
+
+// codigoVigente reports whether the code can be redeemed at now. The window
+// is inclusive at the start and exclusive at the end.
+func codigoVigente(codigo repo.CodigoRow, now time.Time) bool {
+	if !codigo.Activo {
+		return false
+	}
+	if codigo.ValidoDesde != nil && now.Before(*codigo.ValidoDesde) {
+		return false
+	}
+	if codigo.ValidoHasta != nil && !now.Before(*codigo.ValidoHasta) {
+		return false
+	}
+	return true
+}
+
+// codigoAplica reports whether the code covers the ticket type. A fixed
+// discount only applies to tickets sold in its currency.
+func codigoAplica(codigo repo.CodigoRow, tipoID int, moneda string) bool {
+	if codigo.TipoDescuento == dto.DescuentoFijo && (codigo.Moneda == nil || *codigo.Moneda != moneda) {
+		return false
+	}
+	if len(codigo.TiposEntrada) == 0 {
+		return true
+	}
+	for _, id := range codigo.TiposEntrada {
+		if id == tipoID {
+			return true
+		}
+	}
+	return false
+}
+
+// descuentoDe is the amount the code takes off monto, rounded to cents and
+// never more than monto itself.
+func descuentoDe(codigo repo.CodigoRow, monto decimal.Decimal) (decimal.Decimal, error) {
+	valor, err := decimal.NewFromString(codigo.Valor)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	descuento := valor
+	if codigo.TipoDescuento == dto.DescuentoPorcentaje {
+		descuento = monto.Mul(valor).Div(decimal.NewFromInt(100)).Round(2)
+	}
+	if descuento.GreaterThan(monto) {
+		descuento = monto
+	}
+	return descuento, nil
+}
+
+func codigosAleatorios(prefijo string, cantidad int) ([]string, error) {
+	limite := big.NewInt(int64(len(alfabetoCodigos)))
+	codigos := make([]string, 0, cantidad)
+	for len(codigos) < cantidad {
+		var b strings.Builder
+		if prefijo != "" {
+			b.WriteString(prefijo + "-")
+		}
+		for i := 0; i < largoCodigoLote; i++ {
+			n, err := rand.Int(rand.Reader, limite)
+			if err != nil {
+				return nil, err
+			}
+			b.WriteByte(alfabetoCodigos[n.Int64()])
+		}
+		codigos = append(codigos, b.String())
+	}
+	return codigos, nil
+}
+
+func codigoResponse(row repo.CodigoRow, zona string) (dto.CodigoPromocionalResponse, error) {
+	valor, err := decimal.NewFromString(row.Valor)
+	if err != nil {
+		return dto.CodigoPromocionalResponse{}, err
+	}
+	tipos := row.TiposEntrada
+	if tipos == nil {
+		tipos = []int{}
+	}
+	return dto.CodigoPromocionalResponse{
+		ID:             row.IDCodigo,
+		IDEvento:       row.IDEvento,
+		Codigo:         row.Codigo,
+		TipoDescuento:  row.TipoDescuento,
+		Valor:          valor,
+		Moneda:         row.Moneda,
+		UsosMaximos:    row.UsosMaximos,
+		UsosPorUsuario: row.UsosPorUsuario,
+		Usos:           row.Usos,
+		ValidoDesde:    formatoOpcional(row.ValidoDesde, zona),
+		ValidoHasta:    formatoOpcional(row.ValidoHasta, zona),
+		TiposEntrada:   tipos,
+		Lote:           row.Lote,
+		Activo:         row.Activo,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"project/backend/internal/entradas/dto"
+	"project/backend/internal/entradas/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDescuentoDe(t *testing.T) {
+	cases := []struct {
+		name   string
+		codigo repo.CodigoRow
+		monto  string
+		want   string
+	}{
+		{"porcentaje", repo.CodigoRow{TipoDescuento: dto.DescuentoPorcentaje, Valor: "15"}, "20.50", "3.08"},
+		{"porcentaje total", repo.CodigoRow{TipoDescuento: dto.DescuentoPorcentaje, Valor: "100"}, "20.50", "20.5"},
+		{"fijo", repo.CodigoRow{TipoDescuento: dto.DescuentoFijo, Valor: "5"}, "20.50", "5"},
+		{"fijo mayor al monto", repo.CodigoRow{TipoDescuento: dto.DescuentoFijo, Valor: "50"}, "20.50", "20.5"},
+	}
+	for _, c := range cases {
+		got, err := descuentoDe(c.codigo, decimal.RequireFromString(c.monto))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if !got.Equal(decimal.RequireFromString(c.want)) {
+			t.Fatalf("%s: expected %s, got %s", c.name, c.want, got)
+		}
+	}
+}
+
+func TestCodigoAplica(t *testing.T) {
+	usd := "USD"
+	cases := []struct {
+		name   string
+		codigo repo.CodigoRow
+		tipo   int
+		moneda string
+		want   bool
+	}{
+		{"todos los tipos", repo.CodigoRow{TipoDescuento: dto.DescuentoPorcentaje}, 3, "VES", true},
+		{"tipo incluido", repo.CodigoRow{TipoDescuento: dto.DescuentoPorcentaje, TiposEntrada: []int{1, 3}}, 3, "USD", true},
+		{"tipo excluido", repo.CodigoRow{TipoDescuento: dto.DescuentoPorcentaje, TiposEntrada: []int{1}}, 3, "USD", false},
+		{"fijo misma moneda", repo.CodigoRow{TipoDescuento: dto.DescuentoFijo, Moneda: &usd}, 3, "USD", true},
+		{"fijo otra moneda", repo.CodigoRow{TipoDescuento: dto.DescuentoFijo, Moneda: &usd}, 3, "EUR", false},
+	}
+	for _, c := range cases {
+		if got := codigoAplica(c.codigo, c.tipo, c.moneda); got != c.want {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestCodigoVigente(t *testing.T) {
+	desde := time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC)
+	hasta := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+	codigo := repo.CodigoRow{Activo: true, ValidoDesde: &desde, ValidoHasta: &hasta}
+
+	if codigoVigente(codigo, desde.Add(-time.Second)) {
+		t.Fatal("expected code not valid before its window")
+	}
+	if !codigoVigente(codigo, desde) {
+		t.Fatal("expected code valid at the start of its window")
+	}
+	if codigoVigente(codigo, hasta) {
+		t.Fatal("expected code not valid at the end of its window")
+	}
+	codigo.Activo = false
+	if codigoVigente(codigo, desde) {
+		t.Fatal("expected inactive code not valid")
+	}
+}
+
+func TestCodigosAleatorios(t *testing.T) {
+	codigos, err := codigosAleatorios("UCV", 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(codigos) != 50 {
+		t.Fatalf("expected 50 codes, got %d", len(codigos))
+	}
+	vistos := map[string]bool{}
+	for _, c := range codigos {
+		if !strings.HasPrefix(c, "UCV-") || len(c) != len("UCV-")+largoCodigoLote {
+			t.Fatalf("unexpected code %q", c)
+		}
+		if strings.ContainsAny(c[4:], "O0I1") {
+			t.Fatalf("code %q uses ambiguous characters", c)
+		}
+		vistos[c] = true
+	}
+	if len(vistos) != len(codigos) {
+		t.Fatal("expected distinct codes")
+	}
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project/backend/internal/entradas/dto"
+	"project/backend/internal/entradas/repo"
+	"project/backend/internal/entradas/validation"
+	"project/backend/internal/events/domain"
+	"project/backend/prisma/db"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrNotFound        = errors.New("tipo de entrada no encontrado")
+	ErrEventoNotFound  = errors.New("evento no encontrado")
+	ErrNameExists      = errors.New("ya existe un tipo de entrada con ese nombre en el evento")
+	ErrCantidad        = errors.New("la cantidad no puede ser menor a las entradas ya vendidas")
+	ErrRequerida       = errors.New("el evento requiere elegir un tipo de entrada")
+	ErrNoDisponible    = errors.New("el tipo de entrada no está a la venta")
+	ErrSoloPonentes    = errors.New("el tipo de entrada es solo para ponentes del evento")
+	ErrAgotada         = errors.New("no quedan entradas de este tipo")
+	ErrFechasInvalidas = errors.New("fechas de venta inválidas")
+	ErrDB              = errors.New("db error")
+)
+
+type Service struct {
+	repo *repo.Repository
+}
+
+func New(r *repo.Repository) *Service {
+	return &Service{repo: r}
+}
+
+// Cotizacion is the ticket an inscription takes and the amount it owes.
+// IDTipoEntrada and Monto are nil for events that sell no tickets; IDCodigo
+// and Descuento are set when a promo code was redeemed, and Monto is then
+// the amount after the discount.
+type Cotizacion struct {
+	IDTipoEntrada *int
+	Monto         *decimal.Decimal
+	Moneda        string
+	IDCodigo      *int
+	Descuento     *decimal.Decimal
+}
+
+// SinCosto reports whether the chosen ticket is free, so there is nothing
+// left to pay.
+func (c Cotizacion) SinCosto() bool {
+	return c.Monto != nil && c.Monto.IsZero()
+}
+
+// ListTiposEntrada returns the ticket types of an event. Inactive types are
+// only included when todos is set.
+func (s *Service) ListTiposEntrada(ctx context.Context, eventoID int, todos bool, now time.Time) ([]dto.TipoEntradaResponse, error) {
+	zona, err := s.zonaEvento(ctx, eventoID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.List(ctx, eventoID, todos)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make([]dto.TipoEntradaResponse, 0, len(rows))
+	for _, row := range rows {
+		item, err := toResponse(row, zona, now)
+		if err != nil {
+			return nil, ErrDB
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+func (s *Service) GetTipoEntrada(ctx context.Context, id int, now time.Time) (dto.TipoEntradaResponse, error) {
+	row, err := s.findTipo(ctx, id)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, err
+	}
+	zona, err := s.zonaEvento(ctx, row.IDEvento)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, err
+	}
+	res, err := toResponse(row, zona, now)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, ErrDB
+	}
+	return res, nil
+}
+
+func (s *Service) CreateTipoEntrada(ctx context.Context, req dto.TipoEntradaRequest, now time.Time) (dto.TipoEntradaResponse, error) {
+	tipo, err := s.preparar(ctx, req, 0)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, err
+	}
+	id, err := s.repo.Create(ctx, req.IDEvento, tipo)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, ErrDB
+	}
+	return s.GetTipoEntrada(ctx, id, now)
+}
+
+// UpdateTipoEntrada replaces a ticket type. Its event cannot change, and its
+// quantity cannot drop below the tickets already taken. Inscriptions keep
+// the amount they were quoted.
+func (s *Service) UpdateTipoEntrada(ctx context.Context, id int, req dto.TipoEntradaRequest, now time.Time) (dto.TipoEntradaResponse, error) {
+	actual, err := s.findTipo(ctx, id)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, err
+	}
+	if actual.IDEvento != req.IDEvento {
+		return dto.TipoEntradaResponse{}, ErrNotFound
+	}
+	if req.Cantidad != nil && *req.Cantidad < actual.Vendidas {
+		return dto.TipoEntradaResponse{}, ErrCantidad
+	}
+	tipo, err := s.preparar(ctx, req, id)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, err
+	}
+	if err := s.repo.Update(ctx, id, tipo); err != nil {
+		return dto.TipoEntradaResponse{}, ErrDB
+	}
+	return s.GetTipoEntrada(ctx, id, now)
+}
+
+// SetActivo withdraws a ticket type from sale or puts it back on sale.
+func (s *Service) SetActivo(ctx context.Context, id int, activo bool, now time.Time) (dto.TipoEntradaResponse, error) {
+	if _, err := s.findTipo(ctx, id); err != nil {
+		return dto.TipoEntradaResponse{}, err
+	}
+	if err := s.repo.SetActivo(ctx, id, activo); err != nil {
+		return dto.TipoEntradaResponse{}, ErrDB
+	}
+	return s.GetTipoEntrada(ctx, id, now)
+}
+
+// Cotizar checks that the user can buy the ticket type at now and returns
+// the amount due, discounted by the promo code when one is given. Events
+// without active ticket types accept inscriptions without one; otherwise a
+// type is required. The quantity is checked here for a friendly error and
+// again when the inscription is stored, under the event lock.
+func (s *Service) Cotizar(ctx context.Context, eventoID int, tipoID *int, codigo string, usuarioID int, now time.Time) (Cotizacion, error) {
+	rows, err := s.repo.List(ctx, eventoID, false)
+	if err != nil {
+		return Cotizacion{}, ErrDB
+	}
+	if tipoID == nil {
+		if len(rows) > 0 {
+			return Cotizacion{}, ErrRequerida
+		}
+		if strings.TrimSpace(codigo) != "" {
+			return Cotizacion{}, ErrCodigoNoAplica
+		}
+		return Cotizacion{}, nil
+	}
+
+	var tipo *repo.TipoEntradaRow
+	for i := range rows {
+		if rows[i].IDTipoEntrada == *tipoID {
+			tipo = &rows[i]
+			break
+		}
+	}
+	if tipo == nil {
+		return Cotizacion{}, ErrNotFound
+	}
+	if !enVenta(*tipo, now) {
+		return Cotizacion{}, ErrNoDisponible
+	}
+	if tipo.Cantidad != nil && tipo.Vendidas >= *tipo.Cantidad {
+		return Cotizacion{}, ErrAgotada
+	}
+	if tipo.SoloPonentes {
+		ponente, err := s.repo.EsPonente(ctx, eventoID, usuarioID)
+		if err != nil {
+			return Cotizacion{}, ErrDB
+		}
+		if !ponente {
+			return Cotizacion{}, ErrSoloPonentes
+		}
+	}
+	monto, err := precioVigente(*tipo, now)
+	if err != nil {
+		return Cotizacion{}, ErrDB
+	}
+	id := tipo.IDTipoEntrada
+	cot := Cotizacion{IDTipoEntrada: &id, Monto: &monto, Moneda: tipo.Moneda}
+	if strings.TrimSpace(codigo) == "" {
+		return cot, nil
+	}
+	return s.aplicarCodigo(ctx, eventoID, codigo, usuarioID, cot, now)
+}
+
+func (s *Service) preparar(ctx context.Context, req dto.TipoEntradaRequest, excluirID int) (repo.TipoEntrada, error) {
+	zona, err := s.zonaEvento(ctx, req.IDEvento)
+	if err != nil {
+		return repo.TipoEntrada{}, err
+	}
+	fechas, err := validation.ParseFechasEntrada(req, domain.Zona(zona))
+	if err != nil {
+		return repo.TipoEntrada{}, fmt.Errorf("%w: %s", ErrFechasInvalidas, err.Error())
+	}
+	taken, err := s.repo.ExistsNombre(ctx, req.IDEvento, req.Nombre, excluirID)
+	if err != nil {
+		return repo.TipoEntrada{}, ErrDB
+	}
+	if taken {
+		return repo.TipoEntrada{}, ErrNameExists
+	}
+	return repo.TipoEntrada{
+		Nombre:           req.Nombre,
+		Descripcion:      req.Descripcion,
+		Precio:           req.Precio,
+		Moneda:           req.Moneda,
+		Cantidad:         req.Cantidad,
+		VentaDesde:       fechas.VentaDesde,
+		VentaHasta:       fechas.VentaHasta,
+		PrecioAnticipado: req.PrecioAnticipado,
+		AnticipadoHasta:  fechas.AnticipadoHasta,
+		SoloPonentes:     req.SoloPonentes,
+	}, nil
+}
+
+func (s *Service) findTipo(ctx context.Context, id int) (repo.TipoEntradaRow, error) {
+	row, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.TipoEntradaRow{}, ErrNotFound
+		}
+		return repo.TipoEntradaRow{}, ErrDB
+	}
+	return row, nil
+}
+
+func (s *Service) zonaEvento(ctx context.Context, eventoID int) (string, error) {
+	zona, err := s.repo.FindZonaEvento(ctx, eventoID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return "", ErrEventoNotFound
+		}
+		return "", ErrDB
+	}
+	return zona, nil
+}
+
+// enVenta reports whether the ticket type can be bought at now. The sale
+// window is inclusive at the start and exclusive at the end.
+func enVenta(tipo repo.TipoEntradaRow, now time.Time) bool {
+	if !tipo.Activo {
+		return false
+	}
+	if tipo.VentaDesde != nil && now.Before(*tipo.VentaDesde) {
+		return false
+	}
+	if tipo.VentaHasta != nil && !now.Before(*tipo.VentaHasta) {
+		return false
+	}
+	return true
+}
+
+// precioVigente is the early-bird price until its deadline and the regular
+// price afterwards.
+func precioVigente(tipo repo.TipoEntradaRow, now time.Time) (decimal.Decimal, error) {
+	if tipo.PrecioAnticipado != nil && tipo.AnticipadoHasta != nil && now.Before(*tipo.AnticipadoHasta) {
+		return decimal.NewFromString(*tipo.PrecioAnticipado)
+	}
+	return decimal.NewFromString(tipo.Precio)
+}
+
+func toResponse(row repo.TipoEntradaRow, zona string, now time.Time) (dto.TipoEntradaResponse, error) {
+	precio, err := decimal.NewFromString(row.Precio)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, err
+	}
+	actual, err := precioVigente(row, now)
+	if err != nil {
+		return dto.TipoEntradaResponse{}, err
+	}
+	res := dto.TipoEntradaResponse{
+		ID:              row.IDTipoEntrada,
+		IDEvento:        row.IDEvento,
+		Nombre:          row.Nombre,
+		Descripcion:     row.Descripcion,
+		Precio:          precio,
+		AnticipadoHasta: formatoOpcional(row.AnticipadoHasta, zona),
+		PrecioActual:    actual,
+		Moneda:          row.Moneda,
+		Cantidad:        row.Cantidad,
+		Vendidas:        row.Vendidas,
+		VentaDesde:      formatoOpcional(row.VentaDesde, zona),
+		VentaHasta:      formatoOpcional(row.VentaHasta, zona),
+		EnVenta:         enVenta(row, now),
+		SoloPonentes:    row.SoloPonentes,
+		Activo:          row.Activo,
+	}
+	if row.PrecioAnticipado != nil {
+		anticipado, err := decimal.NewFromString(*row.PrecioAnticipado)
+		if err != nil {
+			return dto.TipoEntradaResponse{}, err
+		}
+		res.PrecioAnticipado = &anticipado
+	}
+	if row.Cantidad != nil {
+		disponibles := *row.Cantidad - row.Vendidas
+		if disponibles < 0 {
+			disponibles = 0
+		}
+		res.Disponibles = &disponibles
+		if disponibles == 0 {
+			res.EnVenta = false
+		}
+	}
+	return res, nil
+}
+
+func formatoOpcional(t *time.Time, zona string) *string {
+	if t == nil {
+		return nil
+	}
+	value := domain.FormatoLocal(*t, zona)
+	return &value
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"project/backend/internal/entradas/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestEnVenta(t *testing.T) {
+	desde := time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC)
+	hasta := time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+	tipo := repo.TipoEntradaRow{Activo: true, VentaDesde: &desde, VentaHasta: &hasta}
+
+	cases := []struct {
+		name string
+		tipo repo.TipoEntradaRow
+		now  time.Time
+		want bool
+	}{
+		{"antes de la venta", tipo, desde.Add(-time.Minute), false},
+		{"inicio de la venta", tipo, desde, true},
+		{"fin de la venta", tipo, hasta, false},
+		{"sin ventana", repo.TipoEntradaRow{Activo: true}, hasta, true},
+		{"inactiva", repo.TipoEntradaRow{}, desde, false},
+	}
+	for _, c := range cases {
+		if got := enVenta(c.tipo, c.now); got != c.want {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestPrecioVigente(t *testing.T) {
+	hasta := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+	anticipado := "15.00"
+	tipo := repo.TipoEntradaRow{Precio: "20.50", PrecioAnticipado: &anticipado, AnticipadoHasta: &hasta}
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"anticipado", hasta.Add(-time.Hour), "15"},
+		{"regular", hasta, "20.5"},
+	}
+	for _, c := range cases {
+		got, err := precioVigente(tipo, c.now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if !got.Equal(decimal.RequireFromString(c.want)) {
+			t.Fatalf("%s: expected %s, got %s", c.name, c.want, got)
+		}
+	}
+}
+
+func TestToResponseDisponibles(t *testing.T) {
+	cantidad := 10
+	row := repo.TipoEntradaRow{IDTipoEntrada: 1, Precio: "0", Moneda: "USD", Cantidad: &cantidad, Vendidas: 10, Activo: true}
+
+	res, err := toResponse(row, "UTC", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Disponibles == nil || *res.Disponibles != 0 {
+		t.Fatalf("expected 0 disponibles, got %v", res.Disponibles)
+	}
+	if res.EnVenta {
+		t.Fatal("expected sold out type not to be on sale")
+	}
+}
+
+func TestCotizacionSinCosto(t *testing.T) {
+	cero := decimal.Zero
+	diez := decimal.NewFromInt(10)
+	if (Cotizacion{}).SinCosto() {
+		t.Fatal("expected inscription without ticket to keep its payment status")
+	}
+	if !(Cotizacion{Monto: &cero}).SinCosto() {
+		t.Fatal("expected free ticket to need no payment")
+	}
+	if (Cotizacion{Monto: &diez}).SinCosto() {
+		t.Fatal("expected paid ticket to need payment")
+	}
+}
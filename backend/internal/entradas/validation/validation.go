@@ -0,0 +1,193 @@
+package validation
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"project/backend/internal/entradas/dto"
+	eventvalidation "project/backend/internal/events/validation"
+
+	"github.com/shopspring/decimal"
+)
+
+var monedaPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// precioMaximo is the first value that does not fit in DECIMAL(12,2).
+var precioMaximo = decimal.New(1, 10)
+
+// FechasEntrada are the parsed sale dates of a ticket type; nil means no
+// limit.
+type FechasEntrada struct {
+	VentaDesde      *time.Time
+	VentaHasta      *time.Time
+	AnticipadoHasta *time.Time
+}
+
+func ValidateTipoEntrada(req dto.TipoEntradaRequest) error {
+	if req.IDEvento <= 0 {
+		return errors.New("id_evento es requerido")
+	}
+	nombre := strings.TrimSpace(req.Nombre)
+	if len(nombre) < 3 || len(nombre) > 80 {
+		return errors.New("El nombre del tipo de entrada debe tener entre 3 y 80 caracteres.")
+	}
+	if len(strings.TrimSpace(req.Descripcion)) > 500 {
+		return errors.New("La descripción del tipo de entrada no puede superar 500 caracteres.")
+	}
+	if err := validatePrecio(req.Precio); err != nil {
+		return err
+	}
+	if !monedaPattern.MatchString(strings.ToUpper(strings.TrimSpace(req.Moneda))) {
+		return errors.New("La moneda debe ser un código ISO 4217 de tres letras.")
+	}
+	if req.Cantidad != nil && *req.Cantidad < 1 {
+		return errors.New("La cantidad de entradas debe ser mayor a 0.")
+	}
+	if req.PrecioAnticipado != nil {
+		if err := validatePrecio(*req.PrecioAnticipado); err != nil {
+			return err
+		}
+		if !req.PrecioAnticipado.LessThan(req.Precio) {
+			return errors.New("El precio anticipado debe ser menor al precio regular.")
+		}
+		if strings.TrimSpace(req.AnticipadoHasta) == "" {
+			return errors.New("anticipado_hasta es requerido con un precio anticipado.")
+		}
+	} else if strings.TrimSpace(req.AnticipadoHasta) != "" {
+		return errors.New("anticipado_hasta requiere un precio anticipado.")
+	}
+	return nil
+}
+
+func validatePrecio(precio decimal.Decimal) error {
+	if precio.IsNegative() {
+		return errors.New("El precio no puede ser negativo.")
+	}
+	if !precio.Equal(precio.Round(2)) {
+		return errors.New("El precio admite como máximo dos decimales.")
+	}
+	if !precio.LessThan(precioMaximo) {
+		return errors.New("El precio es demasiado alto.")
+	}
+	return nil
+}
+
+// ParseFechasEntrada reads the sale dates of a ticket type in the event's
+// time zone and checks that the early-bird period ends inside the sale
+// window.
+func ParseFechasEntrada(req dto.TipoEntradaRequest, loc *time.Location) (FechasEntrada, error) {
+	var fechas FechasEntrada
+	var err error
+	if fechas.VentaDesde, err = parseOpcional(req.VentaDesde, loc); err != nil {
+		return FechasEntrada{}, errors.New("venta_desde inválida (formato DD/MM/AAAA o ISO 8601).")
+	}
+	if fechas.VentaHasta, err = parseOpcional(req.VentaHasta, loc); err != nil {
+		return FechasEntrada{}, errors.New("venta_hasta inválida (formato DD/MM/AAAA o ISO 8601).")
+	}
+	if fechas.AnticipadoHasta, err = parseOpcional(req.AnticipadoHasta, loc); err != nil {
+		return FechasEntrada{}, errors.New("anticipado_hasta inválida (formato DD/MM/AAAA o ISO 8601).")
+	}
+	if fechas.VentaDesde != nil && fechas.VentaHasta != nil && !fechas.VentaDesde.Before(*fechas.VentaHasta) {
+		return FechasEntrada{}, errors.New("El inicio de la venta debe ser anterior a su fin.")
+	}
+	if a := fechas.AnticipadoHasta; a != nil {
+		if fechas.VentaDesde != nil && !fechas.VentaDesde.Before(*a) {
+			return FechasEntrada{}, errors.New("El precio anticipado debe terminar después del inicio de la venta.")
+		}
+		if fechas.VentaHasta != nil && a.After(*fechas.VentaHasta) {
+			return FechasEntrada{}, errors.New("El precio anticipado debe terminar antes del fin de la venta.")
+		}
+	}
+	return fechas, nil
+}
+
+func parseOpcional(value string, loc *time.Location) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	t, err := eventvalidation.ParseEventoFecha(value, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+var (
+	codigoPattern  = regexp.MustCompile(`^[A-Z0-9_-]{4,32}$`)
+	prefijoPattern = regexp.MustCompile(`^[A-Z0-9]{0,12}$`)
+)
+
+// MaxLoteCodigos caps how many codes one batch can generate.
+const MaxLoteCodigos = 1000
+
+func ValidateCodigoPromocional(req dto.CodigoPromocionalRequest) error {
+	if !codigoPattern.MatchString(strings.ToUpper(strings.TrimSpace(req.Codigo))) {
+		return errors.New("El código debe tener entre 4 y 32 letras, números, guiones o guiones bajos.")
+	}
+	return validateDescuento(req)
+}
+
+func ValidateLoteCodigos(req dto.LoteCodigosRequest) error {
+	if !prefijoPattern.MatchString(strings.ToUpper(strings.TrimSpace(req.Prefijo))) {
+		return errors.New("El prefijo admite hasta 12 letras o números.")
+	}
+	if req.Cantidad < 1 || req.Cantidad > MaxLoteCodigos {
+		return errors.New("La cantidad de códigos debe estar entre 1 y 1000.")
+	}
+	return validateDescuento(req.CodigoPromocionalRequest)
+}
+
+func validateDescuento(req dto.CodigoPromocionalRequest) error {
+	if req.IDEvento <= 0 {
+		return errors.New("id_evento es requerido")
+	}
+	switch req.TipoDescuento {
+	case dto.DescuentoPorcentaje:
+		if req.Valor.GreaterThan(decimal.NewFromInt(100)) {
+			return errors.New("El porcentaje de descuento no puede superar 100.")
+		}
+	case dto.DescuentoFijo:
+		if !monedaPattern.MatchString(strings.ToUpper(strings.TrimSpace(req.Moneda))) {
+			return errors.New("Un descuento fijo requiere la moneda en código ISO 4217.")
+		}
+	default:
+		return errors.New("tipo_descuento debe ser porcentaje o fijo.")
+	}
+	if !req.Valor.IsPositive() {
+		return errors.New("El descuento debe ser mayor a 0.")
+	}
+	if err := validatePrecio(req.Valor); err != nil {
+		return err
+	}
+	if req.UsosMaximos != nil && *req.UsosMaximos < 1 {
+		return errors.New("usos_maximos debe ser mayor a 0.")
+	}
+	if req.UsosPorUsuario != nil && *req.UsosPorUsuario < 1 {
+		return errors.New("usos_por_usuario debe ser mayor a 0.")
+	}
+	for _, id := range req.TiposEntrada {
+		if id <= 0 {
+			return errors.New("tipos_entrada inválidos")
+		}
+	}
+	return nil
+}
+
+// ParseVigenciaCodigo reads the validity window of a promo code in the
+// event's time zone; nil means no limit.
+func ParseVigenciaCodigo(req dto.CodigoPromocionalRequest, loc *time.Location) (*time.Time, *time.Time, error) {
+	desde, err := parseOpcional(req.ValidoDesde, loc)
+	if err != nil {
+		return nil, nil, errors.New("valido_desde inválida (formato DD/MM/AAAA o ISO 8601).")
+	}
+	hasta, err := parseOpcional(req.ValidoHasta, loc)
+	if err != nil {
+		return nil, nil, errors.New("valido_hasta inválida (formato DD/MM/AAAA o ISO 8601).")
+	}
+	if desde != nil && hasta != nil && !desde.Before(*hasta) {
+		return nil, nil, errors.New("El inicio de la vigencia debe ser anterior a su fin.")
+	}
+	return desde, hasta, nil
+}
@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"project/backend/internal/entradas/dto"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateTipoEntrada(t *testing.T) {
+	cantidad := 100
+	cero := 0
+	anticipado := decimal.RequireFromString("15")
+	caro := decimal.RequireFromString("30")
+	base := func() dto.TipoEntradaRequest {
+		return dto.TipoEntradaRequest{IDEvento: 1, Nombre: "Estudiante", Precio: decimal.RequireFromString("20.50"), Moneda: "usd", Cantidad: &cantidad}
+	}
+	cases := []struct {
+		name    string
+		mutate  func(*dto.TipoEntradaRequest)
+		wantErr bool
+	}{
+		{"valida", func(*dto.TipoEntradaRequest) {}, false},
+		{"gratuita sin limite", func(r *dto.TipoEntradaRequest) { r.Precio = decimal.Zero; r.Cantidad = nil }, false},
+		{"con anticipado", func(r *dto.TipoEntradaRequest) { r.PrecioAnticipado = &anticipado; r.AnticipadoHasta = "01/03/2027" }, false},
+		{"sin evento", func(r *dto.TipoEntradaRequest) { r.IDEvento = 0 }, true},
+		{"nombre corto", func(r *dto.TipoEntradaRequest) { r.Nombre = "VI" }, true},
+		{"precio negativo", func(r *dto.TipoEntradaRequest) { r.Precio = decimal.RequireFromString("-1") }, true},
+		{"tres decimales", func(r *dto.TipoEntradaRequest) { r.Precio = decimal.RequireFromString("10.005") }, true},
+		{"moneda invalida", func(r *dto.TipoEntradaRequest) { r.Moneda = "dolar" }, true},
+		{"cantidad cero", func(r *dto.TipoEntradaRequest) { r.Cantidad = &cero }, true},
+		{"anticipado mas caro", func(r *dto.TipoEntradaRequest) { r.PrecioAnticipado = &caro; r.AnticipadoHasta = "01/03/2027" }, true},
+		{"anticipado sin fecha", func(r *dto.TipoEntradaRequest) { r.PrecioAnticipado = &anticipado }, true},
+		{"fecha sin anticipado", func(r *dto.TipoEntradaRequest) { r.AnticipadoHasta = "01/03/2027" }, true},
+	}
+
+	for _, c := range cases {
+		req := base()
+		c.mutate(&req)
+		err := ValidateTipoEntrada(req)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
+
+func TestParseFechasEntrada(t *testing.T) {
+	loc, err := time.LoadLocation("America/Caracas")
+	if err != nil {
+		t.Skip("zoneinfo no disponible")
+	}
+
+	fechas, err := ParseFechasEntrada(dto.TipoEntradaRequest{VentaDesde: "01/02/2027", VentaHasta: "2027-04-01T00:00:00Z", AnticipadoHasta: "01/03/2027"}, loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2027, 2, 1, 4, 0, 0, 0, time.UTC); !fechas.VentaDesde.Equal(want) {
+		t.Fatalf("expected venta_desde %v, got %v", want, fechas.VentaDesde)
+	}
+
+	vacias, err := ParseFechasEntrada(dto.TipoEntradaRequest{}, loc)
+	if err != nil || vacias.VentaDesde != nil || vacias.VentaHasta != nil || vacias.AnticipadoHasta != nil {
+		t.Fatalf("expected no limits, got %+v (%v)", vacias, err)
+	}
+
+	invalidas := []dto.TipoEntradaRequest{
+		{VentaDesde: "ayer"},
+		{VentaDesde: "01/03/2027", VentaHasta: "01/02/2027"},
+		{VentaDesde: "01/03/2027", AnticipadoHasta: "01/02/2027"},
+		{VentaHasta: "01/03/2027", AnticipadoHasta: "01/04/2027"},
+	}
+	for _, req := range invalidas {
+		if _, err := ParseFechasEntrada(req, loc); err == nil {
+			t.Fatalf("expected error for %+v", req)
+		}
+	}
+}
+
+func TestValidateCodigoPromocional(t *testing.T) {
+	usos := 10
+	cero := 0
+	base := func() dto.CodigoPromocionalRequest {
+		return dto.CodigoPromocionalRequest{IDEvento: 1, Codigo: "aliados-2027", TipoDescuento: dto.DescuentoPorcentaje, Valor: decimal.NewFromInt(20), UsosMaximos: &usos}
+	}
+	cases := []struct {
+		name    string
+		mutate  func(*dto.CodigoPromocionalRequest)
+		wantErr bool
+	}{
+		{"porcentaje", func(*dto.CodigoPromocionalRequest) {}, false},
+		{"fijo", func(r *dto.CodigoPromocionalRequest) {
+			r.TipoDescuento = dto.DescuentoFijo
+			r.Valor = decimal.NewFromInt(150)
+			r.Moneda = "usd"
+		}, false},
+		{"codigo corto", func(r *dto.CodigoPromocionalRequest) { r.Codigo = "AB" }, true},
+		{"codigo con espacios", func(r *dto.CodigoPromocionalRequest) { r.Codigo = "ALIADOS 2027" }, true},
+		{"tipo desconocido", func(r *dto.CodigoPromocionalRequest) { r.TipoDescuento = "regalo" }, true},
+		{"porcentaje mayor a 100", func(r *dto.CodigoPromocionalRequest) { r.Valor = decimal.NewFromInt(120) }, true},
+		{"valor cero", func(r *dto.CodigoPromocionalRequest) { r.Valor = decimal.Zero }, true},
+		{"fijo sin moneda", func(r *dto.CodigoPromocionalRequest) { r.TipoDescuento = dto.DescuentoFijo }, true},
+		{"usos cero", func(r *dto.CodigoPromocionalRequest) { r.UsosMaximos = &cero }, true},
+		{"usos por usuario cero", func(r *dto.CodigoPromocionalRequest) { r.UsosPorUsuario = &cero }, true},
+		{"tipo de entrada invalido", func(r *dto.CodigoPromocionalRequest) { r.TiposEntrada = []int{0} }, true},
+	}
+
+	for _, c := range cases {
+		req := base()
+		c.mutate(&req)
+		err := ValidateCodigoPromocional(req)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
+
+func TestValidateLoteCodigos(t *testing.T) {
+	descuento := dto.CodigoPromocionalRequest{IDEvento: 1, TipoDescuento: dto.DescuentoPorcentaje, Valor: decimal.NewFromInt(50)}
+	cases := []struct {
+		name    string
+		req     dto.LoteCodigosRequest
+		wantErr bool
+	}{
+		{"valido", dto.LoteCodigosRequest{Prefijo: "ucv", Cantidad: 200, CodigoPromocionalRequest: descuento}, false},
+		{"sin prefijo", dto.LoteCodigosRequest{Cantidad: 1, CodigoPromocionalRequest: descuento}, false},
+		{"prefijo invalido", dto.LoteCodigosRequest{Prefijo: "UCV-2027", Cantidad: 10, CodigoPromocionalRequest: descuento}, true},
+		{"sin cantidad", dto.LoteCodigosRequest{Prefijo: "UCV", CodigoPromocionalRequest: descuento}, true},
+		{"demasiados", dto.LoteCodigosRequest{Prefijo: "UCV", Cantidad: MaxLoteCodigos + 1, CodigoPromocionalRequest: descuento}, true},
+	}
+	for _, c := range cases {
+		err := ValidateLoteCodigos(c.req)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"project/backend/internal/events/service"
+	notificationsrepo "project/backend/internal/notifications/repo"
+	"project/backend/prisma/db"
+
+	"github.com/robfig/cron/v3"
+)
+
+func StartEstadosEventoScheduler(prismaClient *db.PrismaClient) {
+	eventService := service.New(prismaClient)
+	jobExecutionRepo := notificationsrepo.NewJobExecutionRepository(prismaClient)
+	jobName := "estados_evento"
+
+	runTransiciones(eventService, jobExecutionRepo, jobName)
+
+	c := cron.New()
+	c.AddFunc("@every 5m", func() {
+		runTransiciones(eventService, jobExecutionRepo, jobName)
+	})
+	c.Start()
+}
+
+func runTransiciones(eventService *service.Service, jobExecutionRepo *notificationsrepo.JobExecutionRepository, jobName string) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	aplicadas, err := eventService.AvanzarEstados(ctx, now)
+	if err != nil {
+		log.Println("[Eventos] Error al actualizar estados de eventos:", err)
+		return
+	}
+	if aplicadas > 0 {
+		log.Println("[Eventos] Transiciones de estado aplicadas:", aplicadas)
+	}
+	archivados, err := eventService.ArchivarFinalizados(ctx, now)
+	if err != nil {
+		log.Println("[Eventos] Error al archivar eventos finalizados:", err)
+		return
+	}
+	if archivados > 0 {
+		log.Println("[Eventos] Eventos archivados:", archivados)
+	}
+	_ = jobExecutionRepo.UpsertLastRun(ctx, jobName, now)
+}
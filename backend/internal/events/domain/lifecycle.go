@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Lifecycle states of an event. New events start as drafts and only become
+// visible to participants once published.
+const (
+	EstadoBorrador   = "Borrador"
+	EstadoPublicado  = "Publicado"
+	EstadoEnCurso    = "En curso"
+	EstadoFinalizado = "Finalizado"
+	EstadoCancelado  = "Cancelado"
+)
+
+var (
+	ErrTransicionInvalida = errors.New("la transición de estado del evento no está permitida")
+	ErrPublicacionVencida = errors.New("no se puede publicar un evento cuya fecha de cierre de inscripción ya pasó")
+	ErrEventoNoIniciado   = errors.New("el evento aún no ha iniciado")
+	ErrEventoNoFinalizado = errors.New("el evento aún no ha finalizado")
+)
+
+// Fechas carries the dates the transition guards depend on.
+type Fechas struct {
+	Inicio time.Time
+	Fin    time.Time
+	Cierre time.Time
+}
+
+var transiciones = map[string][]string{
+	EstadoBorrador:  {EstadoPublicado, EstadoCancelado},
+	EstadoPublicado: {EstadoEnCurso, EstadoCancelado},
+	EstadoEnCurso:   {EstadoFinalizado, EstadoCancelado},
+}
+
+// ValidarTransicion checks that an event in actual can move to destino at
+// now. Finished and cancelled events are terminal.
+func ValidarTransicion(actual, destino string, fechas Fechas, now time.Time) error {
+	if !permitida(actual, destino) {
+		return ErrTransicionInvalida
+	}
+	switch destino {
+	case EstadoPublicado:
+		if !now.Before(fechas.Cierre) || !now.Before(fechas.Inicio) {
+			return ErrPublicacionVencida
+		}
+	case EstadoEnCurso:
+		if now.Before(fechas.Inicio) {
+			return ErrEventoNoIniciado
+		}
+	case EstadoFinalizado:
+		if !now.After(fechas.Fin) {
+			return ErrEventoNoFinalizado
+		}
+	}
+	return nil
+}
+
+// SiguienteAutomatico returns the state the scheduler should move the event
+// to at now, if any. Drafts never advance on their own.
+func SiguienteAutomatico(actual string, fechas Fechas, now time.Time) (string, bool) {
+	switch actual {
+	case EstadoPublicado:
+		if ValidarTransicion(actual, EstadoEnCurso, fechas, now) == nil {
+			return EstadoEnCurso, true
+		}
+	case EstadoEnCurso:
+		if ValidarTransicion(actual, EstadoFinalizado, fechas, now) == nil {
+			return EstadoFinalizado, true
+		}
+	}
+	return "", false
+}
+
+// Solapa reports whether two date ranges share any instant. Ranges that
+// only touch at an end count as overlapping, as the venue check does.
+func (f Fechas) Solapa(o Fechas) bool {
+	return !f.Inicio.After(o.Fin) && !o.Inicio.After(f.Fin)
+}
+
+// EsVisible reports whether participants may see an event in this state.
+func EsVisible(estado string) bool {
+	return estado != EstadoBorrador && estado != EstadoCancelado
+}
+
+// InscripcionesAbiertas reports whether an event takes inscriptions at now:
+// the organizer has not closed them by hand and neither the close date nor
+// the start has been reached.
+func InscripcionesAbiertas(abiertasManual bool, fechas Fechas, now time.Time) bool {
+	return abiertasManual && now.Before(fechas.Cierre) && now.Before(fechas.Inicio)
+}
+
+// AdmiteInscripciones reports whether the state accepts new inscriptions.
+func AdmiteInscripciones(estado string) bool {
+	return estado == EstadoPublicado
+}
+
+func permitida(actual, destino string) bool {
+	for _, estado := range transiciones[actual] {
+		if estado == destino {
+			return true
+		}
+	}
+	return false
+}
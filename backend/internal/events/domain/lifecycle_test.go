@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidarTransicion(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	futuro := Fechas{
+		Cierre: now.AddDate(0, 0, 5),
+		Inicio: now.AddDate(0, 0, 10),
+		Fin:    now.AddDate(0, 0, 12),
+	}
+	enCurso := Fechas{
+		Cierre: now.AddDate(0, 0, -3),
+		Inicio: now.AddDate(0, 0, -1),
+		Fin:    now.AddDate(0, 0, 1),
+	}
+	pasado := Fechas{
+		Cierre: now.AddDate(0, 0, -10),
+		Inicio: now.AddDate(0, 0, -5),
+		Fin:    now.AddDate(0, 0, -2),
+	}
+
+	cases := []struct {
+		name    string
+		actual  string
+		destino string
+		fechas  Fechas
+		want    error
+	}{
+		{"publicar borrador", EstadoBorrador, EstadoPublicado, futuro, nil},
+		{"publicar con cierre vencido", EstadoBorrador, EstadoPublicado, enCurso, ErrPublicacionVencida},
+		{"cancelar borrador", EstadoBorrador, EstadoCancelado, futuro, nil},
+		{"borrador no inicia", EstadoBorrador, EstadoEnCurso, enCurso, ErrTransicionInvalida},
+		{"iniciar antes de fecha", EstadoPublicado, EstadoEnCurso, futuro, ErrEventoNoIniciado},
+		{"iniciar", EstadoPublicado, EstadoEnCurso, enCurso, nil},
+		{"finalizar antes de fecha", EstadoEnCurso, EstadoFinalizado, enCurso, ErrEventoNoFinalizado},
+		{"finalizar", EstadoEnCurso, EstadoFinalizado, pasado, nil},
+		{"cancelar en curso", EstadoEnCurso, EstadoCancelado, enCurso, nil},
+		{"finalizado es terminal", EstadoFinalizado, EstadoCancelado, pasado, ErrTransicionInvalida},
+		{"cancelado es terminal", EstadoCancelado, EstadoPublicado, futuro, ErrTransicionInvalida},
+		{"republicar", EstadoPublicado, EstadoPublicado, futuro, ErrTransicionInvalida},
+	}
+
+	for _, tc := range cases {
+		err := ValidarTransicion(tc.actual, tc.destino, tc.fechas, now)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestSiguienteAutomatico(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	enCurso := Fechas{Cierre: now.AddDate(0, 0, -3), Inicio: now.AddDate(0, 0, -1), Fin: now.AddDate(0, 0, 1)}
+	pasado := Fechas{Cierre: now.AddDate(0, 0, -10), Inicio: now.AddDate(0, 0, -5), Fin: now.AddDate(0, 0, -2)}
+
+	if estado, ok := SiguienteAutomatico(EstadoPublicado, enCurso, now); !ok || estado != EstadoEnCurso {
+		t.Fatalf("expected %q, got %q (%v)", EstadoEnCurso, estado, ok)
+	}
+	if estado, ok := SiguienteAutomatico(EstadoEnCurso, pasado, now); !ok || estado != EstadoFinalizado {
+		t.Fatalf("expected %q, got %q (%v)", EstadoFinalizado, estado, ok)
+	}
+	if _, ok := SiguienteAutomatico(EstadoEnCurso, enCurso, now); ok {
+		t.Fatal("ongoing event should not finish before its end date")
+	}
+	if _, ok := SiguienteAutomatico(EstadoBorrador, pasado, now); ok {
+		t.Fatal("drafts should never advance automatically")
+	}
+}
+
+func TestVisibilidad(t *testing.T) {
+	if EsVisible(EstadoBorrador) || EsVisible(EstadoCancelado) {
+		t.Fatal("drafts and cancelled events should be hidden")
+	}
+	if !EsVisible(EstadoPublicado) || !EsVisible(EstadoEnCurso) || !EsVisible(EstadoFinalizado) {
+		t.Fatal("published, ongoing and finished events should be visible")
+	}
+	if !AdmiteInscripciones(EstadoPublicado) || AdmiteInscripciones(EstadoEnCurso) || AdmiteInscripciones(EstadoBorrador) {
+		t.Fatal("only published events accept inscriptions")
+	}
+}
+
+func TestFechasSolapa(t *testing.T) {
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	a := Fechas{Inicio: base, Fin: base.Add(8 * time.Hour)}
+	cases := []struct {
+		name string
+		b    Fechas
+		want bool
+	}{
+		{"contenido", Fechas{Inicio: base.Add(time.Hour), Fin: base.Add(2 * time.Hour)}, true},
+		{"cruza el inicio", Fechas{Inicio: base.Add(-time.Hour), Fin: base.Add(time.Hour)}, true},
+		{"toca el fin", Fechas{Inicio: base.Add(8 * time.Hour), Fin: base.Add(10 * time.Hour)}, true},
+		{"despues", Fechas{Inicio: base.Add(9 * time.Hour), Fin: base.Add(10 * time.Hour)}, false},
+		{"antes", Fechas{Inicio: base.Add(-3 * time.Hour), Fin: base.Add(-time.Hour)}, false},
+	}
+	for _, tc := range cases {
+		if got := a.Solapa(tc.b); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+		if got := tc.b.Solapa(a); got != tc.want {
+			t.Errorf("%s (inverso): expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlComentario = regexp.MustCompile(`(?s)<!--.*?-->`)
+	// Any HTML tag, including autolinks such as <javascript:...>; plain
+	// text like "a < b" or "<3" is left alone.
+	htmlEtiqueta = regexp.MustCompile(`</?[A-Za-z][^<>]*>`)
+	// Inline links and images: [texto](destino "título").
+	enlaceMarkdown = regexp.MustCompile(`(!?\[[^\]]*\])\(\s*<?([^\s)>]*)>?([^)]*)\)`)
+	// Reference definitions: [id]: destino.
+	referenciaMarkdown = regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:\s*)<?(\S*?)>?(\s.*)?$`)
+)
+
+// SanitizarMarkdown makes a user-written description safe to render as
+// Markdown: raw HTML is removed and link or image destinations other than
+// http, https, mailto or relative ones are replaced with "#".
+func SanitizarMarkdown(texto string) string {
+	texto = strings.ReplaceAll(texto, "\r\n", "\n")
+	texto = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, texto)
+
+	texto = htmlComentario.ReplaceAllString(texto, "")
+	// Removing a tag can join the halves of another one, so repeat.
+	for {
+		limpio := htmlEtiqueta.ReplaceAllString(texto, "")
+		if limpio == texto {
+			break
+		}
+		texto = limpio
+	}
+
+	texto = enlaceMarkdown.ReplaceAllStringFunc(texto, func(m string) string {
+		partes := enlaceMarkdown.FindStringSubmatch(m)
+		if destinoSeguro(partes[2]) {
+			return m
+		}
+		return partes[1] + "(#)"
+	})
+	texto = referenciaMarkdown.ReplaceAllStringFunc(texto, func(m string) string {
+		partes := referenciaMarkdown.FindStringSubmatch(m)
+		if destinoSeguro(partes[2]) {
+			return m
+		}
+		return partes[1] + "#"
+	})
+	return strings.TrimSpace(texto)
+}
+
+func destinoSeguro(destino string) bool {
+	d := strings.ToLower(strings.TrimSpace(destino))
+	// Renderers decode entities in destinations, so "javascript&colon;"
+	// would still run.
+	if strings.ContainsAny(d, "&\\") {
+		return false
+	}
+	// Without a scheme the destination is relative to the page.
+	if i := strings.IndexAny(d, ":/?#"); i < 0 || d[i] != ':' {
+		return true
+	}
+	for _, esquema := range []string{"http:", "https:", "mailto:"} {
+		if strings.HasPrefix(d, esquema) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "testing"
+
+func TestSanitizarMarkdown(t *testing.T) {
+	cases := []struct {
+		entrada string
+		want    string
+	}{
+		{"# Congreso\r\n\r\nTexto **importante**.", "# Congreso\n\nTexto **importante**."},
+		{"Hola <script>alert(1)</script>mundo", "Hola alert(1)mundo"},
+		{"<scr<script>ipt>x", "x"},
+		{"a < b y <3 <!-- oculto -->", "a < b y <3"},
+		{"[programa](https://example.com/p.pdf)", "[programa](https://example.com/p.pdf)"},
+		{"[sala](/sedes/3) y [arriba](#inicio)", "[sala](/sedes/3) y [arriba](#inicio)"},
+		{"[clic](javascript:alert(1))", "[clic](#))"},
+		{"![x](JaVaScRiPt:alert(1) \"t\")", "![x](#) \"t\")"},
+		{"[x](javascript&colon;alert)", "[x](#)"},
+		{"[x](data:text/html;base64,PHA+)", "[x](#)"},
+		{"Ver [doc][1]\n\n[1]: vbscript:msgbox", "Ver [doc][1]\n\n[1]: #"},
+		{"<javascript:alert(1)>", ""},
+	}
+
+	for _, c := range cases {
+		if got := SanitizarMarkdown(c.entrada); got != c.want {
+			t.Fatalf("SanitizarMarkdown(%q) = %q, want %q", c.entrada, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported recurrence frequencies, a subset of RFC 5545 RRULE.
+const (
+	FrecuenciaSemanal = "WEEKLY"
+	FrecuenciaMensual = "MONTHLY"
+)
+
+// MaxOcurrencias bounds how many events a single series may generate.
+const MaxOcurrencias = 104
+
+var (
+	ErrReglaInvalida         = errors.New("regla de recurrencia inválida")
+	ErrExcepcionInvalida     = errors.New("fecha de excepción inválida (formato AAAA-MM-DD)")
+	ErrDemasiadasOcurrencias = fmt.Errorf("la serie no puede generar más de %d ocurrencias", MaxOcurrencias)
+	ErrSerieSinOcurrencias   = errors.New("la regla no genera ninguna ocurrencia")
+)
+
+// Regla is a parsed recurrence rule: FREQ=WEEKLY|MONTHLY with optional
+// INTERVAL and exactly one of COUNT or UNTIL.
+type Regla struct {
+	Frecuencia string
+	Intervalo  int
+	Conteo     int
+	Hasta      time.Time
+}
+
+// ParseRegla reads an RRULE such as "FREQ=MONTHLY;INTERVAL=1;COUNT=6". An
+// UNTIL without time means the end of that day in loc.
+func ParseRegla(texto string, loc *time.Location) (Regla, error) {
+	regla := Regla{Intervalo: 1}
+	texto = strings.TrimPrefix(strings.TrimSpace(texto), "RRULE:")
+	if texto == "" {
+		return Regla{}, ErrReglaInvalida
+	}
+	for _, parte := range strings.Split(texto, ";") {
+		clave, valor, ok := strings.Cut(parte, "=")
+		if !ok {
+			return Regla{}, fmt.Errorf("%w: %q", ErrReglaInvalida, parte)
+		}
+		valor = strings.TrimSpace(valor)
+		switch strings.ToUpper(strings.TrimSpace(clave)) {
+		case "FREQ":
+			regla.Frecuencia = strings.ToUpper(valor)
+		case "INTERVAL":
+			n, err := strconv.Atoi(valor)
+			if err != nil || n < 1 || n > 12 {
+				return Regla{}, fmt.Errorf("%w: INTERVAL debe estar entre 1 y 12", ErrReglaInvalida)
+			}
+			regla.Intervalo = n
+		case "COUNT":
+			n, err := strconv.Atoi(valor)
+			if err != nil || n < 1 || n > MaxOcurrencias {
+				return Regla{}, fmt.Errorf("%w: COUNT debe estar entre 1 y %d", ErrReglaInvalida, MaxOcurrencias)
+			}
+			regla.Conteo = n
+		case "UNTIL":
+			hasta, err := parseHasta(valor, loc)
+			if err != nil {
+				return Regla{}, fmt.Errorf("%w: UNTIL inválido", ErrReglaInvalida)
+			}
+			regla.Hasta = hasta
+		default:
+			return Regla{}, fmt.Errorf("%w: %s no está soportado", ErrReglaInvalida, clave)
+		}
+	}
+	if regla.Frecuencia != FrecuenciaSemanal && regla.Frecuencia != FrecuenciaMensual {
+		return Regla{}, fmt.Errorf("%w: FREQ debe ser WEEKLY o MONTHLY", ErrReglaInvalida)
+	}
+	if (regla.Conteo == 0) == regla.Hasta.IsZero() {
+		return Regla{}, fmt.Errorf("%w: indique COUNT o UNTIL", ErrReglaInvalida)
+	}
+	return regla, nil
+}
+
+func parseHasta(valor string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", valor); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", valor, loc); err == nil {
+		return t, nil
+	}
+	dia, err := time.ParseInLocation("20060102", valor, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dia.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+// ParseExcepciones reads dates in AAAA-MM-DD form; occurrences starting on
+// those days (in loc) are skipped.
+func ParseExcepciones(valores []string, loc *time.Location) (map[string]bool, error) {
+	res := make(map[string]bool, len(valores))
+	for _, valor := range valores {
+		dia, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(valor), loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrExcepcionInvalida, valor)
+		}
+		res[dia.Format("2006-01-02")] = true
+	}
+	return res, nil
+}
+
+// Ocurrencias expands the rule from the first occurrence. Every occurrence
+// keeps the wall-clock time of the first one in loc, so daylight saving
+// changes do not move it, and the same duration and close-date lead time.
+// As in RFC 5545, monthly dates that do not exist (e.g. the 31st) are
+// skipped and COUNT is applied before exceptions are removed.
+func (r Regla) Ocurrencias(base Fechas, excepciones map[string]bool, loc *time.Location) ([]Fechas, error) {
+	inicio := base.Inicio.In(loc)
+	duracion := base.Fin.Sub(base.Inicio)
+	antelacion := base.Inicio.Sub(base.Cierre)
+
+	res := []Fechas{}
+	generadas := 0
+	for k := 0; generadas < MaxOcurrencias*2; k++ {
+		var candidato time.Time
+		if r.Frecuencia == FrecuenciaSemanal {
+			candidato = time.Date(inicio.Year(), inicio.Month(), inicio.Day()+7*k*r.Intervalo,
+				inicio.Hour(), inicio.Minute(), inicio.Second(), 0, loc)
+		} else {
+			candidato = time.Date(inicio.Year(), inicio.Month()+time.Month(k*r.Intervalo), inicio.Day(),
+				inicio.Hour(), inicio.Minute(), inicio.Second(), 0, loc)
+			if candidato.Day() != inicio.Day() {
+				continue
+			}
+		}
+		if !r.Hasta.IsZero() && candidato.After(r.Hasta) {
+			break
+		}
+		if r.Conteo > 0 && generadas == r.Conteo {
+			break
+		}
+		generadas++
+		if excepciones[candidato.Format("2006-01-02")] {
+			continue
+		}
+		if len(res) == MaxOcurrencias {
+			return nil, ErrDemasiadasOcurrencias
+		}
+		res = append(res, Fechas{
+			Inicio: candidato,
+			Fin:    candidato.Add(duracion),
+			Cierre: candidato.Add(-antelacion),
+		})
+	}
+	if len(res) == 0 {
+		return nil, ErrSerieSinOcurrencias
+	}
+	return res, nil
+}
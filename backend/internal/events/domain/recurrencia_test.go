@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRegla(t *testing.T) {
+	cases := []struct {
+		regla   string
+		wantErr bool
+	}{
+		{"FREQ=MONTHLY;COUNT=6", false},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=20261231", false},
+		{"FREQ=WEEKLY;UNTIL=20261231T235959Z", false},
+		{"FREQ=DAILY;COUNT=3", true},
+		{"FREQ=MONTHLY", true},
+		{"FREQ=MONTHLY;COUNT=3;UNTIL=20261231", true},
+		{"FREQ=MONTHLY;COUNT=0", true},
+		{"FREQ=MONTHLY;BYDAY=MO;COUNT=2", true},
+		{"", true},
+	}
+
+	for _, c := range cases {
+		_, err := ParseRegla(c.regla, time.UTC)
+		if c.wantErr && err == nil {
+			t.Fatalf("expected error for %q", c.regla)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("unexpected error for %q: %v", c.regla, err)
+		}
+	}
+}
+
+func TestOcurrencias(t *testing.T) {
+	caracas := Zona("America/Caracas")
+	base := Fechas{
+		Inicio: time.Date(2026, 1, 31, 9, 0, 0, 0, caracas),
+		Fin:    time.Date(2026, 1, 31, 13, 0, 0, 0, caracas),
+		Cierre: time.Date(2026, 1, 29, 9, 0, 0, 0, caracas),
+	}
+
+	t.Run("monthly skips missing days and exceptions", func(t *testing.T) {
+		regla, err := ParseRegla("FREQ=MONTHLY;COUNT=4", caracas)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		excepciones, _ := ParseExcepciones([]string{"2026-05-31"}, caracas)
+
+		got, err := regla.Ocurrencias(base, excepciones, caracas)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Jan 31, Mar 31, May 31 (excluded), Jul 31.
+		want := []string{"2026-01-31", "2026-03-31", "2026-07-31"}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d occurrences, got %d", len(want), len(got))
+		}
+		for i, o := range got {
+			if o.Inicio.Format("2006-01-02") != want[i] {
+				t.Fatalf("occurrence %d: expected %s, got %s", i, want[i], o.Inicio.Format("2006-01-02"))
+			}
+			if o.Inicio.Hour() != 9 || o.Fin.Sub(o.Inicio) != 4*time.Hour || o.Inicio.Sub(o.Cierre) != 48*time.Hour {
+				t.Fatalf("occurrence %d does not keep the base schedule: %+v", i, o)
+			}
+		}
+	})
+
+	t.Run("weekly until keeps wall clock across DST", func(t *testing.T) {
+		madrid := Zona("Europe/Madrid")
+		inicio := time.Date(2026, 3, 22, 10, 0, 0, 0, madrid)
+		regla, err := ParseRegla("FREQ=WEEKLY;UNTIL=20260405", madrid)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := regla.Ocurrencias(Fechas{Inicio: inicio, Fin: inicio.Add(time.Hour), Cierre: inicio.Add(-time.Hour)}, nil, madrid)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("expected 3 occurrences, got %d", len(got))
+		}
+		for _, o := range got {
+			if o.Inicio.In(madrid).Hour() != 10 {
+				t.Fatalf("expected 10:00 local, got %s", o.Inicio.In(madrid))
+			}
+		}
+	})
+
+	t.Run("every occurrence excluded", func(t *testing.T) {
+		regla, _ := ParseRegla("FREQ=MONTHLY;COUNT=1", caracas)
+		excepciones, _ := ParseExcepciones([]string{"2026-01-31"}, caracas)
+		if _, err := regla.Ocurrencias(base, excepciones, caracas); !errors.Is(err, ErrSerieSinOcurrencias) {
+			t.Fatalf("expected ErrSerieSinOcurrencias, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Answers a participant gives to a reschedule. Every inscription starts as
+// pending; a newer reschedule of the same event supersedes pending answers.
+const (
+	RespuestaPendiente   = "Pendiente"
+	RespuestaConfirmada  = "Confirmada"
+	RespuestaCancelada   = "Cancelada"
+	RespuestaReemplazada = "Reemplazada"
+)
+
+var (
+	ErrRespuestaInvalida = errors.New("la respuesta debe ser Confirmada o Cancelada")
+	ErrRespuestaCerrada  = errors.New("esta reprogramación ya no admite respuestas")
+)
+
+// ValidarRespuesta checks that a participant whose current answer is actual
+// can answer nueva at now, with the event starting at inicio. A confirmed
+// attendance can still be cancelled, but a cancellation is final and
+// answers close once the event starts.
+func ValidarRespuesta(actual, nueva string, inicio, now time.Time) error {
+	if nueva != RespuestaConfirmada && nueva != RespuestaCancelada {
+		return ErrRespuestaInvalida
+	}
+	if actual != RespuestaPendiente && actual != RespuestaConfirmada {
+		return ErrRespuestaCerrada
+	}
+	if !now.Before(inicio) {
+		return ErrRespuestaCerrada
+	}
+	return nil
+}
+
+// DesplazarFechas moves every date so the event starts at inicio, keeping
+// the wall-clock distances between them in loc, and returns the offset it
+// applied.
+func DesplazarFechas(f Fechas, inicio time.Time, loc *time.Location) (Fechas, time.Duration) {
+	offset := DiferenciaLocal(f.Inicio, inicio, loc)
+	return Fechas{
+		Inicio: Desplazar(f.Inicio, offset, loc),
+		Fin:    Desplazar(f.Fin, offset, loc),
+		Cierre: Desplazar(f.Cierre, offset, loc),
+	}, offset
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidarRespuesta(t *testing.T) {
+	now := time.Date(2026, 4, 9, 12, 0, 0, 0, time.UTC)
+	inicio := now.AddDate(0, 0, 7)
+
+	cases := []struct {
+		name   string
+		actual string
+		nueva  string
+		inicio time.Time
+		want   error
+	}{
+		{"confirmar pendiente", RespuestaPendiente, RespuestaConfirmada, inicio, nil},
+		{"cancelar pendiente", RespuestaPendiente, RespuestaCancelada, inicio, nil},
+		{"cancelar confirmada", RespuestaConfirmada, RespuestaCancelada, inicio, nil},
+		{"reconfirmar", RespuestaConfirmada, RespuestaConfirmada, inicio, nil},
+		{"respuesta desconocida", RespuestaPendiente, "Quizás", inicio, ErrRespuestaInvalida},
+		{"pendiente no es respuesta", RespuestaPendiente, RespuestaPendiente, inicio, ErrRespuestaInvalida},
+		{"cancelada es definitiva", RespuestaCancelada, RespuestaConfirmada, inicio, ErrRespuestaCerrada},
+		{"reemplazada", RespuestaReemplazada, RespuestaConfirmada, inicio, ErrRespuestaCerrada},
+		{"evento iniciado", RespuestaPendiente, RespuestaCancelada, now, ErrRespuestaCerrada},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidarRespuesta(tc.actual, tc.nueva, tc.inicio, now)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestDesplazarFechas(t *testing.T) {
+	loc := Zona("Europe/Madrid")
+	// The event moves across the start of summer time: local hours stay.
+	actual := Fechas{
+		Cierre: time.Date(2026, 3, 20, 18, 0, 0, 0, loc),
+		Inicio: time.Date(2026, 3, 24, 10, 0, 0, 0, loc),
+		Fin:    time.Date(2026, 3, 25, 17, 30, 0, 0, loc),
+	}
+	inicio := time.Date(2026, 4, 7, 10, 0, 0, 0, loc)
+
+	got, offset := DesplazarFechas(actual, inicio, loc)
+
+	if offset != 14*24*time.Hour {
+		t.Fatalf("expected a 14 day offset, got %v", offset)
+	}
+	want := Fechas{
+		Cierre: time.Date(2026, 4, 3, 18, 0, 0, 0, loc),
+		Inicio: inicio,
+		Fin:    time.Date(2026, 4, 8, 17, 30, 0, 0, loc),
+	}
+	if !got.Inicio.Equal(want.Inicio) || !got.Fin.Equal(want.Fin) || !got.Cierre.Equal(want.Cierre) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Actions recorded in the version history of an event.
+const (
+	AccionCreado                = "creado"
+	AccionActualizado           = "actualizado"
+	AccionPublicado             = "publicado"
+	AccionCancelado             = "cancelado"
+	AccionEstado                = "estado"
+	AccionInscripcionesAbiertas = "inscripciones_abiertas"
+	AccionInscripcionesCerradas = "inscripciones_cerradas"
+	AccionRestaurado            = "restaurado"
+	AccionReprogramado          = "reprogramado"
+	AccionArchivado             = "archivado"
+)
+
+// Cambio is a field that differs between two snapshots. A field missing
+// from one side is null there.
+type Cambio struct {
+	Campo    string          `json:"campo"`
+	Anterior json.RawMessage `json:"anterior"`
+	Nuevo    json.RawMessage `json:"nuevo"`
+}
+
+var jsonNull = json.RawMessage("null")
+
+// Diferencias compares two snapshots encoded as JSON objects field by field
+// and returns the changed fields sorted by name. An empty anterior means
+// there is no earlier version, so every field of nuevo counts as changed.
+func Diferencias(anterior, nuevo []byte) ([]Cambio, error) {
+	a := map[string]json.RawMessage{}
+	if len(anterior) > 0 {
+		if err := json.Unmarshal(anterior, &a); err != nil {
+			return nil, err
+		}
+	}
+	b := map[string]json.RawMessage{}
+	if err := json.Unmarshal(nuevo, &b); err != nil {
+		return nil, err
+	}
+
+	campos := make([]string, 0, len(a)+len(b))
+	for campo := range a {
+		campos = append(campos, campo)
+	}
+	for campo := range b {
+		if _, ok := a[campo]; !ok {
+			campos = append(campos, campo)
+		}
+	}
+	sort.Strings(campos)
+
+	cambios := []Cambio{}
+	for _, campo := range campos {
+		va, vb := valorJSON(a[campo]), valorJSON(b[campo])
+		if bytes.Equal(va, vb) {
+			continue
+		}
+		cambios = append(cambios, Cambio{Campo: campo, Anterior: va, Nuevo: vb})
+	}
+	return cambios, nil
+}
+
+// valorJSON compacts a value so formatting differences are not reported as
+// changes.
+func valorJSON(v json.RawMessage) json.RawMessage {
+	if len(v) == 0 {
+		return jsonNull
+	}
+	var b bytes.Buffer
+	if err := json.Compact(&b, v); err != nil {
+		return v
+	}
+	return b.Bytes()
+}
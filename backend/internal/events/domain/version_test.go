@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiferencias(t *testing.T) {
+	cases := []struct {
+		name     string
+		anterior string
+		nuevo    string
+		want     []Cambio
+	}{
+		{
+			name:     "sin cambios",
+			anterior: `{"nombre":"Congreso","categorias":["ciencia"]}`,
+			nuevo:    `{"categorias": ["ciencia"], "nombre": "Congreso"}`,
+			want:     []Cambio{},
+		},
+		{
+			name:     "campos modificados",
+			anterior: `{"nombre":"Congreso","ubicacion":"Caracas","id_sede":null}`,
+			nuevo:    `{"nombre":"Congreso 2027","ubicacion":"Caracas","id_sede":3}`,
+			want: []Cambio{
+				{Campo: "id_sede", Anterior: json.RawMessage(`null`), Nuevo: json.RawMessage(`3`)},
+				{Campo: "nombre", Anterior: json.RawMessage(`"Congreso"`), Nuevo: json.RawMessage(`"Congreso 2027"`)},
+			},
+		},
+		{
+			name:  "primera version",
+			nuevo: `{"nombre":"Congreso"}`,
+			want: []Cambio{
+				{Campo: "nombre", Anterior: json.RawMessage(`null`), Nuevo: json.RawMessage(`"Congreso"`)},
+			},
+		},
+		{
+			name:     "campo nuevo en el snapshot",
+			anterior: `{"nombre":"Congreso"}`,
+			nuevo:    `{"nombre":"Congreso","estado":"Publicado"}`,
+			want: []Cambio{
+				{Campo: "estado", Anterior: json.RawMessage(`null`), Nuevo: json.RawMessage(`"Publicado"`)},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Diferencias([]byte(tc.anterior), []byte(tc.nuevo))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tc.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Fatalf("expected %s, got %s", wantJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestDiferenciasInvalido(t *testing.T) {
+	if _, err := Diferencias(nil, []byte(`[1]`)); err == nil {
+		t.Fatal("expected error for a snapshot that is not an object")
+	}
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ZonaHorariaPredeterminada is used for events created without an explicit
+// time zone and for rows stored before events carried one.
+const ZonaHorariaPredeterminada = "America/Caracas"
+
+// FormatoFechaHora is how event instants are shown to people, always in the
+// event's local time.
+const FormatoFechaHora = "02/01/2006 15:04"
+
+// FormatoFecha is the day-only layout event listings have always used and
+// clients parse.
+const FormatoFecha = "02/01/2006"
+
+var ErrZonaHorariaInvalida = errors.New("zona horaria inválida, use un identificador IANA como America/Caracas")
+
+// CargarZona resolves an IANA time zone name. An empty name means the
+// default zone. "Local" is rejected because it depends on the server.
+func CargarZona(nombre string) (*time.Location, error) {
+	if nombre == "" {
+		nombre = ZonaHorariaPredeterminada
+	}
+	if nombre == "Local" {
+		return nil, ErrZonaHorariaInvalida
+	}
+	loc, err := time.LoadLocation(nombre)
+	if err != nil {
+		return nil, ErrZonaHorariaInvalida
+	}
+	return loc, nil
+}
+
+// Zona is CargarZona for names already stored; an unknown zone falls back to
+// the default so dates can still be shown.
+func Zona(nombre string) *time.Location {
+	loc, err := CargarZona(nombre)
+	if err != nil {
+		loc, err = CargarZona(ZonaHorariaPredeterminada)
+		if err != nil {
+			return time.UTC
+		}
+	}
+	return loc
+}
+
+// FormatoLocal formats t in the event's time zone.
+func FormatoLocal(t time.Time, zona string) string {
+	return t.In(Zona(zona)).Format(FormatoFechaHora)
+}
+
+// FechaLocal formats the day of t in the event's time zone.
+func FechaLocal(t time.Time, zona string) string {
+	return t.In(Zona(zona)).Format(FormatoFecha)
+}
+
+// InstanteLocal formats t as RFC 3339 with the offset of the event's time
+// zone, for clients that need the time of day.
+func InstanteLocal(t time.Time, zona string) string {
+	return t.In(Zona(zona)).Format(time.RFC3339)
+}
+
+// FormatoConZona formats t in the event's time zone and names the zone, for
+// messages read outside the app such as notifications and emails.
+func FormatoConZona(t time.Time, zona string) string {
+	loc := Zona(zona)
+	return t.In(loc).Format(FormatoFechaHora) + " (" + loc.String() + ")"
+}
+
+// DiferenciaLocal is the wall-clock distance from a to b in loc, ignoring
+// daylight saving jumps in between.
+func DiferenciaLocal(a, b time.Time, loc *time.Location) time.Duration {
+	return reloj(b, loc).Sub(reloj(a, loc))
+}
+
+// Desplazar moves t by offset on the wall clock of loc, so 10:00 stays
+// 10:00 even if a daylight saving change falls in between.
+func Desplazar(t time.Time, offset time.Duration, loc *time.Location) time.Time {
+	w := reloj(t, loc).Add(offset)
+	return time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), loc)
+}
+
+// reloj returns the wall-clock reading of t in loc as a UTC time, which has
+// no daylight saving and can be used for plain arithmetic.
+func reloj(t time.Time, loc *time.Location) time.Time {
+	l := t.In(loc)
+	return time.Date(l.Year(), l.Month(), l.Day(), l.Hour(), l.Minute(), l.Second(), l.Nanosecond(), time.UTC)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCargarZona(t *testing.T) {
+	cases := []struct {
+		nombre  string
+		want    string
+		wantErr bool
+	}{
+		{"", ZonaHorariaPredeterminada, false},
+		{"Europe/Madrid", "Europe/Madrid", false},
+		{"UTC", "UTC", false},
+		{"Local", "", true},
+		{"Marte/Olympus", "", true},
+	}
+
+	for _, c := range cases {
+		loc, err := CargarZona(c.nombre)
+		if c.wantErr {
+			if err == nil {
+				t.Fatalf("expected error for %q", c.nombre)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", c.nombre, err)
+		}
+		if loc.String() != c.want {
+			t.Fatalf("expected %s, got %s", c.want, loc)
+		}
+	}
+}
+
+func TestFormatoLocal(t *testing.T) {
+	// 03:30 UTC is still the previous day in Caracas (UTC-4).
+	instante := time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC)
+
+	if got := FormatoLocal(instante, "America/Caracas"); got != "09/03/2026 23:30" {
+		t.Fatalf("unexpected Caracas time: %s", got)
+	}
+	if got := FormatoLocal(instante, "Asia/Tokyo"); got != "10/03/2026 12:30" {
+		t.Fatalf("unexpected Tokyo time: %s", got)
+	}
+	if got := FormatoConZona(instante, "Zona/Rota"); got != "09/03/2026 23:30 (America/Caracas)" {
+		t.Fatalf("expected fallback to default zone, got %s", got)
+	}
+}
+
+func TestDesplazar(t *testing.T) {
+	madrid := Zona("Europe/Madrid")
+	// One week later crosses the change to summer time on 29/03/2026.
+	antes := time.Date(2026, 3, 24, 10, 0, 0, 0, madrid)
+	despues := time.Date(2026, 3, 31, 10, 0, 0, 0, madrid)
+
+	offset := DiferenciaLocal(antes, despues, madrid)
+	if offset != 7*24*time.Hour {
+		t.Fatalf("expected a wall-clock week, got %s", offset)
+	}
+
+	sesion := time.Date(2026, 3, 24, 16, 30, 0, 0, madrid)
+	got := Desplazar(sesion, offset, madrid)
+	if got.Hour() != 16 || got.Minute() != 30 || got.Day() != 31 {
+		t.Fatalf("expected 31/03 16:30 local, got %s", got)
+	}
+}
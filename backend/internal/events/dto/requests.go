@@ -1,20 +1,196 @@
 package dto
 
-// CreateEventoRequest represents the payload to create a new event.
+import (
+	"encoding/json"
+
+	"project/backend/internal/events/domain"
+)
+
+// CreateEventoRequest represents the payload to create a new event. Dates
+// accept DD/MM/AAAA, DD/MM/AAAA HH:MM or ISO 8601; those without offset are
+// read in ZonaHoraria, an IANA name that defaults to America/Caracas.
 type CreateEventoRequest struct {
-	Nombre                 string `json:"nombre"`
-	FechaInicio            string `json:"fecha_inicio"`
-	FechaFin               string `json:"fecha_fin"`
-	FechaCierreInscripcion string `json:"fecha_cierre_inscripcion"`
-	Ubicacion              string `json:"ubicacion"`
+	Nombre                 string             `json:"nombre"`
+	FechaInicio            string             `json:"fecha_inicio"`
+	FechaFin               string             `json:"fecha_fin"`
+	FechaCierreInscripcion string             `json:"fecha_cierre_inscripcion"`
+	Ubicacion              string             `json:"ubicacion"`
+	Capacidad              *int               `json:"capacidad"`
+	Publicar               bool               `json:"publicar"`
+	IDSede                 *int               `json:"id_sede"`
+	ForzarConflicto        bool               `json:"forzar_conflicto"`
+	ZonaHoraria            string             `json:"zona_horaria"`
+	Descripcion            string             `json:"descripcion"`
+	Categorias             []string           `json:"categorias"`
+	Organizador            *OrganizadorEvento `json:"organizador"`
+	Enlaces                []EnlaceEvento     `json:"enlaces"`
+	// Actor is who makes the change, recorded in the version history.
+	Actor string `json:"-"`
 }
 
 // UpdateEventoRequest represents the payload to update an existing event.
+// For occurrences of a series, Alcance "futuras" applies the change to this
+// and every later occurrence. A missing Descripcion, Categorias, Organizador
+// or Enlaces keeps the current value; an empty organizer removes it.
+// RestauraVersion is set when the update brings back an earlier version.
+// The venue only changes when id_sede is sent; null removes it.
 type UpdateEventoRequest struct {
-	ID                     int    `json:"id_evento"`
+	ID                     int                `json:"id_evento"`
+	Nombre                 string             `json:"nombre"`
+	FechaInicio            string             `json:"fecha_inicio"`
+	FechaFin               string             `json:"fecha_fin"`
+	FechaCierreInscripcion string             `json:"fecha_cierre_inscripcion"`
+	Ubicacion              string             `json:"ubicacion"`
+	IDSede                 *int               `json:"id_sede"`
+	ForzarConflicto        bool               `json:"forzar_conflicto"`
+	ZonaHoraria            string             `json:"zona_horaria"`
+	Alcance                string             `json:"alcance"`
+	Descripcion            *string            `json:"descripcion"`
+	Categorias             []string           `json:"categorias"`
+	Organizador            *OrganizadorEvento `json:"organizador"`
+	Enlaces                []EnlaceEvento     `json:"enlaces"`
+	Actor                  string             `json:"-"`
+	RestauraVersion        int                `json:"-"`
+	// CambiaSede is true when the payload carries id_sede.
+	CambiaSede bool `json:"-"`
+}
+
+func (r *UpdateEventoRequest) UnmarshalJSON(data []byte) error {
+	type plano UpdateEventoRequest
+	var campos map[string]json.RawMessage
+	if err := json.Unmarshal(data, &campos); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, (*plano)(r)); err != nil {
+		return err
+	}
+	_, r.CambiaSede = campos["id_sede"]
+	return nil
+}
+
+// OrganizadorEvento is who participants can contact about an event. It
+// needs an email or a phone number.
+type OrganizadorEvento struct {
+	Nombre   string `json:"nombre"`
+	Email    string `json:"email"`
+	Telefono string `json:"telefono"`
+}
+
+// DetallesEvento are the descriptive fields of an event: its Markdown
+// description, catalog categories, organizer contact and external links.
+type DetallesEvento struct {
+	Descripcion string
+	Categorias  []string
+	Organizador *OrganizadorEvento
+	Enlaces     []EnlaceEvento
+}
+
+// EnlaceEvento is an external page about an event, such as its website or
+// a streaming link.
+type EnlaceEvento struct {
+	Titulo string `json:"titulo"`
+	URL    string `json:"url"`
+}
+
+// Scopes of an update on an event that belongs to a series.
+const (
+	AlcanceOcurrencia = "ocurrencia"
+	AlcanceFuturas    = "futuras"
+)
+
+// CreateSerieRequest creates a series of events from a recurrence rule. The
+// event fields describe the first occurrence; Regla is an RRULE subset
+// (FREQ=WEEKLY|MONTHLY, INTERVAL, COUNT or UNTIL) and Excepciones lists
+// AAAA-MM-DD days to skip.
+type CreateSerieRequest struct {
+	CreateEventoRequest
+	Regla       string   `json:"regla"`
+	Excepciones []string `json:"excepciones"`
+}
+
+// ClonarEventoRequest copies an event into a new draft starting at
+// FechaInicio. The other dates and every session move by the same offset.
+// ConservarConfiguracion keeps capacity, venue and the manual inscription
+// switch; ConservarPonentes keeps speaker assignments.
+type ClonarEventoRequest struct {
 	Nombre                 string `json:"nombre"`
 	FechaInicio            string `json:"fecha_inicio"`
-	FechaFin               string `json:"fecha_fin"`
+	ConservarPonentes      bool   `json:"conservar_ponentes"`
+	ConservarConfiguracion bool   `json:"conservar_configuracion"`
+	ForzarConflicto        bool   `json:"forzar_conflicto"`
+	Actor                  string `json:"-"`
+}
+
+// ReprogramarEventoRequest moves a published event to FechaInicio. The end
+// and every session move by the same offset on the event's wall clock; so
+// does the registration deadline unless FechaCierreInscripcion is given.
+type ReprogramarEventoRequest struct {
+	FechaInicio            string `json:"fecha_inicio"`
 	FechaCierreInscripcion string `json:"fecha_cierre_inscripcion"`
-	Ubicacion              string `json:"ubicacion"`
+	Motivo                 string `json:"motivo"`
+	ForzarConflicto        bool   `json:"forzar_conflicto"`
+	Actor                  string `json:"-"`
+}
+
+// RespuestaReprogramacionRequest is a participant's answer to a reschedule,
+// sent with the token of the link they were notified with.
+type RespuestaReprogramacionRequest struct {
+	Token     string `json:"token"`
+	Respuesta string `json:"respuesta"`
+}
+
+// UpdateCapacidadRequest sets the capacity of an event. A null capacity
+// removes the limit.
+type UpdateCapacidadRequest struct {
+	Capacidad *int `json:"capacidad"`
+}
+
+// BusquedaEventosRequest filters and pages the event catalog. Texto is a
+// full-text query over name, description and location; Categorias and
+// Ciudades match any of their values; Mes is AAAA-MM in each event's local
+// time; Inscripcion is "abiertas" or "cerradas". Cursor is the
+// siguiente_cursor of the previous page.
+type BusquedaEventosRequest struct {
+	Texto       string
+	Categorias  []string
+	Ciudades    []string
+	Mes         string
+	Inscripcion string
+	Orden       string
+	Cursor      string
+	Limite      int
+}
+
+// Catalog sort orders. Relevance needs a text query.
+const (
+	OrdenRelevancia = "relevancia"
+	OrdenFecha      = "fecha"
+	OrdenFechaDesc  = "-fecha"
+	OrdenNombre     = "nombre"
+)
+
+// Values of the inscription facet and filter.
+const (
+	InscripcionAbiertas = "abiertas"
+	InscripcionCerradas = "cerradas"
+)
+
+// FilaImportacion is a row of a bulk import, numbered from 1 in file order.
+// Error is set when the row could not even be read, such as a capacity that
+// is not a number. ForzarConflicto is ignored: imports never override the
+// overlap check.
+type FilaImportacion struct {
+	Fila   int
+	Evento CreateEventoRequest
+	Error  string
+}
+
+// EventoImportado is a row of a bulk import after the field checks, with
+// its dates parsed in the event's zone. Errores lists every check the row
+// failed; only rows without errors are checked against the database.
+type EventoImportado struct {
+	Fila    int
+	Evento  CreateEventoRequest
+	Fechas  domain.Fechas
+	Errores []string
 }
@@ -1,14 +1,115 @@
 package dto
 
-// EventoResponse represents the response payload for an event.
+import (
+	"time"
+
+	"project/backend/internal/events/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// EventoResponse represents the response payload for an event. Dates are
+// shown in the event's local time, given by ZonaHoraria: the fecha_* fields
+// as DD/MM/AAAA and the fecha_hora_* fields as RFC 3339 with the time of
+// day. Descripcion is sanitized Markdown.
 type EventoResponse struct {
-	ID                     int    `json:"id_evento"`
-	Nombre                 string `json:"nombre"`
-	FechaInicio            string `json:"fecha_inicio"`
-	FechaFin               string `json:"fecha_fin"`
-	FechaCierreInscripcion string `json:"fecha_cierre_inscripcion"`
-	InscripcionesAbiertas  bool   `json:"inscripciones_abiertas"`
-	Ubicacion              string `json:"ubicacion"`
+	ID                         int                `json:"id_evento"`
+	Nombre                     string             `json:"nombre"`
+	FechaInicio                string             `json:"fecha_inicio"`
+	FechaFin                   string             `json:"fecha_fin"`
+	FechaCierreInscripcion     string             `json:"fecha_cierre_inscripcion"`
+	FechaHoraInicio            string             `json:"fecha_hora_inicio"`
+	FechaHoraFin               string             `json:"fecha_hora_fin"`
+	FechaHoraCierreInscripcion string             `json:"fecha_hora_cierre_inscripcion"`
+	InscripcionesAbiertas      bool               `json:"inscripciones_abiertas"`
+	Ubicacion                  string             `json:"ubicacion"`
+	Estado                     string             `json:"estado"`
+	Capacidad                  *int               `json:"capacidad"`
+	CuposDisponibles           *int               `json:"cupos_disponibles"`
+	EnEspera                   int                `json:"en_espera"`
+	IDSede                     *int               `json:"id_sede"`
+	Sede                       *string            `json:"sede"`
+	ZonaHoraria                string             `json:"zona_horaria"`
+	IDSerie                    *int               `json:"id_serie"`
+	Descripcion                string             `json:"descripcion"`
+	Categorias                 []string           `json:"categorias"`
+	PortadaURL                 *string            `json:"portada_url"`
+	Organizador                *OrganizadorEvento `json:"organizador"`
+	Enlaces                    []EnlaceEvento     `json:"enlaces"`
+	Archivado                  bool               `json:"archivado"`
+}
+
+// SetFechas fills the dates of the response in the event's time zone.
+func (r *EventoResponse) SetFechas(fechas domain.Fechas, zona string) {
+	r.ZonaHoraria = domain.Zona(zona).String()
+	r.FechaInicio = domain.FechaLocal(fechas.Inicio, zona)
+	r.FechaFin = domain.FechaLocal(fechas.Fin, zona)
+	r.FechaCierreInscripcion = domain.FechaLocal(fechas.Cierre, zona)
+	r.FechaHoraInicio = domain.InstanteLocal(fechas.Inicio, zona)
+	r.FechaHoraFin = domain.InstanteLocal(fechas.Fin, zona)
+	r.FechaHoraCierreInscripcion = domain.InstanteLocal(fechas.Cierre, zona)
+}
+
+// EventoDetalle represents the lifecycle state and occupation of an event.
+// CuposDisponibles is nil when the event has no capacity limit.
+type EventoDetalle struct {
+	Estado           string
+	Capacidad        *int
+	CuposDisponibles *int
+	EnEspera         int
+	IDSede           *int
+	Sede             *string
+	ZonaHoraria      string
+	Fechas           domain.Fechas
+	IDSerie          *int
+	Detalles         DetallesEvento
+	PortadaURL       *string
+	Archivado        bool
+}
+
+// SerieResponse represents a series of recurring events.
+type SerieResponse struct {
+	IDSerie     int              `json:"id_serie"`
+	Nombre      string           `json:"nombre"`
+	Regla       string           `json:"regla"`
+	Excepciones []string         `json:"excepciones"`
+	ZonaHoraria string           `json:"zona_horaria"`
+	Eventos     []EventoResponse `json:"eventos"`
+}
+
+// FacetaResponse is how many catalog events share a facet value.
+type FacetaResponse struct {
+	Valor string `json:"valor"`
+	Total int    `json:"total"`
+}
+
+// FacetasEventosResponse holds the catalog facets. Each one is counted with
+// every filter applied except its own.
+type FacetasEventosResponse struct {
+	Categorias  []FacetaResponse `json:"categorias"`
+	Ciudades    []FacetaResponse `json:"ciudades"`
+	Meses       []FacetaResponse `json:"meses"`
+	Inscripcion []FacetaResponse `json:"inscripcion"`
+}
+
+// BusquedaEventosResponse is a page of catalog results. SiguienteCursor is
+// nil on the last page.
+type BusquedaEventosResponse struct {
+	Eventos         []EventoResponse       `json:"eventos"`
+	Facetas         FacetasEventosResponse `json:"facetas"`
+	Total           int                    `json:"total"`
+	SiguienteCursor *string                `json:"siguiente_cursor"`
+}
+
+// ConflictoEvento represents an event that already occupies a venue on the
+// requested dates.
+type ConflictoEvento struct {
+	ID          int    `json:"id_evento"`
+	Nombre      string `json:"nombre"`
+	FechaInicio string `json:"fecha_inicio"`
+	FechaFin    string `json:"fecha_fin"`
+	IDSede      int    `json:"id_sede"`
+	Sede        string `json:"sede"`
 }
 
 // RangoFechas represents a date range with a start and end date.
@@ -16,3 +117,222 @@ type RangoFechas struct {
 	FechaInicio string `json:"fecha_inicio"`
 	FechaFin    string `json:"fecha_fin"`
 }
+
+// EstadoHistorialResponse represents a lifecycle transition of an event.
+type EstadoHistorialResponse struct {
+	IDHistorial    int    `json:"id_historial"`
+	EstadoAnterior string `json:"estado_anterior"`
+	EstadoNuevo    string `json:"estado_nuevo"`
+	Nota           string `json:"nota"`
+	Actor          string `json:"actor"`
+	FechaCambio    string `json:"fecha_cambio"`
+}
+
+// SnapshotEvento is the state of an event saved with each version. Dates
+// are instants in the event's own zone; InscripcionesAbiertas is the manual
+// switch, regardless of the dates.
+type SnapshotEvento struct {
+	Nombre                 string             `json:"nombre"`
+	FechaInicio            time.Time          `json:"fecha_inicio"`
+	FechaFin               time.Time          `json:"fecha_fin"`
+	FechaCierreInscripcion time.Time          `json:"fecha_cierre_inscripcion"`
+	ZonaHoraria            string             `json:"zona_horaria"`
+	Ubicacion              string             `json:"ubicacion"`
+	IDSede                 *int               `json:"id_sede"`
+	Estado                 string             `json:"estado"`
+	InscripcionesAbiertas  bool               `json:"inscripciones_abiertas"`
+	Descripcion            string             `json:"descripcion"`
+	Categorias             []string           `json:"categorias"`
+	Organizador            *OrganizadorEvento `json:"organizador"`
+	Enlaces                []EnlaceEvento     `json:"enlaces"`
+}
+
+// VersionEventoResponse is an entry of the version history of an event.
+// Cambios are the fields that differ from the previous version; Snapshot is
+// only included when a single version is requested.
+type VersionEventoResponse struct {
+	Version  int             `json:"version"`
+	Accion   string          `json:"accion"`
+	Actor    string          `json:"actor"`
+	Nota     string          `json:"nota"`
+	Fecha    string          `json:"fecha"`
+	Cambios  []domain.Cambio `json:"cambios"`
+	Snapshot *SnapshotEvento `json:"snapshot,omitempty"`
+}
+
+// DiferenciaVersionesResponse lists the fields that differ between two
+// versions of an event.
+type DiferenciaVersionesResponse struct {
+	Desde   int             `json:"desde"`
+	Hasta   int             `json:"hasta"`
+	Cambios []domain.Cambio `json:"cambios"`
+}
+
+// ResumenRespuestas counts the answers to a reschedule.
+type ResumenRespuestas struct {
+	Pendientes  int `json:"pendientes"`
+	Confirmadas int `json:"confirmadas"`
+	Canceladas  int `json:"canceladas"`
+}
+
+// ReprogramacionResponse is a reschedule of an event with the summary of
+// its answers. Respuestas is only included when a single reschedule is
+// requested. Dates are formatted in the event's zone.
+type ReprogramacionResponse struct {
+	IDReprogramacion    int                     `json:"id_reprogramacion"`
+	IDEvento            int                     `json:"id_evento"`
+	FechaInicioAnterior string                  `json:"fecha_inicio_anterior"`
+	FechaFinAnterior    string                  `json:"fecha_fin_anterior"`
+	FechaInicio         string                  `json:"fecha_inicio"`
+	FechaFin            string                  `json:"fecha_fin"`
+	Motivo              string                  `json:"motivo"`
+	Actor               string                  `json:"actor"`
+	Fecha               string                  `json:"fecha"`
+	Notificados         int                     `json:"notificados"`
+	Resumen             ResumenRespuestas       `json:"resumen"`
+	Respuestas          []RespuestaParticipante `json:"respuestas,omitempty"`
+}
+
+// RespuestaParticipante is the answer of one inscription to a reschedule.
+type RespuestaParticipante struct {
+	IDInscripcion      int    `json:"id_inscripcion"`
+	IDUsuario          int    `json:"id_usuario"`
+	Nombre             string `json:"nombre"`
+	Email              string `json:"email"`
+	Respuesta          string `json:"respuesta"`
+	FechaRespuesta     string `json:"fecha_respuesta"`
+	ReembolsoPendiente bool   `json:"reembolso_pendiente"`
+}
+
+// RespuestaReprogramacionResponse is what a participant sees behind the
+// link of a reschedule notification.
+type RespuestaReprogramacionResponse struct {
+	IDEvento            int    `json:"id_evento"`
+	Evento              string `json:"evento"`
+	FechaInicioAnterior string `json:"fecha_inicio_anterior"`
+	FechaInicio         string `json:"fecha_inicio"`
+	FechaFin            string `json:"fecha_fin"`
+	Motivo              string `json:"motivo"`
+	Respuesta           string `json:"respuesta"`
+	PuedeResponder      bool   `json:"puede_responder"`
+	ReembolsoPendiente  bool   `json:"reembolso_pendiente"`
+}
+
+// ConteoEstado is how many inscriptions of an event are in one status.
+type ConteoEstado struct {
+	Estado string `json:"estado"`
+	Total  int    `json:"total"`
+}
+
+// IngresoEvento is what the paid inscriptions of an event add up to in one
+// currency.
+type IngresoEvento struct {
+	Moneda string          `json:"moneda"`
+	Total  decimal.Decimal `json:"total"`
+}
+
+// ResumenEventoResponse is the post-event report sent to organizers when an
+// event is archived. Pagadas leaves out paid inscriptions that were later
+// cancelled.
+type ResumenEventoResponse struct {
+	IDEvento             int             `json:"id_evento"`
+	Nombre               string          `json:"nombre"`
+	Estado               string          `json:"estado"`
+	FechaInicio          string          `json:"fecha_inicio"`
+	FechaFin             string          `json:"fecha_fin"`
+	Archivado            bool            `json:"archivado"`
+	Inscripciones        int             `json:"inscripciones"`
+	PorEstado            []ConteoEstado  `json:"por_estado"`
+	Pagadas              int             `json:"pagadas"`
+	ReembolsosPendientes int             `json:"reembolsos_pendientes"`
+	Ingresos             []IngresoEvento `json:"ingresos"`
+	Sesiones             int             `json:"sesiones"`
+	SesionesCanceladas   int             `json:"sesiones_canceladas"`
+}
+
+// ImportacionEventosResponse is the report of a bulk import. Unless it was
+// confirmed nothing is created, and every IDEvento is nil.
+type ImportacionEventosResponse struct {
+	Confirmada bool                      `json:"confirmada"`
+	Total      int                       `json:"total"`
+	Validas    int                       `json:"validas"`
+	Invalidas  int                       `json:"invalidas"`
+	Creados    int                       `json:"creados"`
+	Filas      []FilaImportacionResponse `json:"filas"`
+}
+
+// FilaImportacionResponse is the outcome of one row of a bulk import.
+type FilaImportacionResponse struct {
+	Fila     int      `json:"fila"`
+	Nombre   string   `json:"nombre"`
+	Valida   bool     `json:"valida"`
+	Errores  []string `json:"errores"`
+	IDEvento *int     `json:"id_evento"`
+}
+
+// DashboardEventoResponse gathers the statistics organizers follow for an
+// event. The figures are cached briefly; Generado is when they were
+// computed.
+type DashboardEventoResponse struct {
+	IDEvento      int                 `json:"id_evento"`
+	Nombre        string              `json:"nombre"`
+	Estado        string              `json:"estado"`
+	Generado      string              `json:"generado"`
+	Inscripciones int                 `json:"inscripciones"`
+	PorEstado     []ConteoEstado      `json:"por_estado"`
+	SerieDiaria   []InscripcionesDia  `json:"serie_diaria"`
+	Conversion    ConversionPago      `json:"conversion"`
+	Recaudacion   []RecaudacionMoneda `json:"recaudacion"`
+	Ocupacion     OcupacionEvento     `json:"ocupacion"`
+	EnEspera      int                 `json:"en_espera"`
+	Afiliaciones  []ConteoAfiliacion  `json:"afiliaciones"`
+	Sesiones      []SesionPonentes    `json:"sesiones"`
+}
+
+// InscripcionesDia is how many inscriptions arrived on a day of the event's
+// zone (AAAA-MM-DD) and how many had arrived up to it.
+type InscripcionesDia struct {
+	Dia       string `json:"dia"`
+	Total     int    `json:"total"`
+	Acumulado int    `json:"acumulado"`
+}
+
+// ConversionPago is how many of the inscriptions that had to pay did.
+// Tasa is the percentage of Pagadas over ConPago.
+type ConversionPago struct {
+	ConPago    int     `json:"con_pago"`
+	Pagadas    int     `json:"pagadas"`
+	Pendientes int     `json:"pendientes"`
+	Tasa       float64 `json:"tasa"`
+}
+
+// RecaudacionMoneda is the money collected and still owed in one currency.
+type RecaudacionMoneda struct {
+	Moneda    string          `json:"moneda"`
+	Cobrado   decimal.Decimal `json:"cobrado"`
+	Pendiente decimal.Decimal `json:"pendiente"`
+}
+
+// OcupacionEvento is the use of the event capacity. Capacidad, Disponibles
+// and Porcentaje are nil when the event has no capacity limit.
+type OcupacionEvento struct {
+	Capacidad   *int     `json:"capacidad"`
+	Ocupados    int      `json:"ocupados"`
+	Disponibles *int     `json:"disponibles"`
+	Porcentaje  *float64 `json:"porcentaje"`
+}
+
+// ConteoAfiliacion is how many participants holding a seat share an
+// affiliation.
+type ConteoAfiliacion struct {
+	Afiliacion string `json:"afiliacion"`
+	Total      int    `json:"total"`
+}
+
+// SesionPonentes is a session of an event with its number of speakers.
+type SesionPonentes struct {
+	IDSesion  int    `json:"id_sesion"`
+	Titulo    string `json:"titulo"`
+	Cancelada bool   `json:"cancelada"`
+	Ponentes  int    `json:"ponentes"`
+}
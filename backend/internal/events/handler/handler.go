@@ -4,43 +4,77 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	"project/backend/internal/events/domain"
 	"project/backend/internal/events/dto"
 	"project/backend/internal/events/service"
 	"project/backend/internal/events/validation"
+	"project/backend/internal/policy"
+	roles "project/backend/internal/roles/service"
+	sesionesdto "project/backend/internal/sesiones/dto"
+	sesionessrv "project/backend/internal/sesiones/service"
 	"project/backend/internal/shared/httperror"
 	"project/backend/prisma/db"
 )
 
 const (
-	dateLayout       = "02/01/2006"
 	contentTypeKey   = "Content-Type"
 	contentTypeJSON  = "application/json"
 	dbErrorMessage   = "db error"
+
+	forzarConflictoMessage = "solo los organizadores pueden forzar un conflicto de sede"
 )
 
 type Handler struct {
-	svc EventService
+	svc         EventService
+	roleService roles.UserRoleService
 }
 
 type EventService interface {
 	EnsureNombreUnico(ctx context.Context, nombre string) error
-	EnsureNoSolapamiento(ctx context.Context, start, end time.Time) error
+	BuscarConflictos(ctx context.Context, sedeID int, start, end time.Time, excluirID int) ([]dto.ConflictoEvento, error)
 	CreateEvento(ctx context.Context, req dto.CreateEventoRequest, start, end, cierre time.Time) (*db.EventoModel, error)
-	ListEventos(ctx context.Context) ([]db.EventoModel, error)
+	ListEventos(ctx context.Context, incluirBorradores, incluirArchivados bool) ([]db.EventoModel, error)
 	GetEventoByID(ctx context.Context, id int) (*db.EventoModel, error)
 	UpdateEvento(ctx context.Context, req dto.UpdateEventoRequest, start, end, cierre time.Time) (*db.EventoModel, error)
-	DeleteEvento(ctx context.Context, id int) error
-	CerrarInscripciones(ctx context.Context, eventoID int) (*db.EventoModel, error)
-	AbrirInscripciones(ctx context.Context, eventoID int) (*db.EventoModel, error)
-	GetFechasOcupadas(ctx context.Context) ([]dto.RangoFechas, error)
+	DeleteEvento(ctx context.Context, id int, actor string) error
+	CerrarInscripciones(ctx context.Context, eventoID int, actor string) (*db.EventoModel, error)
+	AbrirInscripciones(ctx context.Context, eventoID int, actor string) (*db.EventoModel, error)
+	GetFechasOcupadas(ctx context.Context, sedeID *int) ([]dto.RangoFechas, error)
+	ActualizarCapacidad(ctx context.Context, eventoID int, capacidad *int) (*db.EventoModel, error)
+	DetallesPorEvento(ctx context.Context, eventoIDs []int) (map[int]dto.EventoDetalle, error)
+	PublicarEvento(ctx context.Context, id int, actor string) (*db.EventoModel, error)
+	HistorialEstados(ctx context.Context, id int) ([]dto.EstadoHistorialResponse, error)
+	ZonaHoraria(ctx context.Context, id int) (string, error)
+	CrearSerie(ctx context.Context, req dto.CreateSerieRequest, primera domain.Fechas, now time.Time) (int, error)
+	GetSerie(ctx context.Context, id int) (dto.SerieResponse, []db.EventoModel, error)
+	ActualizarFuturas(ctx context.Context, req dto.UpdateEventoRequest, start, end, cierre time.Time) ([]db.EventoModel, error)
+	ClonarEvento(ctx context.Context, origenID int, req dto.ClonarEventoRequest, start, now time.Time) (*db.EventoModel, error)
+	BuscarEventos(ctx context.Context, req dto.BusquedaEventosRequest, incluirBorradores bool, now time.Time) (dto.BusquedaEventosResponse, []db.EventoModel, error)
+	ActualizarPortada(ctx context.Context, id int, imagen io.Reader) (string, error)
+	EliminarPortada(ctx context.Context, id int) error
+	VersionesEvento(ctx context.Context, id int) ([]dto.VersionEventoResponse, error)
+	VersionEvento(ctx context.Context, id, version int) (dto.VersionEventoResponse, error)
+	DiferenciaVersiones(ctx context.Context, id, desde, hasta int) (dto.DiferenciaVersionesResponse, error)
+	ReprogramarEvento(ctx context.Context, id int, req dto.ReprogramarEventoRequest, start time.Time, cierre *time.Time, enlace string, now time.Time) (dto.ReprogramacionResponse, error)
+	ReprogramacionesEvento(ctx context.Context, id int) ([]dto.ReprogramacionResponse, error)
+	ReprogramacionEvento(ctx context.Context, id, reprogramacionID int) (dto.ReprogramacionResponse, error)
+	ConsultarReprogramacion(ctx context.Context, token string, now time.Time) (dto.RespuestaReprogramacionResponse, error)
+	ResponderReprogramacion(ctx context.Context, req dto.RespuestaReprogramacionRequest, now time.Time) (dto.RespuestaReprogramacionResponse, error)
+	ResumenEvento(ctx context.Context, id int) (dto.ResumenEventoResponse, error)
+	ImportarEventos(ctx context.Context, filas []dto.EventoImportado, confirmar bool, actor string, now time.Time) (dto.ImportacionEventosResponse, error)
+	DashboardEvento(ctx context.Context, id int, now time.Time) (dto.DashboardEventoResponse, error)
 }
 
 func New(client *db.PrismaClient) http.Handler {
-	return &Handler{svc: service.New(client)}
+	return &Handler{svc: service.New(client), roleService: roles.NewUserRoleService(client)}
 }
 
 func NewWithService(svc EventService) *Handler {
@@ -50,6 +84,10 @@ func NewWithService(svc EventService) *Handler {
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
+		if r.URL.Query().Get("action") == "clonar" {
+			h.clonarEvento(w, r)
+			return
+		}
 		h.createEvento(w, r)
 	case http.MethodGet:
 		h.listEventos(w, r)
@@ -71,13 +109,21 @@ func (h *Handler) createEvento(w http.ResponseWriter, r *http.Request) {
 		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
 		return
 	}
+	req.Actor = actorFromRequest(r)
 
 	if err := validation.ValidateEventoNombre(req.Nombre); err != nil {
 		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	startDate, endDate, cierreDate, err := validation.ValidateEventoFechas(req.FechaInicio, req.FechaFin, req.FechaCierreInscripcion, time.Now())
+	loc, err := domain.CargarZona(strings.TrimSpace(req.ZonaHoraria))
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ZonaHoraria = loc.String()
+
+	startDate, endDate, cierreDate, err := validation.ValidateEventoFechas(req.FechaInicio, req.FechaFin, req.FechaCierreInscripcion, time.Now().In(loc))
 	if err != nil {
 		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
 		return
@@ -88,6 +134,18 @@ func (h *Handler) createEvento(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validation.ValidateEventoCapacidad(req.Capacidad); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	categorias, err := validarDetalles(&req.Descripcion, req.Categorias, req.Organizador, req.Enlaces)
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Categorias = categorias
+
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
@@ -100,32 +158,78 @@ func (h *Handler) createEvento(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.svc.EnsureNoSolapamiento(ctx, startDate, endDate); err != nil {
-		if errors.Is(err, service.ErrOverlap) {
-			httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	if req.ForzarConflicto && !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, forzarConflictoMessage)
+		return
+	}
+
+	created, err := h.svc.CreateEvento(ctx, req, startDate, endDate, cierreDate)
+	if handleEventoError(w, err) {
+		return
+	}
+
+	if req.Publicar {
+		created, err = h.svc.PublicarEvento(ctx, created.IDEvento, actorFromRequest(r))
+		if handleLifecycleError(w, err) {
 			return
 		}
-		httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+	}
+
+	now := time.Now()
+	res := h.eventoResponse(ctx, created, now)
+
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// clonarEvento serves POST /api/eventos?action=clonar&id=N.
+func (h *Handler) clonarEvento(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
 		return
 	}
 
-	created, err := h.svc.CreateEvento(ctx, req, startDate, endDate, cierreDate)
+	var req dto.ClonarEventoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	req.Actor = actorFromRequest(r)
+	if err := validation.ValidateEventoNombre(req.Nombre); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if req.ForzarConflicto && !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, forzarConflictoMessage)
+		return
+	}
+
+	zona, err := h.svc.ZonaHoraria(ctx, id)
+	if handleEventoError(w, err) {
+		return
+	}
+	start, err := validation.ParseEventoFecha(req.FechaInicio, domain.Zona(zona))
 	if err != nil {
-		httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+		httperror.WriteJSON(w, http.StatusBadRequest, "Fecha de inicio inválida (formato DD/MM/AAAA o ISO 8601).")
 		return
 	}
 
-	now := time.Now()
-	res := dto.EventoResponse{
-		ID:                     created.IDEvento,
-		Nombre:                 created.Nombre,
-		FechaInicio:            created.FechaInicio.Format(dateLayout),
-		FechaFin:               created.FechaFin.Format(dateLayout),
-		FechaCierreInscripcion: created.FechaCierreInscripcion.Format(dateLayout),
-		InscripcionesAbiertas:  isInscripcionesAbiertas(created, now),
-		Ubicacion:              created.Ubicacion,
+	created, err := h.svc.ClonarEvento(ctx, id, req, start, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrClonInvalido) {
+			httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		handleEventoError(w, err)
+		return
 	}
 
+	res := h.eventoResponse(ctx, created, time.Now())
 	w.Header().Set(contentTypeKey, contentTypeJSON)
 	_ = json.NewEncoder(w).Encode(res)
 }
@@ -134,7 +238,8 @@ func (h *Handler) listEventos(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	eventos, err := h.svc.ListEventos(ctx)
+	incluirArchivados := r.URL.Query().Get("archivados") == "true"
+	eventos, err := h.svc.ListEventos(ctx, roles.CanManageEvents(ctx, h.roleService, r), incluirArchivados)
 	if err != nil {
 		httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
 		return
@@ -143,16 +248,9 @@ func (h *Handler) listEventos(w http.ResponseWriter, r *http.Request) {
 	now := time.Now()
 	res := make([]dto.EventoResponse, 0, len(eventos))
 	for _, ev := range eventos {
-		res = append(res, dto.EventoResponse{
-			ID:                     ev.IDEvento,
-			Nombre:                 ev.Nombre,
-			FechaInicio:            ev.FechaInicio.Format(dateLayout),
-			FechaFin:               ev.FechaFin.Format(dateLayout),
-			FechaCierreInscripcion: ev.FechaCierreInscripcion.Format(dateLayout),
-			InscripcionesAbiertas:  isInscripcionesAbiertas(&ev, now),
-			Ubicacion:              ev.Ubicacion,
-		})
+		res = append(res, toEventoResponse(&ev, now))
 	}
+	h.applyDetalles(ctx, res)
 
 	w.Header().Set(contentTypeKey, contentTypeJSON)
 	_ = json.NewEncoder(w).Encode(res)
@@ -165,12 +263,23 @@ func (h *Handler) updateEvento(w http.ResponseWriter, r *http.Request) {
 		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
 		return
 	}
+	req.Actor = actorFromRequest(r)
+	h.aplicarUpdate(w, r, req)
+}
 
+// aplicarUpdate validates and applies an update, whether it comes from a PUT
+// or from restoring an earlier version.
+func (h *Handler) aplicarUpdate(w http.ResponseWriter, r *http.Request, req dto.UpdateEventoRequest) {
 	if req.ID == 0 {
 		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento es requerido para actualizar")
 		return
 	}
 
+	if req.Alcance != "" && req.Alcance != dto.AlcanceOcurrencia && req.Alcance != dto.AlcanceFuturas {
+		httperror.WriteJSON(w, http.StatusBadRequest, "alcance debe ser 'ocurrencia' o 'futuras'")
+		return
+	}
+
 	if err := validation.ValidateEventoNombre(req.Nombre); err != nil {
 		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
 		return
@@ -181,9 +290,21 @@ func (h *Handler) updateEvento(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	categorias, err := validarDetalles(req.Descripcion, req.Categorias, req.Organizador, req.Enlaces)
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Categorias = categorias
+
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
+	if req.ForzarConflicto && !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, forzarConflictoMessage)
+		return
+	}
+
 	existing, err := h.svc.GetEventoByID(ctx, req.ID)
 	if err != nil {
 		if errors.Is(err, service.ErrNotFound) {
@@ -194,11 +315,25 @@ func (h *Handler) updateEvento(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	zona := strings.TrimSpace(req.ZonaHoraria)
+	if zona == "" {
+		if zona, err = h.svc.ZonaHoraria(ctx, req.ID); err != nil {
+			httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+			return
+		}
+	}
+	loc, err := domain.CargarZona(zona)
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ZonaHoraria = loc.String()
+
 	startDate, endDate, cierreDate, err := validation.ValidateEventoFechasUpdate(
 		req.FechaInicio,
 		req.FechaFin,
 		req.FechaCierreInscripcion,
-		time.Now(),
+		time.Now().In(loc),
 		existing.FechaCierreInscripcion,
 	)
 	if err != nil {
@@ -206,21 +341,24 @@ func (h *Handler) updateEvento(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Alcance == dto.AlcanceFuturas {
+		actualizados, err := h.svc.ActualizarFuturas(ctx, req, startDate, endDate, cierreDate)
+		if handleSerieError(w, err) {
+			return
+		}
+		res := h.eventoResponses(ctx, actualizados, time.Now())
+		w.Header().Set(contentTypeKey, contentTypeJSON)
+		_ = json.NewEncoder(w).Encode(res)
+		return
+	}
+
 	updated, err := h.svc.UpdateEvento(ctx, req, startDate, endDate, cierreDate)
-	if handleUpdateEventoError(w, err) {
+	if handleEventoError(w, err) {
 		return
 	}
 
 	now := time.Now()
-	res := dto.EventoResponse{
-		ID:                     updated.IDEvento,
-		Nombre:                 updated.Nombre,
-		FechaInicio:            updated.FechaInicio.Format(dateLayout),
-		FechaFin:               updated.FechaFin.Format(dateLayout),
-		FechaCierreInscripcion: updated.FechaCierreInscripcion.Format(dateLayout),
-		InscripcionesAbiertas:  isInscripcionesAbiertas(updated, now),
-		Ubicacion:              updated.Ubicacion,
-	}
+	res := h.eventoResponse(ctx, updated, now)
 
 	w.Header().Set(contentTypeKey, contentTypeJSON)
 	_ = json.NewEncoder(w).Encode(res)
@@ -246,8 +384,12 @@ func (h *Handler) patchEvento(w http.ResponseWriter, r *http.Request) {
 		h.cerrarInscripciones(w, r, id)
 	case "abrir":
 		h.abrirInscripciones(w, r, id)
+	case "capacidad":
+		h.actualizarCapacidad(w, r, id)
+	case "publicar":
+		h.publicarEvento(w, r, id)
 	default:
-		httperror.WriteJSON(w, http.StatusBadRequest, "action debe ser 'cerrar' o 'abrir'")
+		httperror.WriteJSON(w, http.StatusBadRequest, "action debe ser 'cerrar', 'abrir', 'capacidad' o 'publicar'")
 	}
 }
 
@@ -264,13 +406,8 @@ func (h *Handler) deleteEvento(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
-	err = h.svc.DeleteEvento(ctx, id)
-	if err != nil {
-		if errors.Is(err, service.ErrNotFound) {
-			httperror.WriteJSON(w, http.StatusNotFound, err.Error())
-			return
-		}
-		httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+	err = h.svc.DeleteEvento(ctx, id, actorFromRequest(r))
+	if handleLifecycleError(w, err) {
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -280,7 +417,7 @@ func (h *Handler) cerrarInscripciones(w http.ResponseWriter, r *http.Request, ev
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	updated, err := h.svc.CerrarInscripciones(ctx, eventoID)
+	updated, err := h.svc.CerrarInscripciones(ctx, eventoID, actorFromRequest(r))
 	if err != nil {
 		if errors.Is(err, service.ErrNotFound) {
 			httperror.WriteJSON(w, http.StatusNotFound, err.Error())
@@ -295,15 +432,7 @@ func (h *Handler) cerrarInscripciones(w http.ResponseWriter, r *http.Request, ev
 	}
 
 	now := time.Now()
-	res := dto.EventoResponse{
-		ID:                     updated.IDEvento,
-		Nombre:                 updated.Nombre,
-		FechaInicio:            updated.FechaInicio.Format(dateLayout),
-		FechaFin:               updated.FechaFin.Format(dateLayout),
-		FechaCierreInscripcion: updated.FechaCierreInscripcion.Format(dateLayout),
-		InscripcionesAbiertas:  isInscripcionesAbiertas(updated, now),
-		Ubicacion:              updated.Ubicacion,
-	}
+	res := h.eventoResponse(ctx, updated, now)
 	w.Header().Set(contentTypeKey, contentTypeJSON)
 	_ = json.NewEncoder(w).Encode(res)
 }
@@ -312,7 +441,7 @@ func (h *Handler) abrirInscripciones(w http.ResponseWriter, r *http.Request, eve
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	updated, err := h.svc.AbrirInscripciones(ctx, eventoID)
+	updated, err := h.svc.AbrirInscripciones(ctx, eventoID, actorFromRequest(r))
 	if err != nil {
 		if errors.Is(err, service.ErrNotFound) {
 			httperror.WriteJSON(w, http.StatusNotFound, err.Error())
@@ -331,28 +460,148 @@ func (h *Handler) abrirInscripciones(w http.ResponseWriter, r *http.Request, eve
 	}
 
 	now := time.Now()
-	res := dto.EventoResponse{
-		ID:                     updated.IDEvento,
-		Nombre:                 updated.Nombre,
-		FechaInicio:            updated.FechaInicio.Format(dateLayout),
-		FechaFin:               updated.FechaFin.Format(dateLayout),
-		FechaCierreInscripcion: updated.FechaCierreInscripcion.Format(dateLayout),
-		InscripcionesAbiertas:  isInscripcionesAbiertas(updated, now),
-		Ubicacion:              updated.Ubicacion,
+	res := h.eventoResponse(ctx, updated, now)
+
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (h *Handler) publicarEvento(w http.ResponseWriter, r *http.Request, eventoID int) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	updated, err := h.svc.PublicarEvento(ctx, eventoID, actorFromRequest(r))
+	if handleLifecycleError(w, err) {
+		return
 	}
 
+	res := h.eventoResponse(ctx, updated, time.Now())
 	w.Header().Set(contentTypeKey, contentTypeJSON)
 	_ = json.NewEncoder(w).Encode(res)
 }
 
-func isInscripcionesAbiertas(evento *db.EventoModel, now time.Time) bool {
-	if !evento.InscripcionesAbiertasManual {
-		return false
+func (h *Handler) actualizarCapacidad(w http.ResponseWriter, r *http.Request, eventoID int) {
+	var req dto.UpdateCapacidadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
 	}
-	if !now.Before(evento.FechaInicio) {
-		return false
+	if err := validation.ValidateEventoCapacidad(req.Capacidad); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	return now.Before(evento.FechaCierreInscripcion)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	updated, err := h.svc.ActualizarCapacidad(ctx, eventoID, req.Capacidad)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+		return
+	}
+
+	res := h.eventoResponse(ctx, updated, time.Now())
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// toEventoResponse shows the dates in the default zone; applyDetalles
+// switches them to the event's own zone.
+func toEventoResponse(evento *db.EventoModel, now time.Time) dto.EventoResponse {
+	fechas := domain.Fechas{
+		Inicio: evento.FechaInicio,
+		Fin:    evento.FechaFin,
+		Cierre: evento.FechaCierreInscripcion,
+	}
+	res := dto.EventoResponse{
+		ID:                    evento.IDEvento,
+		Nombre:                evento.Nombre,
+		InscripcionesAbiertas: domain.InscripcionesAbiertas(evento.InscripcionesAbiertasManual, fechas, now),
+		Ubicacion:             evento.Ubicacion,
+		Categorias:            []string{},
+		Enlaces:               []dto.EnlaceEvento{},
+	}
+	res.SetFechas(fechas, domain.ZonaHorariaPredeterminada)
+	return res
+}
+
+func (h *Handler) eventoResponse(ctx context.Context, evento *db.EventoModel, now time.Time) dto.EventoResponse {
+	res := []dto.EventoResponse{toEventoResponse(evento, now)}
+	h.applyDetalles(ctx, res)
+	return res[0]
+}
+
+// applyDetalles fills lifecycle state, capacity, waitlist figures and local
+// dates in place. They are informative, so a failure leaves them empty instead of
+// failing the request.
+func (h *Handler) applyDetalles(ctx context.Context, eventos []dto.EventoResponse) {
+	if len(eventos) == 0 {
+		return
+	}
+	ids := make([]int, 0, len(eventos))
+	for _, ev := range eventos {
+		ids = append(ids, ev.ID)
+	}
+	detalles, err := h.svc.DetallesPorEvento(ctx, ids)
+	if err != nil {
+		return
+	}
+	for i := range eventos {
+		detalle, ok := detalles[eventos[i].ID]
+		if !ok {
+			continue
+		}
+		eventos[i].Estado = detalle.Estado
+		eventos[i].Capacidad = detalle.Capacidad
+		eventos[i].CuposDisponibles = detalle.CuposDisponibles
+		eventos[i].EnEspera = detalle.EnEspera
+		eventos[i].IDSede = detalle.IDSede
+		eventos[i].Sede = detalle.Sede
+		eventos[i].Descripcion = detalle.Detalles.Descripcion
+		if detalle.Detalles.Categorias != nil {
+			eventos[i].Categorias = detalle.Detalles.Categorias
+		}
+		eventos[i].Organizador = detalle.Detalles.Organizador
+		if detalle.Detalles.Enlaces != nil {
+			eventos[i].Enlaces = detalle.Detalles.Enlaces
+		}
+		eventos[i].PortadaURL = detalle.PortadaURL
+		eventos[i].Archivado = detalle.Archivado
+		eventos[i].SetFechas(detalle.Fechas, detalle.ZonaHoraria)
+	}
+}
+
+func actorFromRequest(r *http.Request) string {
+	subject := policy.SubjectFromRequest(r)
+	if subject.UserID > 0 {
+		return fmt.Sprintf("usuario:%d", subject.UserID)
+	}
+	return strings.Join(subject.Roles, ",")
+}
+
+// validarDetalles checks the descriptive fields of a create or update
+// payload and returns the normalized categories. Missing fields are valid and
+// nil categories stay nil, meaning "keep" on update.
+func validarDetalles(descripcion *string, categorias []string, organizador *dto.OrganizadorEvento, enlaces []dto.EnlaceEvento) ([]string, error) {
+	if descripcion != nil {
+		if err := validation.ValidateEventoDescripcion(*descripcion); err != nil {
+			return nil, err
+		}
+	}
+	if err := validation.ValidateEventoOrganizador(organizador); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateEventoEnlaces(enlaces); err != nil {
+		return nil, err
+	}
+	if categorias == nil {
+		return nil, nil
+	}
+	return validation.NormalizarCategorias(categorias)
 }
 
 func (h *Handler) Svc() EventService {
@@ -362,7 +611,16 @@ func (h *Handler) Svc() EventService {
 func GetFechasOcupadasHandler(svc EventService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		fechas, err := svc.GetFechasOcupadas(ctx)
+		var sedeID *int
+		if raw := r.URL.Query().Get("id_sede"); raw != "" {
+			id, err := strconv.Atoi(raw)
+			if err != nil || id <= 0 {
+				httperror.WriteJSON(w, http.StatusBadRequest, "id_sede inválido")
+				return
+			}
+			sedeID = &id
+		}
+		fechas, err := svc.GetFechasOcupadas(ctx, sedeID)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			json.NewEncoder(w).Encode(map[string]string{"error": "Error obteniendo fechas ocupadas por los eventos existentes"})
@@ -373,13 +631,411 @@ func GetFechasOcupadasHandler(svc EventService) http.HandlerFunc {
 	}
 }
 
-func handleUpdateEventoError(w http.ResponseWriter, err error) bool {
+// GetConflictosHandler reports the events that occupy a venue between two
+// dates, so organizers can review them before forcing a conflict.
+func GetConflictosHandler(svc EventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		sedeID, err := strconv.Atoi(q.Get("id_sede"))
+		if err != nil || sedeID <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, "id_sede inválido")
+			return
+		}
+		loc, err := domain.CargarZona(strings.TrimSpace(q.Get("zona_horaria")))
+		if err != nil {
+			httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		start, err := validation.ParseEventoFecha(q.Get("fecha_inicio"), loc)
+		if err != nil {
+			httperror.WriteJSON(w, http.StatusBadRequest, "fecha_inicio inválida")
+			return
+		}
+		end, err := validation.ParseEventoFin(q.Get("fecha_fin"), loc)
+		if err != nil || end.Before(start) {
+			httperror.WriteJSON(w, http.StatusBadRequest, "fecha_fin inválida")
+			return
+		}
+		excluirID := 0
+		if raw := q.Get("excluir"); raw != "" {
+			if excluirID, err = strconv.Atoi(raw); err != nil {
+				httperror.WriteJSON(w, http.StatusBadRequest, "excluir inválido")
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		defer cancel()
+
+		conflictos, err := svc.BuscarConflictos(ctx, sedeID, start, end, excluirID)
+		if err != nil {
+			httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+			return
+		}
+		w.Header().Set(contentTypeKey, contentTypeJSON)
+		_ = json.NewEncoder(w).Encode(conflictos)
+	}
+}
+
+func GetHistorialEstadosHandler(svc EventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil || id <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		defer cancel()
+
+		historial, err := svc.HistorialEstados(ctx, id)
+		if err != nil {
+			if errors.Is(err, service.ErrNotFound) {
+				httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+				return
+			}
+			httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+			return
+		}
+		w.Header().Set(contentTypeKey, contentTypeJSON)
+		_ = json.NewEncoder(w).Encode(historial)
+	}
+}
+
+// PortadaHandler serves /api/eventos/portada?id=N: POST uploads the cover
+// image as the multipart field "portada" and DELETE removes it.
+func (h *Handler) PortadaHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	if !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, "no tienes permisos para cambiar la portada del evento")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		// Leave room for the multipart headers around the file.
+		r.Body = http.MaxBytesReader(w, r.Body, service.MaxPortadaBytes+64<<10)
+		file, _, err := r.FormFile("portada")
+		if err != nil {
+			httperror.WriteJSON(w, http.StatusBadRequest, service.ErrPortadaInvalida.Error())
+			return
+		}
+		defer file.Close()
+		if _, err := h.svc.ActualizarPortada(ctx, id, file); err != nil {
+			handlePortadaError(w, err)
+			return
+		}
+	case http.MethodDelete:
+		if err := h.svc.EliminarPortada(ctx, id); err != nil {
+			handlePortadaError(w, err)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	evento, err := h.svc.GetEventoByID(ctx, id)
+	if handleEventoError(w, err) {
+		return
+	}
+	res := h.eventoResponse(ctx, evento, time.Now())
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func handlePortadaError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrPortadaInvalida):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrAlmacenamiento):
+		httperror.WriteJSON(w, http.StatusInternalServerError, err.Error())
+	default:
+		handleEventoError(w, err)
+	}
+}
+
+// BuscarHandler serves GET /api/eventos/buscar, the paginated event catalog
+// with full-text search, filters and facet counts.
+func (h *Handler) BuscarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, err := parseBusqueda(r.URL.Query())
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	res, eventos, err := h.svc.BuscarEventos(ctx, req, roles.CanManageEvents(ctx, h.roleService, r), now)
+	if err != nil {
+		if errors.Is(err, service.ErrCursorInvalido) {
+			httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+		return
+	}
+	res.Eventos = h.eventoResponses(ctx, eventos, now)
+
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// parseBusqueda reads the catalog query string. categoria and ciudad may be
+// repeated or hold comma-separated values.
+func parseBusqueda(q url.Values) (dto.BusquedaEventosRequest, error) {
+	req := dto.BusquedaEventosRequest{
+		Texto:       strings.TrimSpace(q.Get("q")),
+		Ciudades:    valoresLista(q["ciudad"]),
+		Mes:         strings.TrimSpace(q.Get("mes")),
+		Inscripcion: strings.TrimSpace(q.Get("inscripcion")),
+		Orden:       strings.TrimSpace(q.Get("orden")),
+		Cursor:      strings.TrimSpace(q.Get("cursor")),
+	}
+	if len(req.Texto) > 200 {
+		return req, errors.New("la búsqueda no puede superar los 200 caracteres")
+	}
+	categorias, err := validation.NormalizarCategorias(valoresLista(q["categoria"]))
+	if err != nil {
+		return req, err
+	}
+	req.Categorias = categorias
+	if req.Mes != "" {
+		if _, err := time.Parse("2006-01", req.Mes); err != nil {
+			return req, errors.New("mes inválido (formato AAAA-MM)")
+		}
+	}
+	switch req.Inscripcion {
+	case "", dto.InscripcionAbiertas, dto.InscripcionCerradas:
+	default:
+		return req, errors.New("inscripcion debe ser 'abiertas' o 'cerradas'")
+	}
+	switch req.Orden {
+	case "", dto.OrdenRelevancia, dto.OrdenFecha, dto.OrdenFechaDesc, dto.OrdenNombre:
+	default:
+		return req, errors.New("orden debe ser 'relevancia', 'fecha', '-fecha' o 'nombre'")
+	}
+	if raw := q.Get("limite"); raw != "" {
+		limite, err := strconv.Atoi(raw)
+		if err != nil || limite < 1 || limite > service.LimiteBusquedaMax {
+			return req, fmt.Errorf("limite debe estar entre 1 y %d", service.LimiteBusquedaMax)
+		}
+		req.Limite = limite
+	}
+	return req, nil
+}
+
+func valoresLista(valores []string) []string {
+	res := []string{}
+	for _, v := range valores {
+		for _, parte := range strings.Split(v, ",") {
+			if parte = strings.TrimSpace(parte); parte != "" {
+				res = append(res, parte)
+			}
+		}
+	}
+	return res
+}
+
+// SeriesHandler serves /api/eventos/series: POST creates a series of
+// recurring events and GET ?id returns a series with its occurrences.
+func (h *Handler) SeriesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.crearSerie(w, r)
+	case http.MethodGet:
+		h.getSerie(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) crearSerie(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateSerieRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	req.Actor = actorFromRequest(r)
+
+	if err := validation.ValidateEventoNombre(req.Nombre); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validation.ValidateEventoUbicacion(req.Ubicacion); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validation.ValidateEventoCapacidad(req.Capacidad); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	categorias, err := validarDetalles(&req.Descripcion, req.Categorias, req.Organizador, req.Enlaces)
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Categorias = categorias
+	loc, err := domain.CargarZona(strings.TrimSpace(req.ZonaHoraria))
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ZonaHoraria = loc.String()
+
+	now := time.Now()
+	startDate, endDate, cierreDate, err := validation.ValidateEventoFechas(req.FechaInicio, req.FechaFin, req.FechaCierreInscripcion, now.In(loc))
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if req.ForzarConflicto && !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, forzarConflictoMessage)
+		return
+	}
+
+	serieID, err := h.svc.CrearSerie(ctx, req, domain.Fechas{Inicio: startDate, Fin: endDate, Cierre: cierreDate}, now)
+	if handleSerieError(w, err) {
+		return
+	}
+
+	serie, eventos, err := h.svc.GetSerie(ctx, serieID)
+	if handleSerieError(w, err) {
+		return
+	}
+	serie.Eventos = h.eventoResponses(ctx, eventos, time.Now())
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(serie)
+}
+
+func (h *Handler) getSerie(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	serie, eventos, err := h.svc.GetSerie(ctx, id)
+	if handleSerieError(w, err) {
+		return
+	}
+
+	res := h.eventoResponses(ctx, eventos, time.Now())
+	if !roles.CanManageEvents(ctx, h.roleService, r) {
+		visibles := make([]dto.EventoResponse, 0, len(res))
+		for _, ev := range res {
+			if domain.EsVisible(ev.Estado) {
+				visibles = append(visibles, ev)
+			}
+		}
+		res = visibles
+	}
+	serie.Eventos = res
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(serie)
+}
+
+func (h *Handler) eventoResponses(ctx context.Context, eventos []db.EventoModel, now time.Time) []dto.EventoResponse {
+	res := make([]dto.EventoResponse, 0, len(eventos))
+	for _, ev := range eventos {
+		res = append(res, toEventoResponse(&ev, now))
+	}
+	h.applyDetalles(ctx, res)
+	return res
+}
+
+func handleSerieError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrSerieNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrReglaInvalida),
+		errors.Is(err, domain.ErrExcepcionInvalida),
+		errors.Is(err, domain.ErrDemasiadasOcurrencias),
+		errors.Is(err, domain.ErrSerieSinOcurrencias),
+		errors.Is(err, service.ErrOcurrenciaInvalida),
+		errors.Is(err, service.ErrSinSerie):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+	default:
+		return handleEventoError(w, err)
+	}
+	return true
+}
+
+func handleLifecycleError(w http.ResponseWriter, err error) bool {
 	if err == nil {
 		return false
 	}
 	switch {
+	case errors.Is(err, service.ErrNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrTransicionInvalida):
+		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrPublicacionVencida):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, dbErrorMessage)
+	}
+	return true
+}
+
+func handleEventoError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	var conflicto *service.ConflictoError
+	var ocupados *sesionessrv.ConflictoError
+	switch {
+	case errors.As(err, &conflicto):
+		w.Header().Set(contentTypeKey, contentTypeJSON)
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"message":    conflicto.Error(),
+			"conflictos": conflicto.Conflictos,
+		})
+	case errors.As(err, &ocupados):
+		w.Header().Set(contentTypeKey, contentTypeJSON)
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(sesionesdto.ConflictoPonentesResponse{
+			Message:    ocupados.Error(),
+			Conflictos: ocupados.Conflictos,
+		})
 	case errors.Is(err, service.ErrNameExists), errors.Is(err, service.ErrOverlap):
 		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrSedeNotFound), errors.Is(err, service.ErrCapacidadSede):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
 	case errors.Is(err, service.ErrCloseDateLocked):
 		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
 	case errors.Is(err, service.ErrNotFound):
@@ -389,3 +1045,190 @@ func handleUpdateEventoError(w http.ResponseWriter, err error) bool {
 	}
 	return true
 }
+
+// VersionesHandler serves /api/eventos/versiones?id=N. GET lists the
+// version history, adds &version=V for a single version with its snapshot
+// or &desde=A&hasta=B for the changes between two versions. POST
+// &version=V restores that version as a regular update, so it goes through
+// the same validations; lifecycle state and the inscription switch are not
+// restored.
+func (h *Handler) VersionesHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, err := strconv.Atoi(q.Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getVersiones(w, r, id)
+	case http.MethodPost:
+		version, err := strconv.Atoi(q.Get("version"))
+		if err != nil || version <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, "version inválida")
+			return
+		}
+		h.restaurarVersion(w, r, id, version)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) getVersiones(w http.ResponseWriter, r *http.Request, id int) {
+	q := r.URL.Query()
+	numeros := map[string]int{}
+	for _, param := range []string{"version", "desde", "hasta"} {
+		raw := q.Get(param)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, param+" inválido")
+			return
+		}
+		numeros[param] = n
+	}
+	_, conDesde := numeros["desde"]
+	_, conHasta := numeros["hasta"]
+	if conDesde != conHasta {
+		httperror.WriteJSON(w, http.StatusBadRequest, "desde y hasta deben indicarse juntos")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	var (
+		res interface{}
+		err error
+	)
+	switch {
+	case conDesde:
+		res, err = h.svc.DiferenciaVersiones(ctx, id, numeros["desde"], numeros["hasta"])
+	case numeros["version"] > 0:
+		res, err = h.svc.VersionEvento(ctx, id, numeros["version"])
+	default:
+		res, err = h.svc.VersionesEvento(ctx, id)
+	}
+	if handleVersionError(w, err) {
+		return
+	}
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (h *Handler) restaurarVersion(w http.ResponseWriter, r *http.Request, id, version int) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	anterior, err := h.svc.VersionEvento(ctx, id, version)
+	if handleVersionError(w, err) {
+		return
+	}
+	req := restauracion(id, *anterior.Snapshot)
+	req.RestauraVersion = version
+	req.ForzarConflicto = r.URL.Query().Get("forzar_conflicto") == "true"
+	req.Actor = actorFromRequest(r)
+	h.aplicarUpdate(w, r, req)
+}
+
+// restauracion turns a snapshot into the update that brings it back. Empty
+// descriptive fields are sent explicitly so they replace the current ones.
+func restauracion(id int, snapshot dto.SnapshotEvento) dto.UpdateEventoRequest {
+	descripcion := snapshot.Descripcion
+	categorias := snapshot.Categorias
+	if categorias == nil {
+		categorias = []string{}
+	}
+	organizador := snapshot.Organizador
+	if organizador == nil {
+		organizador = &dto.OrganizadorEvento{}
+	}
+	enlaces := snapshot.Enlaces
+	if enlaces == nil {
+		enlaces = []dto.EnlaceEvento{}
+	}
+	return dto.UpdateEventoRequest{
+		ID:                     id,
+		Nombre:                 snapshot.Nombre,
+		FechaInicio:            snapshot.FechaInicio.Format(time.RFC3339),
+		FechaFin:               snapshot.FechaFin.Format(time.RFC3339),
+		FechaCierreInscripcion: snapshot.FechaCierreInscripcion.Format(time.RFC3339),
+		Ubicacion:              snapshot.Ubicacion,
+		IDSede:                 snapshot.IDSede,
+		CambiaSede:             true,
+		ZonaHoraria:            snapshot.ZonaHoraria,
+		Descripcion:            &descripcion,
+		Categorias:             categorias,
+		Organizador:            organizador,
+		Enlaces:                enlaces,
+	}
+}
+
+func handleVersionError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, service.ErrVersionNotFound) {
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+		return true
+	}
+	return handleEventoError(w, err)
+}
+
+// ResumenHandler serves GET /api/eventos/resumen?id=N, the post-event
+// report organizers also get by email when the event is archived.
+func (h *Handler) ResumenHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, "no tienes permisos para ver el resumen del evento")
+		return
+	}
+
+	res, err := h.svc.ResumenEvento(ctx, id)
+	if handleEventoError(w, err) {
+		return
+	}
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// DashboardHandler serves GET /api/eventos/dashboard?id=N, the statistics
+// organizers follow while an event takes inscriptions.
+func (h *Handler) DashboardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, "no tienes permisos para ver las estadísticas del evento")
+		return
+	}
+
+	res, err := h.svc.DashboardEvento(ctx, id, time.Now())
+	if handleEventoError(w, err) {
+		return
+	}
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
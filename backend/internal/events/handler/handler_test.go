@@ -5,28 +5,67 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
 
+	"project/backend/internal/events/domain"
 	"project/backend/internal/events/dto"
 	"project/backend/internal/events/service"
+	roles "project/backend/internal/roles/service"
 	"project/backend/prisma/db"
 )
 
+// adminRoles lets requests with the ADMIN role through roles.CanManageEvents,
+// which never asks the role service about administrators.
+type adminRoles struct {
+	roles.UserRoleService
+}
+
+func newManagerHandler(svc EventService) *Handler {
+	h := NewWithService(svc)
+	h.roleService = adminRoles{}
+	return h
+}
+
 type mockEventService struct {
 	ensureNombreUnico     func(ctx context.Context, nombre string) error
-	ensureNoSolapamiento  func(ctx context.Context, start, end time.Time) error
+	buscarConflictos      func(ctx context.Context, sedeID int, start, end time.Time, excluirID int) ([]dto.ConflictoEvento, error)
 	createEvento          func(ctx context.Context, req dto.CreateEventoRequest, start, end, cierre time.Time) (*db.EventoModel, error)
-	listEventos           func(ctx context.Context) ([]db.EventoModel, error)
+	listEventos           func(ctx context.Context, incluirBorradores, incluirArchivados bool) ([]db.EventoModel, error)
 	getEventoByID         func(ctx context.Context, id int) (*db.EventoModel, error)
 	updateEvento          func(ctx context.Context, req dto.UpdateEventoRequest, start, end, cierre time.Time) (*db.EventoModel, error)
-	deleteEvento          func(ctx context.Context, id int) error
-	cerrarInscripciones   func(ctx context.Context, eventoID int) (*db.EventoModel, error)
-	abrirInscripciones    func(ctx context.Context, eventoID int) (*db.EventoModel, error)
-	getFechasOcupadas     func(ctx context.Context) ([]dto.RangoFechas, error)
+	deleteEvento          func(ctx context.Context, id int, actor string) error
+	cerrarInscripciones   func(ctx context.Context, eventoID int, actor string) (*db.EventoModel, error)
+	abrirInscripciones    func(ctx context.Context, eventoID int, actor string) (*db.EventoModel, error)
+	getFechasOcupadas     func(ctx context.Context, sedeID *int) ([]dto.RangoFechas, error)
+	actualizarCapacidad   func(ctx context.Context, eventoID int, capacidad *int) (*db.EventoModel, error)
+	detallesPorEvento     func(ctx context.Context, eventoIDs []int) (map[int]dto.EventoDetalle, error)
+	publicarEvento        func(ctx context.Context, id int, actor string) (*db.EventoModel, error)
+	historialEstados      func(ctx context.Context, id int) ([]dto.EstadoHistorialResponse, error)
+	zonaHoraria           func(ctx context.Context, id int) (string, error)
+	crearSerie            func(ctx context.Context, req dto.CreateSerieRequest, primera domain.Fechas, now time.Time) (int, error)
+	getSerie              func(ctx context.Context, id int) (dto.SerieResponse, []db.EventoModel, error)
+	actualizarFuturas     func(ctx context.Context, req dto.UpdateEventoRequest, start, end, cierre time.Time) ([]db.EventoModel, error)
+	clonarEvento          func(ctx context.Context, origenID int, req dto.ClonarEventoRequest, start, now time.Time) (*db.EventoModel, error)
+	buscarEventos         func(ctx context.Context, req dto.BusquedaEventosRequest, incluirBorradores bool, now time.Time) (dto.BusquedaEventosResponse, []db.EventoModel, error)
+	actualizarPortada     func(ctx context.Context, id int, imagen io.Reader) (string, error)
+	eliminarPortada       func(ctx context.Context, id int) error
+	versionesEvento       func(ctx context.Context, id int) ([]dto.VersionEventoResponse, error)
+	versionEvento         func(ctx context.Context, id, version int) (dto.VersionEventoResponse, error)
+	diferenciaVersiones   func(ctx context.Context, id, desde, hasta int) (dto.DiferenciaVersionesResponse, error)
+	reprogramarEvento     func(ctx context.Context, id int, req dto.ReprogramarEventoRequest, start time.Time, cierre *time.Time, enlace string, now time.Time) (dto.ReprogramacionResponse, error)
+	reprogramaciones      func(ctx context.Context, id int) ([]dto.ReprogramacionResponse, error)
+	reprogramacion        func(ctx context.Context, id, reprogramacionID int) (dto.ReprogramacionResponse, error)
+	consultarRespuesta    func(ctx context.Context, token string, now time.Time) (dto.RespuestaReprogramacionResponse, error)
+	responderRespuesta    func(ctx context.Context, req dto.RespuestaReprogramacionRequest, now time.Time) (dto.RespuestaReprogramacionResponse, error)
+	resumenEvento         func(ctx context.Context, id int) (dto.ResumenEventoResponse, error)
+	importarEventos       func(ctx context.Context, filas []dto.EventoImportado, confirmar bool, actor string, now time.Time) (dto.ImportacionEventosResponse, error)
+	dashboardEvento       func(ctx context.Context, id int, now time.Time) (dto.DashboardEventoResponse, error)
 }
 
 func (m mockEventService) EnsureNombreUnico(ctx context.Context, nombre string) error {
@@ -36,11 +75,11 @@ func (m mockEventService) EnsureNombreUnico(ctx context.Context, nombre string)
 	return m.ensureNombreUnico(ctx, nombre)
 }
 
-func (m mockEventService) EnsureNoSolapamiento(ctx context.Context, start, end time.Time) error {
-	if m.ensureNoSolapamiento == nil {
-		return nil
+func (m mockEventService) BuscarConflictos(ctx context.Context, sedeID int, start, end time.Time, excluirID int) ([]dto.ConflictoEvento, error) {
+	if m.buscarConflictos == nil {
+		return []dto.ConflictoEvento{}, nil
 	}
-	return m.ensureNoSolapamiento(ctx, start, end)
+	return m.buscarConflictos(ctx, sedeID, start, end, excluirID)
 }
 
 func (m mockEventService) CreateEvento(ctx context.Context, req dto.CreateEventoRequest, start, end, cierre time.Time) (*db.EventoModel, error) {
@@ -50,11 +89,11 @@ func (m mockEventService) CreateEvento(ctx context.Context, req dto.CreateEvento
 	return m.createEvento(ctx, req, start, end, cierre)
 }
 
-func (m mockEventService) ListEventos(ctx context.Context) ([]db.EventoModel, error) {
+func (m mockEventService) ListEventos(ctx context.Context, incluirBorradores, incluirArchivados bool) ([]db.EventoModel, error) {
 	if m.listEventos == nil {
 		return nil, errors.New("not implemented")
 	}
-	return m.listEventos(ctx)
+	return m.listEventos(ctx, incluirBorradores, incluirArchivados)
 }
 
 func (m mockEventService) GetEventoByID(ctx context.Context, id int) (*db.EventoModel, error) {
@@ -71,32 +110,193 @@ func (m mockEventService) UpdateEvento(ctx context.Context, req dto.UpdateEvento
 	return m.updateEvento(ctx, req, start, end, cierre)
 }
 
-func (m mockEventService) DeleteEvento(ctx context.Context, id int) error {
+func (m mockEventService) DeleteEvento(ctx context.Context, id int, actor string) error {
 	if m.deleteEvento == nil {
 		return errors.New("not implemented")
 	}
-	return m.deleteEvento(ctx, id)
+	return m.deleteEvento(ctx, id, actor)
 }
 
-func (m mockEventService) CerrarInscripciones(ctx context.Context, eventoID int) (*db.EventoModel, error) {
+func (m mockEventService) CerrarInscripciones(ctx context.Context, eventoID int, actor string) (*db.EventoModel, error) {
 	if m.cerrarInscripciones == nil {
 		return nil, errors.New("not implemented")
 	}
-	return m.cerrarInscripciones(ctx, eventoID)
+	return m.cerrarInscripciones(ctx, eventoID, actor)
 }
 
-func (m mockEventService) AbrirInscripciones(ctx context.Context, eventoID int) (*db.EventoModel, error) {
+func (m mockEventService) AbrirInscripciones(ctx context.Context, eventoID int, actor string) (*db.EventoModel, error) {
 	if m.abrirInscripciones == nil {
 		return nil, errors.New("not implemented")
 	}
-	return m.abrirInscripciones(ctx, eventoID)
+	return m.abrirInscripciones(ctx, eventoID, actor)
 }
 
-func (m mockEventService) GetFechasOcupadas(ctx context.Context) ([]dto.RangoFechas, error) {
+func (m mockEventService) GetFechasOcupadas(ctx context.Context, sedeID *int) ([]dto.RangoFechas, error) {
 	if m.getFechasOcupadas == nil {
 		return nil, errors.New("not implemented")
 	}
-	return m.getFechasOcupadas(ctx)
+	return m.getFechasOcupadas(ctx, sedeID)
+}
+
+func (m mockEventService) ActualizarCapacidad(ctx context.Context, eventoID int, capacidad *int) (*db.EventoModel, error) {
+	if m.actualizarCapacidad == nil {
+		return nil, errors.New("not implemented")
+	}
+	return m.actualizarCapacidad(ctx, eventoID, capacidad)
+}
+
+func (m mockEventService) DetallesPorEvento(ctx context.Context, eventoIDs []int) (map[int]dto.EventoDetalle, error) {
+	if m.detallesPorEvento == nil {
+		return map[int]dto.EventoDetalle{}, nil
+	}
+	return m.detallesPorEvento(ctx, eventoIDs)
+}
+
+func (m mockEventService) PublicarEvento(ctx context.Context, id int, actor string) (*db.EventoModel, error) {
+	if m.publicarEvento == nil {
+		return nil, errors.New("not implemented")
+	}
+	return m.publicarEvento(ctx, id, actor)
+}
+
+func (m mockEventService) HistorialEstados(ctx context.Context, id int) ([]dto.EstadoHistorialResponse, error) {
+	if m.historialEstados == nil {
+		return nil, errors.New("not implemented")
+	}
+	return m.historialEstados(ctx, id)
+}
+
+func (m mockEventService) ZonaHoraria(ctx context.Context, id int) (string, error) {
+	if m.zonaHoraria == nil {
+		return "", nil
+	}
+	return m.zonaHoraria(ctx, id)
+}
+
+func (m mockEventService) CrearSerie(ctx context.Context, req dto.CreateSerieRequest, primera domain.Fechas, now time.Time) (int, error) {
+	if m.crearSerie == nil {
+		return 0, errors.New("not implemented")
+	}
+	return m.crearSerie(ctx, req, primera, now)
+}
+
+func (m mockEventService) GetSerie(ctx context.Context, id int) (dto.SerieResponse, []db.EventoModel, error) {
+	if m.getSerie == nil {
+		return dto.SerieResponse{}, nil, errors.New("not implemented")
+	}
+	return m.getSerie(ctx, id)
+}
+
+func (m mockEventService) ActualizarFuturas(ctx context.Context, req dto.UpdateEventoRequest, start, end, cierre time.Time) ([]db.EventoModel, error) {
+	if m.actualizarFuturas == nil {
+		return nil, errors.New("not implemented")
+	}
+	return m.actualizarFuturas(ctx, req, start, end, cierre)
+}
+
+func (m mockEventService) ClonarEvento(ctx context.Context, origenID int, req dto.ClonarEventoRequest, start, now time.Time) (*db.EventoModel, error) {
+	if m.clonarEvento == nil {
+		return nil, errors.New("not implemented")
+	}
+	return m.clonarEvento(ctx, origenID, req, start, now)
+}
+
+func (m mockEventService) BuscarEventos(ctx context.Context, req dto.BusquedaEventosRequest, incluirBorradores bool, now time.Time) (dto.BusquedaEventosResponse, []db.EventoModel, error) {
+	if m.buscarEventos == nil {
+		return dto.BusquedaEventosResponse{}, nil, errors.New("not implemented")
+	}
+	return m.buscarEventos(ctx, req, incluirBorradores, now)
+}
+
+func (m mockEventService) ActualizarPortada(ctx context.Context, id int, imagen io.Reader) (string, error) {
+	if m.actualizarPortada == nil {
+		return "", errors.New("not implemented")
+	}
+	return m.actualizarPortada(ctx, id, imagen)
+}
+
+func (m mockEventService) EliminarPortada(ctx context.Context, id int) error {
+	if m.eliminarPortada == nil {
+		return errors.New("not implemented")
+	}
+	return m.eliminarPortada(ctx, id)
+}
+
+func (m mockEventService) VersionesEvento(ctx context.Context, id int) ([]dto.VersionEventoResponse, error) {
+	if m.versionesEvento == nil {
+		return nil, errors.New("not implemented")
+	}
+	return m.versionesEvento(ctx, id)
+}
+
+func (m mockEventService) VersionEvento(ctx context.Context, id, version int) (dto.VersionEventoResponse, error) {
+	if m.versionEvento == nil {
+		return dto.VersionEventoResponse{}, errors.New("not implemented")
+	}
+	return m.versionEvento(ctx, id, version)
+}
+
+func (m mockEventService) DiferenciaVersiones(ctx context.Context, id, desde, hasta int) (dto.DiferenciaVersionesResponse, error) {
+	if m.diferenciaVersiones == nil {
+		return dto.DiferenciaVersionesResponse{}, errors.New("not implemented")
+	}
+	return m.diferenciaVersiones(ctx, id, desde, hasta)
+}
+
+func (m mockEventService) ReprogramarEvento(ctx context.Context, id int, req dto.ReprogramarEventoRequest, start time.Time, cierre *time.Time, enlace string, now time.Time) (dto.ReprogramacionResponse, error) {
+	if m.reprogramarEvento == nil {
+		return dto.ReprogramacionResponse{}, errors.New("not implemented")
+	}
+	return m.reprogramarEvento(ctx, id, req, start, cierre, enlace, now)
+}
+
+func (m mockEventService) ReprogramacionesEvento(ctx context.Context, id int) ([]dto.ReprogramacionResponse, error) {
+	if m.reprogramaciones == nil {
+		return nil, errors.New("not implemented")
+	}
+	return m.reprogramaciones(ctx, id)
+}
+
+func (m mockEventService) ReprogramacionEvento(ctx context.Context, id, reprogramacionID int) (dto.ReprogramacionResponse, error) {
+	if m.reprogramacion == nil {
+		return dto.ReprogramacionResponse{}, errors.New("not implemented")
+	}
+	return m.reprogramacion(ctx, id, reprogramacionID)
+}
+
+func (m mockEventService) ConsultarReprogramacion(ctx context.Context, token string, now time.Time) (dto.RespuestaReprogramacionResponse, error) {
+	if m.consultarRespuesta == nil {
+		return dto.RespuestaReprogramacionResponse{}, errors.New("not implemented")
+	}
+	return m.consultarRespuesta(ctx, token, now)
+}
+
+func (m mockEventService) ResponderReprogramacion(ctx context.Context, req dto.RespuestaReprogramacionRequest, now time.Time) (dto.RespuestaReprogramacionResponse, error) {
+	if m.responderRespuesta == nil {
+		return dto.RespuestaReprogramacionResponse{}, errors.New("not implemented")
+	}
+	return m.responderRespuesta(ctx, req, now)
+}
+
+func (m mockEventService) ResumenEvento(ctx context.Context, id int) (dto.ResumenEventoResponse, error) {
+	if m.resumenEvento == nil {
+		return dto.ResumenEventoResponse{}, errors.New("not implemented")
+	}
+	return m.resumenEvento(ctx, id)
+}
+
+func (m mockEventService) ImportarEventos(ctx context.Context, filas []dto.EventoImportado, confirmar bool, actor string, now time.Time) (dto.ImportacionEventosResponse, error) {
+	if m.importarEventos == nil {
+		return dto.ImportacionEventosResponse{}, errors.New("not implemented")
+	}
+	return m.importarEventos(ctx, filas, confirmar, actor, now)
+}
+
+func (m mockEventService) DashboardEvento(ctx context.Context, id int, now time.Time) (dto.DashboardEventoResponse, error) {
+	if m.dashboardEvento == nil {
+		return dto.DashboardEventoResponse{}, errors.New("not implemented")
+	}
+	return m.dashboardEvento(ctx, id, now)
 }
 
 func TestServeHTTPMethodNotAllowed(t *testing.T) {
@@ -145,6 +345,10 @@ func TestCreateEvento(t *testing.T) {
 			createEvento: func(_ context.Context, req dto.CreateEventoRequest, startDate, endDate, cierreDate time.Time) (*db.EventoModel, error) {
 				return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: 1, Nombre: req.Nombre, FechaInicio: startDate, FechaFin: endDate, FechaCierreInscripcion: cierreDate, InscripcionesAbiertasManual: true, Ubicacion: req.Ubicacion}}, nil
 			},
+			publicarEvento: func(_ context.Context, _ int, _ string) (*db.EventoModel, error) {
+				t.Fatalf("a new event must stay a draft unless publicar is sent")
+				return nil, nil
+			},
 		}
 
 		h := NewWithService(svc)
@@ -161,7 +365,7 @@ func TestListEventos(t *testing.T) {
 	rr := httptest.NewRecorder()
 
 	svc := mockEventService{
-		listEventos: func(_ context.Context) ([]db.EventoModel, error) {
+		listEventos: func(_ context.Context, _, _ bool) ([]db.EventoModel, error) {
 			return []db.EventoModel{}, nil
 		},
 	}
@@ -217,6 +421,54 @@ func TestUpdateEvento(t *testing.T) {
 	}
 }
 
+func TestUpdateEventoSedeOpcional(t *testing.T) {
+	now := time.Now()
+	start := now.Add(48 * time.Hour)
+	end := now.Add(72 * time.Hour)
+	cierre := now.Add(24 * time.Hour)
+	base := `"id_evento": 1, "nombre": "Evento Actualizado", "ubicacion": "Caracas, Venezuela",
+		"fecha_inicio": "` + start.Format("02/01/2006") + `", "fecha_fin": "` + end.Format("02/01/2006") + `",
+		"fecha_cierre_inscripcion": "` + cierre.Format("02/01/2006") + `"`
+	tres := 3
+
+	cases := []struct {
+		name   string
+		body   string
+		cambia bool
+		sede   *int
+	}{
+		{"without id_sede", "{" + base + "}", false, nil},
+		{"null id_sede", "{" + base + `, "id_sede": null}`, true, nil},
+		{"with id_sede", "{" + base + `, "id_sede": 3}`, true, &tres},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/eventos", bytes.NewBufferString(tc.body))
+			rr := httptest.NewRecorder()
+
+			svc := mockEventService{
+				getEventoByID: func(_ context.Context, id int) (*db.EventoModel, error) {
+					return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: id, FechaInicio: start, FechaFin: end, FechaCierreInscripcion: cierre}}, nil
+				},
+				updateEvento: func(_ context.Context, got dto.UpdateEventoRequest, startDate, endDate, cierreDate time.Time) (*db.EventoModel, error) {
+					if got.CambiaSede != tc.cambia {
+						t.Fatalf("expected CambiaSede %v, got %v", tc.cambia, got.CambiaSede)
+					}
+					if (got.IDSede == nil) != (tc.sede == nil) || (got.IDSede != nil && *got.IDSede != *tc.sede) {
+						t.Fatalf("unexpected sede: %v", got.IDSede)
+					}
+					return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: got.ID, Nombre: got.Nombre, FechaInicio: startDate, FechaFin: endDate, FechaCierreInscripcion: cierreDate}}, nil
+				},
+			}
+			NewWithService(svc).ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
 func TestPatchEvento(t *testing.T) {
 	t.Run("invalid action", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPatch, "/api/eventos?action=unknown&id=1", nil)
@@ -248,7 +500,7 @@ func TestPatchEvento(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		svc := mockEventService{
-			cerrarInscripciones: func(_ context.Context, eventoID int) (*db.EventoModel, error) {
+			cerrarInscripciones: func(_ context.Context, eventoID int, _ string) (*db.EventoModel, error) {
 				return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: eventoID, Nombre: "Evento", FechaInicio: time.Now().Add(24 * time.Hour), FechaFin: time.Now().Add(48 * time.Hour), FechaCierreInscripcion: time.Now().Add(12 * time.Hour), InscripcionesAbiertasManual: false, Ubicacion: "Caracas, Venezuela"}}, nil
 			},
 		}
@@ -267,7 +519,7 @@ func TestPatchEvento(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		svc := mockEventService{
-			abrirInscripciones: func(_ context.Context, eventoID int) (*db.EventoModel, error) {
+			abrirInscripciones: func(_ context.Context, eventoID int, _ string) (*db.EventoModel, error) {
 				return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: eventoID, Nombre: "Evento", FechaInicio: time.Now().Add(24 * time.Hour), FechaFin: time.Now().Add(48 * time.Hour), FechaCierreInscripcion: time.Now().Add(12 * time.Hour), InscripcionesAbiertasManual: true, Ubicacion: "Caracas, Venezuela"}}, nil
 			},
 		}
@@ -281,6 +533,171 @@ func TestPatchEvento(t *testing.T) {
 	})
 }
 
+func TestPatchEventoCapacidad(t *testing.T) {
+	evento := func(id int) *db.EventoModel {
+		return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: id, Nombre: "Evento", FechaInicio: time.Now().Add(24 * time.Hour), FechaFin: time.Now().Add(48 * time.Hour), FechaCierreInscripcion: time.Now().Add(12 * time.Hour), InscripcionesAbiertasManual: true, Ubicacion: "Caracas, Venezuela"}}
+	}
+
+	t.Run("invalid capacity", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/eventos?action=capacidad&id=4", bytes.NewBufferString(`{"capacidad":0}`))
+		rr := httptest.NewRecorder()
+
+		h := NewWithService(mockEventService{})
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/eventos?action=capacidad&id=4", bytes.NewBufferString(`{"capacidad":10}`))
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			actualizarCapacidad: func(_ context.Context, _ int, _ *int) (*db.EventoModel, error) {
+				return nil, service.ErrNotFound
+			},
+		}
+
+		h := NewWithService(svc)
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/eventos?action=capacidad&id=4", bytes.NewBufferString(`{"capacidad":2}`))
+		rr := httptest.NewRecorder()
+
+		capacidad, disponibles := 2, 0
+		svc := mockEventService{
+			actualizarCapacidad: func(_ context.Context, eventoID int, c *int) (*db.EventoModel, error) {
+				if c == nil || *c != 2 {
+					t.Fatalf("expected capacity 2, got %v", c)
+				}
+				return evento(eventoID), nil
+			},
+			detallesPorEvento: func(_ context.Context, ids []int) (map[int]dto.EventoDetalle, error) {
+				return map[int]dto.EventoDetalle{ids[0]: {Capacidad: &capacidad, CuposDisponibles: &disponibles, EnEspera: 3}}, nil
+			},
+		}
+
+		h := NewWithService(svc)
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+		}
+		var res dto.EventoResponse
+		if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if res.Capacidad == nil || *res.Capacidad != 2 || res.CuposDisponibles == nil || *res.CuposDisponibles != 0 || res.EnEspera != 3 {
+			t.Fatalf("unexpected capacity figures: %+v", res)
+		}
+	})
+}
+
+func TestPatchEventoPublicar(t *testing.T) {
+	t.Run("invalid transition", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/eventos?action=publicar&id=5", nil)
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			publicarEvento: func(_ context.Context, _ int, _ string) (*db.EventoModel, error) {
+				return nil, domain.ErrTransicionInvalida
+			},
+		}
+
+		h := NewWithService(svc)
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected %d, got %d", http.StatusConflict, rr.Code)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/eventos?action=publicar&id=5", nil)
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			publicarEvento: func(_ context.Context, id int, _ string) (*db.EventoModel, error) {
+				return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: id, Nombre: "Evento", FechaInicio: time.Now().Add(24 * time.Hour), FechaFin: time.Now().Add(48 * time.Hour), FechaCierreInscripcion: time.Now().Add(12 * time.Hour), InscripcionesAbiertasManual: true, Ubicacion: "Caracas, Venezuela"}}, nil
+			},
+			detallesPorEvento: func(_ context.Context, ids []int) (map[int]dto.EventoDetalle, error) {
+				return map[int]dto.EventoDetalle{ids[0]: {Estado: domain.EstadoPublicado}}, nil
+			},
+		}
+
+		h := NewWithService(svc)
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+		}
+		var res dto.EventoResponse
+		if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if res.Estado != domain.EstadoPublicado {
+			t.Fatalf("expected estado %q, got %q", domain.EstadoPublicado, res.Estado)
+		}
+	})
+}
+
+func TestListEventosHidesDraftsFromParticipants(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/eventos", nil)
+	rr := httptest.NewRecorder()
+
+	svc := mockEventService{
+		listEventos: func(_ context.Context, incluirBorradores, _ bool) ([]db.EventoModel, error) {
+			if incluirBorradores {
+				t.Fatal("drafts should not be listed for participants")
+			}
+			return []db.EventoModel{}, nil
+		},
+	}
+
+	h := NewWithService(svc)
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+}
+
+func TestListEventosArchivados(t *testing.T) {
+	cases := []struct {
+		target string
+		want   bool
+	}{
+		{"/api/eventos", false},
+		{"/api/eventos?archivados=true", true},
+	}
+	for _, tc := range cases {
+		var got bool
+		svc := mockEventService{
+			listEventos: func(_ context.Context, _, incluirArchivados bool) ([]db.EventoModel, error) {
+				got = incluirArchivados
+				return []db.EventoModel{}, nil
+			},
+		}
+		rr := httptest.NewRecorder()
+		NewWithService(svc).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected %d, got %d", tc.target, http.StatusOK, rr.Code)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: expected incluirArchivados %v, got %v", tc.target, tc.want, got)
+		}
+	}
+}
+
 func TestCreateEventoNameConflict(t *testing.T) {
 	now := time.Now()
 	start := now.Add(48 * time.Hour)
@@ -311,3 +728,730 @@ func TestCreateEventoNameConflict(t *testing.T) {
 		t.Fatalf("expected %d, got %d", http.StatusConflict, rr.Code)
 	}
 }
+
+func TestCreateEventoSedeConflict(t *testing.T) {
+	now := time.Now()
+	sedeID := 3
+	reqBody := dto.CreateEventoRequest{
+		Nombre:                 "Evento Prueba",
+		FechaInicio:            now.Add(48 * time.Hour).Format("02/01/2006"),
+		FechaFin:               now.Add(72 * time.Hour).Format("02/01/2006"),
+		FechaCierreInscripcion: now.Add(24 * time.Hour).Format("02/01/2006"),
+		Ubicacion:              "Caracas, Venezuela",
+		IDSede:                 &sedeID,
+	}
+
+	t.Run("reports conflicting events", func(t *testing.T) {
+		payload, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos", bytes.NewBuffer(payload))
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			createEvento: func(_ context.Context, req dto.CreateEventoRequest, _, _, _ time.Time) (*db.EventoModel, error) {
+				if req.IDSede == nil || *req.IDSede != sedeID {
+					t.Fatalf("expected sede %d, got %v", sedeID, req.IDSede)
+				}
+				return nil, &service.ConflictoError{Conflictos: []dto.ConflictoEvento{{ID: 7, Nombre: "Otro", IDSede: sedeID}}}
+			},
+		}
+
+		h := NewWithService(svc)
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected %d, got %d", http.StatusConflict, rr.Code)
+		}
+		var body struct {
+			Conflictos []dto.ConflictoEvento `json:"conflictos"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(body.Conflictos) != 1 || body.Conflictos[0].ID != 7 {
+			t.Fatalf("unexpected conflictos: %+v", body.Conflictos)
+		}
+	})
+
+	t.Run("override requires organizer", func(t *testing.T) {
+		forzado := reqBody
+		forzado.ForzarConflicto = true
+		payload, _ := json.Marshal(forzado)
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos", bytes.NewBuffer(payload))
+		rr := httptest.NewRecorder()
+
+		h := NewWithService(mockEventService{})
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+}
+
+func TestCrearSerie(t *testing.T) {
+	now := time.Now()
+	reqBody := dto.CreateSerieRequest{
+		CreateEventoRequest: dto.CreateEventoRequest{
+			Nombre:                 "Taller mensual",
+			FechaInicio:            now.Add(48 * time.Hour).Format("02/01/2006"),
+			FechaFin:               now.Add(72 * time.Hour).Format("02/01/2006"),
+			FechaCierreInscripcion: now.Add(24 * time.Hour).Format("02/01/2006"),
+			Ubicacion:              "Caracas, Venezuela",
+		},
+		Regla: "FREQ=MONTHLY;COUNT=3",
+	}
+
+	t.Run("invalid rule", func(t *testing.T) {
+		payload, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos/series", bytes.NewBuffer(payload))
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			crearSerie: func(_ context.Context, _ dto.CreateSerieRequest, _ domain.Fechas, _ time.Time) (int, error) {
+				return 0, domain.ErrReglaInvalida
+			},
+		}
+		NewWithService(svc).SeriesHandler(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		payload, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos/series", bytes.NewBuffer(payload))
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			crearSerie: func(_ context.Context, got dto.CreateSerieRequest, primera domain.Fechas, _ time.Time) (int, error) {
+				if got.Regla != reqBody.Regla || !primera.Cierre.Before(primera.Inicio) {
+					t.Fatalf("unexpected series request: %+v %+v", got, primera)
+				}
+				return 4, nil
+			},
+			getSerie: func(_ context.Context, id int) (dto.SerieResponse, []db.EventoModel, error) {
+				eventos := []db.EventoModel{
+					{InnerEvento: db.InnerEvento{IDEvento: 10, Nombre: "Taller mensual"}},
+					{InnerEvento: db.InnerEvento{IDEvento: 11, Nombre: "Taller mensual"}},
+				}
+				return dto.SerieResponse{IDSerie: id, Regla: reqBody.Regla}, eventos, nil
+			},
+		}
+		NewWithService(svc).SeriesHandler(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+		}
+		var res dto.SerieResponse
+		if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if res.IDSerie != 4 || len(res.Eventos) != 2 {
+			t.Fatalf("unexpected response: %+v", res)
+		}
+	})
+}
+
+func TestUpdateEventoFuturas(t *testing.T) {
+	now := time.Now()
+	start := now.Add(48 * time.Hour)
+	end := now.Add(72 * time.Hour)
+	cierre := now.Add(24 * time.Hour)
+
+	reqBody := dto.UpdateEventoRequest{
+		ID:                     1,
+		Nombre:                 "Taller mensual",
+		FechaInicio:            start.Format("02/01/2006"),
+		FechaFin:               end.Format("02/01/2006"),
+		FechaCierreInscripcion: cierre.Format("02/01/2006"),
+		Ubicacion:              "Caracas, Venezuela",
+		Alcance:                dto.AlcanceFuturas,
+	}
+	payload, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPut, "/api/eventos", bytes.NewBuffer(payload))
+	rr := httptest.NewRecorder()
+
+	svc := mockEventService{
+		getEventoByID: func(_ context.Context, id int) (*db.EventoModel, error) {
+			return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: id, FechaCierreInscripcion: cierre}}, nil
+		},
+		updateEvento: func(_ context.Context, _ dto.UpdateEventoRequest, _, _, _ time.Time) (*db.EventoModel, error) {
+			t.Fatal("a single occurrence should not be updated")
+			return nil, nil
+		},
+		actualizarFuturas: func(_ context.Context, req dto.UpdateEventoRequest, _, _, _ time.Time) ([]db.EventoModel, error) {
+			return []db.EventoModel{
+				{InnerEvento: db.InnerEvento{IDEvento: req.ID}},
+				{InnerEvento: db.InnerEvento{IDEvento: req.ID + 1}},
+			}, nil
+		},
+	}
+
+	h := NewWithService(svc)
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	var res []dto.EventoResponse
+	if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("expected 2 updated occurrences, got %d", len(res))
+	}
+}
+
+func TestClonarEvento(t *testing.T) {
+	start := time.Now().AddDate(1, 0, 0)
+	body := func(req dto.ClonarEventoRequest) *bytes.Buffer {
+		payload, _ := json.Marshal(req)
+		return bytes.NewBuffer(payload)
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos?action=clonar&id=9", body(dto.ClonarEventoRequest{
+			Nombre:      "Congreso Nacional",
+			FechaInicio: start.Format(time.RFC3339),
+		}))
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			zonaHoraria: func(_ context.Context, _ int) (string, error) {
+				return "", service.ErrNotFound
+			},
+		}
+		NewWithService(svc).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	t.Run("invalid copy", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos?action=clonar&id=9", body(dto.ClonarEventoRequest{
+			Nombre:      "Congreso Nacional",
+			FechaInicio: start.Format(time.RFC3339),
+		}))
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			clonarEvento: func(_ context.Context, _ int, _ dto.ClonarEventoRequest, _, _ time.Time) (*db.EventoModel, error) {
+				return nil, service.ErrClonInvalido
+			},
+		}
+		NewWithService(svc).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos?action=clonar&id=9", body(dto.ClonarEventoRequest{
+			Nombre:            "Congreso Nacional",
+			FechaInicio:       start.Format(time.RFC3339),
+			ConservarPonentes: true,
+		}))
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			clonarEvento: func(_ context.Context, origenID int, req dto.ClonarEventoRequest, got, _ time.Time) (*db.EventoModel, error) {
+				if origenID != 9 || !req.ConservarPonentes || !got.Equal(start.Truncate(time.Second)) {
+					t.Fatalf("unexpected clone request: %d %+v %s", origenID, req, got)
+				}
+				return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: 10, Nombre: req.Nombre, FechaInicio: got}}, nil
+			},
+			createEvento: func(_ context.Context, _ dto.CreateEventoRequest, _, _, _ time.Time) (*db.EventoModel, error) {
+				t.Fatal("clone should not go through plain creation")
+				return nil, nil
+			},
+		}
+		NewWithService(svc).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+		}
+	})
+}
+
+func TestBuscarEventos(t *testing.T) {
+	invalidas := []string{
+		"mes=2026-13",
+		"inscripcion=todas",
+		"orden=precio",
+		"limite=0",
+		"limite=500",
+		"categoria=ciencia%3Bdrop",
+	}
+	for _, query := range invalidas {
+		req := httptest.NewRequest(http.MethodGet, "/api/eventos/buscar?"+query, nil)
+		rr := httptest.NewRecorder()
+		NewWithService(mockEventService{}).BuscarHandler(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected %d, got %d", query, http.StatusBadRequest, rr.Code)
+		}
+	}
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/eventos/buscar?cursor=xyz", nil)
+		rr := httptest.NewRecorder()
+		svc := mockEventService{
+			buscarEventos: func(_ context.Context, _ dto.BusquedaEventosRequest, _ bool, _ time.Time) (dto.BusquedaEventosResponse, []db.EventoModel, error) {
+				return dto.BusquedaEventosResponse{}, nil, service.ErrCursorInvalido
+			},
+		}
+		NewWithService(svc).BuscarHandler(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet,
+			"/api/eventos/buscar?q=congreso+salud&categoria=Salud,Ciencia&categoria=salud&ciudad=Caracas&mes=2026-05&inscripcion=abiertas&orden=nombre&limite=5", nil)
+		rr := httptest.NewRecorder()
+
+		siguiente := "abc"
+		svc := mockEventService{
+			buscarEventos: func(_ context.Context, got dto.BusquedaEventosRequest, incluirBorradores bool, _ time.Time) (dto.BusquedaEventosResponse, []db.EventoModel, error) {
+				if got.Texto != "congreso salud" || got.Mes != "2026-05" || got.Inscripcion != dto.InscripcionAbiertas ||
+					got.Orden != dto.OrdenNombre || got.Limite != 5 {
+					t.Fatalf("unexpected request: %+v", got)
+				}
+				if len(got.Categorias) != 2 || got.Categorias[0] != "salud" || got.Categorias[1] != "ciencia" {
+					t.Fatalf("expected normalized categories, got %v", got.Categorias)
+				}
+				if len(got.Ciudades) != 1 || got.Ciudades[0] != "Caracas" {
+					t.Fatalf("unexpected cities: %v", got.Ciudades)
+				}
+				if incluirBorradores {
+					t.Fatal("anonymous callers must not see drafts")
+				}
+				return dto.BusquedaEventosResponse{
+						Facetas: dto.FacetasEventosResponse{
+							Categorias: []dto.FacetaResponse{{Valor: "salud", Total: 3}},
+						},
+						Total:           3,
+						SiguienteCursor: &siguiente,
+					}, []db.EventoModel{
+						{InnerEvento: db.InnerEvento{IDEvento: 1, Nombre: "Congreso de Salud", FechaInicio: time.Now().AddDate(0, 1, 0)}},
+					}, nil
+			},
+		}
+		NewWithService(svc).BuscarHandler(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		var res dto.BusquedaEventosResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if len(res.Eventos) != 1 || res.Eventos[0].ID != 1 || res.Total != 3 {
+			t.Fatalf("unexpected response: %+v", res)
+		}
+		if res.SiguienteCursor == nil || *res.SiguienteCursor != siguiente || len(res.Facetas.Categorias) != 1 {
+			t.Fatalf("expected cursor and facets, got %+v", res)
+		}
+	})
+}
+
+func TestCreateEventoDetalles(t *testing.T) {
+	now := time.Now()
+	reqBody := dto.CreateEventoRequest{
+		Nombre:                 "Congreso 2027: Salud & Tecnología",
+		FechaInicio:            now.Add(48 * time.Hour).Format("02/01/2006"),
+		FechaFin:               now.Add(72 * time.Hour).Format("02/01/2006"),
+		FechaCierreInscripcion: now.Add(24 * time.Hour).Format("02/01/2006"),
+		Ubicacion:              "Caracas, Venezuela",
+		Descripcion:            "## Programa\n\nCharlas y **talleres**.",
+		Organizador:            &dto.OrganizadorEvento{Nombre: "Ana Pérez", Email: "ana@example.com"},
+		Enlaces:                []dto.EnlaceEvento{{Titulo: "Sitio web", URL: "https://congreso.example.com"}},
+	}
+
+	t.Run("invalid link", func(t *testing.T) {
+		invalido := reqBody
+		invalido.Enlaces = []dto.EnlaceEvento{{Titulo: "Script", URL: "javascript:alert(1)"}}
+		payload, _ := json.Marshal(invalido)
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos", bytes.NewBuffer(payload))
+		rr := httptest.NewRecorder()
+
+		NewWithService(mockEventService{}).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		payload, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos", bytes.NewBuffer(payload))
+		rr := httptest.NewRecorder()
+
+		svc := mockEventService{
+			createEvento: func(_ context.Context, got dto.CreateEventoRequest, start, end, cierre time.Time) (*db.EventoModel, error) {
+				if got.Organizador == nil || got.Organizador.Email != "ana@example.com" || len(got.Enlaces) != 1 {
+					t.Fatalf("details not passed to the service: %+v", got)
+				}
+				return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: 5, Nombre: got.Nombre, FechaInicio: start, FechaFin: end, FechaCierreInscripcion: cierre}}, nil
+			},
+		}
+		NewWithService(svc).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	})
+}
+
+func TestPortadaHandler(t *testing.T) {
+	upload := func(campo string, contenido []byte) *http.Request {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile(campo, "portada.png")
+		_, _ = part.Write(contenido)
+		_ = mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/eventos/portada?id=4", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("X-Role", "ADMIN")
+		return req
+	}
+	png := []byte("\x89PNG\r\n\x1a\nresto")
+
+	t.Run("requires manager", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		svc := mockEventService{
+			eliminarPortada: func(_ context.Context, _ int) error {
+				t.Fatal("participants must not change the cover")
+				return nil
+			},
+		}
+		NewWithService(svc).PortadaHandler(rr, httptest.NewRequest(http.MethodDelete, "/api/eventos/portada?id=4", nil))
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		newManagerHandler(mockEventService{}).PortadaHandler(rr, upload("imagen", png))
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("rejected image", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		svc := mockEventService{
+			actualizarPortada: func(_ context.Context, _ int, _ io.Reader) (string, error) {
+				return "", service.ErrPortadaInvalida
+			},
+		}
+		newManagerHandler(svc).PortadaHandler(rr, upload("portada", []byte("texto")))
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("upload", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		url := "/uploads/eventos/4/portada-abc.png"
+		svc := mockEventService{
+			actualizarPortada: func(_ context.Context, id int, imagen io.Reader) (string, error) {
+				contenido, _ := io.ReadAll(imagen)
+				if id != 4 || !bytes.Equal(contenido, png) {
+					t.Fatalf("unexpected upload for %d: %q", id, contenido)
+				}
+				return url, nil
+			},
+			getEventoByID: func(_ context.Context, id int) (*db.EventoModel, error) {
+				return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: id, Nombre: "Congreso"}}, nil
+			},
+			detallesPorEvento: func(_ context.Context, _ []int) (map[int]dto.EventoDetalle, error) {
+				return map[int]dto.EventoDetalle{4: {PortadaURL: &url}}, nil
+			},
+		}
+		newManagerHandler(svc).PortadaHandler(rr, upload("portada", png))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		var res dto.EventoResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if res.PortadaURL == nil || *res.PortadaURL != url {
+			t.Fatalf("expected cover url %s, got %v", url, res.PortadaURL)
+		}
+	})
+
+	t.Run("delete unknown event", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		svc := mockEventService{
+			eliminarPortada: func(_ context.Context, _ int) error {
+				return service.ErrNotFound
+			},
+		}
+		req := httptest.NewRequest(http.MethodDelete, "/api/eventos/portada?id=4", nil)
+		req.Header.Set("X-Role", "ADMIN")
+		newManagerHandler(svc).PortadaHandler(rr, req)
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
+
+func TestVersionesHandler(t *testing.T) {
+	caracas, _ := time.LoadLocation("America/Caracas")
+	now := time.Now().In(caracas)
+	start := time.Date(now.Year(), now.Month(), now.Day()+10, 9, 0, 0, 0, caracas)
+	snapshot := dto.SnapshotEvento{
+		Nombre:                 "Congreso Anterior",
+		FechaInicio:            start,
+		FechaFin:               start.Add(8 * time.Hour),
+		FechaCierreInscripcion: start.Add(-48 * time.Hour),
+		ZonaHoraria:            "America/Caracas",
+		Ubicacion:              "Caracas, Venezuela",
+	}
+
+	var restaurado dto.UpdateEventoRequest
+	svc := mockEventService{
+		versionesEvento: func(_ context.Context, id int) ([]dto.VersionEventoResponse, error) {
+			return []dto.VersionEventoResponse{{Version: 1, Accion: domain.AccionCreado}}, nil
+		},
+		versionEvento: func(_ context.Context, id, version int) (dto.VersionEventoResponse, error) {
+			if version != 1 {
+				return dto.VersionEventoResponse{}, service.ErrVersionNotFound
+			}
+			return dto.VersionEventoResponse{Version: 1, Snapshot: &snapshot}, nil
+		},
+		diferenciaVersiones: func(_ context.Context, id, desde, hasta int) (dto.DiferenciaVersionesResponse, error) {
+			return dto.DiferenciaVersionesResponse{Desde: desde, Hasta: hasta, Cambios: []domain.Cambio{}}, nil
+		},
+		getEventoByID: func(_ context.Context, id int) (*db.EventoModel, error) {
+			return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: id, FechaCierreInscripcion: start.Add(-24 * time.Hour)}}, nil
+		},
+		updateEvento: func(_ context.Context, req dto.UpdateEventoRequest, startDate, endDate, cierreDate time.Time) (*db.EventoModel, error) {
+			restaurado = req
+			return &db.EventoModel{InnerEvento: db.InnerEvento{IDEvento: req.ID, Nombre: req.Nombre, FechaInicio: startDate, FechaFin: endDate, FechaCierreInscripcion: cierreDate}}, nil
+		},
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		query  string
+		want   int
+	}{
+		{"lista", http.MethodGet, "id=1", http.StatusOK},
+		{"una version", http.MethodGet, "id=1&version=1", http.StatusOK},
+		{"diferencia", http.MethodGet, "id=1&desde=1&hasta=2", http.StatusOK},
+		{"diferencia incompleta", http.MethodGet, "id=1&desde=1", http.StatusBadRequest},
+		{"version inexistente", http.MethodGet, "id=1&version=9", http.StatusNotFound},
+		{"id invalido", http.MethodGet, "id=x", http.StatusBadRequest},
+		{"restaurar sin version", http.MethodPost, "id=1", http.StatusBadRequest},
+		{"restaurar", http.MethodPost, "id=1&version=1", http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/eventos/versiones?"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			NewWithService(svc).VersionesHandler(rr, req)
+			if rr.Code != tc.want {
+				t.Fatalf("expected %d, got %d: %s", tc.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	if restaurado.RestauraVersion != 1 || restaurado.Nombre != snapshot.Nombre {
+		t.Fatalf("unexpected restore request: %+v", restaurado)
+	}
+	if restaurado.Descripcion == nil || restaurado.Organizador == nil || restaurado.Categorias == nil || restaurado.Enlaces == nil {
+		t.Fatalf("restore must replace every descriptive field: %+v", restaurado)
+	}
+}
+
+func TestReprogramacionesHandlerRequiresManager(t *testing.T) {
+	svc := mockEventService{
+		reprogramarEvento: func(_ context.Context, _ int, _ dto.ReprogramarEventoRequest, _ time.Time, _ *time.Time, _ string, _ time.Time) (dto.ReprogramacionResponse, error) {
+			t.Fatal("participants must not reschedule events")
+			return dto.ReprogramacionResponse{}, nil
+		},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/eventos/reprogramaciones?id=1", bytes.NewBufferString(`{"fecha_inicio":"2030-01-10T09:00:00Z"}`))
+	rr := httptest.NewRecorder()
+	NewWithService(svc).ReprogramacionesHandler(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestRespuestaReprogramacionHandler(t *testing.T) {
+	svc := mockEventService{
+		consultarRespuesta: func(_ context.Context, token string, _ time.Time) (dto.RespuestaReprogramacionResponse, error) {
+			if token != "abc" {
+				return dto.RespuestaReprogramacionResponse{}, service.ErrEnlaceNotFound
+			}
+			return dto.RespuestaReprogramacionResponse{Respuesta: domain.RespuestaPendiente, PuedeResponder: true}, nil
+		},
+		responderRespuesta: func(_ context.Context, req dto.RespuestaReprogramacionRequest, _ time.Time) (dto.RespuestaReprogramacionResponse, error) {
+			switch {
+			case req.Token != "abc":
+				return dto.RespuestaReprogramacionResponse{}, service.ErrEnlaceNotFound
+			case req.Respuesta != domain.RespuestaConfirmada && req.Respuesta != domain.RespuestaCancelada:
+				return dto.RespuestaReprogramacionResponse{}, domain.ErrRespuestaInvalida
+			case req.Respuesta == domain.RespuestaConfirmada:
+				return dto.RespuestaReprogramacionResponse{}, domain.ErrRespuestaCerrada
+			}
+			return dto.RespuestaReprogramacionResponse{Respuesta: req.Respuesta, ReembolsoPendiente: true}, nil
+		},
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{"consultar", http.MethodGet, "?token=abc", "", http.StatusOK},
+		{"enlace desconocido", http.MethodGet, "?token=otro", "", http.StatusNotFound},
+		{"cancelar", http.MethodPost, "", `{"token":"abc","respuesta":"Cancelada"}`, http.StatusOK},
+		{"ya cancelada", http.MethodPost, "", `{"token":"abc","respuesta":"Confirmada"}`, http.StatusConflict},
+		{"respuesta invalida", http.MethodPost, "", `{"token":"abc","respuesta":"Quizás"}`, http.StatusBadRequest},
+		{"json invalido", http.MethodPost, "", `{`, http.StatusBadRequest},
+		{"metodo", http.MethodDelete, "", "", http.StatusMethodNotAllowed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, RespuestaReprogramacionPath+tc.target, bytes.NewBufferString(tc.body))
+			rr := httptest.NewRecorder()
+			NewWithService(svc).RespuestaReprogramacionHandler(rr, req)
+			if rr.Code != tc.want {
+				t.Fatalf("expected %d, got %d: %s", tc.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestResumenHandlerRequiresManager(t *testing.T) {
+	svc := mockEventService{
+		resumenEvento: func(_ context.Context, _ int) (dto.ResumenEventoResponse, error) {
+			t.Fatal("participants must not see the event summary")
+			return dto.ResumenEventoResponse{}, nil
+		},
+	}
+	cases := []struct {
+		method string
+		target string
+		want   int
+	}{
+		{http.MethodGet, "/api/eventos/resumen?id=1", http.StatusForbidden},
+		{http.MethodGet, "/api/eventos/resumen?id=x", http.StatusBadRequest},
+		{http.MethodPost, "/api/eventos/resumen?id=1", http.StatusMethodNotAllowed},
+	}
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		NewWithService(svc).ResumenHandler(rr, httptest.NewRequest(tc.method, tc.target, nil))
+		if rr.Code != tc.want {
+			t.Fatalf("%s %s: expected %d, got %d", tc.method, tc.target, tc.want, rr.Code)
+		}
+	}
+}
+
+func TestImportarHandlerRequiresManager(t *testing.T) {
+	svc := mockEventService{
+		importarEventos: func(_ context.Context, _ []dto.EventoImportado, _ bool, _ string, _ time.Time) (dto.ImportacionEventosResponse, error) {
+			t.Fatal("participants must not import events")
+			return dto.ImportacionEventosResponse{}, nil
+		},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/eventos/importar", bytes.NewBufferString(`[{"nombre":"Congreso anual"}]`))
+	rr := httptest.NewRecorder()
+	NewWithService(svc).ImportarHandler(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestLeerFilasCSV(t *testing.T) {
+	data := "\ufeffnombre;fecha_inicio;capacidad;categorias;publicar\n" +
+		"Jornada de Física;2030-03-01;50;Ciencia|Física;sí\n" +
+		";;;;\n" +
+		"Jornada de Química;2030-03-02;muchos;;no\n"
+	filas, err := leerFilasCSV([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filas) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(filas))
+	}
+	primera := filas[0]
+	if primera.Fila != 1 || primera.Error != "" || primera.Evento.Nombre != "Jornada de Física" || !primera.Evento.Publicar {
+		t.Fatalf("unexpected first row: %+v", primera)
+	}
+	if primera.Evento.Capacidad == nil || *primera.Evento.Capacidad != 50 || len(primera.Evento.Categorias) != 2 {
+		t.Fatalf("unexpected first row fields: %+v", primera.Evento)
+	}
+	if filas[1].Fila != 3 || filas[1].Error == "" {
+		t.Fatalf("expected the third row to fail on its capacity, got %+v", filas[1])
+	}
+
+	if _, err := leerFilasCSV([]byte("nombre,fecha\nJornada,2030-03-01\n")); err == nil {
+		t.Fatal("expected an unknown column to be rejected")
+	}
+}
+
+func TestValidarFilaImportacion(t *testing.T) {
+	now := time.Date(2030, 1, 10, 12, 0, 0, 0, time.UTC)
+	valida := dto.FilaImportacion{Fila: 1, Evento: dto.CreateEventoRequest{
+		Nombre:                 "Jornada de Física",
+		FechaInicio:            "2030-03-01T09:00",
+		FechaFin:               "2030-03-01T18:00",
+		FechaCierreInscripcion: "2030-02-25",
+		Ubicacion:              "Caracas, Venezuela",
+	}}
+	res := validarFilaImportacion(valida, now)
+	if len(res.Errores) != 0 {
+		t.Fatalf("expected a valid row, got %v", res.Errores)
+	}
+	if res.Evento.ZonaHoraria != domain.ZonaHorariaPredeterminada || res.Fechas.Inicio.IsZero() {
+		t.Fatalf("expected the zone and dates to be filled in, got %+v", res)
+	}
+
+	invalida := valida
+	invalida.Evento.Nombre = "abc"
+	invalida.Evento.FechaFin = "2030-02-01"
+	if res := validarFilaImportacion(invalida, now); len(res.Errores) != 2 {
+		t.Fatalf("expected the name and date errors, got %v", res.Errores)
+	}
+}
+
+func TestDashboardHandlerRequiresManager(t *testing.T) {
+	svc := mockEventService{
+		dashboardEvento: func(_ context.Context, _ int, _ time.Time) (dto.DashboardEventoResponse, error) {
+			t.Fatal("participants must not see the event statistics")
+			return dto.DashboardEventoResponse{}, nil
+		},
+	}
+	cases := []struct {
+		method string
+		target string
+		want   int
+	}{
+		{http.MethodGet, "/api/eventos/dashboard?id=1", http.StatusForbidden},
+		{http.MethodGet, "/api/eventos/dashboard", http.StatusBadRequest},
+		{http.MethodDelete, "/api/eventos/dashboard?id=1", http.StatusMethodNotAllowed},
+	}
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		NewWithService(svc).DashboardHandler(rr, httptest.NewRequest(tc.method, tc.target, nil))
+		if rr.Code != tc.want {
+			t.Fatalf("%s %s: expected %d, got %d", tc.method, tc.target, tc.want, rr.Code)
+		}
+	}
+}
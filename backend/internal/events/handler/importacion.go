@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/validation"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+)
+
+const (
+	maxImportacionBytes = 2 << 20
+	maxFilasImportacion = 500
+)
+
+// columnasImportacion are the CSV columns a bulk import understands, named
+// like the JSON fields. Categories are separated by "|".
+var columnasImportacion = map[string]bool{
+	"nombre": true, "fecha_inicio": true, "fecha_fin": true, "fecha_cierre_inscripcion": true,
+	"ubicacion": true, "capacidad": true, "id_sede": true, "zona_horaria": true, "descripcion": true,
+	"categorias": true, "organizador_nombre": true, "organizador_email": true, "organizador_telefono": true,
+	"publicar": true,
+}
+
+// ImportarHandler serves POST /api/eventos/importar for organizers. The
+// events come as a CSV file with a header row or as a JSON array of create
+// payloads, either as the request body or as the "archivo" field of a
+// multipart form. Every row is validated and reported; nothing is created
+// unless ?confirmar=true, and then only the valid rows are.
+func (h *Handler) ImportarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, "no tienes permisos para importar eventos")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportacionBytes+64<<10)
+	filas, err := leerImportacion(r)
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(filas) == 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "el archivo no contiene eventos")
+		return
+	}
+	if len(filas) > maxFilasImportacion {
+		httperror.WriteJSON(w, http.StatusBadRequest, fmt.Sprintf("no se pueden importar más de %d eventos a la vez", maxFilasImportacion))
+		return
+	}
+
+	now := time.Now()
+	importados := make([]dto.EventoImportado, 0, len(filas))
+	for _, fila := range filas {
+		importados = append(importados, validarFilaImportacion(fila, now))
+	}
+
+	confirmar := r.URL.Query().Get("confirmar") == "true"
+	res, err := h.svc.ImportarEventos(ctx, importados, confirmar, actorFromRequest(r), now)
+	if handleEventoError(w, err) {
+		return
+	}
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// leerImportacion reads the rows of a bulk import, telling CSV from JSON by
+// the file extension or the content type.
+func leerImportacion(r *http.Request) ([]dto.FilaImportacion, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var (
+		contenido io.Reader = r.Body
+		esCSV               = mediaType == "text/csv" || mediaType == "application/csv"
+	)
+	if mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("archivo")
+		if err != nil {
+			return nil, errors.New("falta el archivo a importar")
+		}
+		defer file.Close()
+		contenido = file
+		esCSV = strings.EqualFold(filepath.Ext(header.Filename), ".csv")
+	}
+	data, err := io.ReadAll(io.LimitReader(contenido, maxImportacionBytes+1))
+	if err != nil {
+		return nil, errors.New("no se pudo leer el archivo")
+	}
+	if len(data) > maxImportacionBytes {
+		return nil, errors.New("el archivo supera los 2 MB")
+	}
+	if esCSV {
+		return leerFilasCSV(data)
+	}
+	return leerFilasJSON(data)
+}
+
+func leerFilasJSON(data []byte) ([]dto.FilaImportacion, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.New("json inválido: se espera una lista de eventos")
+	}
+	filas := make([]dto.FilaImportacion, 0, len(raw))
+	for i, item := range raw {
+		fila := dto.FilaImportacion{Fila: i + 1}
+		if err := json.Unmarshal(item, &fila.Evento); err != nil {
+			fila.Error = "json inválido"
+		}
+		filas = append(filas, fila)
+	}
+	return filas, nil
+}
+
+// leerFilasCSV reads a CSV export with a header row. Spreadsheets in
+// Spanish locales separate fields with ";", so the header decides the
+// separator.
+func leerFilasCSV(data []byte) ([]dto.FilaImportacion, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	cabecera, _, _ := strings.Cut(string(data), "\n")
+	reader := csv.NewReader(bytes.NewReader(data))
+	if strings.Count(cabecera, ";") > strings.Count(cabecera, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	registros, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.New("csv inválido: " + err.Error())
+	}
+	if len(registros) == 0 {
+		return nil, nil
+	}
+	columnas := make([]string, len(registros[0]))
+	for i, nombre := range registros[0] {
+		nombre = strings.ToLower(strings.TrimSpace(nombre))
+		if !columnasImportacion[nombre] {
+			return nil, fmt.Errorf("columna desconocida: %q", nombre)
+		}
+		columnas[i] = nombre
+	}
+
+	filas := make([]dto.FilaImportacion, 0, len(registros)-1)
+	for i, registro := range registros[1:] {
+		if vacio(registro) {
+			continue
+		}
+		fila := dto.FilaImportacion{Fila: i + 1}
+		valores := map[string]string{}
+		for j, valor := range registro {
+			if j < len(columnas) {
+				valores[columnas[j]] = strings.TrimSpace(valor)
+			}
+		}
+		fila.Evento, fila.Error = eventoCSV(valores)
+		filas = append(filas, fila)
+	}
+	return filas, nil
+}
+
+func eventoCSV(v map[string]string) (dto.CreateEventoRequest, string) {
+	ev := dto.CreateEventoRequest{
+		Nombre:                 v["nombre"],
+		FechaInicio:            v["fecha_inicio"],
+		FechaFin:               v["fecha_fin"],
+		FechaCierreInscripcion: v["fecha_cierre_inscripcion"],
+		Ubicacion:              v["ubicacion"],
+		ZonaHoraria:            v["zona_horaria"],
+		Descripcion:            v["descripcion"],
+	}
+	if raw := v["categorias"]; raw != "" {
+		ev.Categorias = strings.Split(raw, "|")
+	}
+	if v["organizador_nombre"] != "" || v["organizador_email"] != "" || v["organizador_telefono"] != "" {
+		ev.Organizador = &dto.OrganizadorEvento{
+			Nombre:   v["organizador_nombre"],
+			Email:    v["organizador_email"],
+			Telefono: v["organizador_telefono"],
+		}
+	}
+	if raw := v["capacidad"]; raw != "" {
+		capacidad, err := strconv.Atoi(raw)
+		if err != nil {
+			return ev, "capacidad inválida"
+		}
+		ev.Capacidad = &capacidad
+	}
+	if raw := v["id_sede"]; raw != "" {
+		sede, err := strconv.Atoi(raw)
+		if err != nil || sede <= 0 {
+			return ev, "id_sede inválido"
+		}
+		ev.IDSede = &sede
+	}
+	switch strings.ToLower(v["publicar"]) {
+	case "", "false", "no", "0":
+	case "true", "si", "sí", "1":
+		ev.Publicar = true
+	default:
+		return ev, "publicar debe ser sí o no"
+	}
+	return ev, ""
+}
+
+func vacio(registro []string) bool {
+	for _, valor := range registro {
+		if strings.TrimSpace(valor) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// validarFilaImportacion runs the same field checks as creating a single
+// event, collecting every failure instead of stopping at the first one.
+func validarFilaImportacion(fila dto.FilaImportacion, now time.Time) dto.EventoImportado {
+	res := dto.EventoImportado{Fila: fila.Fila, Evento: fila.Evento}
+	if fila.Error != "" {
+		res.Errores = []string{fila.Error}
+		return res
+	}
+	ev := &res.Evento
+
+	if err := validation.ValidateEventoNombre(ev.Nombre); err != nil {
+		res.Errores = append(res.Errores, err.Error())
+	}
+	if loc, err := domain.CargarZona(strings.TrimSpace(ev.ZonaHoraria)); err != nil {
+		res.Errores = append(res.Errores, err.Error())
+	} else {
+		ev.ZonaHoraria = loc.String()
+		start, end, cierre, err := validation.ValidateEventoFechas(ev.FechaInicio, ev.FechaFin, ev.FechaCierreInscripcion, now.In(loc))
+		if err != nil {
+			res.Errores = append(res.Errores, err.Error())
+		}
+		res.Fechas = domain.Fechas{Inicio: start, Fin: end, Cierre: cierre}
+	}
+	if err := validation.ValidateEventoUbicacion(ev.Ubicacion); err != nil {
+		res.Errores = append(res.Errores, err.Error())
+	}
+	if err := validation.ValidateEventoCapacidad(ev.Capacidad); err != nil {
+		res.Errores = append(res.Errores, err.Error())
+	}
+	categorias, err := validarDetalles(&ev.Descripcion, ev.Categorias, ev.Organizador, ev.Enlaces)
+	if err != nil {
+		res.Errores = append(res.Errores, err.Error())
+	}
+	ev.Categorias = categorias
+	return res
+}
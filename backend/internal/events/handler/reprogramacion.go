@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/service"
+	"project/backend/internal/events/validation"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+	"project/backend/internal/shared/publicurl"
+)
+
+// RespuestaReprogramacionPath is where the links sent with a reschedule
+// point to: RespuestaReprogramacionPath + "?token=" + secret.
+const RespuestaReprogramacionPath = "/api/eventos/reprogramacion/respuesta"
+
+const maxMotivoReprogramacion = 500
+
+// ReprogramacionesHandler serves /api/eventos/reprogramaciones?id=N for
+// organizers. GET lists the reschedules of the event with the summary of
+// their answers, or one reschedule with every answer when
+// &reprogramacion=R is given. POST reschedules the event.
+func (h *Handler) ReprogramacionesHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, err := strconv.Atoi(q.Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if !roles.CanManageEvents(ctx, h.roleService, r) {
+		httperror.WriteJSON(w, http.StatusForbidden, "no tienes permisos para reprogramar eventos")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var res interface{}
+		if raw := q.Get("reprogramacion"); raw != "" {
+			reprogramacionID, err := strconv.Atoi(raw)
+			if err != nil || reprogramacionID <= 0 {
+				httperror.WriteJSON(w, http.StatusBadRequest, "reprogramacion inválida")
+				return
+			}
+			res, err = h.svc.ReprogramacionEvento(ctx, id, reprogramacionID)
+			if handleReprogramacionError(w, err) {
+				return
+			}
+		} else {
+			res, err = h.svc.ReprogramacionesEvento(ctx, id)
+			if handleReprogramacionError(w, err) {
+				return
+			}
+		}
+		w.Header().Set(contentTypeKey, contentTypeJSON)
+		_ = json.NewEncoder(w).Encode(res)
+	case http.MethodPost:
+		h.reprogramarEvento(ctx, w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) reprogramarEvento(ctx context.Context, w http.ResponseWriter, r *http.Request, id int) {
+	var req dto.ReprogramarEventoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(req.Motivo)) > maxMotivoReprogramacion {
+		httperror.WriteJSON(w, http.StatusBadRequest, "el motivo no puede superar los 500 caracteres")
+		return
+	}
+	req.Actor = actorFromRequest(r)
+
+	zona, err := h.svc.ZonaHoraria(ctx, id)
+	if handleEventoError(w, err) {
+		return
+	}
+	loc := domain.Zona(zona)
+	start, err := validation.ParseEventoFecha(req.FechaInicio, loc)
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "Fecha de inicio inválida (formato DD/MM/AAAA o ISO 8601).")
+		return
+	}
+	var cierre *time.Time
+	if strings.TrimSpace(req.FechaCierreInscripcion) != "" {
+		parsed, err := validation.ParseEventoFecha(req.FechaCierreInscripcion, loc)
+		if err != nil {
+			httperror.WriteJSON(w, http.StatusBadRequest, "Fecha de cierre de inscripción inválida (formato DD/MM/AAAA o ISO 8601).")
+			return
+		}
+		cierre = &parsed
+	}
+
+	enlace := publicurl.Base() + RespuestaReprogramacionPath + "?token="
+	res, err := h.svc.ReprogramarEvento(ctx, id, req, start, cierre, enlace, time.Now())
+	if handleReprogramacionError(w, err) {
+		return
+	}
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// RespuestaReprogramacionHandler serves the link participants get when an
+// event is rescheduled. The token is the only credential: GET ?token=T
+// shows the new dates and the current answer, POST {token, respuesta}
+// confirms or cancels the attendance.
+func (h *Handler) RespuestaReprogramacionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var (
+		res dto.RespuestaReprogramacionResponse
+		err error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		res, err = h.svc.ConsultarReprogramacion(ctx, r.URL.Query().Get("token"), time.Now())
+	case http.MethodPost:
+		var req dto.RespuestaReprogramacionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+			return
+		}
+		res, err = h.svc.ResponderReprogramacion(ctx, req, time.Now())
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if handleReprogramacionError(w, err) {
+		return
+	}
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func handleReprogramacionError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrReprogramacionInvalida), errors.Is(err, domain.ErrRespuestaInvalida):
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrNoReprogramable), errors.Is(err, domain.ErrRespuestaCerrada):
+		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrReprogramacionNotFound), errors.Is(err, service.ErrEnlaceNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	default:
+		return handleEventoError(w, err)
+	}
+	return true
+}
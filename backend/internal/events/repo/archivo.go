@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"project/backend/internal/events/domain"
+	inscripciones "project/backend/internal/inscripciones/validation"
+)
+
+// ArchivoRow is the outcome of archiving an event: how many unpaid
+// inscriptions expired with it.
+type ArchivoRow struct {
+	IDEvento  int `json:"id_evento"`
+	Expiradas int `json:"expiradas"`
+}
+
+// ResumenRow holds the figures of the post-event summary.
+type ResumenRow struct {
+	Inscripciones        int `json:"inscripciones"`
+	Pagadas              int `json:"pagadas"`
+	ReembolsosPendientes int `json:"reembolsos_pendientes"`
+	Sesiones             int `json:"sesiones"`
+	SesionesCanceladas   int `json:"sesiones_canceladas"`
+}
+
+// EstadoConteoRow counts the inscriptions of an event in one status.
+type EstadoConteoRow struct {
+	Estado string `json:"estado"`
+	Total  int    `json:"total"`
+}
+
+// IngresoRow is what the paid inscriptions of an event add up to in one
+// currency. Total is a decimal rendered as text.
+type IngresoRow struct {
+	Moneda string `json:"moneda"`
+	Total  string `json:"total"`
+}
+
+// FindPorArchivar lists the finished events not archived yet that ended
+// before hasta.
+func (r *Repository) FindPorArchivar(ctx context.Context, hasta time.Time) ([]EstadoRow, error) {
+	query := estadoSelect + ` WHERE e."estado" = '` + domain.EstadoFinalizado + `' AND NOT e."archivado"
+		AND e."fecha_fin" <= ($1::timestamptz AT TIME ZONE 'UTC')
+		ORDER BY e."fecha_fin", e."id_evento"`
+	var rows []EstadoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, hasta).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Archivar closes the inscriptions of a finished event, expires the pending
+// and waitlisted inscriptions that never paid and marks the event archived,
+// all in one statement. It returns ok false when the event was no longer a
+// finished, unarchived event.
+func (r *Repository) Archivar(ctx context.Context, id int) (ArchivoRow, bool, error) {
+	query := `WITH "archivado" AS (
+			UPDATE "Evento" SET "archivado" = true, "archivado_en" = NOW(), "inscripciones_abiertas_manual" = false
+			WHERE "id_evento" = $1::int AND "estado" = '` + domain.EstadoFinalizado + `' AND NOT "archivado"
+			RETURNING "id_evento"
+		), "vencidas" AS (
+			SELECT i."id_inscripcion", i."estado" FROM "Inscripcion" i
+			JOIN "archivado" a ON a."id_evento" = i."id_evento"
+			WHERE NOT i."estado_pago" AND i."estado" IN ('Pendiente', 'En espera')
+		), "expiradas" AS (
+			UPDATE "Inscripcion" i SET "estado" = 'Expirado', "posicion_espera" = NULL, "updatedAt" = NOW()
+			FROM "vencidas" v WHERE i."id_inscripcion" = v."id_inscripcion"
+		), "historial" AS (
+			INSERT INTO "InscripcionHistorial" ("id_inscripcion", "estado_anterior", "estado_nuevo", "nota", "actor", "fecha_cambio")
+			SELECT v."id_inscripcion", v."estado", 'Expirado', 'Pago no recibido al archivar el evento', 'system', NOW()
+			FROM "vencidas" v
+		)
+		SELECT a."id_evento", (SELECT COUNT(*) FROM "vencidas")::int AS "expiradas" FROM "archivado" a`
+	var rows []ArchivoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return ArchivoRow{}, false, err
+	}
+	if len(rows) == 0 {
+		return ArchivoRow{}, false, nil
+	}
+	return rows[0], true, nil
+}
+
+// FindResumen returns the totals of the post-event summary. Released
+// inscriptions count toward Inscripciones but not toward Pagadas.
+func (r *Repository) FindResumen(ctx context.Context, id int) (ResumenRow, error) {
+	query := `SELECT
+			(SELECT COUNT(*) FROM "Inscripcion" WHERE "id_evento" = $1::int)::int AS "inscripciones",
+			(SELECT COUNT(*) FROM "Inscripcion" WHERE "id_evento" = $1::int AND "estado_pago"
+				AND "estado" NOT IN ` + inscripciones.EstadosLiberados + `)::int AS "pagadas",
+			(SELECT COUNT(*) FROM "Inscripcion" WHERE "id_evento" = $1::int AND "reembolso_pendiente")::int AS "reembolsos_pendientes",
+			(SELECT COUNT(*) FROM "Sesion" WHERE "id_evento" = $1::int)::int AS "sesiones",
+			(SELECT COUNT(*) FROM "Sesion" WHERE "id_evento" = $1::int AND "cancelado")::int AS "sesiones_canceladas"`
+	var rows []ResumenRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return ResumenRow{}, err
+	}
+	if len(rows) == 0 {
+		return ResumenRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// ContarPorEstado counts the inscriptions of an event by status.
+func (r *Repository) ContarPorEstado(ctx context.Context, id int) ([]EstadoConteoRow, error) {
+	query := `SELECT "estado", COUNT(*)::int AS "total" FROM "Inscripcion"
+		WHERE "id_evento" = $1::int GROUP BY "estado" ORDER BY "estado"`
+	var rows []EstadoConteoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListIngresos sums the amounts of the paid inscriptions of an event by
+// currency. Inscriptions without a ticket type carry no amount and are left
+// out.
+func (r *Repository) ListIngresos(ctx context.Context, id int) ([]IngresoRow, error) {
+	query := `SELECT "moneda", SUM("monto")::text AS "total" FROM "Inscripcion"
+		WHERE "id_evento" = $1::int AND "estado_pago" AND "monto" IS NOT NULL AND "moneda" IS NOT NULL
+			AND "estado" NOT IN ` + inscripciones.EstadosLiberados + `
+		GROUP BY "moneda" ORDER BY "moneda"`
+	var rows []IngresoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,213 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/dto"
+)
+
+// FiltroBusqueda selects events from the catalog. Empty fields do not
+// filter. Categories and cities match any of the given values.
+type FiltroBusqueda struct {
+	Texto             string
+	Categorias        []string
+	Ciudades          []string
+	Mes               string
+	Abiertas          *bool
+	IncluirBorradores bool
+	Ahora             time.Time
+}
+
+// CursorBusqueda points just after the last event of a page: Clave is the
+// sort key of that event as returned in BusquedaRow.
+type CursorBusqueda struct {
+	Clave string `json:"clave"`
+	ID    int    `json:"id"`
+}
+
+// BusquedaRow is a catalog hit. Clave is its sort key, used to build the
+// cursor of the next page.
+type BusquedaRow struct {
+	EventoLocal
+	Clave string `json:"clave"`
+}
+
+type FacetaRow struct {
+	Valor string `json:"valor"`
+	Total int    `json:"total"`
+}
+
+// FacetasRows holds the facet counts of a search. Each facet is counted with
+// every filter except its own, so picking a value does not hide the others.
+type FacetasRows struct {
+	Categorias  []FacetaRow
+	Ciudades    []FacetaRow
+	Meses       []FacetaRow
+	Inscripcion []FacetaRow
+}
+
+// documentoBusqueda must stay identical to the expression of the
+// "Evento_busqueda_idx" index for the index to be used.
+const documentoBusqueda = `(setweight(to_tsvector('spanish', e."nombre"), 'A') ||
+		setweight(to_tsvector('spanish', e."descripcion"), 'B') ||
+		setweight(to_tsvector('spanish', e."ubicacion"), 'C'))`
+
+const (
+	consultaBusqueda = `websearch_to_tsquery('spanish', $1::text)`
+	// The venue city when there is one, otherwise the first part of the
+	// free-text location, which is written as "ciudad, país".
+	ciudadBusqueda = `COALESCE(c."nombre", NULLIF(btrim(split_part(translate(e."ubicacion", '.', ','), ',', 1)), ''))`
+	mesBusqueda    = `to_char((e."fecha_inicio" AT TIME ZONE 'UTC') AT TIME ZONE e."zona_horaria", 'YYYY-MM')`
+	// Mirrors the inscripciones_abiertas flag of event responses.
+	abiertaBusqueda = `(e."inscripciones_abiertas_manual" AND ($7::timestamptz AT TIME ZONE 'UTC') < e."fecha_cierre_inscripcion"
+		AND ($7::timestamptz AT TIME ZONE 'UTC') < e."fecha_inicio")`
+	rangoBusqueda = `round(ts_rank(` + documentoBusqueda + `, ` + consultaBusqueda + `)::numeric, 6)`
+)
+
+const desdeBusqueda = ` FROM "Evento" e
+		LEFT JOIN "Sede" s ON s."id_sede" = e."id_sede"
+		LEFT JOIN "Ciudad" c ON c."id_ciudad" = s."id_ciudad"`
+
+// filtrosBusqueda are the WHERE conditions of a search, keyed by facet so
+// that a facet can be counted without its own filter. Every search query
+// takes the same seven leading parameters, see paramsBusqueda.
+var filtrosBusqueda = []struct {
+	faceta    string
+	condicion string
+}{
+	{"", `e."cancelado" = false AND ($6::boolean OR e."estado" <> 'Borrador')`},
+	{"", `($1::text = '' OR ` + documentoBusqueda + ` @@ ` + consultaBusqueda + `)`},
+	{"categorias", `(cardinality($2::text[]) = 0 OR e."categorias" && $2::text[])`},
+	{"ciudades", `(cardinality($3::text[]) = 0 OR lower(` + ciudadBusqueda + `) = ANY($3::text[]))`},
+	{"meses", `($4::text = '' OR ` + mesBusqueda + ` = $4::text)`},
+	{"inscripcion", `($5::text = '' OR ` + abiertaBusqueda + ` = ($5::text = 'abiertas'))`},
+}
+
+func whereBusqueda(sinFaceta string) string {
+	condiciones := make([]string, 0, len(filtrosBusqueda))
+	for _, f := range filtrosBusqueda {
+		if f.faceta != "" && f.faceta == sinFaceta {
+			// Still referenced so PostgreSQL can type every parameter.
+			condiciones = append(condiciones, "("+f.condicion+" OR true)")
+			continue
+		}
+		condiciones = append(condiciones, f.condicion)
+	}
+	return " WHERE " + strings.Join(condiciones, " AND ")
+}
+
+func paramsBusqueda(f FiltroBusqueda) []interface{} {
+	categorias := f.Categorias
+	if categorias == nil {
+		categorias = []string{}
+	}
+	ciudades := make([]string, 0, len(f.Ciudades))
+	for _, c := range f.Ciudades {
+		ciudades = append(ciudades, strings.ToLower(strings.TrimSpace(c)))
+	}
+	abiertas := ""
+	if f.Abiertas != nil {
+		abiertas = dto.InscripcionCerradas
+		if *f.Abiertas {
+			abiertas = dto.InscripcionAbiertas
+		}
+	}
+	return []interface{}{strings.TrimSpace(f.Texto), categorias, ciudades, f.Mes, abiertas, f.IncluirBorradores, f.Ahora}
+}
+
+// ordenBusqueda returns the sort key expression, the ORDER BY clause and the
+// condition that keeps rows after the cursor ($8 key, $9 id) for an order.
+func ordenBusqueda(orden string) (clave, orderBy, despues string) {
+	switch orden {
+	case dto.OrdenRelevancia:
+		return rangoBusqueda + `::text`,
+			rangoBusqueda + ` DESC, e."id_evento" ASC`,
+			`(` + rangoBusqueda + ` < $8::numeric OR (` + rangoBusqueda + ` = $8::numeric AND e."id_evento" > $9::int))`
+	case dto.OrdenFechaDesc:
+		return `e."fecha_inicio"::text`,
+			`e."fecha_inicio" DESC, e."id_evento" DESC`,
+			`(e."fecha_inicio", e."id_evento") < ($8::timestamp, $9::int)`
+	case dto.OrdenNombre:
+		return `lower(e."nombre")`,
+			`lower(e."nombre") ASC, e."id_evento" ASC`,
+			`(lower(e."nombre"), e."id_evento") > ($8::text, $9::int)`
+	default:
+		return `e."fecha_inicio"::text`,
+			`e."fecha_inicio" ASC, e."id_evento" ASC`,
+			`(e."fecha_inicio", e."id_evento") > ($8::timestamp, $9::int)`
+	}
+}
+
+// rangoClave matches a relevance key, a rank rounded to 6 decimals.
+var rangoClave = regexp.MustCompile(`^[0-9]{1,10}(\.[0-9]{1,6})?$`)
+
+// fechaClave is how a start date key is written as text.
+const fechaClave = "2006-01-02 15:04:05.999999"
+
+// ClaveValida reports whether clave has the shape of the sort key that
+// ordenBusqueda uses for orden, so a forged cursor is refused before it
+// reaches the query.
+func ClaveValida(orden, clave string) bool {
+	switch orden {
+	case dto.OrdenRelevancia:
+		return rangoClave.MatchString(clave)
+	case dto.OrdenNombre:
+		return clave != "" && !strings.ContainsRune(clave, 0)
+	default:
+		_, err := time.Parse(fechaClave, clave)
+		return err == nil
+	}
+}
+
+// BuscarEventos returns up to limite events matching f in the given order,
+// starting after cursor when it is not nil.
+func (r *Repository) BuscarEventos(ctx context.Context, f FiltroBusqueda, orden string, cursor *CursorBusqueda, limite int) ([]BusquedaRow, error) {
+	clave, orderBy, despues := ordenBusqueda(orden)
+	params := paramsBusqueda(f)
+	where := whereBusqueda("")
+	if cursor != nil {
+		where += " AND " + despues
+		params = append(params, cursor.Clave, cursor.ID)
+	}
+	query := `SELECT e.*, ` + clave + ` AS "clave"` + desdeBusqueda + where +
+		fmt.Sprintf(" ORDER BY %s LIMIT %d", orderBy, limite)
+
+	var rows []BusquedaRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ContarFacetas counts the events matching f by category, city, local month
+// of start and whether inscriptions are open.
+func (r *Repository) ContarFacetas(ctx context.Context, f FiltroBusqueda) (FacetasRows, error) {
+	var res FacetasRows
+	facetas := []struct {
+		nombre  string
+		valor   string
+		desde   string
+		destino *[]FacetaRow
+	}{
+		{"categorias", `cat`, desdeBusqueda + ` CROSS JOIN LATERAL unnest(e."categorias") AS cat`, &res.Categorias},
+		{"ciudades", ciudadBusqueda, desdeBusqueda, &res.Ciudades},
+		{"meses", mesBusqueda, desdeBusqueda, &res.Meses},
+		{"inscripcion", `CASE WHEN ` + abiertaBusqueda + ` THEN 'abiertas' ELSE 'cerradas' END`, desdeBusqueda, &res.Inscripcion},
+	}
+	params := paramsBusqueda(f)
+	for _, faceta := range facetas {
+		query := `SELECT "valor", COUNT(*)::int AS "total" FROM (SELECT ` + faceta.valor + ` AS "valor"` +
+			faceta.desde + whereBusqueda(faceta.nombre) + `) f
+			WHERE "valor" IS NOT NULL GROUP BY "valor" ORDER BY "total" DESC, "valor" ASC`
+		rows := []FacetaRow{}
+		if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+			return FacetasRows{}, err
+		}
+		*faceta.destino = rows
+	}
+	return res, nil
+}
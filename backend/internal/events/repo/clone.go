@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	sesionesrepo "project/backend/internal/sesiones/repo"
+	"project/backend/prisma/db"
+)
+
+// ClonarEvento creates nuevo and copies the active sessions of origenID into
+// it in a single statement, so the copy is never left without its sessions.
+// Sessions are moved by offset on the wall clock of zona. The tracks of
+// origenID are copied along and the copies keep their track; they keep
+// their room only when nuevo is held at the same venue. Speaker assignments
+// are copied too when conPonentes is set, as long as every speaker is free
+// at the new times; otherwise nothing is created and the conflicts are
+// returned. The check and the copy then run in one transaction holding row
+// locks on origenID and the speakers. Copies are matched back to their
+// originals by position, since neither titles nor track names are unique.
+func (r *Repository) ClonarEvento(ctx context.Context, origenID int, nuevo NuevoEvento, zona string, offset time.Duration, conPonentes bool, actor string) (int, []sesionesrepo.ConflictoRow, error) {
+	payload, err := eventosJSON([]NuevoEvento{nuevo})
+	if err != nil {
+		return 0, nil, err
+	}
+	libre := "true"
+	if conPonentes {
+		libre = `NOT EXISTS (` + sesionesrepo.ConflictosSQL(ponentesDesplazados("$3::int", "$4", "$5", "0")) + `)`
+	}
+	query := `WITH ` + insertarEventos("NULL::int", libre) + `, "origen" AS (
+			SELECT "id_sesion", "titulo", "descripcion", "fecha_inicio", "fecha_fin", "ubicacion", "id_sala", "id_track",
+				row_number() OVER (ORDER BY "id_sesion") AS "n"
+			FROM "Sesion" WHERE "id_evento" = $3::int AND "cancelado" = false
+		), "origen_tracks" AS (
+			SELECT "id_track", "nombre", "color", row_number() OVER (ORDER BY "id_track") AS "n"
+			FROM "Track" WHERE "id_evento" = $3::int
+		), "tracks" AS (
+			INSERT INTO "Track" ("id_evento", "nombre", "color", "createdAt")
+			SELECT c."id_evento", t."nombre", t."color", NOW()
+			FROM "origen_tracks" t CROSS JOIN "creados" c
+			ORDER BY t."n"
+			RETURNING "id_track"
+		), "pares_tracks" AS (
+			SELECT "id_track", row_number() OVER (ORDER BY "id_track") AS "n" FROM "tracks"
+		), "copia" AS (
+			INSERT INTO "Sesion" ("titulo", "descripcion", "fecha_inicio", "fecha_fin", "ubicacion", "id_evento", "id_sala", "id_track")
+			SELECT o."titulo", o."descripcion",
+				` + desplazar(`o."fecha_inicio"`, "$4", "$5") + `,
+				` + desplazar(`o."fecha_fin"`, "$4", "$5") + `,
+				o."ubicacion", c."id_evento", sa."id_sala", pt."id_track"
+			FROM "origen" o
+			CROSS JOIN "creados" c
+			LEFT JOIN "Sala" sa ON sa."id_sala" = o."id_sala"
+				AND sa."id_sede" = (SELECT "id_sede" FROM "filas")
+			LEFT JOIN "origen_tracks" ot ON ot."id_track" = o."id_track"
+			LEFT JOIN "pares_tracks" pt ON pt."n" = ot."n"
+			ORDER BY o."n"
+			RETURNING "id_sesion"
+		), "pares" AS (
+			SELECT "id_sesion", row_number() OVER (ORDER BY "id_sesion") AS "n" FROM "copia"
+		), "ponentes" AS (
+			INSERT INTO "SesionPonente" ("id_sesion", "id_usuario")
+			SELECT p."id_sesion", sp."id_usuario"
+			FROM "pares" p
+			JOIN "origen" o ON o."n" = p."n"
+			JOIN "SesionPonente" sp ON sp."id_sesion" = o."id_sesion"
+			WHERE $6::boolean
+		)
+		SELECT "id_evento" FROM "creados"`
+	args := []any{payload, strings.TrimSpace(actor), origenID, zona, offset.Seconds(), conPonentes}
+	var rows []struct {
+		IDEvento int `json:"id_evento"`
+	}
+	if !conPonentes {
+		if err := r.client.Prisma.Raw.QueryRaw(query, args...).Exec(ctx, &rows); err != nil {
+			return 0, nil, err
+		}
+	} else {
+		lock := `SELECT "id_evento" FROM "Evento" WHERE "id_evento" = $1::int FOR UPDATE`
+		conflictos := sesionesrepo.ConflictosSQL(ponentesDesplazados("$1::int", "$2", "$3", "0"))
+		ocupados := r.client.Prisma.Raw.QueryRaw(conflictos, origenID, zona, offset.Seconds()).Tx()
+		creado := r.client.Prisma.Raw.QueryRaw(query, args...).Tx()
+		if err := r.client.Prisma.Transaction(
+			r.client.Prisma.Raw.QueryRaw(lock, origenID).Tx(),
+			r.client.Prisma.Raw.QueryRaw(sesionesrepo.BloquearPonentesSQL(ponentesEvento), origenID).Tx(),
+			ocupados,
+			creado,
+		).Exec(ctx); err != nil {
+			return 0, nil, err
+		}
+		var conflictosRows []sesionesrepo.ConflictoRow
+		if err := ocupados.Into(&conflictosRows); err != nil {
+			return 0, nil, err
+		}
+		if len(conflictosRows) > 0 {
+			return 0, conflictosRows, nil
+		}
+		if err := creado.Into(&rows); err != nil {
+			return 0, nil, err
+		}
+	}
+	if len(rows) == 0 {
+		return 0, nil, db.ErrNotFound
+	}
+	return rows[0].IDEvento, nil, nil
+}
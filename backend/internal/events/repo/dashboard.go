@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+
+	inscripciones "project/backend/internal/inscripciones/validation"
+)
+
+// SerieDiaRow is how many inscriptions an event received on one day of its
+// zone, and how many it had received up to that day.
+type SerieDiaRow struct {
+	Dia       string `json:"dia"`
+	Total     int    `json:"total"`
+	Acumulado int    `json:"acumulado"`
+}
+
+// ConversionRow counts the inscriptions that had to pay and how many of
+// them did. Waitlisted and free inscriptions are left out.
+type ConversionRow struct {
+	ConPago    int `json:"con_pago"`
+	Pagadas    int `json:"pagadas"`
+	Pendientes int `json:"pendientes"`
+}
+
+// RecaudacionRow is the money of an event in one currency: Cobrado from
+// paid inscriptions, Pendiente from active ones not paid yet. Both are
+// decimals rendered as text.
+type RecaudacionRow struct {
+	Moneda    string `json:"moneda"`
+	Cobrado   string `json:"cobrado"`
+	Pendiente string `json:"pendiente"`
+}
+
+// AfiliacionRow counts the active inscriptions of one affiliation.
+type AfiliacionRow struct {
+	Afiliacion string `json:"afiliacion"`
+	Total      int    `json:"total"`
+}
+
+// SesionPonentesRow is a session of an event with its number of speakers.
+type SesionPonentesRow struct {
+	IDSesion  int    `json:"id_sesion"`
+	Titulo    string `json:"titulo"`
+	Cancelado bool   `json:"cancelado"`
+	Ponentes  int    `json:"ponentes"`
+}
+
+// SerieInscripciones returns the inscriptions of an event per day in zona,
+// from the first to the last day with any, days without inscriptions
+// included.
+func (r *Repository) SerieInscripciones(ctx context.Context, id int, zona string) ([]SerieDiaRow, error) {
+	query := `WITH "dias" AS (
+			SELECT (("fecha_inscripcion" AT TIME ZONE 'UTC') AT TIME ZONE $2::text)::date AS "dia"
+			FROM "Inscripcion" WHERE "id_evento" = $1::int
+		), "rango" AS (
+			SELECT g."dia"::date AS "dia"
+			FROM (SELECT MIN("dia") AS "desde", MAX("dia") AS "hasta" FROM "dias") m,
+				generate_series(m."desde", m."hasta", interval '1 day') AS g("dia")
+		)
+		SELECT to_char(r."dia", 'YYYY-MM-DD') AS "dia", COUNT(d."dia")::int AS "total",
+			(SUM(COUNT(d."dia")) OVER (ORDER BY r."dia"))::int AS "acumulado"
+		FROM "rango" r
+		LEFT JOIN "dias" d ON d."dia" = r."dia"
+		GROUP BY r."dia"
+		ORDER BY r."dia"`
+	var rows []SerieDiaRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id, zona).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindConversion counts how many inscriptions that had to pay were paid.
+// Released inscriptions stay in the total: they are the ones that never
+// converted.
+func (r *Repository) FindConversion(ctx context.Context, id int) (ConversionRow, error) {
+	query := `SELECT
+			COUNT(*)::int AS "con_pago",
+			COUNT(*) FILTER (WHERE "estado_pago")::int AS "pagadas",
+			COUNT(*) FILTER (WHERE NOT "estado_pago" AND "estado" NOT IN ` + inscripciones.EstadosLiberados + `)::int AS "pendientes"
+		FROM "Inscripcion"
+		WHERE "id_evento" = $1::int AND "estado" <> 'En espera' AND ("monto" IS NULL OR "monto" > 0)`
+	var rows []ConversionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return ConversionRow{}, err
+	}
+	if len(rows) == 0 {
+		return ConversionRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// ListRecaudacion sums the money collected and still owed by the
+// inscriptions of an event, by currency. Inscriptions without a ticket
+// type carry no amount and are left out.
+func (r *Repository) ListRecaudacion(ctx context.Context, id int) ([]RecaudacionRow, error) {
+	query := `SELECT "moneda",
+			COALESCE(SUM("monto") FILTER (WHERE "estado_pago"), 0)::text AS "cobrado",
+			COALESCE(SUM("monto") FILTER (WHERE NOT "estado_pago"), 0)::text AS "pendiente"
+		FROM "Inscripcion"
+		WHERE "id_evento" = $1::int AND "monto" IS NOT NULL AND "moneda" IS NOT NULL
+			AND "estado" <> 'En espera' AND "estado" NOT IN ` + inscripciones.EstadosLiberados + `
+		GROUP BY "moneda" ORDER BY "moneda"`
+	var rows []RecaudacionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListAfiliaciones counts the inscriptions holding a seat by affiliation,
+// most common first. Blank affiliations are grouped under an empty string.
+func (r *Repository) ListAfiliaciones(ctx context.Context, id int) ([]AfiliacionRow, error) {
+	query := `SELECT btrim("afiliacion") AS "afiliacion", COUNT(*)::int AS "total"
+		FROM "Inscripcion"
+		WHERE "id_evento" = $1::int AND "estado" <> 'En espera' AND "estado" NOT IN ` + inscripciones.EstadosLiberados + `
+		GROUP BY btrim("afiliacion")
+		ORDER BY "total" DESC, "afiliacion"`
+	var rows []AfiliacionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListSesionesPonentes lists the sessions of an event in chronological
+// order with how many speakers each one has.
+func (r *Repository) ListSesionesPonentes(ctx context.Context, id int) ([]SesionPonentesRow, error) {
+	query := `SELECT s."id_sesion", s."titulo", s."cancelado", COUNT(sp."id_usuario")::int AS "ponentes"
+		FROM "Sesion" s
+		LEFT JOIN "SesionPonente" sp ON sp."id_sesion" = s."id_sesion"
+		WHERE s."id_evento" = $1::int
+		GROUP BY s."id_sesion"
+		ORDER BY s."fecha_inicio", s."id_sesion"`
+	var rows []SesionPonentesRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+)
+
+// NuevoEvento is an event of a bulk import, ready to be stored. Estado is
+// Borrador or Publicado. Inscriptions start open unless
+// InscripcionesCerradas is set.
+type NuevoEvento struct {
+	Nombre                 string             `json:"nombre"`
+	FechaInicio            time.Time          `json:"fecha_inicio"`
+	FechaFin               time.Time          `json:"fecha_fin"`
+	FechaCierreInscripcion time.Time          `json:"fecha_cierre_inscripcion"`
+	Ubicacion              string             `json:"ubicacion"`
+	Capacidad              *int               `json:"capacidad"`
+	IDSede                 *int               `json:"id_sede"`
+	ZonaHoraria            string             `json:"zona_horaria"`
+	Descripcion            string             `json:"descripcion"`
+	Categorias             []string           `json:"categorias"`
+	OrganizadorNombre      string             `json:"organizador_nombre"`
+	OrganizadorEmail       string             `json:"organizador_email"`
+	OrganizadorTelefono    string             `json:"organizador_telefono"`
+	Enlaces                []dto.EnlaceEvento `json:"enlaces"`
+	Estado                 string             `json:"estado"`
+	InscripcionesCerradas  bool               `json:"inscripciones_cerradas"`
+}
+
+// ImportadoRow is an event created by a bulk import.
+type ImportadoRow struct {
+	IDEvento int    `json:"id_evento"`
+	Nombre   string `json:"nombre"`
+	Estado   string `json:"estado"`
+}
+
+// eventosJSON encodes the events as the JSON read by insertarEventos.
+func eventosJSON(eventos []NuevoEvento) (string, error) {
+	for i := range eventos {
+		eventos[i].FechaInicio = eventos[i].FechaInicio.UTC()
+		eventos[i].FechaFin = eventos[i].FechaFin.UTC()
+		eventos[i].FechaCierreInscripcion = eventos[i].FechaCierreInscripcion.UTC()
+		if eventos[i].Categorias == nil {
+			eventos[i].Categorias = []string{}
+		}
+		if eventos[i].Enlaces == nil {
+			eventos[i].Enlaces = []dto.EnlaceEvento{}
+		}
+	}
+	payload, err := json.Marshal(eventos)
+	return string(payload), err
+}
+
+// insertarEventos returns the steps of a WITH query that create the events
+// given as JSON in $1, linked to the series given by serie, and record the
+// publication of the published ones attributed to $2. Nothing is created
+// unless condicion holds. The created rows are in "creados".
+func insertarEventos(serie, condicion string) string {
+	return `"filas" AS (
+			SELECT * FROM jsonb_to_recordset($1::jsonb) AS f("nombre" text, "fecha_inicio" timestamptz,
+				"fecha_fin" timestamptz, "fecha_cierre_inscripcion" timestamptz, "ubicacion" text, "capacidad" int,
+				"id_sede" int, "zona_horaria" text, "descripcion" text, "categorias" text[], "organizador_nombre" text,
+				"organizador_email" text, "organizador_telefono" text, "enlaces" jsonb, "estado" text,
+				"inscripciones_cerradas" boolean)
+		), "creados" AS (
+			INSERT INTO "Evento" ("nombre", "fecha_inicio", "fecha_fin", "fecha_cierre_inscripcion", "ubicacion",
+				"capacidad", "id_sede", "zona_horaria", "descripcion", "categorias", "organizador_nombre",
+				"organizador_email", "organizador_telefono", "enlaces", "estado", "id_serie",
+				"inscripciones_abiertas_manual")
+			SELECT f."nombre", f."fecha_inicio" AT TIME ZONE 'UTC', f."fecha_fin" AT TIME ZONE 'UTC',
+				f."fecha_cierre_inscripcion" AT TIME ZONE 'UTC', f."ubicacion", f."capacidad", f."id_sede",
+				f."zona_horaria", f."descripcion", f."categorias", NULLIF(f."organizador_nombre", ''),
+				NULLIF(f."organizador_email", ''), NULLIF(f."organizador_telefono", ''), f."enlaces", f."estado",
+				` + serie + `, NOT f."inscripciones_cerradas"
+			FROM "filas" f WHERE ` + condicion + `
+			RETURNING "id_evento", "nombre", "estado"
+		), "historial" AS (
+			INSERT INTO "EventoEstadoHistorial" ("id_evento", "estado_anterior", "estado_nuevo", "nota", "actor", "fecha_cambio")
+			SELECT c."id_evento", '` + domain.EstadoBorrador + `', c."estado", 'Evento publicado', NULLIF($2::text, ''), NOW()
+			FROM "creados" c WHERE c."estado" = '` + domain.EstadoPublicado + `'
+		)`
+}
+
+// ImportarEventos creates every event in a single statement, so either all
+// of them are stored or none is. Published events get their entry in the
+// state history, attributed to actor.
+func (r *Repository) ImportarEventos(ctx context.Context, eventos []NuevoEvento, actor string) ([]ImportadoRow, error) {
+	if len(eventos) == 0 {
+		return []ImportadoRow{}, nil
+	}
+	payload, err := eventosJSON(eventos)
+	if err != nil {
+		return nil, err
+	}
+	query := `WITH ` + insertarEventos("NULL::int", "true") + `
+		SELECT "id_evento", "nombre", "estado" FROM "creados" ORDER BY "id_evento"`
+	var rows []ImportadoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, payload, strings.TrimSpace(actor)).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
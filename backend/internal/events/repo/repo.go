@@ -2,10 +2,15 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"project/backend/internal/events/domain"
 	"project/backend/internal/events/dto"
 	"project/backend/prisma/db"
 	"strings"
 	"time"
+
+	"github.com/steebchen/prisma-client-go/runtime/raw"
 )
 
 type Repository struct {
@@ -29,41 +34,15 @@ func (r *Repository) FindByID(ctx context.Context, id int) (*db.EventoModel, err
 	).Exec(ctx)
 }
 
-func (r *Repository) FindAll(ctx context.Context) ([]db.EventoModel, error) {
-	return r.client.Evento.FindMany(
-		db.Evento.Cancelado.Equals(false),
-	).Exec(ctx)
-}
-
-func (r *Repository) Create(ctx context.Context, reqNombre, reqUbicacion string, start, end, cierre time.Time) (*db.EventoModel, error) {
-	return r.client.Evento.CreateOne(
-		db.Evento.Nombre.Set(strings.TrimSpace(reqNombre)),
-		db.Evento.FechaInicio.Set(start),
-		db.Evento.FechaFin.Set(end),
-		db.Evento.FechaCierreInscripcion.Set(cierre),
-		db.Evento.Ubicacion.Set(strings.TrimSpace(reqUbicacion)),
-	).Exec(ctx)
-}
-
-func (r *Repository) Update(ctx context.Context, id int, reqNombre, reqUbicacion string, start, end, cierre time.Time) (*db.EventoModel, error) {
-	return r.client.Evento.FindUnique(
-		db.Evento.IDEvento.Equals(id),
-	).Update(
-		db.Evento.Nombre.Set(strings.TrimSpace(reqNombre)),
-		db.Evento.FechaInicio.Set(start),
-		db.Evento.FechaFin.Set(end),
-		db.Evento.FechaCierreInscripcion.Set(cierre),
-		db.Evento.Ubicacion.Set(strings.TrimSpace(reqUbicacion)),
-	).Exec(ctx)
-}
-
-func (r *Repository) DeleteByID(ctx context.Context, id int) error {
-	_, err := r.client.Evento.FindUnique(
-		db.Evento.IDEvento.Equals(id),
-	).Update(
-		db.Evento.Cancelado.Set(true),
-	).Exec(ctx)
-	return err
+// FindAll returns the non-cancelled events. Archived events are left out
+// unless incluirArchivados is set.
+func (r *Repository) FindAll(ctx context.Context, incluirArchivados bool) ([]db.EventoModel, error) {
+	query := `SELECT * FROM "Evento" WHERE "cancelado" = false AND (NOT "archivado" OR $1::boolean)`
+	var eventos []db.EventoModel
+	if err := r.client.Prisma.Raw.QueryRaw(query, incluirArchivados).Exec(ctx, &eventos); err != nil {
+		return nil, err
+	}
+	return eventos, nil
 }
 
 func (r *Repository) SetInscripciones(ctx context.Context, id int, abiertas bool) (*db.EventoModel, error) {
@@ -74,42 +53,404 @@ func (r *Repository) SetInscripciones(ctx context.Context, id int, abiertas bool
 	).Exec(ctx)
 }
 
-func (r *Repository) GetFechasOcupadas(ctx context.Context) ([]dto.RangoFechas, error) {
-	       eventos, err := r.client.Evento.FindMany(
-		       db.Evento.Cancelado.Equals(false),
-	       ).Exec(ctx)
-	if err != nil {
+// GetFechasOcupadas returns the date ranges already taken at a venue.
+func (r *Repository) GetFechasOcupadas(ctx context.Context, sedeID int) ([]dto.RangoFechas, error) {
+	query := `SELECT "fecha_inicio", "fecha_fin", "zona_horaria" FROM "Evento"
+		WHERE "id_sede" = $1 AND "cancelado" = false
+		ORDER BY "fecha_inicio"`
+	var eventos []struct {
+		FechaInicio time.Time `json:"fecha_inicio"`
+		FechaFin    time.Time `json:"fecha_fin"`
+		ZonaHoraria string    `json:"zona_horaria"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, sedeID).Exec(ctx, &eventos); err != nil {
 		return nil, err
 	}
 
 	rangos := make([]dto.RangoFechas, 0, len(eventos))
 	for _, ev := range eventos {
 		rangos = append(rangos, dto.RangoFechas{
-			FechaInicio: ev.FechaInicio.Format("02/01/2006"),
-			FechaFin:    ev.FechaFin.Format("02/01/2006"),
+			FechaInicio: ev.FechaInicio.In(domain.Zona(ev.ZonaHoraria)).Format("02/01/2006"),
+			FechaFin:    ev.FechaFin.In(domain.Zona(ev.ZonaHoraria)).Format("02/01/2006"),
 		})
 	}
 	return rangos, nil
 }
 
-func (r *Repository) FindEventosCierreManana(ctx context.Context) ([]db.EventoModel, error) {
-	manana := time.Now().AddDate(0, 0, 1)
-	inicio := time.Date(manana.Year(), manana.Month(), manana.Day(), 0, 0, 0, 0, manana.Location())
-	fin := inicio.Add(24 * time.Hour)
+// FindConflictos lists the active events at the venue whose dates overlap
+// [start, end]. excluirID leaves the event being edited out of the check.
+func (r *Repository) FindConflictos(ctx context.Context, sedeID int, start, end time.Time, excluirID int) ([]ConflictoRow, error) {
+	query := conflictosSedeSQL("$1", "$2", "$3", "$4") + ` ORDER BY e."fecha_inicio"`
+	var rows []ConflictoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, sedeID, excluirID, start, end).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
 
-	return r.client.Evento.FindMany(
-		db.Evento.FechaCierreInscripcion.Gte(inicio),
-		db.Evento.FechaCierreInscripcion.Lt(fin),
-	).Exec(ctx)
+// conflictosSedeSQL lists the active events at the venue sede whose dates
+// overlap [inicio, fin], leaving out the event excluir. The arguments are
+// SQL expressions, so the same check can guard a write.
+func conflictosSedeSQL(sede, excluir, inicio, fin string) string {
+	return `SELECT e."id_evento", e."nombre", e."fecha_inicio", e."fecha_fin", e."zona_horaria", e."id_sede", s."nombre" AS "sede_nombre"
+		FROM "Evento" e
+		JOIN "Sede" s ON s."id_sede" = e."id_sede"
+		WHERE e."id_sede" = ` + sede + ` AND e."cancelado" = false AND e."id_evento" <> ` + excluir + `
+			AND e."fecha_inicio" <= ` + fin + ` AND e."fecha_fin" >= ` + inicio
 }
 
-func (r *Repository) FindEventosInicioManana(ctx context.Context) ([]db.EventoModel, error) {
-	manana := time.Now().UTC().AddDate(0, 0, 1)
-	inicio := time.Date(manana.Year(), manana.Month(), manana.Day(), 0, 0, 0, 0, time.UTC)
-	fin := inicio.Add(24 * time.Hour)
+// lockSede serializes the writes that book dates at a venue.
+const lockSede = `SELECT "id_sede" FROM "Sede" WHERE "id_sede" = $1::int FOR NO KEY UPDATE`
 
-	return r.client.Evento.FindMany(
-		db.Evento.FechaInicio.Gte(inicio),
-		db.Evento.FechaInicio.Lt(fin),
-	).Exec(ctx)
+// CrearEvento stores a new event with its venue, capacity, time zone and
+// details in a single statement. Unless forzar is set, the venue row is
+// locked and the event is only created when no other event there overlaps
+// its dates, so two organizers cannot book the same dates at once; the
+// conflicts are returned otherwise. It returns the id of the new event.
+func (r *Repository) CrearEvento(ctx context.Context, nuevo NuevoEvento, forzar bool) (int, []ConflictoRow, error) {
+	payload, err := eventosJSON([]NuevoEvento{nuevo})
+	if err != nil {
+		return 0, nil, err
+	}
+	condicion := "true"
+	if !forzar {
+		condicion = `NOT EXISTS (` + conflictosSedeSQL(`f."id_sede"`, "0",
+			`f."fecha_inicio" AT TIME ZONE 'UTC'`, `f."fecha_fin" AT TIME ZONE 'UTC'`) + `)`
+	}
+	insert := `WITH ` + insertarEventos("NULL::int", condicion) + `
+		SELECT "id_evento", "nombre", "estado" FROM "creados"`
+
+	ops := []db.PrismaTransaction{}
+	var conflictos *raw.TxQueryResult
+	if nuevo.IDSede != nil && !forzar {
+		consulta := r.client.Prisma.Raw.QueryRaw(conflictosSedeSQL("$1::int", "0", "$2::timestamptz AT TIME ZONE 'UTC'", "$3::timestamptz AT TIME ZONE 'UTC'"),
+			*nuevo.IDSede, nuevo.FechaInicio, nuevo.FechaFin).Tx()
+		conflictos = &consulta
+		ops = append(ops, r.client.Prisma.Raw.QueryRaw(lockSede, *nuevo.IDSede).Tx(), consulta)
+	}
+	creado := r.client.Prisma.Raw.QueryRaw(insert, payload, "").Tx()
+	ops = append(ops, creado)
+	if err := r.client.Prisma.Transaction(ops...).Exec(ctx); err != nil {
+		return 0, nil, err
+	}
+
+	if conflictos != nil {
+		var rows []ConflictoRow
+		if err := conflictos.Into(&rows); err != nil {
+			return 0, nil, err
+		}
+		if len(rows) > 0 {
+			return 0, rows, nil
+		}
+	}
+	var rows []ImportadoRow
+	if err := creado.Into(&rows); err != nil {
+		return 0, nil, err
+	}
+	if len(rows) == 0 {
+		return 0, nil, db.ErrNotFound
+	}
+	return rows[0].IDEvento, nil, nil
+}
+
+// ActualizarEvento applies an update of an event in a single statement: its
+// name, location, dates and time zone, its venue when o.CambiaSede is set
+// and its details unless o.Detalles is nil. o.IDSede is the venue the event
+// ends up in. Unless forzar is set, that venue is locked and the event is
+// only updated when no other event there overlaps its new dates; the
+// conflicts are returned otherwise.
+func (r *Repository) ActualizarEvento(ctx context.Context, o OcurrenciaActualizada, forzar bool) ([]ConflictoRow, error) {
+	detalles := dto.DetallesEvento{}
+	if o.Detalles != nil {
+		detalles = *o.Detalles
+	}
+	args, err := detallesArgs(o.ID, detalles)
+	if err != nil {
+		return nil, err
+	}
+	update := `WITH "evento" AS (
+			UPDATE "Evento" ev SET "nombre" = $2::text, "ubicacion" = $3::text,
+				"fecha_inicio" = $4::timestamptz AT TIME ZONE 'UTC', "fecha_fin" = $5::timestamptz AT TIME ZONE 'UTC',
+				"fecha_cierre_inscripcion" = $6::timestamptz AT TIME ZONE 'UTC', "zona_horaria" = $7::text,
+				"id_sede" = CASE WHEN $8::boolean THEN $9::int ELSE ev."id_sede" END,
+				"descripcion" = CASE WHEN $10::boolean THEN $11::text ELSE ev."descripcion" END,
+				"categorias" = CASE WHEN $10::boolean THEN $12::text[] ELSE ev."categorias" END,
+				"organizador_nombre" = CASE WHEN $10::boolean THEN NULLIF($13::text, '') ELSE ev."organizador_nombre" END,
+				"organizador_email" = CASE WHEN $10::boolean THEN NULLIF($14::text, '') ELSE ev."organizador_email" END,
+				"organizador_telefono" = CASE WHEN $10::boolean THEN NULLIF($15::text, '') ELSE ev."organizador_telefono" END,
+				"enlaces" = CASE WHEN $10::boolean THEN $16::jsonb ELSE ev."enlaces" END
+			WHERE ev."id_evento" = $1::int AND ($17::boolean OR NOT EXISTS (` +
+		conflictosSedeSQL(`CASE WHEN $8::boolean THEN $9::int ELSE ev."id_sede" END`, `$1::int`,
+			`$4::timestamptz AT TIME ZONE 'UTC'`, `$5::timestamptz AT TIME ZONE 'UTC'`) + `))
+			RETURNING ev."id_evento", ev."id_sede"
+		)
+		UPDATE "Sesion" se SET "id_sala" = NULL
+		FROM "Sala" sa, "evento" ev
+		WHERE $8::boolean AND sa."id_sala" = se."id_sala" AND se."id_evento" = ev."id_evento"
+			AND sa."id_sede" IS DISTINCT FROM ev."id_sede"`
+
+	ops := []db.PrismaTransaction{}
+	var conflictos *raw.TxQueryResult
+	if o.IDSede != nil && !forzar {
+		consulta := r.client.Prisma.Raw.QueryRaw(conflictosSedeSQL("$1::int", "$2::int", "$3::timestamptz AT TIME ZONE 'UTC'", "$4::timestamptz AT TIME ZONE 'UTC'"),
+			*o.IDSede, o.ID, o.Fechas.Inicio, o.Fechas.Fin).Tx()
+		conflictos = &consulta
+		ops = append(ops, r.client.Prisma.Raw.QueryRaw(lockSede, *o.IDSede).Tx(), consulta)
+	}
+	ops = append(ops, r.client.Prisma.Raw.ExecuteRaw(update, o.ID, strings.TrimSpace(o.Nombre), strings.TrimSpace(o.Ubicacion),
+		o.Fechas.Inicio, o.Fechas.Fin, o.Fechas.Cierre, o.ZonaHoraria, o.CambiaSede, o.IDSede, o.Detalles != nil,
+		args[1], args[2], args[3], args[4], args[5], args[6], forzar).Tx())
+	if err := r.client.Prisma.Transaction(ops...).Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	if conflictos == nil {
+		return nil, nil
+	}
+	var rows []ConflictoRow
+	if err := conflictos.Into(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// setSedeQuery assigns the venue $2 to the event $1; null leaves it without
+// venue. The event's sessions lose their room when it belongs to another
+// venue.
+const setSedeQuery = `WITH "evento" AS (
+		UPDATE "Evento" SET "id_sede" = $2::int WHERE "id_evento" = $1::int
+	)
+	UPDATE "Sesion" se SET "id_sala" = NULL
+	FROM "Sala" sa
+	WHERE sa."id_sala" = se."id_sala" AND se."id_evento" = $1::int
+		AND sa."id_sede" IS DISTINCT FROM $2::int`
+
+const setDetallesQuery = `UPDATE "Evento" SET "descripcion" = $2::text, "categorias" = $3::text[],
+	"organizador_nombre" = NULLIF($4::text, ''), "organizador_email" = NULLIF($5::text, ''),
+	"organizador_telefono" = NULLIF($6::text, ''), "enlaces" = $7::jsonb
+	WHERE "id_evento" = $1::int`
+
+func detallesArgs(id int, d dto.DetallesEvento) ([]any, error) {
+	categorias := d.Categorias
+	if categorias == nil {
+		categorias = []string{}
+	}
+	enlaces := d.Enlaces
+	if enlaces == nil {
+		enlaces = []dto.EnlaceEvento{}
+	}
+	enlacesJSON, err := json.Marshal(enlaces)
+	if err != nil {
+		return nil, err
+	}
+	var nombre, email, telefono string
+	if d.Organizador != nil {
+		nombre = strings.TrimSpace(d.Organizador.Nombre)
+		email = strings.TrimSpace(d.Organizador.Email)
+		telefono = strings.TrimSpace(d.Organizador.Telefono)
+	}
+	return []any{id, d.Descripcion, categorias, nombre, email, telefono, string(enlacesJSON)}, nil
+}
+
+// SetPortada replaces the cover image of an event and returns the storage
+// key of the previous one, if any, so the caller can delete it.
+func (r *Repository) SetPortada(ctx context.Context, id int, url, clave *string) (*string, error) {
+	query := `UPDATE "Evento" e SET "portada_url" = $2::text, "portada_clave" = $3::text
+		FROM (SELECT "portada_clave" FROM "Evento" WHERE "id_evento" = $1::int FOR UPDATE) AS anterior
+		WHERE e."id_evento" = $1::int
+		RETURNING anterior."portada_clave"`
+	var rows []struct {
+		PortadaClave *string `json:"portada_clave"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, id, url, clave).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return rows[0].PortadaClave, nil
+}
+
+func (r *Repository) FindZonaHoraria(ctx context.Context, id int) (string, error) {
+	var rows []struct {
+		ZonaHoraria string `json:"zona_horaria"`
+	}
+	query := `SELECT "zona_horaria" FROM "Evento" WHERE "id_evento" = $1`
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", db.ErrNotFound
+	}
+	return rows[0].ZonaHoraria, nil
+}
+
+// EventoLocal is an event together with the time zone its dates belong to.
+type EventoLocal struct {
+	db.EventoModel
+	ZonaHoraria string `json:"zona_horaria"`
+	Archivado   bool   `json:"archivado"`
+}
+
+// "Tomorrow" depends on where the event happens, so the day is computed in
+// each event's zone rather than the server's.
+const mananaEnZona = `((%s AT TIME ZONE 'UTC') AT TIME ZONE "zona_horaria")::date = (($1::timestamptz AT TIME ZONE "zona_horaria")::date + 1)`
+
+func (r *Repository) FindEventosCierreManana(ctx context.Context) ([]EventoLocal, error) {
+	return r.findManana(ctx, `"fecha_cierre_inscripcion"`)
+}
+
+func (r *Repository) FindEventosInicioManana(ctx context.Context) ([]EventoLocal, error) {
+	return r.findManana(ctx, `"fecha_inicio"`)
+}
+
+func (r *Repository) findManana(ctx context.Context, columna string) ([]EventoLocal, error) {
+	query := `SELECT * FROM "Evento" WHERE ` + fmt.Sprintf(mananaEnZona, columna)
+	var eventos []EventoLocal
+	if err := r.client.Prisma.Raw.QueryRaw(query, time.Now()).Exec(ctx, &eventos); err != nil {
+		return nil, err
+	}
+	return eventos, nil
+}
+
+type EstadoRow struct {
+	IDEvento               int       `json:"id_evento"`
+	Nombre                 string    `json:"nombre"`
+	Ubicacion              string    `json:"ubicacion"`
+	Estado                 string    `json:"estado"`
+	FechaInicio            time.Time `json:"fecha_inicio"`
+	FechaFin               time.Time `json:"fecha_fin"`
+	FechaCierreInscripcion time.Time `json:"fecha_cierre_inscripcion"`
+	ZonaHoraria            string    `json:"zona_horaria"`
+	IDSede                 *int      `json:"id_sede"`
+	SedeNombre             *string   `json:"sede_nombre"`
+	IDSerie                *int      `json:"id_serie"`
+	Descripcion            string    `json:"descripcion"`
+	Categorias             []string  `json:"categorias"`
+	PortadaURL             *string   `json:"portada_url"`
+	OrganizadorNombre      *string   `json:"organizador_nombre"`
+	OrganizadorEmail       *string   `json:"organizador_email"`
+	OrganizadorTelefono    *string   `json:"organizador_telefono"`
+	Enlaces                string    `json:"enlaces"`
+	InscripcionesAbiertas  bool      `json:"inscripciones_abiertas_manual"`
+	Archivado              bool      `json:"archivado"`
+}
+
+// Detalles returns the descriptive fields of the row.
+func (e EstadoRow) Detalles() dto.DetallesEvento {
+	d := dto.DetallesEvento{Descripcion: e.Descripcion, Categorias: e.Categorias, Enlaces: []dto.EnlaceEvento{}}
+	if e.OrganizadorNombre != nil {
+		d.Organizador = &dto.OrganizadorEvento{Nombre: *e.OrganizadorNombre}
+		if e.OrganizadorEmail != nil {
+			d.Organizador.Email = *e.OrganizadorEmail
+		}
+		if e.OrganizadorTelefono != nil {
+			d.Organizador.Telefono = *e.OrganizadorTelefono
+		}
+	}
+	if e.Enlaces != "" {
+		_ = json.Unmarshal([]byte(e.Enlaces), &d.Enlaces)
+	}
+	return d
+}
+
+type ConflictoRow struct {
+	IDEvento    int       `json:"id_evento"`
+	Nombre      string    `json:"nombre"`
+	FechaInicio time.Time `json:"fecha_inicio"`
+	FechaFin    time.Time `json:"fecha_fin"`
+	ZonaHoraria string    `json:"zona_horaria"`
+	IDSede      int       `json:"id_sede"`
+	SedeNombre  string    `json:"sede_nombre"`
+}
+
+type EstadoHistorialRow struct {
+	IDHistorial    int       `json:"id_historial"`
+	IDEvento       int       `json:"id_evento"`
+	EstadoAnterior string    `json:"estado_anterior"`
+	EstadoNuevo    string    `json:"estado_nuevo"`
+	Nota           *string   `json:"nota"`
+	Actor          *string   `json:"actor"`
+	FechaCambio    time.Time `json:"fecha_cambio"`
+}
+
+const estadoSelect = `SELECT e."id_evento", e."nombre", e."ubicacion", e."estado", e."fecha_inicio", e."fecha_fin", e."fecha_cierre_inscripcion",
+		e."zona_horaria", e."id_sede", s."nombre" AS "sede_nombre", e."id_serie", e."descripcion", e."categorias",
+		e."portada_url", e."organizador_nombre", e."organizador_email", e."organizador_telefono", e."enlaces"::text AS "enlaces",
+		e."inscripciones_abiertas_manual", e."archivado"
+		FROM "Evento" e
+		LEFT JOIN "Sede" s ON s."id_sede" = e."id_sede"`
+
+func (r *Repository) FindEstado(ctx context.Context, id int) (EstadoRow, error) {
+	var rows []EstadoRow
+	if err := r.client.Prisma.Raw.QueryRaw(estadoSelect+` WHERE e."id_evento" = $1`, id).Exec(ctx, &rows); err != nil {
+		return EstadoRow{}, err
+	}
+	if len(rows) == 0 {
+		return EstadoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+func (r *Repository) FindEstados(ctx context.Context, ids []int) ([]EstadoRow, error) {
+	if len(ids) == 0 {
+		return []EstadoRow{}, nil
+	}
+	var rows []EstadoRow
+	if err := r.client.Prisma.Raw.QueryRaw(estadoSelect+` WHERE e."id_evento" = ANY($1::int[])`, ids).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindByEstados lists the events currently in any of the given states.
+func (r *Repository) FindByEstados(ctx context.Context, estados []string) ([]EstadoRow, error) {
+	var rows []EstadoRow
+	if err := r.client.Prisma.Raw.QueryRaw(estadoSelect+` WHERE e."estado" = ANY($1::text[]) ORDER BY e."id_evento"`, estados).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindVisibles returns the events participants may see, hiding drafts and
+// cancelled events, and archived ones unless incluirArchivados is set.
+func (r *Repository) FindVisibles(ctx context.Context, incluirArchivados bool) ([]db.EventoModel, error) {
+	query := `SELECT * FROM "Evento" WHERE "cancelado" = false AND "estado" <> 'Borrador' AND (NOT "archivado" OR $1::boolean)`
+	var eventos []db.EventoModel
+	if err := r.client.Prisma.Raw.QueryRaw(query, incluirArchivados).Exec(ctx, &eventos); err != nil {
+		return nil, err
+	}
+	return eventos, nil
+}
+
+// CambiarEstado moves the event from anterior to nuevo and records the change.
+// It returns false when the event was no longer in anterior, so concurrent
+// transitions cannot both apply.
+func (r *Repository) CambiarEstado(ctx context.Context, id int, anterior, nuevo, actor, nota string) (bool, error) {
+	query := `WITH "actualizado" AS (
+			UPDATE "Evento" SET "estado" = $3::text, "cancelado" = ($3::text = 'Cancelado')
+			WHERE "id_evento" = $1::int AND "estado" = $2::text
+			RETURNING "id_evento"
+		), "historial" AS (
+			INSERT INTO "EventoEstadoHistorial" ("id_evento", "estado_anterior", "estado_nuevo", "nota", "actor", "fecha_cambio")
+			SELECT a."id_evento", $2::text, $3::text, NULLIF($5::text, ''), NULLIF($4::text, ''), NOW() FROM "actualizado" a
+		)
+		SELECT "id_evento" FROM "actualizado"`
+	var rows []struct {
+		IDEvento int `json:"id_evento"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, id, anterior, nuevo, strings.TrimSpace(actor), strings.TrimSpace(nota)).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (r *Repository) ListHistorialEstados(ctx context.Context, id int) ([]EstadoHistorialRow, error) {
+	query := `SELECT "id_historial", "id_evento", "estado_anterior", "estado_nuevo", "nota", "actor", "fecha_cambio"
+		FROM "EventoEstadoHistorial" WHERE "id_evento" = $1 ORDER BY "fecha_cambio" ASC, "id_historial" ASC`
+	var rows []EstadoHistorialRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
 }
@@ -0,0 +1,309 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/domain"
+	inscripciones "project/backend/internal/inscripciones/validation"
+	sesionesrepo "project/backend/internal/sesiones/repo"
+	"project/backend/prisma/db"
+)
+
+// NuevaReprogramacion moves an event to Inicio, Fin and Cierre and its
+// sessions by Offset on the wall clock of Zona. Inscripciones and
+// TokenHashes are parallel: each inscription gets the hash of the secret of
+// its confirmation link.
+type NuevaReprogramacion struct {
+	EventoID      int
+	Zona          string
+	Offset        time.Duration
+	Inicio        time.Time
+	Fin           time.Time
+	Cierre        time.Time
+	Motivo        string
+	Actor         string
+	Inscripciones []int
+	TokenHashes   []string
+}
+
+// PendienteRow is an inscription asked to confirm a reschedule.
+type PendienteRow struct {
+	IDReprogramacion int `json:"id_reprogramacion"`
+	IDInscripcion    int `json:"id_inscripcion"`
+	IDUsuario        int `json:"id_usuario"`
+}
+
+// ReprogramacionRow is a reschedule of an event with the count of its
+// answers. Total includes the answers a later reschedule superseded.
+type ReprogramacionRow struct {
+	IDReprogramacion    int       `json:"id_reprogramacion"`
+	IDEvento            int       `json:"id_evento"`
+	FechaInicioAnterior time.Time `json:"fecha_inicio_anterior"`
+	FechaFinAnterior    time.Time `json:"fecha_fin_anterior"`
+	FechaInicio         time.Time `json:"fecha_inicio"`
+	FechaFin            time.Time `json:"fecha_fin"`
+	Motivo              *string   `json:"motivo"`
+	Actor               *string   `json:"actor"`
+	Fecha               time.Time `json:"fecha"`
+	ZonaHoraria         string    `json:"zona_horaria"`
+	Total               int       `json:"total"`
+	Pendientes          int       `json:"pendientes"`
+	Confirmadas         int       `json:"confirmadas"`
+	Canceladas          int       `json:"canceladas"`
+}
+
+// RespuestaRow is the answer of one inscription to a reschedule.
+type RespuestaRow struct {
+	IDInscripcion      int        `json:"id_inscripcion"`
+	IDUsuario          int        `json:"id_usuario"`
+	Nombre             string     `json:"nombre"`
+	Email              string     `json:"email"`
+	Respuesta          string     `json:"respuesta"`
+	FechaRespuesta     *time.Time `json:"fecha_respuesta"`
+	ReembolsoPendiente bool       `json:"reembolso_pendiente"`
+}
+
+// RespuestaTokenRow is the answer behind a confirmation link, with the
+// event as it is now.
+type RespuestaTokenRow struct {
+	IDRespuesta         int       `json:"id_respuesta"`
+	IDInscripcion       int       `json:"id_inscripcion"`
+	IDUsuario           int       `json:"id_usuario"`
+	IDEvento            int       `json:"id_evento"`
+	Evento              string    `json:"evento"`
+	ZonaHoraria         string    `json:"zona_horaria"`
+	FechaInicioAnterior time.Time `json:"fecha_inicio_anterior"`
+	FechaInicio         time.Time `json:"fecha_inicio"`
+	FechaFin            time.Time `json:"fecha_fin"`
+	Motivo              *string   `json:"motivo"`
+	Respuesta           string    `json:"respuesta"`
+	ReembolsoPendiente  bool      `json:"reembolso_pendiente"`
+}
+
+// RespuestaGuardadaRow is the outcome of an answer. EstadoAnterior is set
+// when the answer cancelled the inscription.
+type RespuestaGuardadaRow struct {
+	IDInscripcion  int     `json:"id_inscripcion"`
+	EstadoAnterior *string `json:"estado_anterior"`
+}
+
+// ListInscritosActivos returns the inscriptions of the event that still
+// hold or wait for a seat.
+func (r *Repository) ListInscritosActivos(ctx context.Context, eventoID int) ([]PendienteRow, error) {
+	query := `SELECT 0 AS "id_reprogramacion", "id_inscripcion", "id_usuario" FROM "Inscripcion"
+		WHERE "id_evento" = $1::int AND "estado" NOT IN ` + inscripciones.EstadosLiberados + `
+		ORDER BY "id_inscripcion"`
+	var rows []PendienteRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// desplazar moves the timestamp columna by the seconds in offset on the
+// wall clock of zona; offset and zona are placeholders.
+func desplazar(columna, zona, offset string) string {
+	return `((` + columna + ` AT TIME ZONE 'UTC' AT TIME ZONE ` + zona + `::text) + make_interval(secs => ` + offset + `::double precision)) AT TIME ZONE ` + zona + `::text AT TIME ZONE 'UTC'`
+}
+
+// ponentesEvento selects the speakers of the active sessions of event $1.
+const ponentesEvento = `SELECT sp."id_usuario" FROM "SesionPonente" sp
+	JOIN "Sesion" s ON s."id_sesion" = sp."id_sesion"
+	WHERE s."id_evento" = $1::int AND NOT s."cancelado"`
+
+// ponentesDesplazados selects, as candidates for
+// sesionesrepo.ConflictosSQL, the speakers of the active sessions of evento
+// at the times those sessions get once moved by offset on the wall clock of
+// zona. Sessions of the event excluir do not count against them.
+func ponentesDesplazados(evento, zona, offset, excluir string) string {
+	return `SELECT sp."id_usuario", ` + desplazar(`s."fecha_inicio"`, zona, offset) + ` AS "inicio",
+			` + desplazar(`s."fecha_fin"`, zona, offset) + ` AS "fin", 0 AS "excluir_sesion", ` + excluir + ` AS "excluir_evento"
+		FROM "Sesion" s JOIN "SesionPonente" sp ON sp."id_sesion" = s."id_sesion"
+		WHERE s."id_evento" = ` + evento + ` AND NOT s."cancelado"`
+}
+
+// Reprogramar moves the event and its sessions and records the reschedule
+// in one transaction, holding row locks on the event and the speakers of its
+// sessions. Pending answers to earlier reschedules are superseded. It
+// returns the inscriptions that were asked to confirm, which leaves out any
+// released since they were listed. When a speaker is busy at the new time of
+// a session nothing changes and the conflicts are returned instead.
+func (r *Repository) Reprogramar(ctx context.Context, n NuevaReprogramacion) ([]PendienteRow, []sesionesrepo.ConflictoRow, error) {
+	lock := `SELECT "id_evento" FROM "Evento" WHERE "id_evento" = $1::int FOR UPDATE`
+	conflictos := sesionesrepo.ConflictosSQL(ponentesDesplazados("$1::int", "$2", "$3", "$1::int"))
+	query := `WITH "registro" AS (
+			INSERT INTO "EventoReprogramacion" ("id_evento", "fecha_inicio_anterior", "fecha_fin_anterior", "fecha_inicio", "fecha_fin", "motivo", "actor", "fecha")
+			SELECT "id_evento", "fecha_inicio", "fecha_fin", $4::timestamptz AT TIME ZONE 'UTC', $5::timestamptz AT TIME ZONE 'UTC',
+				NULLIF($7::text, ''), NULLIF($8::text, ''), NOW()
+			FROM "Evento" WHERE "id_evento" = $1::int
+				AND NOT EXISTS (` + conflictos + `)
+			RETURNING "id_reprogramacion"
+		), "reemplazadas" AS (
+			UPDATE "ReprogramacionRespuesta" SET "respuesta" = '` + domain.RespuestaReemplazada + `'
+			WHERE "respuesta" = '` + domain.RespuestaPendiente + `' AND EXISTS (SELECT 1 FROM "registro")
+				AND "id_reprogramacion" IN (SELECT "id_reprogramacion" FROM "EventoReprogramacion" WHERE "id_evento" = $1::int)
+		), "evento" AS (
+			UPDATE "Evento" SET "fecha_inicio" = $4::timestamptz AT TIME ZONE 'UTC', "fecha_fin" = $5::timestamptz AT TIME ZONE 'UTC',
+				"fecha_cierre_inscripcion" = $6::timestamptz AT TIME ZONE 'UTC'
+			WHERE "id_evento" = $1::int AND EXISTS (SELECT 1 FROM "registro")
+		), "sesiones" AS (
+			UPDATE "Sesion" SET "fecha_inicio" = ` + desplazar(`"fecha_inicio"`, "$2", "$3") + `,
+				"fecha_fin" = ` + desplazar(`"fecha_fin"`, "$2", "$3") + `
+			WHERE "id_evento" = $1::int AND EXISTS (SELECT 1 FROM "registro")
+		), "respuestas" AS (
+			INSERT INTO "ReprogramacionRespuesta" ("id_reprogramacion", "id_inscripcion", "token_hash")
+			SELECT rp."id_reprogramacion", t."id_inscripcion", t."token_hash"
+			FROM "registro" rp
+			CROSS JOIN unnest($9::int[], $10::text[]) AS t("id_inscripcion", "token_hash")
+			JOIN "Inscripcion" i ON i."id_inscripcion" = t."id_inscripcion"
+			WHERE i."id_evento" = $1::int AND i."estado" NOT IN ` + inscripciones.EstadosLiberados + `
+			RETURNING "id_reprogramacion", "id_inscripcion"
+		)
+		SELECT rr."id_reprogramacion", rr."id_inscripcion", i."id_usuario"
+		FROM "respuestas" rr
+		JOIN "Inscripcion" i ON i."id_inscripcion" = rr."id_inscripcion"
+		ORDER BY rr."id_inscripcion"`
+
+	motivo := strings.TrimSpace(n.Motivo)
+	actor := strings.TrimSpace(n.Actor)
+	ids := n.Inscripciones
+	if ids == nil {
+		ids = []int{}
+	}
+	hashes := n.TokenHashes
+	if hashes == nil {
+		hashes = []string{}
+	}
+
+	ocupados := r.client.Prisma.Raw.QueryRaw(conflictos, n.EventoID, n.Zona, n.Offset.Seconds()).Tx()
+	asked := r.client.Prisma.Raw.QueryRaw(query, n.EventoID, n.Zona, n.Offset.Seconds(), n.Inicio, n.Fin, n.Cierre,
+		motivo, actor, ids, hashes).Tx()
+	if err := r.client.Prisma.Transaction(
+		r.client.Prisma.Raw.QueryRaw(lock, n.EventoID).Tx(),
+		r.client.Prisma.Raw.QueryRaw(sesionesrepo.BloquearPonentesSQL(ponentesEvento), n.EventoID).Tx(),
+		ocupados,
+		asked,
+	).Exec(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	var conflictosRows []sesionesrepo.ConflictoRow
+	if err := ocupados.Into(&conflictosRows); err != nil {
+		return nil, nil, err
+	}
+	if len(conflictosRows) > 0 {
+		return nil, conflictosRows, nil
+	}
+	var rows []PendienteRow
+	if err := asked.Into(&rows); err != nil {
+		return nil, nil, err
+	}
+	return rows, nil, nil
+}
+
+const reprogramacionSelect = `SELECT rp."id_reprogramacion", rp."id_evento", rp."fecha_inicio_anterior", rp."fecha_fin_anterior",
+		rp."fecha_inicio", rp."fecha_fin", rp."motivo", rp."actor", rp."fecha", e."zona_horaria",
+		COUNT(rr."id_respuesta")::int AS "total",
+		COUNT(rr."id_respuesta") FILTER (WHERE rr."respuesta" = '` + domain.RespuestaPendiente + `')::int AS "pendientes",
+		COUNT(rr."id_respuesta") FILTER (WHERE rr."respuesta" = '` + domain.RespuestaConfirmada + `')::int AS "confirmadas",
+		COUNT(rr."id_respuesta") FILTER (WHERE rr."respuesta" = '` + domain.RespuestaCancelada + `')::int AS "canceladas"
+		FROM "EventoReprogramacion" rp
+		JOIN "Evento" e ON e."id_evento" = rp."id_evento"
+		LEFT JOIN "ReprogramacionRespuesta" rr ON rr."id_reprogramacion" = rp."id_reprogramacion"`
+
+const reprogramacionGroup = ` GROUP BY rp."id_reprogramacion", e."zona_horaria"`
+
+// ListReprogramaciones returns the reschedules of an event, newest first.
+func (r *Repository) ListReprogramaciones(ctx context.Context, eventoID int) ([]ReprogramacionRow, error) {
+	query := reprogramacionSelect + ` WHERE rp."id_evento" = $1::int` + reprogramacionGroup + ` ORDER BY rp."id_reprogramacion" DESC`
+	var rows []ReprogramacionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindReprogramacion returns a reschedule of the event, or db.ErrNotFound.
+func (r *Repository) FindReprogramacion(ctx context.Context, eventoID, id int) (ReprogramacionRow, error) {
+	query := reprogramacionSelect + ` WHERE rp."id_evento" = $1::int AND rp."id_reprogramacion" = $2::int` + reprogramacionGroup
+	var rows []ReprogramacionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID, id).Exec(ctx, &rows); err != nil {
+		return ReprogramacionRow{}, err
+	}
+	if len(rows) == 0 {
+		return ReprogramacionRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// ListRespuestas returns the answers to a reschedule by participant name.
+func (r *Repository) ListRespuestas(ctx context.Context, id int) ([]RespuestaRow, error) {
+	query := `SELECT i."id_inscripcion", i."id_usuario", i."nombre_participante" AS "nombre", i."email",
+			rr."respuesta", rr."fecha_respuesta", i."reembolso_pendiente"
+		FROM "ReprogramacionRespuesta" rr
+		JOIN "Inscripcion" i ON i."id_inscripcion" = rr."id_inscripcion"
+		WHERE rr."id_reprogramacion" = $1::int
+		ORDER BY i."nombre_participante", i."id_inscripcion"`
+	var rows []RespuestaRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindRespuestaPorToken returns the answer whose link secret hashes to
+// hash, or db.ErrNotFound.
+func (r *Repository) FindRespuestaPorToken(ctx context.Context, hash string) (RespuestaTokenRow, error) {
+	query := `SELECT rr."id_respuesta", i."id_inscripcion", i."id_usuario", e."id_evento", e."nombre" AS "evento", e."zona_horaria",
+			rp."fecha_inicio_anterior", e."fecha_inicio", e."fecha_fin", rp."motivo", rr."respuesta", i."reembolso_pendiente"
+		FROM "ReprogramacionRespuesta" rr
+		JOIN "EventoReprogramacion" rp ON rp."id_reprogramacion" = rr."id_reprogramacion"
+		JOIN "Inscripcion" i ON i."id_inscripcion" = rr."id_inscripcion"
+		JOIN "Evento" e ON e."id_evento" = rp."id_evento"
+		WHERE rr."token_hash" = $1::text`
+	var rows []RespuestaTokenRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, hash).Exec(ctx, &rows); err != nil {
+		return RespuestaTokenRow{}, err
+	}
+	if len(rows) == 0 {
+		return RespuestaTokenRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// GuardarRespuesta stores an answer unless the current one is already
+// final, in which case it returns db.ErrNotFound. A cancellation also
+// cancels the inscription, records it in its history and marks it for
+// refund when it was paid and cost something, all in one statement.
+func (r *Repository) GuardarRespuesta(ctx context.Context, id int, respuesta, actor string) (RespuestaGuardadaRow, error) {
+	query := `WITH "respuesta" AS (
+			UPDATE "ReprogramacionRespuesta" SET "respuesta" = $2::text, "fecha_respuesta" = NOW()
+			WHERE "id_respuesta" = $1::int
+				AND "respuesta" IN ('` + domain.RespuestaPendiente + `', '` + domain.RespuestaConfirmada + `')
+			RETURNING "id_inscripcion"
+		), "anterior" AS (
+			SELECT i."id_inscripcion", i."estado" FROM "Inscripcion" i
+			JOIN "respuesta" r ON r."id_inscripcion" = i."id_inscripcion"
+			WHERE $2::text = '` + domain.RespuestaCancelada + `' AND i."estado" NOT IN ` + inscripciones.EstadosLiberados + `
+		), "cancelada" AS (
+			UPDATE "Inscripcion" i SET "estado" = 'Cancelado', "posicion_espera" = NULL, "updatedAt" = NOW(),
+				"reembolso_pendiente" = i."estado_pago" AND (i."monto" IS NULL OR i."monto" > 0)
+			FROM "anterior" a WHERE i."id_inscripcion" = a."id_inscripcion"
+		), "historial" AS (
+			INSERT INTO "InscripcionHistorial" ("id_inscripcion", "estado_anterior", "estado_nuevo", "nota", "actor", "fecha_cambio")
+			SELECT a."id_inscripcion", a."estado", 'Cancelado', 'Cancelada por reprogramación del evento', NULLIF($3::text, ''), NOW()
+			FROM "anterior" a
+		)
+		SELECT r."id_inscripcion", a."estado" AS "estado_anterior"
+		FROM "respuesta" r LEFT JOIN "anterior" a ON a."id_inscripcion" = r."id_inscripcion"`
+	var rows []RespuestaGuardadaRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id, respuesta, strings.TrimSpace(actor)).Exec(ctx, &rows); err != nil {
+		return RespuestaGuardadaRow{}, err
+	}
+	if len(rows) == 0 {
+		return RespuestaGuardadaRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/prisma/db"
+)
+
+type SerieRow struct {
+	IDSerie     int       `json:"id_serie"`
+	Nombre      string    `json:"nombre"`
+	Regla       string    `json:"regla"`
+	Excepciones []string  `json:"excepciones"`
+	ZonaHoraria string    `json:"zona_horaria"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// CrearSerie creates a series and all of its occurrences in a single
+// statement, so either the whole series is stored or nothing is. Published
+// occurrences get their entry in the state history, attributed to actor.
+func (r *Repository) CrearSerie(ctx context.Context, nombre, regla string, excepciones []string, zona string, eventos []NuevoEvento, actor string) (int, []ImportadoRow, error) {
+	if excepciones == nil {
+		excepciones = []string{}
+	}
+	payload, err := eventosJSON(eventos)
+	if err != nil {
+		return 0, nil, err
+	}
+	query := `WITH "serie" AS (
+			INSERT INTO "EventoSerie" ("nombre", "regla", "excepciones", "zona_horaria")
+			VALUES ($3, $4, $5::text[], $6) RETURNING "id_serie"
+		), ` + insertarEventos(`(SELECT "id_serie" FROM "serie")`, "true") + `
+		SELECT s."id_serie", c."id_evento", c."nombre", c."estado"
+		FROM "serie" s LEFT JOIN "creados" c ON true
+		ORDER BY c."id_evento"`
+	var rows []struct {
+		IDSerie int `json:"id_serie"`
+		ImportadoRow
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, payload, strings.TrimSpace(actor), strings.TrimSpace(nombre), regla, excepciones, zona).Exec(ctx, &rows); err != nil {
+		return 0, nil, err
+	}
+	if len(rows) == 0 {
+		return 0, nil, db.ErrNotFound
+	}
+	creados := make([]ImportadoRow, 0, len(rows))
+	for _, row := range rows {
+		creados = append(creados, row.ImportadoRow)
+	}
+	return rows[0].IDSerie, creados, nil
+}
+
+func (r *Repository) FindSerie(ctx context.Context, id int) (SerieRow, error) {
+	query := `SELECT "id_serie", "nombre", "regla", "excepciones", "zona_horaria", "createdAt"
+		FROM "EventoSerie" WHERE "id_serie" = $1`
+	var rows []SerieRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return SerieRow{}, err
+	}
+	if len(rows) == 0 {
+		return SerieRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// OcurrenciaActualizada is the new state of one occurrence of a series.
+// Detalles is nil when the descriptive fields are left as they are.
+type OcurrenciaActualizada struct {
+	ID          int
+	Nombre      string
+	Ubicacion   string
+	Fechas      domain.Fechas
+	ZonaHoraria string
+	CambiaSede  bool
+	IDSede      *int
+	Detalles    *dto.DetallesEvento
+}
+
+// ActualizarOcurrencias applies the updates of several occurrences in one
+// transaction, so a series is never left half moved.
+func (r *Repository) ActualizarOcurrencias(ctx context.Context, ocurrencias []OcurrenciaActualizada) error {
+	update := `UPDATE "Evento" SET "nombre" = $2::text, "ubicacion" = $3::text,
+			"fecha_inicio" = $4::timestamptz AT TIME ZONE 'UTC', "fecha_fin" = $5::timestamptz AT TIME ZONE 'UTC',
+			"fecha_cierre_inscripcion" = $6::timestamptz AT TIME ZONE 'UTC', "zona_horaria" = $7::text
+		WHERE "id_evento" = $1::int`
+	ops := []db.PrismaTransaction{}
+	for _, o := range ocurrencias {
+		ops = append(ops, r.client.Prisma.Raw.ExecuteRaw(update, o.ID, strings.TrimSpace(o.Nombre), strings.TrimSpace(o.Ubicacion),
+			o.Fechas.Inicio, o.Fechas.Fin, o.Fechas.Cierre, o.ZonaHoraria).Tx())
+		if o.CambiaSede {
+			ops = append(ops, r.client.Prisma.Raw.ExecuteRaw(setSedeQuery, o.ID, o.IDSede).Tx())
+		}
+		if o.Detalles != nil {
+			args, err := detallesArgs(o.ID, *o.Detalles)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, r.client.Prisma.Raw.ExecuteRaw(setDetallesQuery, args...).Tx())
+		}
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	return r.client.Prisma.Transaction(ops...).Exec(ctx)
+}
+
+// FindBySerie lists every occurrence of a series in chronological order.
+func (r *Repository) FindBySerie(ctx context.Context, serieID int) ([]db.EventoModel, error) {
+	query := `SELECT * FROM "Evento" WHERE "id_serie" = $1 ORDER BY "fecha_inicio"`
+	var eventos []db.EventoModel
+	if err := r.client.Prisma.Raw.QueryRaw(query, serieID).Exec(ctx, &eventos); err != nil {
+		return nil, err
+	}
+	return eventos, nil
+}
+
+// FindOcurrenciasDesde lists the active occurrences of a series starting at
+// or after desde.
+func (r *Repository) FindOcurrenciasDesde(ctx context.Context, serieID int, desde time.Time) ([]EstadoRow, error) {
+	var rows []EstadoRow
+	query := estadoSelect + ` WHERE e."id_serie" = $1 AND e."cancelado" = false AND e."fecha_inicio" >= $2
+		ORDER BY e."fecha_inicio"`
+	if err := r.client.Prisma.Raw.QueryRaw(query, serieID, desde).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// NombreEnUso reports whether another active event already has the name.
+// Occurrences of the same series share their name, so serieID excludes them.
+func (r *Repository) NombreEnUso(ctx context.Context, nombre string, excluirID int, serieID *int) (bool, error) {
+	query := `SELECT "id_evento" FROM "Evento"
+		WHERE "nombre" = $1 AND "cancelado" = false AND "id_evento" <> $2
+			AND ("id_serie" IS NULL OR "id_serie" IS DISTINCT FROM $3::int)
+		LIMIT 1`
+	var rows []struct {
+		IDEvento int `json:"id_evento"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, strings.TrimSpace(nombre), excluirID, serieID).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
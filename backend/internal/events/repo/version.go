@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"project/backend/prisma/db"
+)
+
+// VersionRow is a stored version of an event. Snapshot and Cambios hold the
+// JSON documents as text.
+type VersionRow struct {
+	IDEvento int       `json:"id_evento"`
+	Version  int       `json:"version"`
+	Accion   string    `json:"accion"`
+	Actor    *string   `json:"actor"`
+	Nota     *string   `json:"nota"`
+	Snapshot string    `json:"snapshot"`
+	Cambios  string    `json:"cambios"`
+	Fecha    time.Time `json:"fecha"`
+}
+
+const versionSelect = `SELECT "id_evento", "version", "accion", "actor", "nota", "snapshot"::text AS "snapshot",
+		"cambios"::text AS "cambios", "fecha"
+		FROM "EventoVersion"`
+
+// UltimaVersion returns the latest version of an event, or db.ErrNotFound
+// when it has none yet.
+func (r *Repository) UltimaVersion(ctx context.Context, id int) (VersionRow, error) {
+	query := versionSelect + ` WHERE "id_evento" = $1::int ORDER BY "version" DESC LIMIT 1`
+	return r.findVersion(ctx, query, id)
+}
+
+func (r *Repository) FindVersion(ctx context.Context, id, version int) (VersionRow, error) {
+	query := versionSelect + ` WHERE "id_evento" = $1::int AND "version" = $2::int`
+	return r.findVersion(ctx, query, id, version)
+}
+
+func (r *Repository) findVersion(ctx context.Context, query string, params ...interface{}) (VersionRow, error) {
+	var rows []VersionRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return VersionRow{}, err
+	}
+	if len(rows) == 0 {
+		return VersionRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+func (r *Repository) ListVersiones(ctx context.Context, id int) ([]VersionRow, error) {
+	var rows []VersionRow
+	if err := r.client.Prisma.Raw.QueryRaw(versionSelect+` WHERE "id_evento" = $1::int ORDER BY "version" ASC`, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CrearVersion stores version number version of an event. The unique key on
+// (id_evento, version) rejects a concurrent writer that read the same
+// previous version.
+func (r *Repository) CrearVersion(ctx context.Context, id, version int, accion, actor, nota string, snapshot, cambios []byte) error {
+	query := `INSERT INTO "EventoVersion" ("id_evento", "version", "accion", "actor", "nota", "snapshot", "cambios", "fecha")
+		VALUES ($1::int, $2::int, $3::text, NULLIF($4::text, ''), NULLIF($5::text, ''), $6::jsonb, $7::jsonb, NOW())`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, id, version, accion, strings.TrimSpace(actor), strings.TrimSpace(nota), string(snapshot), string(cambios)).Exec(ctx)
+	return err
+}
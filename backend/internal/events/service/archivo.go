@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/repo"
+	"project/backend/internal/shared/smtp"
+	"project/backend/prisma/db"
+
+	"github.com/shopspring/decimal"
+)
+
+// graciaArchivo is how long a finished event stays in the default listings
+// before it is archived.
+const graciaArchivo = 24 * time.Hour
+
+// ArchivarFinalizados archives the finished events that ended more than
+// graciaArchivo before now: their inscriptions are closed, the pending and
+// waitlisted ones expire, and the organizer is emailed the post-event
+// summary. It returns how many events were archived.
+func (s *Service) ArchivarFinalizados(ctx context.Context, now time.Time) (int, error) {
+	eventos, err := s.repo.FindPorArchivar(ctx, now.Add(-graciaArchivo))
+	if err != nil {
+		return 0, ErrDB
+	}
+
+	archivados := 0
+	for _, ev := range eventos {
+		archivo, ok, err := s.repo.Archivar(ctx, ev.IDEvento)
+		if err != nil {
+			return archivados, ErrDB
+		}
+		if !ok {
+			continue
+		}
+		archivados++
+
+		nota := ""
+		if archivo.Expiradas > 0 {
+			nota = fmt.Sprintf("%d inscripciones expiradas", archivo.Expiradas)
+		}
+		s.registrarVersion(ctx, ev.IDEvento, domain.AccionArchivado, "system", nota)
+		s.enviarResumen(ctx, ev)
+	}
+	return archivados, nil
+}
+
+// enviarResumen emails the post-event summary to the organizer contact of
+// the event, if it has one. The event is already archived, so failures are
+// only logged.
+func (s *Service) enviarResumen(ctx context.Context, ev repo.EstadoRow) {
+	if ev.OrganizadorEmail == nil || strings.TrimSpace(*ev.OrganizadorEmail) == "" {
+		return
+	}
+	resumen, err := s.ResumenEvento(ctx, ev.IDEvento)
+	if err != nil {
+		fmt.Println("[Archivo] Error generando el resumen del evento", ev.IDEvento, ":", err)
+		return
+	}
+	if err := smtp.SendEventSummaryEmail(ctx, *ev.OrganizadorEmail, ev.Nombre, textoResumen(resumen)); err != nil {
+		fmt.Println("[Archivo] Error enviando el resumen del evento", ev.IDEvento, ":", err)
+	}
+}
+
+// ResumenEvento returns the post-event report of an event: inscriptions by
+// status, payments, pending refunds, revenue by currency and sessions.
+func (s *Service) ResumenEvento(ctx context.Context, id int) (dto.ResumenEventoResponse, error) {
+	evento, err := s.repo.FindEstado(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.ResumenEventoResponse{}, ErrNotFound
+		}
+		return dto.ResumenEventoResponse{}, ErrDB
+	}
+	totales, err := s.repo.FindResumen(ctx, id)
+	if err != nil {
+		return dto.ResumenEventoResponse{}, ErrDB
+	}
+	conteos, err := s.repo.ContarPorEstado(ctx, id)
+	if err != nil {
+		return dto.ResumenEventoResponse{}, ErrDB
+	}
+	ingresos, err := s.repo.ListIngresos(ctx, id)
+	if err != nil {
+		return dto.ResumenEventoResponse{}, ErrDB
+	}
+
+	res := dto.ResumenEventoResponse{
+		IDEvento:             evento.IDEvento,
+		Nombre:               evento.Nombre,
+		Estado:               evento.Estado,
+		FechaInicio:          domain.FormatoLocal(evento.FechaInicio, evento.ZonaHoraria),
+		FechaFin:             domain.FormatoLocal(evento.FechaFin, evento.ZonaHoraria),
+		Archivado:            evento.Archivado,
+		Inscripciones:        totales.Inscripciones,
+		PorEstado:            make([]dto.ConteoEstado, 0, len(conteos)),
+		Pagadas:              totales.Pagadas,
+		ReembolsosPendientes: totales.ReembolsosPendientes,
+		Ingresos:             make([]dto.IngresoEvento, 0, len(ingresos)),
+		Sesiones:             totales.Sesiones,
+		SesionesCanceladas:   totales.SesionesCanceladas,
+	}
+	for _, c := range conteos {
+		res.PorEstado = append(res.PorEstado, dto.ConteoEstado{Estado: c.Estado, Total: c.Total})
+	}
+	for _, in := range ingresos {
+		total, err := decimal.NewFromString(in.Total)
+		if err != nil {
+			return dto.ResumenEventoResponse{}, ErrDB
+		}
+		res.Ingresos = append(res.Ingresos, dto.IngresoEvento{Moneda: in.Moneda, Total: total})
+	}
+	return res, nil
+}
+
+func textoResumen(r dto.ResumenEventoResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "El evento %q (%s - %s) ha finalizado y fue archivado.\n\n", r.Nombre, r.FechaInicio, r.FechaFin)
+	fmt.Fprintf(&b, "Inscripciones: %d\n", r.Inscripciones)
+	for _, c := range r.PorEstado {
+		fmt.Fprintf(&b, "  %s: %d\n", c.Estado, c.Total)
+	}
+	fmt.Fprintf(&b, "Pagadas: %d\n", r.Pagadas)
+	fmt.Fprintf(&b, "Reembolsos pendientes: %d\n", r.ReembolsosPendientes)
+	for _, in := range r.Ingresos {
+		fmt.Fprintf(&b, "Ingresos (%s): %s\n", in.Moneda, in.Total.StringFixed(2))
+	}
+	fmt.Fprintf(&b, "Sesiones: %d (%d canceladas)\n", r.Sesiones, r.SesionesCanceladas)
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/repo"
+	"project/backend/prisma/db"
+)
+
+// Page sizes of the event catalog.
+const (
+	LimiteBusqueda    = 20
+	LimiteBusquedaMax = 100
+)
+
+var ErrCursorInvalido = errors.New("cursor inválido o de otra búsqueda")
+
+// cursorBusqueda is the opaque siguiente_cursor. It records the order it
+// was built for so it cannot be reused with a different one.
+type cursorBusqueda struct {
+	Orden string `json:"o"`
+	repo.CursorBusqueda
+}
+
+// BuscarEventos searches the catalog. Drafts are only included for callers
+// that manage events. The order defaults to relevance when there is a text
+// query and to start date otherwise.
+func (s *Service) BuscarEventos(ctx context.Context, req dto.BusquedaEventosRequest, incluirBorradores bool, now time.Time) (dto.BusquedaEventosResponse, []db.EventoModel, error) {
+	filtro := repo.FiltroBusqueda{
+		Texto:             req.Texto,
+		Categorias:        req.Categorias,
+		Ciudades:          req.Ciudades,
+		Mes:               req.Mes,
+		IncluirBorradores: incluirBorradores,
+		Ahora:             now,
+	}
+	if req.Inscripcion != "" {
+		abiertas := req.Inscripcion == dto.InscripcionAbiertas
+		filtro.Abiertas = &abiertas
+	}
+
+	orden := req.Orden
+	if orden == "" || (orden == dto.OrdenRelevancia && req.Texto == "") {
+		orden = dto.OrdenFecha
+		if req.Texto != "" {
+			orden = dto.OrdenRelevancia
+		}
+	}
+	limite := req.Limite
+	if limite <= 0 {
+		limite = LimiteBusqueda
+	}
+	if limite > LimiteBusquedaMax {
+		limite = LimiteBusquedaMax
+	}
+
+	var cursor *repo.CursorBusqueda
+	if req.Cursor != "" {
+		c, err := decodeCursor(req.Cursor, orden)
+		if err != nil {
+			return dto.BusquedaEventosResponse{}, nil, ErrCursorInvalido
+		}
+		cursor = &c.CursorBusqueda
+	}
+
+	// One extra row tells whether there is a next page.
+	rows, err := s.repo.BuscarEventos(ctx, filtro, orden, cursor, limite+1)
+	if err != nil {
+		return dto.BusquedaEventosResponse{}, nil, ErrDB
+	}
+	facetas, err := s.repo.ContarFacetas(ctx, filtro)
+	if err != nil {
+		return dto.BusquedaEventosResponse{}, nil, ErrDB
+	}
+
+	res := dto.BusquedaEventosResponse{
+		Facetas: dto.FacetasEventosResponse{
+			Categorias:  facetasResponse(facetas.Categorias),
+			Ciudades:    facetasResponse(facetas.Ciudades),
+			Meses:       facetasResponse(facetas.Meses),
+			Inscripcion: facetasResponse(facetas.Inscripcion),
+		},
+	}
+	// The inscription facet ignores only its own filter, so the total is
+	// the count of the selected value, or of every value without one.
+	for _, f := range facetas.Inscripcion {
+		if req.Inscripcion == "" || f.Valor == req.Inscripcion {
+			res.Total += f.Total
+		}
+	}
+
+	if len(rows) > limite {
+		rows = rows[:limite]
+		ultima := rows[len(rows)-1]
+		siguiente := encodeCursor(cursorBusqueda{
+			Orden:          orden,
+			CursorBusqueda: repo.CursorBusqueda{Clave: ultima.Clave, ID: ultima.IDEvento},
+		})
+		res.SiguienteCursor = &siguiente
+	}
+	eventos := make([]db.EventoModel, 0, len(rows))
+	for _, row := range rows {
+		eventos = append(eventos, row.EventoModel)
+	}
+	return res, eventos, nil
+}
+
+func facetasResponse(rows []repo.FacetaRow) []dto.FacetaResponse {
+	res := make([]dto.FacetaResponse, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, dto.FacetaResponse{Valor: row.Valor, Total: row.Total})
+	}
+	return res
+}
+
+func encodeCursor(c cursorBusqueda) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor reads a cursor and checks that it was built for orden and
+// that its key fits that order.
+func decodeCursor(value, orden string) (cursorBusqueda, error) {
+	var c cursorBusqueda
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, err
+	}
+	if c.ID <= 0 || c.Orden != orden || !repo.ClaveValida(orden, c.Clave) {
+		return c, ErrCursorInvalido
+	}
+	return c, nil
+}
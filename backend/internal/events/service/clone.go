@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/validation"
+	sesionessrv "project/backend/internal/sesiones/service"
+	"project/backend/prisma/db"
+)
+
+var ErrClonInvalido = errors.New("no se puede clonar el evento")
+
+// ClonarEvento creates a new draft from an existing event. Dates, sessions
+// and session times are moved by the distance between the original start
+// and start, on the event's local wall clock. The copy goes through the same
+// checks as a new event and is stored together with its sessions in one
+// statement. Descriptive details are copied but the cover image is not, so
+// deleting one event's cover never affects the other. Keeping the speakers
+// fails with a *sesionessrv.ConflictoError when one of them is busy at the
+// new time of a session.
+func (s *Service) ClonarEvento(ctx context.Context, origenID int, req dto.ClonarEventoRequest, start, now time.Time) (*db.EventoModel, error) {
+	origen, err := s.repo.FindByID(ctx, origenID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrDB
+	}
+	estado, err := s.repo.FindEstado(ctx, origenID)
+	if err != nil {
+		return nil, ErrDB
+	}
+
+	loc := domain.Zona(estado.ZonaHoraria)
+	offset := domain.DiferenciaLocal(origen.FechaInicio, start, loc)
+	end := domain.Desplazar(origen.FechaFin, offset, loc)
+	cierre := domain.Desplazar(origen.FechaCierreInscripcion, offset, loc)
+	if err := validation.ValidateEventoInstantes(start, end, cierre, now); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrClonInvalido, err.Error())
+	}
+	if err := validation.ValidateEventoUbicacion(origen.Ubicacion); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrClonInvalido, err.Error())
+	}
+	if err := s.EnsureNombreUnico(ctx, req.Nombre); err != nil {
+		return nil, err
+	}
+
+	detalles := estado.Detalles()
+	copia := dto.CreateEventoRequest{
+		Nombre:      req.Nombre,
+		Ubicacion:   origen.Ubicacion,
+		ZonaHoraria: estado.ZonaHoraria,
+		Descripcion: detalles.Descripcion,
+		Categorias:  detalles.Categorias,
+		Organizador: detalles.Organizador,
+		Enlaces:     detalles.Enlaces,
+	}
+	if req.ConservarConfiguracion {
+		cupos, err := s.waitlist.Cupos(ctx, []int{origenID})
+		if err != nil {
+			return nil, ErrDB
+		}
+		copia.Capacidad = cupos[origenID].Capacidad
+		copia.IDSede = estado.IDSede
+	}
+	if !req.ForzarConflicto {
+		if err := s.EnsureNoSolapamiento(ctx, copia.IDSede, start, end, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.ensureSede(ctx, copia.IDSede, copia.Capacidad); err != nil {
+		return nil, err
+	}
+
+	nuevo := nuevoEventoDe(copia, domain.Fechas{Inicio: start, Fin: end, Cierre: cierre})
+	nuevo.Estado = domain.EstadoBorrador
+	nuevo.InscripcionesCerradas = req.ConservarConfiguracion && !origen.InscripcionesAbiertasManual
+	id, conflictos, err := s.repo.ClonarEvento(ctx, origenID, nuevo, estado.ZonaHoraria, offset, req.ConservarPonentes, req.Actor)
+	if err != nil {
+		return nil, ErrDB
+	}
+	if err := sesionessrv.NewConflictoError(conflictos); err != nil {
+		return nil, err
+	}
+	s.registrarVersion(ctx, id, domain.AccionCreado, req.Actor, fmt.Sprintf("Clonado del evento %d", origenID))
+	created, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrDB
+	}
+	return created, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/prisma/db"
+
+	"github.com/shopspring/decimal"
+)
+
+// ttlDashboard is how long the statistics of an event are served from
+// memory before they are computed again.
+const ttlDashboard = 30 * time.Second
+
+// DashboardEvento returns the statistics of an event for its organizers:
+// inscriptions per day, conversion from pending to paid, money collected
+// and owed, capacity use, waitlist, affiliations and speakers per session.
+// Every figure is a SQL aggregate; the result is cached for ttlDashboard.
+func (s *Service) DashboardEvento(ctx context.Context, id int, now time.Time) (dto.DashboardEventoResponse, error) {
+	if res, ok := s.dashboards.Get(id); ok {
+		return res, nil
+	}
+
+	evento, err := s.repo.FindEstado(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.DashboardEventoResponse{}, ErrNotFound
+		}
+		return dto.DashboardEventoResponse{}, ErrDB
+	}
+	zona := domain.Zona(evento.ZonaHoraria).String()
+	conteos, err := s.repo.ContarPorEstado(ctx, id)
+	if err != nil {
+		return dto.DashboardEventoResponse{}, ErrDB
+	}
+	serie, err := s.repo.SerieInscripciones(ctx, id, zona)
+	if err != nil {
+		return dto.DashboardEventoResponse{}, ErrDB
+	}
+	conversion, err := s.repo.FindConversion(ctx, id)
+	if err != nil {
+		return dto.DashboardEventoResponse{}, ErrDB
+	}
+	recaudacion, err := s.repo.ListRecaudacion(ctx, id)
+	if err != nil {
+		return dto.DashboardEventoResponse{}, ErrDB
+	}
+	afiliaciones, err := s.repo.ListAfiliaciones(ctx, id)
+	if err != nil {
+		return dto.DashboardEventoResponse{}, ErrDB
+	}
+	sesiones, err := s.repo.ListSesionesPonentes(ctx, id)
+	if err != nil {
+		return dto.DashboardEventoResponse{}, ErrDB
+	}
+	cupos, err := s.waitlist.Cupos(ctx, []int{id})
+	if err != nil {
+		return dto.DashboardEventoResponse{}, ErrDB
+	}
+	cupo := cupos[id]
+
+	res := dto.DashboardEventoResponse{
+		IDEvento:     evento.IDEvento,
+		Nombre:       evento.Nombre,
+		Estado:       evento.Estado,
+		Generado:     domain.FormatoLocal(now, zona),
+		PorEstado:    make([]dto.ConteoEstado, 0, len(conteos)),
+		SerieDiaria:  make([]dto.InscripcionesDia, 0, len(serie)),
+		Recaudacion:  make([]dto.RecaudacionMoneda, 0, len(recaudacion)),
+		Afiliaciones: make([]dto.ConteoAfiliacion, 0, len(afiliaciones)),
+		Sesiones:     make([]dto.SesionPonentes, 0, len(sesiones)),
+		Conversion: dto.ConversionPago{
+			ConPago:    conversion.ConPago,
+			Pagadas:    conversion.Pagadas,
+			Pendientes: conversion.Pendientes,
+			Tasa:       porcentaje(conversion.Pagadas, conversion.ConPago),
+		},
+		Ocupacion: dto.OcupacionEvento{
+			Capacidad:   cupo.Capacidad,
+			Ocupados:    cupo.Ocupados,
+			Disponibles: cupo.Disponibles,
+		},
+		EnEspera: cupo.EnEspera,
+	}
+	if cupo.Capacidad != nil && *cupo.Capacidad > 0 {
+		uso := porcentaje(cupo.Ocupados, *cupo.Capacidad)
+		res.Ocupacion.Porcentaje = &uso
+	}
+	for _, c := range conteos {
+		res.Inscripciones += c.Total
+		res.PorEstado = append(res.PorEstado, dto.ConteoEstado{Estado: c.Estado, Total: c.Total})
+	}
+	for _, d := range serie {
+		res.SerieDiaria = append(res.SerieDiaria, dto.InscripcionesDia{Dia: d.Dia, Total: d.Total, Acumulado: d.Acumulado})
+	}
+	for _, row := range recaudacion {
+		cobrado, err := decimal.NewFromString(row.Cobrado)
+		if err != nil {
+			return dto.DashboardEventoResponse{}, ErrDB
+		}
+		pendiente, err := decimal.NewFromString(row.Pendiente)
+		if err != nil {
+			return dto.DashboardEventoResponse{}, ErrDB
+		}
+		res.Recaudacion = append(res.Recaudacion, dto.RecaudacionMoneda{Moneda: row.Moneda, Cobrado: cobrado, Pendiente: pendiente})
+	}
+	for _, a := range afiliaciones {
+		res.Afiliaciones = append(res.Afiliaciones, dto.ConteoAfiliacion{Afiliacion: a.Afiliacion, Total: a.Total})
+	}
+	for _, sesion := range sesiones {
+		res.Sesiones = append(res.Sesiones, dto.SesionPonentes{
+			IDSesion:  sesion.IDSesion,
+			Titulo:    sesion.Titulo,
+			Cancelada: sesion.Cancelado,
+			Ponentes:  sesion.Ponentes,
+		})
+	}
+
+	s.dashboards.Set(id, res)
+	return res, nil
+}
+
+// porcentaje is parte over total as a percentage with one decimal; zero
+// when there is no total.
+func porcentaje(parte, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(parte)*1000/float64(total)) / 10
+}
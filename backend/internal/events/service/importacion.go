@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/repo"
+)
+
+// ImportarEventos checks the rows of a bulk import against the database and
+// against each other: names must be unique, venues must exist and hold the
+// capacity, and events at the same venue must not overlap. It returns the
+// report of every row. Only when confirmar is set are the valid rows created,
+// all in one transaction; the published ones are then announced in a single
+// batch of notifications.
+func (s *Service) ImportarEventos(ctx context.Context, filas []dto.EventoImportado, confirmar bool, actor string, now time.Time) (dto.ImportacionEventosResponse, error) {
+	res := dto.ImportacionEventosResponse{
+		Confirmada: confirmar,
+		Total:      len(filas),
+		Filas:      make([]dto.FilaImportacionResponse, 0, len(filas)),
+	}
+	nombres := map[string]int{}
+	var (
+		aceptadas []dto.EventoImportado
+		nuevos    []repo.NuevoEvento
+	)
+	for _, fila := range filas {
+		errores := fila.Errores
+		if len(errores) == 0 {
+			var err error
+			errores, err = s.revisarImportado(ctx, fila, aceptadas, nombres, now)
+			if err != nil {
+				return dto.ImportacionEventosResponse{}, err
+			}
+		}
+		nombre := strings.TrimSpace(fila.Evento.Nombre)
+		if _, repetido := nombres[nombre]; !repetido && nombre != "" {
+			nombres[nombre] = fila.Fila
+		}
+
+		item := dto.FilaImportacionResponse{Fila: fila.Fila, Nombre: nombre, Valida: len(errores) == 0, Errores: errores}
+		if item.Errores == nil {
+			item.Errores = []string{}
+		}
+		res.Filas = append(res.Filas, item)
+		if !item.Valida {
+			res.Invalidas++
+			continue
+		}
+		res.Validas++
+		aceptadas = append(aceptadas, fila)
+		nuevos = append(nuevos, nuevoEvento(fila))
+	}
+	if !confirmar || len(nuevos) == 0 {
+		return res, nil
+	}
+
+	creados, err := s.repo.ImportarEventos(ctx, nuevos, actor)
+	if err != nil {
+		return dto.ImportacionEventosResponse{}, ErrDB
+	}
+	ids := make(map[string]int, len(creados))
+	var publicados []int
+	for _, c := range creados {
+		ids[c.Nombre] = c.IDEvento
+		s.registrarVersion(ctx, c.IDEvento, domain.AccionCreado, actor, "Importación masiva")
+		if c.Estado == domain.EstadoPublicado {
+			s.registrarVersion(ctx, c.IDEvento, domain.AccionPublicado, actor, "")
+			publicados = append(publicados, c.IDEvento)
+		}
+	}
+	for i := range res.Filas {
+		if !res.Filas[i].Valida {
+			continue
+		}
+		if id, ok := ids[res.Filas[i].Nombre]; ok {
+			res.Filas[i].IDEvento = &id
+		}
+	}
+	res.Creados = len(creados)
+	s.notificarAperturaLote(ctx, publicados)
+	return res, nil
+}
+
+// revisarImportado returns why a row that passed the field checks cannot be
+// created. aceptadas are the earlier valid rows and nombres the first row
+// each name appeared in. Only database failures are returned as error.
+func (s *Service) revisarImportado(ctx context.Context, fila dto.EventoImportado, aceptadas []dto.EventoImportado, nombres map[string]int, now time.Time) ([]string, error) {
+	var errores []string
+	ev := fila.Evento
+
+	if previa, ok := nombres[strings.TrimSpace(ev.Nombre)]; ok {
+		errores = append(errores, fmt.Sprintf("el nombre ya aparece en la fila %d", previa))
+	} else if err := s.EnsureNombreUnico(ctx, ev.Nombre); err != nil {
+		if !errors.Is(err, ErrNameExists) {
+			return nil, err
+		}
+		errores = append(errores, err.Error())
+	}
+
+	if err := s.ensureSede(ctx, ev.IDSede, ev.Capacidad); err != nil {
+		if errors.Is(err, ErrDB) {
+			return nil, err
+		}
+		errores = append(errores, err.Error())
+	} else if ev.IDSede != nil {
+		var conflicto *ConflictoError
+		err := s.EnsureNoSolapamiento(ctx, ev.IDSede, fila.Fechas.Inicio, fila.Fechas.Fin, 0)
+		switch {
+		case errors.As(err, &conflicto):
+			errores = append(errores, fmt.Sprintf("%s: %s", err.Error(), nombresConflicto(conflicto.Conflictos)))
+		case err != nil:
+			return nil, err
+		}
+		for _, otra := range aceptadas {
+			if otra.Evento.IDSede != nil && *otra.Evento.IDSede == *ev.IDSede && otra.Fechas.Solapa(fila.Fechas) {
+				errores = append(errores, fmt.Sprintf("%s: fila %d", ErrOverlap.Error(), otra.Fila))
+			}
+		}
+	}
+
+	if ev.Publicar {
+		if err := domain.ValidarTransicion(domain.EstadoBorrador, domain.EstadoPublicado, fila.Fechas, now); err != nil {
+			errores = append(errores, err.Error())
+		}
+	}
+	return errores, nil
+}
+
+// notificarAperturaLote announces the events of an import or a new series
+// that were published. They are already created, so failures are only
+// logged.
+func (s *Service) notificarAperturaLote(ctx context.Context, ids []int) {
+	if len(ids) == 0 {
+		return
+	}
+	eventos, err := s.repo.FindEstados(ctx, ids)
+	if err != nil {
+		fmt.Println("[AperturaLote] Error cargando los eventos publicados:", err)
+		return
+	}
+	if err := s.notificationService.NotificarAperturaInscripcionesLote(ctx, eventos); err != nil {
+		fmt.Println("[AperturaLote] Error notificando apertura de inscripciones:", err)
+	}
+}
+
+func nuevoEvento(fila dto.EventoImportado) repo.NuevoEvento {
+	return nuevoEventoDe(fila.Evento, fila.Fechas)
+}
+
+// nuevoEventoDe turns a creation request into an event ready to be stored
+// in bulk, published when the request asks for it.
+func nuevoEventoDe(ev dto.CreateEventoRequest, fechas domain.Fechas) repo.NuevoEvento {
+	nuevo := repo.NuevoEvento{
+		Nombre:                 strings.TrimSpace(ev.Nombre),
+		FechaInicio:            fechas.Inicio,
+		FechaFin:               fechas.Fin,
+		FechaCierreInscripcion: fechas.Cierre,
+		Ubicacion:              strings.TrimSpace(ev.Ubicacion),
+		Capacidad:              ev.Capacidad,
+		IDSede:                 ev.IDSede,
+		ZonaHoraria:            zonaONombre(ev.ZonaHoraria),
+		Descripcion:            domain.SanitizarMarkdown(ev.Descripcion),
+		Categorias:             ev.Categorias,
+		Enlaces:                ev.Enlaces,
+		Estado:                 domain.EstadoBorrador,
+	}
+	if ev.Organizador != nil {
+		nuevo.OrganizadorNombre = strings.TrimSpace(ev.Organizador.Nombre)
+		nuevo.OrganizadorEmail = strings.TrimSpace(ev.Organizador.Email)
+		nuevo.OrganizadorTelefono = strings.TrimSpace(ev.Organizador.Telefono)
+	}
+	if ev.Publicar {
+		nuevo.Estado = domain.EstadoPublicado
+	}
+	return nuevo
+}
+
+func nombresConflicto(conflictos []dto.ConflictoEvento) string {
+	nombres := make([]string, 0, len(conflictos))
+	for _, c := range conflictos {
+		nombres = append(nombres, c.Nombre)
+	}
+	return strings.Join(nombres, ", ")
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"project/backend/prisma/db"
+)
+
+// MaxPortadaBytes is the largest cover image accepted.
+const MaxPortadaBytes = 5 << 20
+
+var (
+	ErrPortadaInvalida = errors.New("la portada debe ser una imagen JPEG, PNG o WebP de hasta 5 MB")
+	ErrAlmacenamiento  = errors.New("no se pudo guardar el archivo")
+)
+
+// formatosPortada maps the accepted image types to the file extension used
+// when storing them.
+var formatosPortada = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// ActualizarPortada stores a new cover image for the event and returns its
+// URL. The type is detected from the content, not from the file name, and
+// the previous cover is deleted once the new one is saved.
+func (s *Service) ActualizarPortada(ctx context.Context, id int, imagen io.Reader) (string, error) {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return "", ErrNotFound
+		}
+		return "", ErrDB
+	}
+
+	contenido, err := io.ReadAll(io.LimitReader(imagen, MaxPortadaBytes+1))
+	if err != nil || len(contenido) == 0 || len(contenido) > MaxPortadaBytes {
+		return "", ErrPortadaInvalida
+	}
+	ext, ok := formatosPortada[http.DetectContentType(contenido)]
+	if !ok {
+		return "", ErrPortadaInvalida
+	}
+
+	sufijo := make([]byte, 6)
+	if _, err := rand.Read(sufijo); err != nil {
+		return "", ErrAlmacenamiento
+	}
+	clave := fmt.Sprintf("eventos/%d/portada-%s.%s", id, hex.EncodeToString(sufijo), ext)
+	if err := s.storage.Put(ctx, clave, bytes.NewReader(contenido)); err != nil {
+		return "", ErrAlmacenamiento
+	}
+
+	url := s.storage.URL(clave)
+	anterior, err := s.repo.SetPortada(ctx, id, &url, &clave)
+	if err != nil {
+		s.eliminarArchivo(ctx, &clave)
+		if errors.Is(err, db.ErrNotFound) {
+			return "", ErrNotFound
+		}
+		return "", ErrDB
+	}
+	s.eliminarArchivo(ctx, anterior)
+	return url, nil
+}
+
+// EliminarPortada removes the cover image of the event.
+func (s *Service) EliminarPortada(ctx context.Context, id int) error {
+	anterior, err := s.repo.SetPortada(ctx, id, nil, nil)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ErrNotFound
+		}
+		return ErrDB
+	}
+	s.eliminarArchivo(ctx, anterior)
+	return nil
+}
+
+// eliminarArchivo deletes a stored file. A leftover file does no harm, so
+// failures are only logged.
+func (s *Service) eliminarArchivo(ctx context.Context, clave *string) {
+	if clave == nil || *clave == "" {
+		return
+	}
+	if err := s.storage.Delete(ctx, *clave); err != nil {
+		fmt.Println("[Portada] Error eliminando archivo", *clave, ":", err)
+	}
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/repo"
+	"project/backend/internal/events/validation"
+	notificationdto "project/backend/internal/notifications/dto"
+	sesionessrv "project/backend/internal/sesiones/service"
+	waitlistrepo "project/backend/internal/waitlist/repo"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrReprogramacionInvalida = errors.New("no se puede reprogramar el evento")
+	ErrNoReprogramable        = errors.New("solo se pueden reprogramar eventos publicados que aún no han iniciado")
+	ErrReprogramacionNotFound = errors.New("reprogramación no encontrada")
+	ErrEnlaceNotFound         = errors.New("enlace de reprogramación no encontrado")
+)
+
+// ReprogramarEvento moves a published event so it starts at start. The end
+// and every session move by the same wall-clock offset in the event's zone,
+// and so does the registration deadline unless cierre is given; a deadline
+// that already passed stays where it is. Every participant still holding or
+// waiting for a seat is notified with a link, built by appending a secret
+// to enlace, to confirm or cancel their attendance. It fails with a
+// *sesionessrv.ConflictoError when a speaker is busy at the new time of a
+// session.
+func (s *Service) ReprogramarEvento(ctx context.Context, id int, req dto.ReprogramarEventoRequest, start time.Time, cierre *time.Time, enlace string, now time.Time) (dto.ReprogramacionResponse, error) {
+	evento, err := s.repo.FindEstado(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.ReprogramacionResponse{}, ErrNotFound
+		}
+		return dto.ReprogramacionResponse{}, ErrDB
+	}
+	if evento.Estado != domain.EstadoPublicado || !now.Before(evento.FechaInicio) {
+		return dto.ReprogramacionResponse{}, ErrNoReprogramable
+	}
+
+	loc := domain.Zona(evento.ZonaHoraria)
+	actual := domain.Fechas{Inicio: evento.FechaInicio, Fin: evento.FechaFin, Cierre: evento.FechaCierreInscripcion}
+	fechas, offset := domain.DesplazarFechas(actual, start, loc)
+	if offset == 0 {
+		return dto.ReprogramacionResponse{}, fmt.Errorf("%w: %s", ErrReprogramacionInvalida, "la nueva fecha de inicio es igual a la actual")
+	}
+	if now.After(evento.FechaCierreInscripcion) {
+		if cierre != nil && !sameDay(cierre.In(loc), evento.FechaCierreInscripcion.In(loc)) {
+			return dto.ReprogramacionResponse{}, ErrCloseDateLocked
+		}
+		fechas.Cierre = evento.FechaCierreInscripcion
+		if !fechas.Inicio.After(now) {
+			return dto.ReprogramacionResponse{}, fmt.Errorf("%w: %s", ErrReprogramacionInvalida, "La fecha de inicio debe ser posterior a la fecha actual.")
+		}
+		if err := validation.ValidateEventoOrden(fechas.Inicio, fechas.Fin, fechas.Cierre); err != nil {
+			return dto.ReprogramacionResponse{}, fmt.Errorf("%w: %s", ErrReprogramacionInvalida, err.Error())
+		}
+	} else {
+		if cierre != nil {
+			fechas.Cierre = *cierre
+		}
+		if err := validation.ValidateEventoInstantes(fechas.Inicio, fechas.Fin, fechas.Cierre, now); err != nil {
+			return dto.ReprogramacionResponse{}, fmt.Errorf("%w: %s", ErrReprogramacionInvalida, err.Error())
+		}
+	}
+	if !req.ForzarConflicto {
+		if err := s.EnsureNoSolapamiento(ctx, evento.IDSede, fechas.Inicio, fechas.Fin, id); err != nil {
+			return dto.ReprogramacionResponse{}, err
+		}
+	}
+
+	inscritos, err := s.repo.ListInscritosActivos(ctx, id)
+	if err != nil {
+		return dto.ReprogramacionResponse{}, ErrDB
+	}
+	nueva := repo.NuevaReprogramacion{
+		EventoID: id,
+		Zona:     evento.ZonaHoraria,
+		Offset:   offset,
+		Inicio:   fechas.Inicio,
+		Fin:      fechas.Fin,
+		Cierre:   fechas.Cierre,
+		Motivo:   req.Motivo,
+		Actor:    req.Actor,
+	}
+	tokens := make(map[int]string, len(inscritos))
+	for _, inscrito := range inscritos {
+		token, err := nuevoToken()
+		if err != nil {
+			return dto.ReprogramacionResponse{}, ErrDB
+		}
+		tokens[inscrito.IDInscripcion] = token
+		nueva.Inscripciones = append(nueva.Inscripciones, inscrito.IDInscripcion)
+		nueva.TokenHashes = append(nueva.TokenHashes, hashToken(token))
+	}
+
+	pendientes, conflictos, err := s.repo.Reprogramar(ctx, nueva)
+	if err != nil {
+		return dto.ReprogramacionResponse{}, ErrDB
+	}
+	if err := sesionessrv.NewConflictoError(conflictos); err != nil {
+		return dto.ReprogramacionResponse{}, err
+	}
+	nota := fmt.Sprintf("Reprogramado del %s al %s", domain.FormatoConZona(evento.FechaInicio, evento.ZonaHoraria), domain.FormatoConZona(fechas.Inicio, evento.ZonaHoraria))
+	motivo := ""
+	if m := strings.TrimSpace(req.Motivo); m != "" {
+		nota += ": " + m
+		motivo = " Motivo: " + strings.TrimSuffix(m, ".") + "."
+	}
+	s.registrarVersion(ctx, id, domain.AccionReprogramado, req.Actor, nota)
+
+	for _, p := range pendientes {
+		mensaje := fmt.Sprintf(
+			notificationdto.MsgReprogramacionEvento,
+			evento.Nombre,
+			domain.FormatoConZona(fechas.Inicio, evento.ZonaHoraria),
+			domain.FormatoConZona(fechas.Fin, evento.ZonaHoraria),
+			motivo,
+			enlace+tokens[p.IDInscripcion],
+		)
+		_, notifErr := s.notificationService.CreateNotification(ctx, notificationdto.CreateNotificationRequest{
+			UserID:  p.IDUsuario,
+			EventID: &id,
+			Type:    notificationdto.NotificationTypeReprogramacionEvento,
+			Message: mensaje,
+		})
+		if notifErr != nil {
+			fmt.Println("[Reprogramacion] Error notificando al usuario", p.IDUsuario, ":", notifErr)
+		}
+	}
+
+	rows, err := s.repo.ListReprogramaciones(ctx, id)
+	if err != nil || len(rows) == 0 {
+		return dto.ReprogramacionResponse{}, ErrDB
+	}
+	return reprogramacionResponse(rows[0]), nil
+}
+
+// ReprogramacionesEvento lists the reschedules of an event, newest first,
+// with the summary of their answers.
+func (s *Service) ReprogramacionesEvento(ctx context.Context, id int) ([]dto.ReprogramacionResponse, error) {
+	if _, err := s.repo.FindEstado(ctx, id); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrDB
+	}
+	rows, err := s.repo.ListReprogramaciones(ctx, id)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make([]dto.ReprogramacionResponse, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, reprogramacionResponse(row))
+	}
+	return res, nil
+}
+
+// ReprogramacionEvento returns a reschedule of the event with the answer of
+// every participant asked about it.
+func (s *Service) ReprogramacionEvento(ctx context.Context, id, reprogramacionID int) (dto.ReprogramacionResponse, error) {
+	row, err := s.repo.FindReprogramacion(ctx, id, reprogramacionID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.ReprogramacionResponse{}, ErrReprogramacionNotFound
+		}
+		return dto.ReprogramacionResponse{}, ErrDB
+	}
+	respuestas, err := s.repo.ListRespuestas(ctx, reprogramacionID)
+	if err != nil {
+		return dto.ReprogramacionResponse{}, ErrDB
+	}
+
+	res := reprogramacionResponse(row)
+	res.Respuestas = make([]dto.RespuestaParticipante, 0, len(respuestas))
+	for _, r := range respuestas {
+		item := dto.RespuestaParticipante{
+			IDInscripcion:      r.IDInscripcion,
+			IDUsuario:          r.IDUsuario,
+			Nombre:             r.Nombre,
+			Email:              r.Email,
+			Respuesta:          r.Respuesta,
+			ReembolsoPendiente: r.ReembolsoPendiente,
+		}
+		if r.FechaRespuesta != nil {
+			item.FechaRespuesta = domain.FormatoLocal(*r.FechaRespuesta, row.ZonaHoraria)
+		}
+		res.Respuestas = append(res.Respuestas, item)
+	}
+	return res, nil
+}
+
+// ConsultarReprogramacion returns the reschedule behind a link token as the
+// participant sees it.
+func (s *Service) ConsultarReprogramacion(ctx context.Context, token string, now time.Time) (dto.RespuestaReprogramacionResponse, error) {
+	row, err := s.respuestaPorToken(ctx, token)
+	if err != nil {
+		return dto.RespuestaReprogramacionResponse{}, err
+	}
+	return respuestaResponse(row, now), nil
+}
+
+// ResponderReprogramacion stores a participant's answer to a reschedule.
+// Cancelling releases the seat, so the waitlist moves up, and marks a paid
+// inscription for refund.
+func (s *Service) ResponderReprogramacion(ctx context.Context, req dto.RespuestaReprogramacionRequest, now time.Time) (dto.RespuestaReprogramacionResponse, error) {
+	row, err := s.respuestaPorToken(ctx, req.Token)
+	if err != nil {
+		return dto.RespuestaReprogramacionResponse{}, err
+	}
+	if err := domain.ValidarRespuesta(row.Respuesta, req.Respuesta, row.FechaInicio, now); err != nil {
+		return dto.RespuestaReprogramacionResponse{}, err
+	}
+
+	guardada, err := s.repo.GuardarRespuesta(ctx, row.IDRespuesta, req.Respuesta, fmt.Sprintf("usuario:%d", row.IDUsuario))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.RespuestaReprogramacionResponse{}, domain.ErrRespuestaCerrada
+		}
+		return dto.RespuestaReprogramacionResponse{}, ErrDB
+	}
+	if guardada.EstadoAnterior != nil && *guardada.EstadoAnterior != waitlistrepo.EstadoEnEspera {
+		evento, err := s.repo.FindByID(ctx, row.IDEvento)
+		if err == nil {
+			_, err = s.waitlist.PromoverSiguientes(ctx, evento)
+		}
+		if err != nil {
+			fmt.Println("[Reprogramacion] Error promoviendo lista de espera del evento", row.IDEvento, ":", err)
+		}
+	}
+
+	row, err = s.respuestaPorToken(ctx, req.Token)
+	if err != nil {
+		return dto.RespuestaReprogramacionResponse{}, err
+	}
+	return respuestaResponse(row, now), nil
+}
+
+func (s *Service) respuestaPorToken(ctx context.Context, token string) (repo.RespuestaTokenRow, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return repo.RespuestaTokenRow{}, ErrEnlaceNotFound
+	}
+	row, err := s.repo.FindRespuestaPorToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.RespuestaTokenRow{}, ErrEnlaceNotFound
+		}
+		return repo.RespuestaTokenRow{}, ErrDB
+	}
+	return row, nil
+}
+
+func reprogramacionResponse(row repo.ReprogramacionRow) dto.ReprogramacionResponse {
+	zona := row.ZonaHoraria
+	res := dto.ReprogramacionResponse{
+		IDReprogramacion:    row.IDReprogramacion,
+		IDEvento:            row.IDEvento,
+		FechaInicioAnterior: domain.FormatoConZona(row.FechaInicioAnterior, zona),
+		FechaFinAnterior:    domain.FormatoConZona(row.FechaFinAnterior, zona),
+		FechaInicio:         domain.FormatoConZona(row.FechaInicio, zona),
+		FechaFin:            domain.FormatoConZona(row.FechaFin, zona),
+		Fecha:               domain.FormatoLocal(row.Fecha, zona),
+		Notificados:         row.Total,
+		Resumen: dto.ResumenRespuestas{
+			Pendientes:  row.Pendientes,
+			Confirmadas: row.Confirmadas,
+			Canceladas:  row.Canceladas,
+		},
+	}
+	if row.Motivo != nil {
+		res.Motivo = *row.Motivo
+	}
+	if row.Actor != nil {
+		res.Actor = *row.Actor
+	}
+	return res
+}
+
+func respuestaResponse(row repo.RespuestaTokenRow, now time.Time) dto.RespuestaReprogramacionResponse {
+	res := dto.RespuestaReprogramacionResponse{
+		IDEvento:            row.IDEvento,
+		Evento:              row.Evento,
+		FechaInicioAnterior: domain.FormatoConZona(row.FechaInicioAnterior, row.ZonaHoraria),
+		FechaInicio:         domain.FormatoConZona(row.FechaInicio, row.ZonaHoraria),
+		FechaFin:            domain.FormatoConZona(row.FechaFin, row.ZonaHoraria),
+		Respuesta:           row.Respuesta,
+		PuedeResponder:      domain.ValidarRespuesta(row.Respuesta, domain.RespuestaCancelada, row.FechaInicio, now) == nil,
+		ReembolsoPendiente:  row.ReembolsoPendiente,
+	}
+	if row.Motivo != nil {
+		res.Motivo = *row.Motivo
+	}
+	return res
+}
+
+// nuevoToken returns a random link secret. Only its hash is stored.
+func nuevoToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/repo"
+	"project/backend/internal/events/validation"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrOcurrenciaInvalida = errors.New("ocurrencia inválida")
+	ErrSinSerie           = errors.New("el evento no pertenece a una serie")
+	ErrSerieNotFound      = errors.New("serie no encontrada")
+)
+
+// CrearSerie expands the recurrence rule from the first occurrence and
+// creates one event per occurrence, linked to a new series. Every
+// occurrence goes through the same date, venue and overlap checks as a
+// single event, and the series is stored in one statement, published when
+// the request asks for it, so nothing is created unless all of it is.
+func (s *Service) CrearSerie(ctx context.Context, req dto.CreateSerieRequest, primera domain.Fechas, now time.Time) (int, error) {
+	zona := zonaONombre(req.ZonaHoraria)
+	loc := domain.Zona(zona)
+
+	regla, err := domain.ParseRegla(req.Regla, loc)
+	if err != nil {
+		return 0, err
+	}
+	excepciones, err := domain.ParseExcepciones(req.Excepciones, loc)
+	if err != nil {
+		return 0, err
+	}
+	ocurrencias, err := regla.Ocurrencias(primera, excepciones, loc)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, o := range ocurrencias {
+		if err := validation.ValidateEventoInstantes(o.Inicio, o.Fin, o.Cierre, now); err != nil {
+			return 0, fmt.Errorf("%w: ocurrencia del %s: %s", ErrOcurrenciaInvalida, domain.FormatoLocal(o.Inicio, zona), err.Error())
+		}
+		if i > 0 && o.Inicio.Before(ocurrencias[i-1].Fin) {
+			return 0, fmt.Errorf("%w: la ocurrencia del %s empieza antes de que termine la anterior", ErrOcurrenciaInvalida, domain.FormatoLocal(o.Inicio, zona))
+		}
+	}
+
+	enUso, err := s.repo.NombreEnUso(ctx, req.Nombre, 0, nil)
+	if err != nil {
+		return 0, ErrDB
+	}
+	if enUso {
+		return 0, ErrNameExists
+	}
+	if err := s.ensureSede(ctx, req.IDSede, req.Capacidad); err != nil {
+		return 0, err
+	}
+	if !req.ForzarConflicto {
+		if err := s.ensureSerieSinConflictos(ctx, req.IDSede, ocurrencias, nil); err != nil {
+			return 0, err
+		}
+	}
+
+	evento := req.CreateEventoRequest
+	evento.ZonaHoraria = zona
+	nuevos := make([]repo.NuevoEvento, 0, len(ocurrencias))
+	for _, o := range ocurrencias {
+		nuevos = append(nuevos, nuevoEventoDe(evento, o))
+	}
+	serieID, creados, err := s.repo.CrearSerie(ctx, req.Nombre, req.Regla, req.Excepciones, zona, nuevos, req.Actor)
+	if err != nil {
+		return 0, ErrDB
+	}
+	var publicados []int
+	for _, c := range creados {
+		s.registrarVersion(ctx, c.IDEvento, domain.AccionCreado, req.Actor, "")
+		if c.Estado == domain.EstadoPublicado {
+			s.registrarVersion(ctx, c.IDEvento, domain.AccionPublicado, req.Actor, "")
+			publicados = append(publicados, c.IDEvento)
+		}
+	}
+	s.notificarAperturaLote(ctx, publicados)
+	return serieID, nil
+}
+
+// ensureSerieSinConflictos gathers the venue conflicts of every occurrence
+// into a single error, ignoring the occurrences listed in propias.
+func (s *Service) ensureSerieSinConflictos(ctx context.Context, sedeID *int, ocurrencias []domain.Fechas, propias map[int]bool) error {
+	if sedeID == nil {
+		return nil
+	}
+	vistos := map[int]bool{}
+	conflictos := []dto.ConflictoEvento{}
+	for _, o := range ocurrencias {
+		encontrados, err := s.BuscarConflictos(ctx, *sedeID, o.Inicio, o.Fin, 0)
+		if err != nil {
+			return err
+		}
+		for _, c := range encontrados {
+			if propias[c.ID] || vistos[c.ID] {
+				continue
+			}
+			vistos[c.ID] = true
+			conflictos = append(conflictos, c)
+		}
+	}
+	if len(conflictos) > 0 {
+		return &ConflictoError{Conflictos: conflictos}
+	}
+	return nil
+}
+
+// GetSerie returns a series and all of its occurrences.
+func (s *Service) GetSerie(ctx context.Context, id int) (dto.SerieResponse, []db.EventoModel, error) {
+	serie, err := s.repo.FindSerie(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.SerieResponse{}, nil, ErrSerieNotFound
+		}
+		return dto.SerieResponse{}, nil, ErrDB
+	}
+	eventos, err := s.repo.FindBySerie(ctx, id)
+	if err != nil {
+		return dto.SerieResponse{}, nil, ErrDB
+	}
+	excepciones := serie.Excepciones
+	if excepciones == nil {
+		excepciones = []string{}
+	}
+	return dto.SerieResponse{
+		IDSerie:     serie.IDSerie,
+		Nombre:      serie.Nombre,
+		Regla:       serie.Regla,
+		Excepciones: excepciones,
+		ZonaHoraria: serie.ZonaHoraria,
+	}, eventos, nil
+}
+
+// ActualizarFuturas applies an update to an occurrence and every later
+// occurrence of its series. The new dates of the edited occurrence give the
+// shift applied to the others, so the series keeps its rhythm. Every
+// occurrence is checked first and all of them are stored in one
+// transaction.
+func (s *Service) ActualizarFuturas(ctx context.Context, req dto.UpdateEventoRequest, start, end, cierre time.Time) ([]db.EventoModel, error) {
+	actual, err := s.repo.FindEstado(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrDB
+	}
+	if actual.IDSerie == nil {
+		return nil, ErrSinSerie
+	}
+	sedeID := req.IDSede
+	if !req.CambiaSede {
+		sedeID = actual.IDSede
+	}
+	ocurrencias, err := s.repo.FindOcurrenciasDesde(ctx, *actual.IDSerie, actual.FechaInicio)
+	if err != nil {
+		return nil, ErrDB
+	}
+	enUso, err := s.repo.NombreEnUso(ctx, req.Nombre, req.ID, actual.IDSerie)
+	if err != nil {
+		return nil, ErrDB
+	}
+	if enUso {
+		return nil, ErrNameExists
+	}
+	ids := make([]int, 0, len(ocurrencias))
+	for _, o := range ocurrencias {
+		ids = append(ids, o.IDEvento)
+	}
+	cupos, err := s.waitlist.Cupos(ctx, ids)
+	if err != nil {
+		return nil, ErrDB
+	}
+
+	// The shift is measured on the wall clock of the series' zone, so an
+	// occurrence on the other side of a daylight saving change keeps its
+	// local time.
+	zona := zonaONombre(req.ZonaHoraria)
+	loc := domain.Zona(zona)
+	dInicio := domain.DiferenciaLocal(actual.FechaInicio, start, loc)
+	dFin := domain.DiferenciaLocal(actual.FechaFin, end, loc)
+	dCierre := domain.DiferenciaLocal(actual.FechaCierreInscripcion, cierre, loc)
+	now := time.Now()
+
+	propias := make(map[int]bool, len(ocurrencias))
+	nuevas := make([]domain.Fechas, 0, len(ocurrencias))
+	cambios := make([]repo.OcurrenciaActualizada, 0, len(ocurrencias))
+	for _, o := range ocurrencias {
+		f := domain.Fechas{
+			Inicio: domain.Desplazar(o.FechaInicio, dInicio, loc),
+			Fin:    domain.Desplazar(o.FechaFin, dFin, loc),
+			Cierre: domain.Desplazar(o.FechaCierreInscripcion, dCierre, loc),
+		}
+		if err := validation.ValidateEventoOrden(f.Inicio, f.Fin, f.Cierre); err != nil {
+			return nil, fmt.Errorf("%w: ocurrencia del %s: %s", ErrOcurrenciaInvalida, domain.FormatoLocal(f.Inicio, zona), err.Error())
+		}
+		if now.After(o.FechaCierreInscripcion) && !sameDay(f.Cierre.In(loc), o.FechaCierreInscripcion.In(loc)) {
+			return nil, ErrCloseDateLocked
+		}
+		if err := s.ensureSede(ctx, sedeID, cupos[o.IDEvento].Capacidad); err != nil {
+			return nil, err
+		}
+		propias[o.IDEvento] = true
+		nuevas = append(nuevas, f)
+		cambios = append(cambios, repo.OcurrenciaActualizada{
+			ID:          o.IDEvento,
+			Nombre:      req.Nombre,
+			Ubicacion:   req.Ubicacion,
+			Fechas:      f,
+			ZonaHoraria: zona,
+			CambiaSede:  req.CambiaSede,
+			IDSede:      sedeID,
+			Detalles:    detallesActualizados(req, o),
+		})
+	}
+	if !req.ForzarConflicto {
+		if err := s.ensureSerieSinConflictos(ctx, sedeID, nuevas, propias); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.ActualizarOcurrencias(ctx, cambios); err != nil {
+		return nil, ErrDB
+	}
+	for i, o := range ocurrencias {
+		s.registrarVersion(ctx, o.IDEvento, domain.AccionActualizado, req.Actor, "")
+		s.notificarCambios(ctx, o, req.Nombre, req.Ubicacion, nuevas[i], zona)
+	}
+
+	eventos, err := s.repo.FindBySerie(ctx, *actual.IDSerie)
+	if err != nil {
+		return nil, ErrDB
+	}
+	actualizados := make([]db.EventoModel, 0, len(ocurrencias))
+	for _, ev := range eventos {
+		if propias[ev.IDEvento] {
+			actualizados = append(actualizados, ev)
+		}
+	}
+	return actualizados, nil
+}
@@ -7,12 +7,17 @@ import (
 	"strings"
 	"time"
 
+	"project/backend/internal/events/domain"
 	"project/backend/internal/events/dto"
 	"project/backend/internal/events/repo"
 	notificationdto "project/backend/internal/notifications/dto"
 	notificationsrepo "project/backend/internal/notifications/repo"
 	notificationsrv "project/backend/internal/notifications/service"
 	registrationrepo "project/backend/internal/registrations/repo"
+	sedesrepo "project/backend/internal/sedes/repo"
+	"project/backend/internal/shared/cache"
+	"project/backend/internal/shared/storage"
+	waitlistsrv "project/backend/internal/waitlist/service"
 	"project/backend/prisma/db"
 )
 
@@ -25,12 +30,32 @@ var (
 	ErrCannotOpenAfterStart  = errors.New("No se pueden reabrir inscripciones después de que el evento haya iniciado")
 	ErrCannotOpenAfterClose  = errors.New("No se pueden reabrir inscripciones después de la fecha de cierre")
 	ErrCloseDateLocked       = errors.New("La fecha de cierre de inscripción no puede modificarse una vez alcanzada")
+	ErrSedeNotFound          = errors.New("sede no encontrada")
+	ErrCapacidadSede         = errors.New("la capacidad del evento supera la capacidad de la sede")
 )
 
+// ConflictoError reports the events that already occupy the venue on the
+// requested dates. It matches ErrOverlap with errors.Is.
+type ConflictoError struct {
+	Conflictos []dto.ConflictoEvento
+}
+
+func (e *ConflictoError) Error() string {
+	return ErrOverlap.Error()
+}
+
+func (e *ConflictoError) Unwrap() error {
+	return ErrOverlap
+}
+
 type Service struct {
 	repo                *repo.Repository
 	inscripcionRepo     *registrationrepo.Repository
 	notificationService notificationsrv.NotificationService
+	waitlist            *waitlistsrv.Service
+	sedes               *sedesrepo.Repository
+	storage             storage.Storage
+	dashboards          *cache.TTL[int, dto.DashboardEventoResponse]
 }
 
 func New(prismaClient *db.PrismaClient) *Service {
@@ -43,6 +68,10 @@ func New(prismaClient *db.PrismaClient) *Service {
 		repo:                eventRepo,
 		inscripcionRepo:     inscripcionRepo,
 		notificationService: notificationService,
+		waitlist:            waitlistsrv.New(prismaClient),
+		sedes:               sedesrepo.New(prismaClient),
+		storage:             storage.NewLocalFromEnv(),
+		dashboards:          cache.NewTTL[int, dto.DashboardEventoResponse](ttlDashboard),
 	}
 }
 
@@ -57,33 +86,223 @@ func (s *Service) EnsureNombreUnico(ctx context.Context, nombre string) error {
 	return nil
 }
 
-func (s *Service) EnsureNoSolapamiento(ctx context.Context, start, end time.Time) error {
-	eventos, err := s.repo.FindAll(ctx)
+// EnsureNoSolapamiento rejects dates that overlap another active event at
+// the same venue. Events without venue are never in conflict. excluirID
+// leaves the event being edited out of the check.
+func (s *Service) EnsureNoSolapamiento(ctx context.Context, sedeID *int, start, end time.Time, excluirID int) error {
+	if sedeID == nil {
+		return nil
+	}
+	conflictos, err := s.BuscarConflictos(ctx, *sedeID, start, end, excluirID)
 	if err != nil {
-		return ErrDB
+		return err
 	}
-	for _, ev := range eventos {
-		if !start.After(ev.FechaFin) && !end.Before(ev.FechaInicio) {
-			return ErrOverlap
+	if len(conflictos) > 0 {
+		return &ConflictoError{Conflictos: conflictos}
+	}
+	return nil
+}
+
+// BuscarConflictos lists the events that occupy the venue between start and
+// end.
+func (s *Service) BuscarConflictos(ctx context.Context, sedeID int, start, end time.Time, excluirID int) ([]dto.ConflictoEvento, error) {
+	rows, err := s.repo.FindConflictos(ctx, sedeID, start, end, excluirID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	return conflictosEvento(rows), nil
+}
+
+func conflictosEvento(rows []repo.ConflictoRow) []dto.ConflictoEvento {
+	res := make([]dto.ConflictoEvento, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, dto.ConflictoEvento{
+			ID:          row.IDEvento,
+			Nombre:      row.Nombre,
+			FechaInicio: domain.FormatoLocal(row.FechaInicio, row.ZonaHoraria),
+			FechaFin:    domain.FormatoLocal(row.FechaFin, row.ZonaHoraria),
+			IDSede:      row.IDSede,
+			Sede:        row.SedeNombre,
+		})
+	}
+	return res
+}
+
+// ensureSede checks that the venue exists and can hold the event capacity.
+func (s *Service) ensureSede(ctx context.Context, sedeID, capacidad *int) error {
+	if sedeID == nil {
+		return nil
+	}
+	sede, err := s.sedes.FindByID(ctx, *sedeID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ErrSedeNotFound
 		}
+		return ErrDB
+	}
+	if capacidad != nil && sede.Capacidad != nil && *capacidad > *sede.Capacidad {
+		return ErrCapacidadSede
 	}
 	return nil
 }
 
+// CreateEvento stores the event as a draft. Unless req.ForzarConflicto is
+// set, the overlap check at the venue runs under a lock in the same
+// transaction as the insert.
 func (s *Service) CreateEvento(ctx context.Context, req dto.CreateEventoRequest, start, end, cierre time.Time) (*db.EventoModel, error) {
-	created, err := s.repo.Create(ctx, req.Nombre, req.Ubicacion, start, end, cierre)
+	if err := s.ensureSede(ctx, req.IDSede, req.Capacidad); err != nil {
+		return nil, err
+	}
+	nuevo := nuevoEventoDe(req, domain.Fechas{Inicio: start, Fin: end, Cierre: cierre})
+	nuevo.Estado = domain.EstadoBorrador
+	id, conflictos, err := s.repo.CrearEvento(ctx, nuevo, req.ForzarConflicto)
 	if err != nil {
 		return nil, ErrDB
 	}
-	notifErr := s.notificationService.NotificarAperturaInscripciones(ctx, created, s.inscripcionRepo)
-	if notifErr != nil {
-		fmt.Println("[CreateEvento] Error notificando apertura de inscripciones:", notifErr)
+	if len(conflictos) > 0 {
+		return nil, &ConflictoError{Conflictos: conflictosEvento(conflictos)}
 	}
+	created, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrDB
+	}
+	s.registrarVersion(ctx, created.IDEvento, domain.AccionCreado, req.Actor, "")
 	return created, nil
 }
 
-func (s *Service) ListEventos(ctx context.Context) ([]db.EventoModel, error) {
-	eventos, err := s.repo.FindAll(ctx)
+// PublicarEvento makes a draft visible to participants and announces that
+// its inscriptions are open.
+func (s *Service) PublicarEvento(ctx context.Context, id int, actor string) (*db.EventoModel, error) {
+	evento, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrDB
+	}
+	if err := s.cambiarEstado(ctx, id, domain.EstadoPublicado, actor, "Evento publicado", time.Now()); err != nil {
+		return nil, err
+	}
+	s.registrarVersion(ctx, id, domain.AccionPublicado, actor, "")
+
+	zona, err := s.repo.FindZonaHoraria(ctx, id)
+	if err != nil {
+		zona = domain.ZonaHorariaPredeterminada
+	}
+	notifErr := s.notificationService.NotificarAperturaInscripciones(ctx, evento, zona, s.inscripcionRepo)
+	if notifErr != nil {
+		fmt.Println("[PublicarEvento] Error notificando apertura de inscripciones:", notifErr)
+	}
+	return evento, nil
+}
+
+// AvanzarEstados moves published events to ongoing and ongoing events to
+// finished once their dates are reached. It returns how many transitions
+// were applied.
+func (s *Service) AvanzarEstados(ctx context.Context, now time.Time) (int, error) {
+	eventos, err := s.repo.FindByEstados(ctx, []string{domain.EstadoPublicado, domain.EstadoEnCurso})
+	if err != nil {
+		return 0, ErrDB
+	}
+
+	aplicadas := 0
+	for _, ev := range eventos {
+		actual := ev.Estado
+		for {
+			siguiente, ok := domain.SiguienteAutomatico(actual, fechasDe(ev), now)
+			if !ok {
+				break
+			}
+			cambiado, err := s.repo.CambiarEstado(ctx, ev.IDEvento, actual, siguiente, "system", "Transición automática por fecha")
+			if err != nil {
+				return aplicadas, ErrDB
+			}
+			if !cambiado {
+				break
+			}
+			aplicadas++
+			actual = siguiente
+		}
+		if actual != ev.Estado {
+			s.registrarVersion(ctx, ev.IDEvento, domain.AccionEstado, "system", "Transición automática por fecha")
+		}
+	}
+	return aplicadas, nil
+}
+
+func (s *Service) HistorialEstados(ctx context.Context, id int) ([]dto.EstadoHistorialResponse, error) {
+	evento, err := s.repo.FindEstado(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrDB
+	}
+	rows, err := s.repo.ListHistorialEstados(ctx, id)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make([]dto.EstadoHistorialResponse, 0, len(rows))
+	for _, row := range rows {
+		item := dto.EstadoHistorialResponse{
+			IDHistorial:    row.IDHistorial,
+			EstadoAnterior: row.EstadoAnterior,
+			EstadoNuevo:    row.EstadoNuevo,
+			FechaCambio:    domain.FormatoLocal(row.FechaCambio, evento.ZonaHoraria),
+		}
+		if row.Nota != nil {
+			item.Nota = *row.Nota
+		}
+		if row.Actor != nil {
+			item.Actor = *row.Actor
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// cambiarEstado applies a manual transition after checking its guards.
+func (s *Service) cambiarEstado(ctx context.Context, id int, destino, actor, nota string, now time.Time) error {
+	actual, err := s.repo.FindEstado(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ErrNotFound
+		}
+		return ErrDB
+	}
+	if err := domain.ValidarTransicion(actual.Estado, destino, fechasDe(actual), now); err != nil {
+		return err
+	}
+	cambiado, err := s.repo.CambiarEstado(ctx, id, actual.Estado, destino, actor, nota)
+	if err != nil {
+		return ErrDB
+	}
+	if !cambiado {
+		return domain.ErrTransicionInvalida
+	}
+	return nil
+}
+
+func fechasDe(ev repo.EstadoRow) domain.Fechas {
+	return domain.Fechas{
+		Inicio: ev.FechaInicio,
+		Fin:    ev.FechaFin,
+		Cierre: ev.FechaCierreInscripcion,
+	}
+}
+
+// ListEventos returns the non-cancelled events. Drafts are only included for
+// callers that manage events, archived events only when asked for.
+func (s *Service) ListEventos(ctx context.Context, incluirBorradores, incluirArchivados bool) ([]db.EventoModel, error) {
+	var (
+		eventos []db.EventoModel
+		err     error
+	)
+	if incluirBorradores {
+		eventos, err = s.repo.FindAll(ctx, incluirArchivados)
+	} else {
+		eventos, err = s.repo.FindVisibles(ctx, incluirArchivados)
+	}
 	if err != nil {
 		return nil, ErrDB
 	}
@@ -110,81 +329,145 @@ func (s *Service) UpdateEvento(ctx context.Context, req dto.UpdateEventoRequest,
 		return nil, ErrDB
 	}
 
-	if time.Now().After(evento.FechaCierreInscripcion) && !sameDay(cierre, evento.FechaCierreInscripcion) {
+	zona := zonaONombre(req.ZonaHoraria)
+	loc := domain.Zona(zona)
+
+	if time.Now().After(evento.FechaCierreInscripcion) && !sameDay(cierre.In(loc), evento.FechaCierreInscripcion.In(loc)) {
 		return nil, ErrCloseDateLocked
 	}
 
-	existingName, err := s.repo.FindByName(ctx, strings.TrimSpace(req.Nombre))
-	if err == nil && existingName != nil && existingName.IDEvento != req.ID {
+	actual, err := s.repo.FindEstado(ctx, req.ID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	if !req.CambiaSede {
+		req.IDSede = actual.IDSede
+	}
+	enUso, err := s.repo.NombreEnUso(ctx, req.Nombre, req.ID, actual.IDSerie)
+	if err != nil {
+		return nil, ErrDB
+	}
+	if enUso {
 		return nil, ErrNameExists
 	}
 
-	eventos, err := s.repo.FindAll(ctx)
+	cupos, err := s.waitlist.Cupos(ctx, []int{req.ID})
 	if err != nil {
 		return nil, ErrDB
 	}
-	for _, ev := range eventos {
-		if ev.IDEvento == req.ID {
-			continue
-		}
-		if !start.After(ev.FechaFin) && !end.Before(ev.FechaInicio) {
-			return nil, ErrOverlap
-		}
+	if err := s.ensureSede(ctx, req.IDSede, cupos[req.ID].Capacidad); err != nil {
+		return nil, err
 	}
-
-	updated, err := s.repo.Update(ctx, req.ID, req.Nombre, req.Ubicacion, start, end, cierre)
+	fechas := domain.Fechas{Inicio: start, Fin: end, Cierre: cierre}
+	conflictos, err := s.repo.ActualizarEvento(ctx, repo.OcurrenciaActualizada{
+		ID:          req.ID,
+		Nombre:      req.Nombre,
+		Ubicacion:   req.Ubicacion,
+		Fechas:      fechas,
+		ZonaHoraria: zona,
+		CambiaSede:  req.CambiaSede,
+		IDSede:      req.IDSede,
+		Detalles:    detallesActualizados(req, actual),
+	}, req.ForzarConflicto)
 	if err != nil {
 		return nil, ErrDB
 	}
+	if len(conflictos) > 0 {
+		return nil, &ConflictoError{Conflictos: conflictosEvento(conflictos)}
+	}
+	updated, err := s.repo.FindByID(ctx, req.ID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	accion, nota := domain.AccionActualizado, ""
+	if req.RestauraVersion > 0 {
+		accion, nota = domain.AccionRestaurado, fmt.Sprintf("Restaurada la versión %d", req.RestauraVersion)
+	}
+	s.registrarVersion(ctx, req.ID, accion, req.Actor, nota)
+
+	s.notificarCambios(ctx, actual, req.Nombre, req.Ubicacion, fechas, zona)
+
+	return updated, nil
+}
 
+// detallesActualizados merges the descriptive fields present in an update
+// into the current ones. It returns nil when the update leaves them out.
+func detallesActualizados(req dto.UpdateEventoRequest, actual repo.EstadoRow) *dto.DetallesEvento {
+	if req.Descripcion == nil && req.Categorias == nil && req.Organizador == nil && req.Enlaces == nil {
+		return nil
+	}
+	detalles := actual.Detalles()
+	if req.Descripcion != nil {
+		detalles.Descripcion = domain.SanitizarMarkdown(*req.Descripcion)
+	}
+	if req.Categorias != nil {
+		detalles.Categorias = req.Categorias
+	}
+	if req.Organizador != nil {
+		detalles.Organizador = req.Organizador
+	}
+	if req.Enlaces != nil {
+		detalles.Enlaces = req.Enlaces
+	}
+	return &detalles
+}
+
+// notificarCambios tells the attendees of an event which of its name,
+// dates, location and time zone an update changed. The update is already
+// stored, so failures are only logged.
+func (s *Service) notificarCambios(ctx context.Context, antes repo.EstadoRow, nombre, ubicacion string, fechas domain.Fechas, zona string) {
+	nombre = strings.TrimSpace(nombre)
+	ubicacion = strings.TrimSpace(ubicacion)
 	cambios := []string{}
 
-	if evento.Nombre != req.Nombre {
-		cambios = append(cambios, fmt.Sprintf("nuevo nombre: %s", req.Nombre))
+	if antes.Nombre != nombre {
+		cambios = append(cambios, fmt.Sprintf("nuevo nombre: %s", nombre))
+	}
+	if !antes.FechaInicio.Equal(fechas.Inicio) {
+		cambios = append(cambios, fmt.Sprintf("nueva fecha de inicio: %s", domain.FormatoConZona(fechas.Inicio, zona)))
 	}
-	if !evento.FechaInicio.Equal(start) {
-		cambios = append(cambios, fmt.Sprintf("nueva fecha de inicio: %s", start.Format("02/01/2006")))
+	if !antes.FechaFin.Equal(fechas.Fin) {
+		cambios = append(cambios, fmt.Sprintf("nueva fecha de fin: %s", domain.FormatoConZona(fechas.Fin, zona)))
 	}
-	if !evento.FechaFin.Equal(end) {
-		cambios = append(cambios, fmt.Sprintf("nueva fecha de fin: %s", end.Format("02/01/2006")))
+	if !antes.FechaCierreInscripcion.Equal(fechas.Cierre) {
+		cambios = append(cambios, fmt.Sprintf("nueva fecha de cierre de inscripción: %s", domain.FormatoConZona(fechas.Cierre, zona)))
 	}
-	if !evento.FechaCierreInscripcion.Equal(cierre) {
-		cambios = append(cambios, fmt.Sprintf("nueva fecha de cierre de inscripción: %s", cierre.Format("02/01/2006")))
+	if antes.Ubicacion != ubicacion {
+		cambios = append(cambios, fmt.Sprintf("nueva ubicación: %s", ubicacion))
 	}
-	if evento.Ubicacion != req.Ubicacion {
-		cambios = append(cambios, fmt.Sprintf("nueva ubicación: %s", req.Ubicacion))
+	if antes.ZonaHoraria != zona {
+		cambios = append(cambios, fmt.Sprintf("nueva zona horaria: %s", zona))
+	}
+	if len(cambios) == 0 {
+		return
 	}
 
-	if len(cambios) > 0 {
-		mensaje := fmt.Sprintf(
-			notificationdto.MsgCambioEvento,
-			updated.Nombre,
-			strings.Join(cambios, ", "),
-		)
+	mensaje := fmt.Sprintf(
+		notificationdto.MsgCambioEvento,
+		nombre,
+		strings.Join(cambios, ", "),
+	)
 
-		// Notificar a los usuarios inscritos
-		inscripciones, err := s.inscripcionRepo.FindByEventoID(ctx, req.ID)
-		if err != nil {
-			fmt.Println("Error obteniendo inscripciones:", err)
-		}
+	// Notificar a los usuarios inscritos
+	inscripciones, err := s.inscripcionRepo.FindByEventoID(ctx, antes.IDEvento)
+	if err != nil {
+		fmt.Println("Error obteniendo inscripciones:", err)
+	}
 
-		for _, inscripcion := range inscripciones {
-			_, notifErr := s.notificationService.CreateNotification(ctx, notificationdto.CreateNotificationRequest{
-				UserID:  inscripcion.IDUsuario,
-				EventID: &req.ID,
-				Type:    notificationdto.NotificationTypeCambioEvento,
-				Message: mensaje,
-			})
-			if notifErr != nil {
-				fmt.Println("Error creando notificación para usuario", inscripcion.IDUsuario, ":", notifErr)
-			}
+	for _, inscripcion := range inscripciones {
+		_, notifErr := s.notificationService.CreateNotification(ctx, notificationdto.CreateNotificationRequest{
+			UserID:  inscripcion.IDUsuario,
+			EventID: &antes.IDEvento,
+			Type:    notificationdto.NotificationTypeCambioEvento,
+			Message: mensaje,
+		})
+		if notifErr != nil {
+			fmt.Println("Error creando notificación para usuario", inscripcion.IDUsuario, ":", notifErr)
 		}
 	}
-
-	return updated, nil
 }
 
-func (s *Service) DeleteEvento(ctx context.Context, id int) error {
+func (s *Service) DeleteEvento(ctx context.Context, id int, actor string) error {
 	// Verificar que el evento existe
 	evento, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -193,10 +476,11 @@ func (s *Service) DeleteEvento(ctx context.Context, id int) error {
 		}
 		return ErrDB
 	}
-	// Soft delete: marcar como cancelado
-	if err := s.repo.DeleteByID(ctx, id); err != nil {
-		return ErrDB
+	// Soft delete: el evento pasa a cancelado y queda en el historial
+	if err := s.cambiarEstado(ctx, id, domain.EstadoCancelado, actor, "Evento cancelado", time.Now()); err != nil {
+		return err
 	}
+	s.registrarVersion(ctx, id, domain.AccionCancelado, actor, "")
 	notifErr := s.notificationService.NotificarCancelacionEvento(ctx, evento, s.inscripcionRepo)
 	if notifErr != nil {
 		fmt.Println("[DeleteEvento] Error notificando cancelación de evento:", notifErr)
@@ -204,7 +488,7 @@ func (s *Service) DeleteEvento(ctx context.Context, id int) error {
 	return nil
 }
 
-func (s *Service) CerrarInscripciones(ctx context.Context, eventoID int) (*db.EventoModel, error) {
+func (s *Service) CerrarInscripciones(ctx context.Context, eventoID int, actor string) (*db.EventoModel, error) {
 	evento, err := s.repo.FindByID(ctx, eventoID)
 	if err != nil {
 		if errors.Is(err, db.ErrNotFound) {
@@ -221,10 +505,11 @@ func (s *Service) CerrarInscripciones(ctx context.Context, eventoID int) (*db.Ev
 	if err != nil {
 		return nil, ErrDB
 	}
+	s.registrarVersion(ctx, eventoID, domain.AccionInscripcionesCerradas, actor, "")
 	return updated, nil
 }
 
-func (s *Service) AbrirInscripciones(ctx context.Context, eventoID int) (*db.EventoModel, error) {
+func (s *Service) AbrirInscripciones(ctx context.Context, eventoID int, actor string) (*db.EventoModel, error) {
 	evento, err := s.repo.FindByID(ctx, eventoID)
 	if err != nil {
 		if errors.Is(err, db.ErrNotFound) {
@@ -244,15 +529,103 @@ func (s *Service) AbrirInscripciones(ctx context.Context, eventoID int) (*db.Eve
 	if err != nil {
 		return nil, ErrDB
 	}
+	s.registrarVersion(ctx, eventoID, domain.AccionInscripcionesAbiertas, actor, "")
 	return updated, nil
 }
 
+// ActualizarCapacidad changes the capacity of an event and promotes
+// waitlisted inscriptions into any seat the change frees up.
+func (s *Service) ActualizarCapacidad(ctx context.Context, eventoID int, capacidad *int) (*db.EventoModel, error) {
+	evento, err := s.repo.FindByID(ctx, eventoID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrDB
+	}
+	estado, err := s.repo.FindEstado(ctx, eventoID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	if err := s.ensureSede(ctx, estado.IDSede, capacidad); err != nil {
+		return nil, err
+	}
+
+	if err := s.waitlist.SetCapacidad(ctx, eventoID, capacidad); err != nil {
+		return nil, ErrDB
+	}
+	if _, err := s.waitlist.PromoverSiguientes(ctx, evento); err != nil {
+		fmt.Println("[ActualizarCapacidad] Error promoviendo lista de espera:", err)
+	}
+	return evento, nil
+}
+
+// DetallesPorEvento returns the lifecycle state and occupation of the given
+// events.
+func (s *Service) DetallesPorEvento(ctx context.Context, eventoIDs []int) (map[int]dto.EventoDetalle, error) {
+	estados, err := s.repo.FindEstados(ctx, eventoIDs)
+	if err != nil {
+		return nil, ErrDB
+	}
+	cupos, err := s.waitlist.Cupos(ctx, eventoIDs)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make(map[int]dto.EventoDetalle, len(estados))
+	for _, ev := range estados {
+		cupo := cupos[ev.IDEvento]
+		res[ev.IDEvento] = dto.EventoDetalle{
+			Estado:           ev.Estado,
+			Capacidad:        cupo.Capacidad,
+			CuposDisponibles: cupo.Disponibles,
+			EnEspera:         cupo.EnEspera,
+			IDSede:           ev.IDSede,
+			Sede:             ev.SedeNombre,
+			ZonaHoraria:      ev.ZonaHoraria,
+			Fechas:           fechasDe(ev),
+			IDSerie:          ev.IDSerie,
+			Detalles:         ev.Detalles(),
+			PortadaURL:       ev.PortadaURL,
+			Archivado:        ev.Archivado,
+		}
+	}
+	return res, nil
+}
+
+// ZonaHoraria returns the IANA time zone of an event.
+func (s *Service) ZonaHoraria(ctx context.Context, id int) (string, error) {
+	zona, err := s.repo.FindZonaHoraria(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return "", ErrNotFound
+		}
+		return "", ErrDB
+	}
+	return zona, nil
+}
+
+func zonaONombre(zona string) string {
+	if zona == "" {
+		return domain.ZonaHorariaPredeterminada
+	}
+	return zona
+}
+
 func sameDay(a, b time.Time) bool {
 	y1, m1, d1 := a.Date()
 	y2, m2, d2 := b.Date()
 	return y1 == y2 && m1 == m2 && d1 == d2
 }
 
-func (s *Service) GetFechasOcupadas(ctx context.Context) ([]dto.RangoFechas, error) {
-	return s.repo.GetFechasOcupadas(ctx)
+// GetFechasOcupadas returns the dates taken at a venue. Without venue there
+// is nothing to collide with, so the list is empty.
+func (s *Service) GetFechasOcupadas(ctx context.Context, sedeID *int) ([]dto.RangoFechas, error) {
+	if sedeID == nil {
+		return []dto.RangoFechas{}, nil
+	}
+	fechas, err := s.repo.GetFechasOcupadas(ctx, *sedeID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	return fechas, nil
 }
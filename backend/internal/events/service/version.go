@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"project/backend/internal/events/domain"
+	"project/backend/internal/events/dto"
+	"project/backend/internal/events/repo"
+	"project/backend/prisma/db"
+)
+
+var ErrVersionNotFound = errors.New("versión no encontrada")
+
+// registrarVersion saves the current state of the event as its next
+// version, with the fields changed since the previous one. An update that
+// changed nothing is not recorded. The change it describes is already
+// applied, so failures are only logged.
+func (s *Service) registrarVersion(ctx context.Context, id int, accion, actor, nota string) {
+	// Two writers can read the same previous version; the loser retries
+	// once on top of the winner's.
+	for intento := 0; intento < 2; intento++ {
+		err := s.guardarVersion(ctx, id, accion, actor, nota)
+		if err == nil {
+			return
+		}
+		if intento == 1 {
+			fmt.Println("[Versiones] Error registrando versión del evento", id, ":", err)
+		}
+	}
+}
+
+func (s *Service) guardarVersion(ctx context.Context, id int, accion, actor, nota string) error {
+	actual, err := s.repo.FindEstado(ctx, id)
+	if err != nil {
+		return err
+	}
+	snapshot, err := json.Marshal(snapshotDe(actual))
+	if err != nil {
+		return err
+	}
+
+	numero := 1
+	var anterior []byte
+	ultima, err := s.repo.UltimaVersion(ctx, id)
+	switch {
+	case err == nil:
+		numero = ultima.Version + 1
+		anterior = []byte(ultima.Snapshot)
+	case !errors.Is(err, db.ErrNotFound):
+		return err
+	}
+
+	cambios, err := domain.Diferencias(anterior, snapshot)
+	if err != nil {
+		return err
+	}
+	if len(cambios) == 0 && accion == domain.AccionActualizado {
+		return nil
+	}
+	cambiosJSON, err := json.Marshal(cambios)
+	if err != nil {
+		return err
+	}
+	return s.repo.CrearVersion(ctx, id, numero, accion, actor, nota, snapshot, cambiosJSON)
+}
+
+func snapshotDe(ev repo.EstadoRow) dto.SnapshotEvento {
+	loc := domain.Zona(ev.ZonaHoraria)
+	detalles := ev.Detalles()
+	categorias := detalles.Categorias
+	if categorias == nil {
+		categorias = []string{}
+	}
+	return dto.SnapshotEvento{
+		Nombre:                 ev.Nombre,
+		FechaInicio:            ev.FechaInicio.In(loc),
+		FechaFin:               ev.FechaFin.In(loc),
+		FechaCierreInscripcion: ev.FechaCierreInscripcion.In(loc),
+		ZonaHoraria:            ev.ZonaHoraria,
+		Ubicacion:              ev.Ubicacion,
+		IDSede:                 ev.IDSede,
+		Estado:                 ev.Estado,
+		InscripcionesAbiertas:  ev.InscripcionesAbiertas,
+		Descripcion:            detalles.Descripcion,
+		Categorias:             categorias,
+		Organizador:            detalles.Organizador,
+		Enlaces:                detalles.Enlaces,
+	}
+}
+
+// VersionesEvento lists the version history of an event, oldest first.
+func (s *Service) VersionesEvento(ctx context.Context, id int) ([]dto.VersionEventoResponse, error) {
+	evento, err := s.repo.FindEstado(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrDB
+	}
+	rows, err := s.repo.ListVersiones(ctx, id)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make([]dto.VersionEventoResponse, 0, len(rows))
+	for _, row := range rows {
+		item, err := versionResponse(row, evento.ZonaHoraria)
+		if err != nil {
+			return nil, ErrDB
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// VersionEvento returns one version of an event with its full snapshot.
+func (s *Service) VersionEvento(ctx context.Context, id, version int) (dto.VersionEventoResponse, error) {
+	evento, err := s.repo.FindEstado(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.VersionEventoResponse{}, ErrNotFound
+		}
+		return dto.VersionEventoResponse{}, ErrDB
+	}
+	row, err := s.findVersion(ctx, id, version)
+	if err != nil {
+		return dto.VersionEventoResponse{}, err
+	}
+	res, err := versionResponse(row, evento.ZonaHoraria)
+	if err != nil {
+		return dto.VersionEventoResponse{}, ErrDB
+	}
+	var snapshot dto.SnapshotEvento
+	if err := json.Unmarshal([]byte(row.Snapshot), &snapshot); err != nil {
+		return dto.VersionEventoResponse{}, ErrDB
+	}
+	res.Snapshot = &snapshot
+	return res, nil
+}
+
+// DiferenciaVersiones compares any two versions of an event.
+func (s *Service) DiferenciaVersiones(ctx context.Context, id, desde, hasta int) (dto.DiferenciaVersionesResponse, error) {
+	a, err := s.findVersion(ctx, id, desde)
+	if err != nil {
+		return dto.DiferenciaVersionesResponse{}, err
+	}
+	b, err := s.findVersion(ctx, id, hasta)
+	if err != nil {
+		return dto.DiferenciaVersionesResponse{}, err
+	}
+	cambios, err := domain.Diferencias([]byte(a.Snapshot), []byte(b.Snapshot))
+	if err != nil {
+		return dto.DiferenciaVersionesResponse{}, ErrDB
+	}
+	return dto.DiferenciaVersionesResponse{Desde: desde, Hasta: hasta, Cambios: cambios}, nil
+}
+
+func (s *Service) findVersion(ctx context.Context, id, version int) (repo.VersionRow, error) {
+	row, err := s.repo.FindVersion(ctx, id, version)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.VersionRow{}, ErrVersionNotFound
+		}
+		return repo.VersionRow{}, ErrDB
+	}
+	return row, nil
+}
+
+func versionResponse(row repo.VersionRow, zona string) (dto.VersionEventoResponse, error) {
+	res := dto.VersionEventoResponse{
+		Version: row.Version,
+		Accion:  row.Accion,
+		Fecha:   domain.FormatoLocal(row.Fecha, zona),
+		Cambios: []domain.Cambio{},
+	}
+	if row.Actor != nil {
+		res.Actor = *row.Actor
+	}
+	if row.Nota != nil {
+		res.Nota = *row.Nota
+	}
+	if row.Cambios != "" {
+		if err := json.Unmarshal([]byte(row.Cambios), &res.Cambios); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
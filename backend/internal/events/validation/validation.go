@@ -2,40 +2,101 @@ package validation
 
 import (
 	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"project/backend/internal/events/dto"
 )
 
 var (
-	nameRegex     = regexp.MustCompile(`^[\p{L} ]+$`)
-	locationRegex = regexp.MustCompile(`^[\p{L}0-9\s,\.\-]+$`)
+	nameRegex      = regexp.MustCompile(`^[\p{L}\p{M}\p{N} .,:;!?¡¿'"()&/#+\-]+$`)
+	locationRegex  = regexp.MustCompile(`^[\p{L}0-9\s,\.\-]+$`)
+	categoriaRegex = regexp.MustCompile(`^[\p{L}0-9 \-]+$`)
+	telefonoRegex  = regexp.MustCompile(`^\+?[0-9 ()\-]{7,20}$`)
 )
 
+// ValidateEventoNombre accepts letters, digits, spaces and common
+// punctuation, so names like "Congreso 2027: Salud & Tecnología" are valid.
+// Names must contain at least one letter.
 func ValidateEventoNombre(nombre string) error {
 	trimmed := strings.TrimSpace(nombre)
-	if len(trimmed) < 5 || len(trimmed) > 100 {
+	if n := utf8.RuneCountInString(trimmed); n < 5 || n > 100 {
 		return errors.New("El nombre del evento debe tener entre 5 y 100 caracteres.")
 	}
-	if !nameRegex.MatchString(trimmed) {
-		return errors.New("El nombre del evento solo puede contener letras y espacios")
+	if !nameRegex.MatchString(trimmed) || strings.IndexFunc(trimmed, unicode.IsLetter) < 0 {
+		return errors.New("El nombre del evento solo puede contener letras, números, espacios y signos de puntuación comunes.")
 	}
 	return nil
 }
 
+// fechaLayouts are the accepted inputs for event dates, in the order they
+// are tried. Inputs with an offset are absolute instants; the rest are read
+// as wall-clock time in the event's zone.
+var fechaLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"02/01/2006 15:04",
+	"02/01/2006",
+}
+
+// ParseEventoFecha reads a date in any of fechaLayouts and returns it in loc.
+func ParseEventoFecha(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	var err error
+	for _, layout := range fechaLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, value, loc); err == nil {
+			return t.In(loc), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// ParseEventoFin is ParseEventoFecha for the end of an event. A date without
+// time of day means the end of that day in loc, so an event ending on
+// "DD/MM/AAAA" lasts the whole day.
+func ParseEventoFin(value string, loc *time.Location) (time.Time, error) {
+	t, err := ParseEventoFecha(value, loc)
+	if err != nil || !soloFecha(value) {
+		return t, err
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, loc), nil
+}
+
+func soloFecha(value string) bool {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "02/01/2006"} {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEventoFechas parses and checks the dates of a new event. Dates
+// without offset are read in now's location, which callers set to the
+// event's time zone.
 func ValidateEventoFechas(fechaInicio, fechaFin, fechaCierre string, now time.Time) (time.Time, time.Time, time.Time, error) {
 	loc := now.Location()
-	start, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(fechaInicio), loc)
+	start, err := ParseEventoFecha(fechaInicio, loc)
 	if err != nil {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de inicio inválida (formato DD/MM/AAAA).")
+		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de inicio inválida (formato DD/MM/AAAA o ISO 8601).")
 	}
-	end, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(fechaFin), loc)
+	end, err := ParseEventoFin(fechaFin, loc)
 	if err != nil {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de fin inválida (formato DD/MM/AAAA).")
+		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de fin inválida (formato DD/MM/AAAA o ISO 8601).")
 	}
-	cierre, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(fechaCierre), loc)
+	cierre, err := ParseEventoFecha(fechaCierre, loc)
 	if err != nil {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de cierre de inscripción inválida (formato DD/MM/AAAA).")
+		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de cierre de inscripción inválida (formato DD/MM/AAAA o ISO 8601).")
 	}
 
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
@@ -47,11 +108,8 @@ func ValidateEventoFechas(fechaInicio, fechaFin, fechaCierre string, now time.Ti
 		return time.Time{}, time.Time{}, time.Time{}, errors.New("La fecha de cierre de inscripción debe ser posterior a la fecha actual.")
 	}
 
-	if !end.After(start) {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("La fecha de fin debe ser posterior a la fecha de inicio.")
-	}
-	if !cierre.Before(start) {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("La fecha de cierre de inscripción debe ser anterior a la fecha de inicio del evento.")
+	if err := ValidateEventoOrden(start, end, cierre); err != nil {
+		return time.Time{}, time.Time{}, time.Time{}, err
 	}
 
 	return start, end, cierre, nil
@@ -59,20 +117,21 @@ func ValidateEventoFechas(fechaInicio, fechaFin, fechaCierre string, now time.Ti
 
 func ValidateEventoFechasUpdate(fechaInicio, fechaFin, fechaCierre string, now, currentCierre time.Time) (time.Time, time.Time, time.Time, error) {
 	loc := now.Location()
-	start, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(fechaInicio), loc)
+	start, err := ParseEventoFecha(fechaInicio, loc)
 	if err != nil {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de inicio inválida (formato DD/MM/AAAA).")
+		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de inicio inválida (formato DD/MM/AAAA o ISO 8601).")
 	}
-	end, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(fechaFin), loc)
+	end, err := ParseEventoFin(fechaFin, loc)
 	if err != nil {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de fin inválida (formato DD/MM/AAAA).")
+		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de fin inválida (formato DD/MM/AAAA o ISO 8601).")
 	}
-	cierre, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(fechaCierre), loc)
+	cierre, err := ParseEventoFecha(fechaCierre, loc)
 	if err != nil {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de cierre de inscripción inválida (formato DD/MM/AAAA).")
+		return time.Time{}, time.Time{}, time.Time{}, errors.New("Fecha de cierre de inscripción inválida (formato DD/MM/AAAA o ISO 8601).")
 	}
 
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	currentCierre = currentCierre.In(loc)
 	currentCierreDay := time.Date(currentCierre.Year(), currentCierre.Month(), currentCierre.Day(), 0, 0, 0, 0, loc)
 	requestedCierreDay := time.Date(cierre.Year(), cierre.Month(), cierre.Day(), 0, 0, 0, 0, loc)
 
@@ -85,14 +144,36 @@ func ValidateEventoFechasUpdate(fechaInicio, fechaFin, fechaCierre string, now,
 	if !requestedCierreDay.After(today) && !requestedCierreDay.Equal(currentCierreDay) {
 		return time.Time{}, time.Time{}, time.Time{}, errors.New("La fecha de cierre de inscripción debe ser posterior a la fecha actual.")
 	}
+	if err := ValidateEventoOrden(start, end, cierre); err != nil {
+		return time.Time{}, time.Time{}, time.Time{}, err
+	}
+
+	return start, end, cierre, nil
+}
+
+// ValidateEventoOrden checks that the event ends after it starts and that
+// inscriptions close before it starts.
+func ValidateEventoOrden(start, end, cierre time.Time) error {
 	if !end.After(start) {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("La fecha de fin debe ser posterior a la fecha de inicio.")
+		return errors.New("La fecha de fin debe ser posterior a la fecha de inicio.")
 	}
 	if !cierre.Before(start) {
-		return time.Time{}, time.Time{}, time.Time{}, errors.New("La fecha de cierre de inscripción debe ser anterior a la fecha de inicio del evento.")
+		return errors.New("La fecha de cierre de inscripción debe ser anterior a la fecha de inicio del evento.")
 	}
+	return nil
+}
 
-	return start, end, cierre, nil
+// ValidateEventoInstantes checks already parsed dates of a new event, as
+// ValidateEventoFechas does for user input. It is used for dates the server
+// derives, such as the occurrences of a series.
+func ValidateEventoInstantes(start, end, cierre, now time.Time) error {
+	if !start.After(now) {
+		return errors.New("La fecha de inicio debe ser posterior a la fecha actual.")
+	}
+	if !cierre.After(now) {
+		return errors.New("La fecha de cierre de inscripción debe ser posterior a la fecha actual.")
+	}
+	return ValidateEventoOrden(start, end, cierre)
 }
 
 func ValidateEventoUbicacion(ubicacion string) error {
@@ -111,3 +192,97 @@ func ValidateEventoUbicacion(ubicacion string) error {
 	}
 	return nil
 }
+
+// ValidateEventoCapacidad accepts a nil capacity, meaning the event has no
+// limit, or a positive number of seats.
+func ValidateEventoCapacidad(capacidad *int) error {
+	if capacidad == nil {
+		return nil
+	}
+	if *capacidad < 1 || *capacidad > 100000 {
+		return errors.New("La capacidad del evento debe estar entre 1 y 100000.")
+	}
+	return nil
+}
+
+// MaxCategorias bounds how many categories an event may carry.
+const MaxCategorias = 10
+
+// NormalizarCategorias trims, lowercases and de-duplicates the categories of
+// an event so that catalog filters and facets match regardless of how they
+// were typed.
+func NormalizarCategorias(categorias []string) ([]string, error) {
+	res := make([]string, 0, len(categorias))
+	vistas := make(map[string]bool, len(categorias))
+	for _, c := range categorias {
+		c = strings.ToLower(strings.Join(strings.Fields(c), " "))
+		if c == "" || vistas[c] {
+			continue
+		}
+		if utf8.RuneCountInString(c) > 40 || !categoriaRegex.MatchString(c) {
+			return nil, errors.New("Cada categoría debe tener hasta 40 letras, números, espacios o guiones.")
+		}
+		vistas[c] = true
+		res = append(res, c)
+	}
+	if len(res) > MaxCategorias {
+		return nil, fmt.Errorf("Un evento puede tener como máximo %d categorías.", MaxCategorias)
+	}
+	return res, nil
+}
+
+func ValidateEventoDescripcion(descripcion string) error {
+	if utf8.RuneCountInString(strings.TrimSpace(descripcion)) > 5000 {
+		return errors.New("La descripción no puede superar los 5000 caracteres.")
+	}
+	return nil
+}
+
+// ValidateEventoOrganizador checks the organizer contact. A nil or empty
+// organizer means the event has none.
+func ValidateEventoOrganizador(organizador *dto.OrganizadorEvento) error {
+	if organizador == nil {
+		return nil
+	}
+	nombre := strings.TrimSpace(organizador.Nombre)
+	email := strings.TrimSpace(organizador.Email)
+	telefono := strings.TrimSpace(organizador.Telefono)
+	if nombre == "" && email == "" && telefono == "" {
+		return nil
+	}
+	if n := utf8.RuneCountInString(nombre); n < 2 || n > 100 {
+		return errors.New("El nombre del organizador debe tener entre 2 y 100 caracteres.")
+	}
+	if email == "" && telefono == "" {
+		return errors.New("Indique un correo o un teléfono de contacto del organizador.")
+	}
+	if email != "" {
+		if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			return errors.New("El correo del organizador no es válido.")
+		}
+	}
+	if telefono != "" && !telefonoRegex.MatchString(telefono) {
+		return errors.New("El teléfono del organizador no es válido.")
+	}
+	return nil
+}
+
+// MaxEnlaces bounds how many external links an event may list.
+const MaxEnlaces = 10
+
+// ValidateEventoEnlaces accepts absolute http and https links with a title.
+func ValidateEventoEnlaces(enlaces []dto.EnlaceEvento) error {
+	if len(enlaces) > MaxEnlaces {
+		return fmt.Errorf("Un evento puede tener como máximo %d enlaces.", MaxEnlaces)
+	}
+	for _, e := range enlaces {
+		if n := utf8.RuneCountInString(strings.TrimSpace(e.Titulo)); n < 1 || n > 80 {
+			return errors.New("Cada enlace debe tener un título de hasta 80 caracteres.")
+		}
+		u, err := url.Parse(strings.TrimSpace(e.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(e.URL) > 500 {
+			return fmt.Errorf("El enlace %q debe ser una URL http o https válida.", e.Titulo)
+		}
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	"project/backend/internal/events/dto"
 )
 
 func TestValidateEventoNombre(t *testing.T) {
@@ -12,8 +15,12 @@ func TestValidateEventoNombre(t *testing.T) {
 	}{
 		{"Congreso Nacional", false},
 		{"AB", true},
-		{"Evento 2024", true},
+		{"Evento 2024", false},
+		{"Congreso 2027: Salud & Tecnología", false},
+		{"¿Qué es la IA? (Taller)", false},
+		{"2027 - 2028", true},
 		{"Evento@", true},
+		{"<script>x</script>", true},
 	}
 
 	for _, c := range cases {
@@ -45,7 +52,7 @@ func TestValidateEventoFechas(t *testing.T) {
 		t.Fatalf("expected error for close date in past")
 	}
 
-	_, _, _, err = ValidateEventoFechas("15/02/2026", "15/02/2026", "14/02/2026", now)
+	_, _, _, err = ValidateEventoFechas("15/02/2026", "14/02/2026", "13/02/2026", now)
 	if err == nil {
 		t.Fatalf("expected error for end not after start")
 	}
@@ -84,6 +91,60 @@ func TestValidateEventoUbicacion(t *testing.T) {
 	}
 }
 
+func TestValidateEventoFechasISO(t *testing.T) {
+	caracas, err := time.LoadLocation("America/Caracas")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	now := time.Date(2026, 2, 4, 10, 0, 0, 0, caracas)
+
+	start, end, cierre, err := ValidateEventoFechas("2026-02-07T09:30", "2026-02-07T18:00:00Z", "06/02/2026 20:00", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := start.UTC().Format(time.RFC3339); got != "2026-02-07T13:30:00Z" {
+		t.Fatalf("start should be read in the event zone, got %s", got)
+	}
+	if end.Location() != caracas || end.Hour() != 14 {
+		t.Fatalf("end should keep its instant and be shown in the event zone, got %s", end)
+	}
+	if cierre.Hour() != 20 {
+		t.Fatalf("unexpected close time: %s", cierre)
+	}
+
+	_, _, _, err = ValidateEventoFechas("2026-02-07T09:30", "2026-02-07T09:00", "2026-02-06", now)
+	if err == nil {
+		t.Fatalf("expected error for end before start on the same day")
+	}
+}
+
+func TestValidateEventoFechasFinDelDia(t *testing.T) {
+	caracas, err := time.LoadLocation("America/Caracas")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	now := time.Date(2026, 2, 4, 10, 0, 0, 0, caracas)
+
+	start, end, _, err := ValidateEventoFechas("15/02/2026", "15/02/2026", "14/02/2026", now)
+	if err != nil {
+		t.Fatalf("a one-day event should be valid: %v", err)
+	}
+	if !start.Equal(time.Date(2026, 2, 15, 0, 0, 0, 0, caracas)) {
+		t.Fatalf("a date-only start should begin the day, got %s", start)
+	}
+	if !end.Equal(time.Date(2026, 2, 15, 23, 59, 59, 0, caracas)) {
+		t.Fatalf("a date-only end should last until the end of the day, got %s", end)
+	}
+
+	_, end, _, err = ValidateEventoFechas("15/02/2026 09:00", "15/02/2026 18:00", "14/02/2026", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if end.Hour() != 18 || end.Minute() != 0 {
+		t.Fatalf("an explicit end time must be kept, got %s", end)
+	}
+}
+
 func TestValidateEventoFechasUpdate(t *testing.T) {
 	now := time.Date(2026, 2, 11, 10, 0, 0, 0, time.Local)
 	currentCierre := time.Date(2026, 2, 10, 0, 0, 0, 0, time.Local)
@@ -98,3 +159,105 @@ func TestValidateEventoFechasUpdate(t *testing.T) {
 		t.Fatalf("expected error when modifying close date after it was reached")
 	}
 }
+
+func TestValidateEventoCapacidad(t *testing.T) {
+	valor := func(v int) *int { return &v }
+	cases := []struct {
+		capacidad *int
+		wantErr   bool
+	}{
+		{nil, false},
+		{valor(1), false},
+		{valor(250), false},
+		{valor(0), true},
+		{valor(-10), true},
+		{valor(100001), true},
+	}
+
+	for _, c := range cases {
+		err := ValidateEventoCapacidad(c.capacidad)
+		if c.wantErr && err == nil {
+			t.Fatalf("expected error for %v", *c.capacidad)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestNormalizarCategorias(t *testing.T) {
+	got, err := NormalizarCategorias([]string{" Tecnología ", "tecnología", "", "Salud  Pública", "IA-2027"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"tecnología", "salud pública", "ia-2027"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	if _, err := NormalizarCategorias([]string{"ciencia; drop"}); err == nil {
+		t.Fatal("expected error for punctuation")
+	}
+	muchas := make([]string, MaxCategorias+1)
+	for i := range muchas {
+		muchas[i] = strings.Repeat("a", i+1)
+	}
+	if _, err := NormalizarCategorias(muchas); err == nil {
+		t.Fatal("expected error for too many categories")
+	}
+}
+
+func TestValidateEventoOrganizador(t *testing.T) {
+	cases := []struct {
+		organizador *dto.OrganizadorEvento
+		wantErr     bool
+	}{
+		{nil, false},
+		{&dto.OrganizadorEvento{}, false},
+		{&dto.OrganizadorEvento{Nombre: "Ana Pérez", Email: "ana@example.com"}, false},
+		{&dto.OrganizadorEvento{Nombre: "Ana Pérez", Telefono: "+58 (212) 555-1234"}, false},
+		{&dto.OrganizadorEvento{Nombre: "Ana Pérez"}, true},
+		{&dto.OrganizadorEvento{Nombre: "Ana Pérez", Email: "Ana <ana@example.com>"}, true},
+		{&dto.OrganizadorEvento{Nombre: "Ana Pérez", Telefono: "llamar"}, true},
+		{&dto.OrganizadorEvento{Email: "ana@example.com"}, true},
+	}
+
+	for _, c := range cases {
+		err := ValidateEventoOrganizador(c.organizador)
+		if c.wantErr && err == nil {
+			t.Fatalf("expected error for %+v", c.organizador)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.organizador, err)
+		}
+	}
+}
+
+func TestValidateEventoEnlaces(t *testing.T) {
+	cases := []struct {
+		enlaces []dto.EnlaceEvento
+		wantErr bool
+	}{
+		{nil, false},
+		{[]dto.EnlaceEvento{{Titulo: "Sitio web", URL: "https://congreso.example.com"}}, false},
+		{[]dto.EnlaceEvento{{Titulo: "", URL: "https://congreso.example.com"}}, true},
+		{[]dto.EnlaceEvento{{Titulo: "Script", URL: "javascript:alert(1)"}}, true},
+		{[]dto.EnlaceEvento{{Titulo: "Relativo", URL: "/eventos/3"}}, true},
+		{make([]dto.EnlaceEvento, MaxEnlaces+1), true},
+	}
+
+	for i, c := range cases {
+		err := ValidateEventoEnlaces(c.enlaces)
+		if c.wantErr && err == nil {
+			t.Fatalf("case %d: expected error", i)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("case %d: unexpected error: %v", i, err)
+		}
+	}
+}
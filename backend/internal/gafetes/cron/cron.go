@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"project/backend/internal/gafetes/repo"
+	"project/backend/internal/gafetes/service"
+	notificationsrepo "project/backend/internal/notifications/repo"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/storage"
+	"project/backend/prisma/db"
+
+	"github.com/robfig/cron/v3"
+)
+
+func StartGeneracionGafetesScheduler(prismaClient *db.PrismaClient) {
+	gafeteService := service.New(repo.New(prismaClient), storage.NewPrivateFromEnv(), roles.NewUserRoleService(prismaClient))
+	jobExecutionRepo := notificationsrepo.NewJobExecutionRepository(prismaClient)
+	jobName := "generacion_gafetes"
+
+	runGeneracion(gafeteService, jobExecutionRepo, jobName)
+
+	c := cron.New()
+	c.AddFunc("@every 1m", func() {
+		runGeneracion(gafeteService, jobExecutionRepo, jobName)
+	})
+	c.Start()
+}
+
+func runGeneracion(gafeteService *service.Service, jobExecutionRepo *notificationsrepo.JobExecutionRepository, jobName string) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	procesados, err := gafeteService.ProcesarPendientes(ctx)
+	if procesados > 0 {
+		log.Println("[Gafetes] Lotes procesados:", procesados)
+	}
+	if err != nil {
+		log.Println("[Gafetes] Error al generar gafetes:", err)
+		return
+	}
+	_ = jobExecutionRepo.UpsertLastRun(ctx, jobName, now)
+}
@@ -0,0 +1,21 @@
+package dto
+
+// GafetesRequest asks for the badges of an event. The filters work like
+// those of the inscription list: Estado defaults to Aprobado, Q matches the
+// participant's name or email, and Desde and Hasta (dd/mm/yyyy) bound the
+// inscription date.
+type GafetesRequest struct {
+	IDEvento int    `json:"id_evento"`
+	Estado   string `json:"estado"`
+	Q        string `json:"q"`
+	Desde    string `json:"desde"`
+	Hasta    string `json:"hasta"`
+}
+
+// States of a badge batch.
+const (
+	LotePendiente  = "pendiente"
+	LoteProcesando = "procesando"
+	LoteListo      = "listo"
+	LoteError      = "error"
+)
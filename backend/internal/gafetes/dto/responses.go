@@ -0,0 +1,22 @@
+package dto
+
+// LoteResponse is a badge batch. URL is set once the PDF is ready.
+type LoteResponse struct {
+	IDLote      int     `json:"id_lote"`
+	IDEvento    int     `json:"id_evento"`
+	Estado      string  `json:"estado"`
+	Filtros     Filtros `json:"filtros"`
+	Total       int     `json:"total"`
+	URL         string  `json:"url,omitempty"`
+	Error       string  `json:"error,omitempty"`
+	CreadoEn    string  `json:"creado_en"`
+	TerminadoEn string  `json:"terminado_en,omitempty"`
+}
+
+// Filtros are the filters a batch was requested with.
+type Filtros struct {
+	Estado string `json:"estado"`
+	Q      string `json:"q,omitempty"`
+	Desde  string `json:"desde,omitempty"`
+	Hasta  string `json:"hasta,omitempty"`
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project/backend/internal/gafetes/dto"
+	"project/backend/internal/gafetes/repo"
+	"project/backend/internal/gafetes/service"
+	"project/backend/internal/gafetes/validation"
+	"project/backend/internal/policy"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/httperror"
+	"project/backend/internal/shared/storage"
+	"project/backend/prisma/db"
+)
+
+type Handler struct {
+	svc         *service.Service
+	roleService roles.UserRoleService
+}
+
+func New(client *db.PrismaClient) *Handler {
+	roleService := roles.NewUserRoleService(client)
+	return &Handler{
+		svc:         service.New(repo.New(client), storage.NewPrivateFromEnv(), roleService),
+		roleService: roleService,
+	}
+}
+
+// ServeHTTP serves /api/gafetes for organizers. POST asks for the badges of
+// an event, filtered like the inscription list; small batches come back
+// ready (201) and large ones pending (202), to be polled with
+// GET ?id_lote=N. GET ?id_lote=N&descargar=true downloads the PDF of a
+// ready batch and GET ?id_evento=N lists the batches of an event.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.get(w, r)
+	case http.MethodPost:
+		h.post(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	if loteStr := r.URL.Query().Get("id_lote"); loteStr != "" {
+		loteID, err := strconv.Atoi(loteStr)
+		if err != nil || loteID <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, "id_lote inválido")
+			return
+		}
+		if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+			return
+		}
+
+		if r.URL.Query().Get("descargar") == "true" {
+			h.descargar(w, r, loteID)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		defer cancel()
+
+		lote, err := h.svc.GetLote(ctx, loteID)
+		if writeGafeteError(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusOK, lote)
+		return
+	}
+
+	eventoID, err := strconv.Atoi(r.URL.Query().Get("id_evento"))
+	if err != nil || eventoID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_evento inválido")
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	lotes, err := h.svc.ListLotes(ctx, eventoID)
+	if writeGafeteError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, lotes)
+}
+
+// descargar streams the PDF of a ready batch.
+func (h *Handler) descargar(w http.ResponseWriter, r *http.Request, loteID int) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	archivo, err := h.svc.Archivo(ctx, loteID)
+	if writeGafeteError(w, err) {
+		return
+	}
+	defer archivo.Close()
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="gafetes-lote-%d.pdf"`, loteID))
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = io.Copy(w, archivo)
+}
+
+func (h *Handler) post(w http.ResponseWriter, r *http.Request) {
+	var req dto.GafetesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateGafetes(req, time.Now().Location()); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	lote, err := h.svc.Solicitar(ctx, req, policy.SubjectFromRequest(r).UserID)
+	if writeGafeteError(w, err) {
+		return
+	}
+	if lote.Estado == dto.LotePendiente {
+		writeJSON(w, http.StatusAccepted, lote)
+		return
+	}
+	writeJSON(w, http.StatusCreated, lote)
+}
+
+// msgSinPermisos answers callers without events.management.
+const msgSinPermisos = "no tienes permisos para generar gafetes"
+
+func writeGafeteError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrEventoNotFound), errors.Is(err, service.ErrLoteNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrSinArchivo):
+		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrSinAsistentes):
+		httperror.WriteJSON(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,232 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"project/backend/internal/gafetes/dto"
+	"project/backend/prisma/db"
+)
+
+// procesoVencido is how long a batch may stay in processing before another
+// worker takes it over, in case the one that claimed it died.
+const procesoVencido = "30 minutes"
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+type EventoRow struct {
+	IDEvento    int    `json:"id_evento"`
+	Nombre      string `json:"nombre"`
+	ZonaHoraria string `json:"zona_horaria"`
+}
+
+// Filtros select the inscriptions of an event that get a badge. Nil fields
+// do not filter.
+type Filtros struct {
+	IDEvento int
+	Estado   *string
+	Q        *string
+	Desde    *time.Time
+	Hasta    *time.Time
+}
+
+type LoteRow struct {
+	IDLote            int        `json:"id_lote"`
+	IDEvento          int        `json:"id_evento"`
+	EstadoInscripcion *string    `json:"estado_inscripcion"`
+	Q                 *string    `json:"q"`
+	Desde             *time.Time `json:"desde"`
+	Hasta             *time.Time `json:"hasta"`
+	Estado            string     `json:"estado"`
+	Total             int        `json:"total"`
+	ArchivoClave      *string    `json:"archivo_clave"`
+	ArchivoURL        *string    `json:"archivo_url"`
+	Error             *string    `json:"error"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	TerminadoEn       *time.Time `json:"terminado_en"`
+}
+
+// Filtros returns the filters the batch was requested with.
+func (l LoteRow) Filtros() Filtros {
+	return Filtros{IDEvento: l.IDEvento, Estado: l.EstadoInscripcion, Q: l.Q, Desde: l.Desde, Hasta: l.Hasta}
+}
+
+// AsistenteRow is what a badge shows about an attendee. Roles are the
+// active roles of the attendee's account and Ponente tells whether they
+// speak at a session of the event.
+type AsistenteRow struct {
+	IDInscripcion int      `json:"id_inscripcion"`
+	Nombre        string   `json:"nombre"`
+	Afiliacion    string   `json:"afiliacion"`
+	TipoEntrada   string   `json:"tipo_entrada"`
+	Roles         []string `json:"roles"`
+	Ponente       bool     `json:"ponente"`
+}
+
+const loteColumns = `"id_lote", "id_evento", "estado_inscripcion", "q", "desde", "hasta", "estado", "total",
+	"archivo_clave", "archivo_url", "error", "createdAt", "terminado_en"`
+
+// FindEvento returns the event, or db.ErrNotFound.
+func (r *Repository) FindEvento(ctx context.Context, id int) (EventoRow, error) {
+	query := `SELECT "id_evento", "nombre", "zona_horaria" FROM "Evento" WHERE "id_evento" = $1::int`
+	var rows []EventoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return EventoRow{}, err
+	}
+	if len(rows) == 0 {
+		return EventoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// Crear records a batch. A batch generated right away is created already
+// processing, so the scheduler does not pick it up too.
+func (r *Repository) Crear(ctx context.Context, f Filtros, creadoPor *int, inmediato bool) (LoteRow, error) {
+	estado := dto.LotePendiente
+	if inmediato {
+		estado = dto.LoteProcesando
+	}
+	query := `INSERT INTO "LoteGafetes" ("id_evento", "estado_inscripcion", "q", "desde", "hasta", "estado", "creado_por", "createdAt", "iniciado_en")
+		VALUES ($1::int, $2::text, $3::text, ($4::timestamptz AT TIME ZONE 'UTC'), ($5::timestamptz AT TIME ZONE 'UTC'), $6::text, $7::int, NOW(),
+			CASE WHEN $8::boolean THEN NOW() END)
+		RETURNING ` + loteColumns
+	var rows []LoteRow
+	err := r.client.Prisma.Raw.QueryRaw(query, f.IDEvento, f.Estado, f.Q, utc(f.Desde), utc(f.Hasta), estado, creadoPor, inmediato).Exec(ctx, &rows)
+	if err != nil {
+		return LoteRow{}, err
+	}
+	if len(rows) == 0 {
+		return LoteRow{}, fmt.Errorf("no rows")
+	}
+	return rows[0], nil
+}
+
+// FindLote returns the batch, or db.ErrNotFound.
+func (r *Repository) FindLote(ctx context.Context, id int) (LoteRow, error) {
+	query := `SELECT ` + loteColumns + ` FROM "LoteGafetes" WHERE "id_lote" = $1::int`
+	var rows []LoteRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return LoteRow{}, err
+	}
+	if len(rows) == 0 {
+		return LoteRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// ListLotes returns the batches of an event, newest first.
+func (r *Repository) ListLotes(ctx context.Context, eventoID int) ([]LoteRow, error) {
+	query := `SELECT ` + loteColumns + ` FROM "LoteGafetes" WHERE "id_evento" = $1::int ORDER BY "createdAt" DESC, "id_lote" DESC`
+	var rows []LoteRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Reclamar marks the oldest pending batch as processing and returns it, or
+// db.ErrNotFound when there is none. SKIP LOCKED lets several instances
+// run the scheduler without generating the same batch twice.
+func (r *Repository) Reclamar(ctx context.Context) (LoteRow, error) {
+	query := `UPDATE "LoteGafetes" SET "estado" = '` + dto.LoteProcesando + `', "iniciado_en" = NOW()
+		WHERE "id_lote" = (
+			SELECT "id_lote" FROM "LoteGafetes"
+			WHERE "estado" = '` + dto.LotePendiente + `'
+				OR ("estado" = '` + dto.LoteProcesando + `' AND "iniciado_en" < NOW() - INTERVAL '` + procesoVencido + `')
+			ORDER BY "createdAt", "id_lote"
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + loteColumns
+	var rows []LoteRow
+	if err := r.client.Prisma.Raw.QueryRaw(query).Exec(ctx, &rows); err != nil {
+		return LoteRow{}, err
+	}
+	if len(rows) == 0 {
+		return LoteRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// Terminar records the generated file of a batch.
+func (r *Repository) Terminar(ctx context.Context, id, total int, clave, url string) error {
+	query := `UPDATE "LoteGafetes" SET "estado" = '` + dto.LoteListo + `', "total" = $2::int, "archivo_clave" = $3::text,
+		"archivo_url" = $4::text, "error" = NULL, "terminado_en" = NOW()
+		WHERE "id_lote" = $1::int`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, id, total, clave, url).Exec(ctx)
+	return err
+}
+
+// Fallar records why a batch could not be generated.
+func (r *Repository) Fallar(ctx context.Context, id int, motivo string) error {
+	query := `UPDATE "LoteGafetes" SET "estado" = '` + dto.LoteError + `', "error" = $2::text, "terminado_en" = NOW()
+		WHERE "id_lote" = $1::int`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, id, motivo).Exec(ctx)
+	return err
+}
+
+// ListAsistentes returns the inscriptions that match f in name order. Like
+// the inscription list, Q matches the participant's name or email and Desde
+// and Hasta bound the inscription date.
+func (r *Repository) ListAsistentes(ctx context.Context, f Filtros) ([]AsistenteRow, error) {
+	query := `SELECT i."id_inscripcion", btrim(i."nombre_participante") AS "nombre", btrim(i."afiliacion") AS "afiliacion",
+			COALESCE(t."nombre", '') AS "tipo_entrada",
+			COALESCE((
+				SELECT array_agg(ro."nombre_rol" ORDER BY ro."nombre_rol")
+				FROM "UsuarioRoles" ur
+				JOIN "Roles" ro ON ro."id_rol" = ur."id_rol"
+				WHERE ur."id_usuario" = i."id_usuario"
+					AND (ur."valid_from" IS NULL OR ur."valid_from" <= NOW())
+					AND (ur."valid_until" IS NULL OR ur."valid_until" > NOW())
+			), '{}') AS "roles",
+			EXISTS (
+				SELECT 1 FROM "SesionPonente" sp
+				JOIN "Sesion" s ON s."id_sesion" = sp."id_sesion"
+				WHERE sp."id_usuario" = i."id_usuario" AND s."id_evento" = i."id_evento" AND NOT s."cancelado"
+			) AS "ponente"
+		FROM "Inscripcion" i
+		LEFT JOIN "TipoEntrada" t ON t."id_tipo_entrada" = i."id_tipo_entrada"
+		WHERE i."id_evento" = $1::int`
+
+	params := []interface{}{f.IDEvento}
+	addCond := func(format string, value interface{}) {
+		params = append(params, value)
+		query += fmt.Sprintf(format, len(params))
+	}
+	if f.Estado != nil {
+		addCond(` AND i."estado" = $%d::text`, *f.Estado)
+	}
+	if f.Q != nil {
+		params = append(params, "%"+*f.Q+"%")
+		idx := len(params)
+		query += fmt.Sprintf(` AND (i."nombre_participante" ILIKE $%d::text OR i."email" ILIKE $%d::text)`, idx, idx)
+	}
+	if f.Desde != nil {
+		addCond(` AND i."fecha_inscripcion" >= ($%d::timestamptz AT TIME ZONE 'UTC')`, f.Desde.UTC())
+	}
+	if f.Hasta != nil {
+		addCond(` AND i."fecha_inscripcion" <= ($%d::timestamptz AT TIME ZONE 'UTC')`, f.Hasta.UTC())
+	}
+	query += ` ORDER BY lower(btrim(i."nombre_participante")), i."id_inscripcion"`
+
+	var rows []AsistenteRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
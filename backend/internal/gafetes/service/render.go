@@ -0,0 +1,105 @@
+package service
+
+import (
+	"project/backend/internal/shared/pdf"
+	"project/backend/internal/shared/qr"
+)
+
+// Badges are laid out on A4 portrait pages in a grid of columnas by filas,
+// each anchoGafete by altoGafete points with a thin outline to cut along.
+const (
+	columnas      = 2
+	filas         = 4
+	porPagina     = columnas * filas
+	anchoGafete   = 270.0
+	altoGafete    = 180.0
+	rellenoGafete = 14.0
+	ladoQR        = 68.0
+)
+
+// gafete is what one badge shows. entrada is the signed ticket payload
+// encoded in the QR code that staff scan at check-in.
+type gafete struct {
+	nombre     string
+	afiliacion string
+	etiqueta   string
+	entrada    string
+}
+
+// posicionGafete returns the top-left corner of the i-th badge's box on
+// its page. The grid is centered on the page.
+func posicionGafete(i int) (x, y float64) {
+	margenX := (pdf.A4.Width - columnas*anchoGafete) / 2
+	margenY := (pdf.A4.Height - filas*altoGafete) / 2
+	celda := i % porPagina
+	return margenX + float64(celda%columnas)*anchoGafete, margenY + float64(celda/columnas)*altoGafete
+}
+
+// renderGafetes lays out the badges porPagina to a page, in order.
+func renderGafetes(evento string, gafetes []gafete) ([]byte, error) {
+	doc := pdf.New(pdf.A4)
+	var page *pdf.Page
+	for i, g := range gafetes {
+		if i%porPagina == 0 {
+			page = doc.AddPage()
+		}
+		x, y := posicionGafete(i)
+		if err := renderGafete(page, x, y, evento, g); err != nil {
+			return nil, err
+		}
+	}
+	return doc.Bytes(), nil
+}
+
+// renderGafete draws one badge: the event's name across the top, the
+// attendee's name in up to two lines, their affiliation and role on the
+// left and the QR code in the bottom-right corner, clear of the text by
+// its quiet zone.
+func renderGafete(page *pdf.Page, x, y float64, evento string, g gafete) error {
+	code, err := qr.Encode([]byte(g.entrada))
+	if err != nil {
+		return err
+	}
+	ancho := anchoGafete - 2*rellenoGafete
+	izquierda := x + rellenoGafete
+	anchoTexto := ancho - ladoQR - 10
+
+	page.SetColor(0.6, 0.6, 0.6)
+	page.Rect(x, y, anchoGafete, altoGafete, 0.5)
+
+	page.SetColor(0.35, 0.35, 0.35)
+	page.Text(izquierda, y+24, pdf.Helvetica, 9, pdf.AlignLeft, recortar(pdf.Helvetica, 9, ancho, evento, 1)[0])
+	page.Line(izquierda, y+32, izquierda+ancho, y+32, 0.5)
+
+	page.SetColor(0.1, 0.1, 0.1)
+	linea := y + 60
+	for _, l := range recortar(pdf.HelveticaBold, 20, ancho, g.nombre, 2) {
+		page.Text(izquierda, linea, pdf.HelveticaBold, 20, pdf.AlignLeft, l)
+		linea += 22
+	}
+	if g.afiliacion != "" {
+		page.Text(izquierda, y+116, pdf.Helvetica, 11, pdf.AlignLeft, recortar(pdf.Helvetica, 11, anchoTexto, g.afiliacion, 1)[0])
+	}
+	page.Text(izquierda, y+altoGafete-rellenoGafete-4, pdf.HelveticaBold, 13, pdf.AlignLeft, recortar(pdf.HelveticaBold, 13, anchoTexto, g.etiqueta, 1)[0])
+
+	page.Grid(x+anchoGafete-rellenoGafete-ladoQR, y+altoGafete-rellenoGafete-ladoQR, ladoQR, code.Size(), code.Dark)
+	return nil
+}
+
+// recortar wraps s to width and keeps at most max lines, ending the last
+// one in "..." when text was left out or a single word did not fit. It
+// always returns at least one line.
+func recortar(font pdf.Font, size, width float64, s string, max int) []string {
+	lineas := pdf.Wrap(font, size, width, s)
+	if len(lineas) <= max && pdf.TextWidth(font, size, lineas[len(lineas)-1]) <= width {
+		return lineas
+	}
+	lineas = lineas[:min(len(lineas), max)]
+	ultima := lineas[len(lineas)-1]
+	for ultima != "" && pdf.TextWidth(font, size, ultima+"...") > width {
+		runas := []rune(ultima)
+		ultima = string(runas[:len(runas)-1])
+	}
+	lineas[len(lineas)-1] = ultima + "..."
+	return lineas
+}
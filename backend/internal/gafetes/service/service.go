@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"project/backend/internal/asistencia/ticket"
+	"project/backend/internal/events/domain"
+	"project/backend/internal/gafetes/dto"
+	"project/backend/internal/gafetes/repo"
+	inscripciones "project/backend/internal/inscripciones/validation"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/storage"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrEventoNotFound = errors.New("evento no encontrado")
+	ErrLoteNotFound   = errors.New("lote de gafetes no encontrado")
+	ErrSinAsistentes  = errors.New("ninguna inscripción coincide con los filtros")
+	ErrSinClave       = errors.New("las entradas no están configuradas")
+	ErrAlmacenamiento = errors.New("no se pudo guardar el archivo")
+	ErrSinArchivo     = errors.New("el lote de gafetes no tiene archivo")
+	ErrDB             = errors.New("db error")
+)
+
+// MaxGafetesInmediatos is the largest batch generated during the request.
+// Larger ones are left to the scheduler so the request does not time out.
+const MaxGafetesInmediatos = 100
+
+// Labels printed on a badge besides the ticket type.
+const (
+	EtiquetaPonente   = "Ponente"
+	EtiquetaStaff     = "Staff"
+	EtiquetaAsistente = "Asistente"
+)
+
+const formatoFecha = "02/01/2006"
+
+// DescargaURL is where organizers download the PDF of a batch. The file
+// carries the signed ticket of every attendee, so it is never public.
+func DescargaURL(loteID int) string {
+	return fmt.Sprintf("/api/gafetes?id_lote=%d&descargar=true", loteID)
+}
+
+type Service struct {
+	repo        *repo.Repository
+	archivos    storage.Private
+	roleService roles.UserRoleService
+}
+
+func New(r *repo.Repository, archivos storage.Private, roleService roles.UserRoleService) *Service {
+	return &Service{repo: r, archivos: archivos, roleService: roleService}
+}
+
+// Solicitar creates a badge batch for the inscriptions of an event that
+// match req. Batches of up to MaxGafetesInmediatos badges are generated
+// before returning; larger ones come back pending and the scheduler
+// generates them. The request must have been validated.
+func (s *Service) Solicitar(ctx context.Context, req dto.GafetesRequest, usuarioID int) (dto.LoteResponse, error) {
+	evento, err := s.evento(ctx, req.IDEvento)
+	if err != nil {
+		return dto.LoteResponse{}, err
+	}
+	filtros := buildFiltros(req, time.Now().Location())
+	asistentes, err := s.repo.ListAsistentes(ctx, filtros)
+	if err != nil {
+		return dto.LoteResponse{}, ErrDB
+	}
+	if len(asistentes) == 0 {
+		return dto.LoteResponse{}, ErrSinAsistentes
+	}
+
+	var creadoPor *int
+	if usuarioID > 0 {
+		creadoPor = &usuarioID
+	}
+	inmediato := len(asistentes) <= MaxGafetesInmediatos
+	lote, err := s.repo.Crear(ctx, filtros, creadoPor, inmediato)
+	if err != nil {
+		return dto.LoteResponse{}, ErrDB
+	}
+	if !inmediato {
+		return loteResponse(lote, evento.ZonaHoraria), nil
+	}
+
+	if err := s.generar(ctx, lote, evento, asistentes); errors.Is(err, ErrDB) {
+		return dto.LoteResponse{}, err
+	}
+	lote, err = s.repo.FindLote(ctx, lote.IDLote)
+	if err != nil {
+		return dto.LoteResponse{}, ErrDB
+	}
+	return loteResponse(lote, evento.ZonaHoraria), nil
+}
+
+// GetLote returns a batch, with the URL of its PDF once it is ready.
+func (s *Service) GetLote(ctx context.Context, id int) (dto.LoteResponse, error) {
+	lote, err := s.repo.FindLote(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.LoteResponse{}, ErrLoteNotFound
+		}
+		return dto.LoteResponse{}, ErrDB
+	}
+	evento, err := s.evento(ctx, lote.IDEvento)
+	if err != nil {
+		return dto.LoteResponse{}, err
+	}
+	return loteResponse(lote, evento.ZonaHoraria), nil
+}
+
+// Archivo opens the PDF of a ready batch; the caller closes it.
+func (s *Service) Archivo(ctx context.Context, id int) (io.ReadCloser, error) {
+	lote, err := s.repo.FindLote(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrLoteNotFound
+		}
+		return nil, ErrDB
+	}
+	if lote.ArchivoClave == nil {
+		return nil, ErrSinArchivo
+	}
+	archivo, err := s.archivos.Open(ctx, *lote.ArchivoClave)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrSinArchivo
+		}
+		return nil, ErrAlmacenamiento
+	}
+	return archivo, nil
+}
+
+// ListLotes returns the badge batches of an event, newest first.
+func (s *Service) ListLotes(ctx context.Context, eventoID int) ([]dto.LoteResponse, error) {
+	evento, err := s.evento(ctx, eventoID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListLotes(ctx, eventoID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make([]dto.LoteResponse, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, loteResponse(row, evento.ZonaHoraria))
+	}
+	return res, nil
+}
+
+// ProcesarPendientes generates the pending batches one at a time and
+// returns how many it finished. A batch that fails is marked as such with
+// the reason and does not stop the others.
+func (s *Service) ProcesarPendientes(ctx context.Context) (int, error) {
+	procesados := 0
+	for {
+		lote, err := s.repo.Reclamar(ctx)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return procesados, nil
+			}
+			return procesados, ErrDB
+		}
+
+		err = s.procesar(ctx, lote)
+		if errors.Is(err, ErrDB) {
+			return procesados, err
+		}
+		if err != nil {
+			fmt.Println("[Gafetes] Error generando el lote", lote.IDLote, ":", err)
+		}
+		procesados++
+	}
+}
+
+func (s *Service) procesar(ctx context.Context, lote repo.LoteRow) error {
+	evento, err := s.repo.FindEvento(ctx, lote.IDEvento)
+	if err != nil {
+		// The event cascades its batches, so it can only be missing if it
+		// was deleted after the batch was claimed.
+		return s.fallar(ctx, lote.IDLote, ErrEventoNotFound)
+	}
+	asistentes, err := s.repo.ListAsistentes(ctx, lote.Filtros())
+	if err != nil {
+		return ErrDB
+	}
+	return s.generar(ctx, lote, evento, asistentes)
+}
+
+// generar renders the badges of a claimed batch, stores the PDF and marks
+// the batch ready, or marks it failed with the reason.
+func (s *Service) generar(ctx context.Context, lote repo.LoteRow, evento repo.EventoRow, asistentes []repo.AsistenteRow) error {
+	if len(asistentes) == 0 {
+		return s.fallar(ctx, lote.IDLote, ErrSinAsistentes)
+	}
+
+	staff := map[string]bool{}
+	gafetes := make([]gafete, 0, len(asistentes))
+	for _, a := range asistentes {
+		payload, err := ticket.Firmar(a.IDInscripcion, evento.IDEvento)
+		if err != nil {
+			return s.fallar(ctx, lote.IDLote, ErrSinClave)
+		}
+		esStaff, err := s.esStaff(ctx, a.Roles, staff)
+		if err != nil {
+			return ErrDB
+		}
+		gafetes = append(gafetes, gafete{
+			nombre:     a.Nombre,
+			afiliacion: a.Afiliacion,
+			etiqueta:   etiqueta(a, esStaff),
+			entrada:    payload,
+		})
+	}
+
+	contenido, err := renderGafetes(evento.Nombre, gafetes)
+	if err != nil {
+		return s.fallar(ctx, lote.IDLote, err)
+	}
+
+	sufijo := make([]byte, 6)
+	if _, err := rand.Read(sufijo); err != nil {
+		return s.fallar(ctx, lote.IDLote, ErrAlmacenamiento)
+	}
+	clave := fmt.Sprintf("gafetes/%d/lote-%d-%s.pdf", evento.IDEvento, lote.IDLote, hex.EncodeToString(sufijo))
+	if err := s.archivos.Put(ctx, clave, bytes.NewReader(contenido)); err != nil {
+		return s.fallar(ctx, lote.IDLote, ErrAlmacenamiento)
+	}
+	if err := s.repo.Terminar(ctx, lote.IDLote, len(gafetes), clave, DescargaURL(lote.IDLote)); err != nil {
+		_ = s.archivos.Delete(ctx, clave)
+		return ErrDB
+	}
+	return nil
+}
+
+func (s *Service) fallar(ctx context.Context, loteID int, motivo error) error {
+	if err := s.repo.Fallar(ctx, loteID, motivo.Error()); err != nil {
+		return ErrDB
+	}
+	return motivo
+}
+
+// esStaff reports whether any of the roles may manage events, remembering
+// the answer per role in cache: a batch has few distinct roles.
+func (s *Service) esStaff(ctx context.Context, nombres []string, cache map[string]bool) (bool, error) {
+	for _, nombre := range nombres {
+		permitido, ok := cache[nombre]
+		if !ok {
+			var err error
+			permitido, err = roles.AuthorizeRoleNames(ctx, s.roleService, []string{nombre}, "events.management")
+			if err != nil {
+				return false, err
+			}
+			cache[nombre] = permitido
+		}
+		if permitido {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) evento(ctx context.Context, id int) (repo.EventoRow, error) {
+	evento, err := s.repo.FindEvento(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.EventoRow{}, ErrEventoNotFound
+		}
+		return repo.EventoRow{}, ErrDB
+	}
+	return evento, nil
+}
+
+// etiqueta is the role printed on a badge: speakers first, then staff,
+// then the ticket type the attendee bought.
+func etiqueta(a repo.AsistenteRow, staff bool) string {
+	switch {
+	case a.Ponente:
+		return EtiquetaPonente
+	case staff:
+		return EtiquetaStaff
+	case strings.TrimSpace(a.TipoEntrada) != "":
+		return strings.TrimSpace(a.TipoEntrada)
+	default:
+		return EtiquetaAsistente
+	}
+}
+
+// buildFiltros turns a validated request into the repository's filters.
+// Estado defaults to Aprobado: only approved tickets let attendees in.
+func buildFiltros(req dto.GafetesRequest, loc *time.Location) repo.Filtros {
+	f := repo.Filtros{IDEvento: req.IDEvento}
+	estado := inscripciones.StatusAprobado
+	if valor := strings.TrimSpace(req.Estado); valor != "" {
+		estado = inscripciones.NormalizeStatus(valor)
+	}
+	f.Estado = &estado
+	if q := strings.TrimSpace(req.Q); q != "" {
+		f.Q = &q
+	}
+	if desde, err := inscripciones.ParseDate(req.Desde, loc); err == nil {
+		f.Desde = &desde
+	}
+	if hasta, err := inscripciones.ParseDate(req.Hasta, loc); err == nil {
+		f.Hasta = &hasta
+	}
+	return f
+}
+
+func loteResponse(l repo.LoteRow, zona string) dto.LoteResponse {
+	res := dto.LoteResponse{
+		IDLote:   l.IDLote,
+		IDEvento: l.IDEvento,
+		Estado:   l.Estado,
+		Total:    l.Total,
+		CreadoEn: domain.FormatoLocal(l.CreatedAt, zona),
+	}
+	if l.EstadoInscripcion != nil {
+		res.Filtros.Estado = *l.EstadoInscripcion
+	}
+	if l.Q != nil {
+		res.Filtros.Q = *l.Q
+	}
+	if l.Desde != nil {
+		res.Filtros.Desde = l.Desde.In(time.Now().Location()).Format(formatoFecha)
+	}
+	if l.Hasta != nil {
+		res.Filtros.Hasta = l.Hasta.In(time.Now().Location()).Format(formatoFecha)
+	}
+	if l.ArchivoClave != nil {
+		res.URL = DescargaURL(l.IDLote)
+	}
+	if l.Error != nil {
+		res.Error = *l.Error
+	}
+	if l.TerminadoEn != nil {
+		res.TerminadoEn = domain.FormatoLocal(*l.TerminadoEn, zona)
+	}
+	return res
+}
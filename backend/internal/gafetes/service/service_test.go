@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"project/backend/internal/gafetes/dto"
+	"project/backend/internal/gafetes/repo"
+	"project/backend/internal/shared/pdf"
+)
+
+func TestEtiqueta(t *testing.T) {
+	cases := []struct {
+		name  string
+		row   repo.AsistenteRow
+		staff bool
+		want  string
+	}{
+		{"ponente y staff", repo.AsistenteRow{Ponente: true, TipoEntrada: "VIP"}, true, EtiquetaPonente},
+		{"staff", repo.AsistenteRow{TipoEntrada: "General"}, true, EtiquetaStaff},
+		{"tipo de entrada", repo.AsistenteRow{TipoEntrada: " Estudiante "}, false, "Estudiante"},
+		{"sin tipo", repo.AsistenteRow{}, false, EtiquetaAsistente},
+	}
+	for _, c := range cases {
+		if got := etiqueta(c.row, c.staff); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestBuildFiltros(t *testing.T) {
+	f := buildFiltros(dto.GafetesRequest{IDEvento: 4}, time.UTC)
+	if f.IDEvento != 4 || f.Estado == nil || *f.Estado != "Aprobado" || f.Q != nil || f.Desde != nil || f.Hasta != nil {
+		t.Fatalf("defaults: %+v", f)
+	}
+
+	f = buildFiltros(dto.GafetesRequest{IDEvento: 4, Estado: "en espera", Q: " ana ", Desde: "01/05/2026"}, time.UTC)
+	if *f.Estado != "En espera" || *f.Q != "ana" || !f.Desde.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) || f.Hasta != nil {
+		t.Fatalf("filters: %+v", f)
+	}
+}
+
+func TestPosicionGafete(t *testing.T) {
+	x0, y0 := posicionGafete(0)
+	x1, y1 := posicionGafete(1)
+	x2, y2 := posicionGafete(2)
+	if x1-x0 != anchoGafete || y1 != y0 || x2 != x0 || y2-y0 != altoGafete {
+		t.Fatalf("unexpected grid: (%v,%v) (%v,%v) (%v,%v)", x0, y0, x1, y1, x2, y2)
+	}
+	if xs, ys := posicionGafete(porPagina); xs != x0 || ys != y0 {
+		t.Errorf("the first badge of a page should start at the top-left")
+	}
+	xl, yl := posicionGafete(porPagina - 1)
+	if xl+anchoGafete > pdf.A4.Width-x0+0.01 || yl+altoGafete > pdf.A4.Height-y0+0.01 || x0 < 0 || y0 < 0 {
+		t.Errorf("the grid should be centered on the page")
+	}
+}
+
+func TestRecortar(t *testing.T) {
+	if got := recortar(pdf.Helvetica, 10, 200, "Ana Pérez", 2); len(got) != 1 || got[0] != "Ana Pérez" {
+		t.Fatalf("short text: %q", got)
+	}
+	largo := strings.Repeat("Palabra ", 40)
+	got := recortar(pdf.Helvetica, 10, 100, largo, 2)
+	if len(got) != 2 || !strings.HasSuffix(got[1], "...") {
+		t.Fatalf("long text: %q", got)
+	}
+	palabra := recortar(pdf.Helvetica, 10, 50, strings.Repeat("x", 60), 1)
+	if len(palabra) != 1 || pdf.TextWidth(pdf.Helvetica, 10, palabra[0]) > 50 {
+		t.Fatalf("long word: %q", palabra)
+	}
+}
+
+func TestRenderGafetes(t *testing.T) {
+	gafetes := make([]gafete, 0, porPagina+1)
+	for i := 0; i < porPagina+1; i++ {
+		gafetes = append(gafetes, gafete{
+			nombre:   fmt.Sprintf("Asistente %d", i),
+			etiqueta: EtiquetaAsistente,
+			entrada:  fmt.Sprintf("INS1.%d.7.AAAAAAAAAAAAAAAAAAAAAA", i+1),
+		})
+	}
+	gafetes[0].nombre = "María Núñez"
+	gafetes[0].afiliacion = "Universidad Central"
+	gafetes[0].etiqueta = EtiquetaPonente
+
+	out, err := renderGafetes("Congreso (edición 2026)", gafetes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"/Count 2", "/MediaBox [0 0 595.28 841.89]", "Mar\xeda N\xfa\xf1ez", "(Universidad Central)", "(Ponente)", "\\(edici", "(Asistente 8)", " re f"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+}
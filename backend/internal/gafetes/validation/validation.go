@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"project/backend/internal/gafetes/dto"
+	inscripciones "project/backend/internal/inscripciones/validation"
+)
+
+func ValidateGafetes(req dto.GafetesRequest, loc *time.Location) error {
+	if req.IDEvento <= 0 {
+		return errors.New("id_evento inválido")
+	}
+	if estado := strings.TrimSpace(req.Estado); estado != "" && !IsFilterStatus(estado) {
+		return errors.New("estado inválido")
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(req.Q)) > 100 {
+		return errors.New("La búsqueda no puede superar 100 caracteres.")
+	}
+
+	var desde, hasta time.Time
+	if valor := strings.TrimSpace(req.Desde); valor != "" {
+		parsed, err := inscripciones.ParseDate(valor, loc)
+		if err != nil {
+			return errors.New("fecha desde inválida")
+		}
+		desde = parsed
+	}
+	if valor := strings.TrimSpace(req.Hasta); valor != "" {
+		parsed, err := inscripciones.ParseDate(valor, loc)
+		if err != nil {
+			return errors.New("fecha hasta inválida")
+		}
+		hasta = parsed
+	}
+	if !desde.IsZero() && !hasta.IsZero() && hasta.Before(desde) {
+		return errors.New("La fecha hasta no puede ser anterior a la fecha desde.")
+	}
+	return nil
+}
+
+// IsFilterStatus reports whether inscriptions can be selected by value.
+// Unlike a manual status change, the waitlist and expired statuses count.
+func IsFilterStatus(value string) bool {
+	switch inscripciones.NormalizeStatus(value) {
+	case inscripciones.StatusEnEspera, inscripciones.StatusExpirado:
+		return true
+	default:
+		return inscripciones.IsAllowedStatus(value)
+	}
+}
@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"project/backend/internal/gafetes/dto"
+)
+
+func TestValidateGafetes(t *testing.T) {
+	cases := []struct {
+		name    string
+		req     dto.GafetesRequest
+		wantErr bool
+	}{
+		{"solo evento", dto.GafetesRequest{IDEvento: 3}, false},
+		{"con filtros", dto.GafetesRequest{IDEvento: 3, Estado: "pagado", Q: "ana", Desde: "01/05/2026", Hasta: "31/05/2026"}, false},
+		{"en espera", dto.GafetesRequest{IDEvento: 3, Estado: "en espera"}, false},
+		{"sin evento", dto.GafetesRequest{}, true},
+		{"estado desconocido", dto.GafetesRequest{IDEvento: 3, Estado: "Invitado"}, true},
+		{"fecha mal escrita", dto.GafetesRequest{IDEvento: 3, Desde: "2026-05-01"}, true},
+		{"rango invertido", dto.GafetesRequest{IDEvento: 3, Desde: "31/05/2026", Hasta: "01/05/2026"}, true},
+	}
+	for _, c := range cases {
+		err := ValidateGafetes(c.req, time.UTC)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: got %v, wantErr %v", c.name, err, c.wantErr)
+		}
+	}
+}
@@ -7,6 +7,8 @@ type CreateInscripcionRequest struct {
 	Email             string `json:"email"`
 	Afiliacion        string `json:"afiliacion"`
 	ComprobantePago   string `json:"comprobante_pago"`
+	IDTipoEntrada     *int   `json:"id_tipo_entrada"`
+	Codigo            string `json:"codigo"`
 }
 
 type UpdateEstadoRequest struct {
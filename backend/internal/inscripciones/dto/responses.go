@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/shopspring/decimal"
+
 type InscripcionResponse struct {
 	IDInscripcion      int     `json:"id_inscripcion"`
 	IDEvento           int     `json:"id_evento"`
@@ -12,6 +14,13 @@ type InscripcionResponse struct {
 	FechaInscripcion   string  `json:"fecha_inscripcion"`
 	FechaLimitePago    string  `json:"fecha_limite_pago"`
 	Estado             string  `json:"estado"`
+	PosicionEspera     *int    `json:"posicion_espera,omitempty"`
+	EstadoPago         bool    `json:"estado_pago"`
+	TipoEntrada        *string `json:"tipo_entrada"`
+	Monto              *string `json:"monto"`
+	Moneda             *string `json:"moneda"`
+	Codigo             *string `json:"codigo"`
+	Descuento          *string `json:"descuento"`
 }
 
 type HistorialResponse struct {
@@ -34,9 +43,21 @@ type PreferenciasResponse struct {
 type ReporteResponse struct {
 	Total      int                      `json:"total"`
 	PorEstado  map[string]int           `json:"por_estado"`
+	Montos     []MontoReporteResponse   `json:"montos"`
 	Registros  []InscripcionResponse    `json:"registros"`
 }
 
+// MontoReporteResponse sums what the inscriptions of a ticket type owe in
+// one currency, and how much of it is paid.
+type MontoReporteResponse struct {
+	Moneda        string          `json:"moneda"`
+	TipoEntrada   string          `json:"tipo_entrada"`
+	Inscripciones int             `json:"inscripciones"`
+	Total         decimal.Decimal `json:"total"`
+	Pagado        decimal.Decimal `json:"pagado"`
+	Pendiente     decimal.Decimal `json:"pendiente"`
+}
+
 type ReporteProgramadoResponse struct {
 	IDReporte  int     `json:"id_reporte"`
 	IDEvento   *int    `json:"id_evento"`
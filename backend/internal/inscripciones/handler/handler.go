@@ -10,23 +10,35 @@ import (
 	"strings"
 	"time"
 
+	"project/backend/internal/asistencia/ticket"
+	entradasrepo "project/backend/internal/entradas/repo"
+	entradassrv "project/backend/internal/entradas/service"
 	"project/backend/internal/inscripciones/dto"
 	"project/backend/internal/inscripciones/repo"
 	"project/backend/internal/inscripciones/service"
 	"project/backend/internal/inscripciones/validation"
+	"project/backend/internal/policy"
+	roles "project/backend/internal/roles/service"
 	"project/backend/internal/shared/httperror"
+	"project/backend/internal/shared/pdf"
+	"project/backend/internal/shared/qr"
+	waitlistsrv "project/backend/internal/waitlist/service"
 	"project/backend/prisma/db"
-
-	"golang.org/x/text/encoding/charmap"
 )
 
 type Handler struct {
-	svc *service.Service
+	svc         *service.Service
+	roleService roles.UserRoleService
+	policy      *policy.Engine
 }
 
 func New(client *db.PrismaClient) *Handler {
 	repository := repo.New(client)
-	return &Handler{svc: service.New(repository)}
+	return &Handler{
+		svc:         service.New(repository, waitlistsrv.New(client), entradassrv.New(entradasrepo.New(client))),
+		roleService: roles.NewUserRoleService(client),
+		policy:      policy.Default(),
+	}
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -65,6 +77,9 @@ func (h *Handler) UpdateEstadoHandler(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, service.ErrEstadoInvalido):
 			httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
 			return
+		case errors.Is(err, service.ErrEnListaEspera), errors.Is(err, service.ErrInscripcionLiberada), errors.Is(err, service.ErrEstadoCambiado):
+			httperror.WriteJSON(w, http.StatusConflict, err.Error())
+			return
 		case errors.Is(err, service.ErrInscripcionNotFound):
 			httperror.WriteJSON(w, http.StatusNotFound, err.Error())
 			return
@@ -275,7 +290,21 @@ func (h *Handler) ComprobanteHandler(w http.ResponseWriter, r *http.Request) {
 		"Comprobante de pago: " + comprobante,
 	}
 
-	pdf := buildSimplePDF(lines)
+	// Approved inscriptions carry their ticket, the QR code scanned at the
+	// door.
+	doc, page, y := layoutLines(lines)
+	if row.Estado == validation.StatusAprobado {
+		payload, err := ticket.Firmar(row.IDInscripcion, row.IDEvento)
+		if err != nil {
+			httperror.WriteJSON(w, http.StatusInternalServerError, "no se pudo generar la entrada")
+			return
+		}
+		if err := drawTicket(doc, page, y, payload); err != nil {
+			httperror.WriteJSON(w, http.StatusInternalServerError, "no se pudo generar la entrada")
+			return
+		}
+	}
+	pdf := doc.Bytes()
 
 	w.Header().Set("Content-Type", "application/pdf")
 	w.Header().Set("Content-Disposition", "attachment; filename=comprobante_inscripcion.pdf")
@@ -304,6 +333,12 @@ func (h *Handler) ReportesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	montos, err := h.svc.ReporteMontos(ctx, filters)
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	rows, err := h.svc.ListInscripciones(ctx, filters)
 	if err != nil {
 		httperror.WriteJSON(w, http.StatusInternalServerError, err.Error())
@@ -324,16 +359,23 @@ func (h *Handler) ReportesHandler(w http.ResponseWriter, r *http.Request) {
 			FechaInscripcion:   row.FechaInscripcion,
 			FechaLimitePago:    row.FechaLimitePago,
 			Estado:             row.Estado,
+			PosicionEspera:     row.PosicionEspera,
+			EstadoPago:         row.EstadoPago,
+			TipoEntrada:        row.TipoEntrada,
+			Monto:              row.Monto,
+			Moneda:             row.Moneda,
+			Codigo:             row.Codigo,
+			Descuento:          row.Descuento,
 		})
 	}
 
 	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
 	if format == "csv" {
-		writeCSVReport(w, porEstado, total)
+		writeCSVReport(w, porEstado, total, montos)
 		return
 	}
 	if format == "pdf" {
-		writePDFReport(w, porEstado, total)
+		writePDFReport(w, porEstado, total, montos)
 		return
 	}
 
@@ -341,6 +383,7 @@ func (h *Handler) ReportesHandler(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(dto.ReporteResponse{
 		Total:     total,
 		PorEstado: porEstado,
+		Montos:    montos,
 		Registros: resRows,
 	})
 }
@@ -461,6 +504,16 @@ func (h *Handler) createInscripcion(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, service.ErrInscripcionExists):
 			httperror.WriteJSON(w, http.StatusConflict, err.Error())
 			return
+		case errors.Is(err, entradassrv.ErrNotFound), errors.Is(err, entradassrv.ErrCodigoNotFound):
+			httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+			return
+		case errors.Is(err, entradassrv.ErrRequerida), errors.Is(err, entradassrv.ErrNoDisponible), errors.Is(err, entradassrv.ErrSoloPonentes),
+			errors.Is(err, entradassrv.ErrCodigoVencido), errors.Is(err, entradassrv.ErrCodigoNoAplica):
+			httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, entradassrv.ErrAgotada), errors.Is(err, entradassrv.ErrCodigoAgotado):
+			httperror.WriteJSON(w, http.StatusConflict, err.Error())
+			return
 		default:
 			httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
 			return
@@ -497,6 +550,11 @@ func (h *Handler) listInscripciones(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
+	if status, msg := h.authorizeList(ctx, r, filters); status != http.StatusOK {
+		httperror.WriteJSON(w, status, msg)
+		return
+	}
+
 	rows, err := h.svc.ListInscripciones(ctx, filters)
 	if err != nil {
 		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
@@ -517,6 +575,13 @@ func (h *Handler) listInscripciones(w http.ResponseWriter, r *http.Request) {
 			FechaInscripcion:   row.FechaInscripcion,
 			FechaLimitePago:    row.FechaLimitePago,
 			Estado:             row.Estado,
+			PosicionEspera:     row.PosicionEspera,
+			EstadoPago:         row.EstadoPago,
+			TipoEntrada:        row.TipoEntrada,
+			Monto:              row.Monto,
+			Moneda:             row.Moneda,
+			Codigo:             row.Codigo,
+			Descuento:          row.Descuento,
 		})
 	}
 
@@ -524,6 +589,46 @@ func (h *Handler) listInscripciones(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(res)
 }
 
+// authorizeList lets inscriptions.management list everything. Anyone else is
+// restricted to their own inscriptions: the user_id filter defaults to the
+// caller and must match the identity in the bearer token.
+func (h *Handler) authorizeList(ctx context.Context, r *http.Request, filters map[string]interface{}) (int, string) {
+	subject := policy.SubjectFromRequest(r)
+	if !subject.Identified() {
+		return http.StatusUnauthorized, "autenticación requerida"
+	}
+	subject, err := roles.ActiveSubject(ctx, h.roleService, subject)
+	if err != nil {
+		return http.StatusInternalServerError, "db error"
+	}
+	allowed, err := roles.AuthorizeRoleNames(ctx, h.roleService, subject.Roles, "inscriptions.management")
+	if err != nil {
+		return http.StatusInternalServerError, "db error"
+	}
+	if allowed {
+		return http.StatusOK, ""
+	}
+
+	ownerID, _ := filters["id_usuario"].(int)
+	if ownerID == 0 && subject.Authenticated() {
+		ownerID = subject.UserID
+		filters["id_usuario"] = ownerID
+	}
+
+	decision := h.policy.Evaluate(policy.Request{
+		Subject:  subject,
+		Action:   policy.ActionRead,
+		Resource: policy.Resource{Type: policy.ResourceInscripcion, OwnerID: ownerID},
+	})
+	if decision.Allowed {
+		return http.StatusOK, ""
+	}
+	if !subject.Authenticated() {
+		return http.StatusUnauthorized, "autenticación requerida"
+	}
+	return http.StatusForbidden, "no autorizado"
+}
+
 func parseListFilters(r *http.Request) (map[string]interface{}, error) {
 	filters := map[string]interface{}{}
 
@@ -575,13 +680,20 @@ func parseReportFilters(r *http.Request) (map[string]interface{}, error) {
 	return parseListFilters(r)
 }
 
-func writeCSVReport(w http.ResponseWriter, porEstado map[string]int, total int) {
+func writeCSVReport(w http.ResponseWriter, porEstado map[string]int, total int, montos []dto.MontoReporteResponse) {
 	var builder strings.Builder
 	builder.WriteString("estado,total\n")
 	for estado, count := range porEstado {
 		builder.WriteString(fmt.Sprintf("%s,%d\n", estado, count))
 	}
 	builder.WriteString(fmt.Sprintf("TOTAL,%d\n", total))
+	if len(montos) > 0 {
+		builder.WriteString("\nmoneda,tipo_entrada,inscripciones,total,pagado,pendiente\n")
+		for _, m := range montos {
+			builder.WriteString(fmt.Sprintf("%s,%s,%d,%s,%s,%s\n", m.Moneda, csvField(m.TipoEntrada), m.Inscripciones,
+				m.Total.StringFixed(2), m.Pagado.StringFixed(2), m.Pendiente.StringFixed(2)))
+		}
+	}
 
 	w.Header().Set("Content-Type", "text/csv")
 	w.Header().Set("Content-Disposition", "attachment; filename=reportes_inscripciones.csv")
@@ -589,12 +701,28 @@ func writeCSVReport(w http.ResponseWriter, porEstado map[string]int, total int)
 	_, _ = w.Write([]byte(builder.String()))
 }
 
-func writePDFReport(w http.ResponseWriter, porEstado map[string]int, total int) {
+// csvField quotes a free-text value, such as a ticket type name, when it
+// holds a separator or a quote.
+func csvField(value string) string {
+	if !strings.ContainsAny(value, ",\"\n") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+func writePDFReport(w http.ResponseWriter, porEstado map[string]int, total int, montos []dto.MontoReporteResponse) {
 	lines := []string{"Reporte de inscripciones", ""}
 	for estado, count := range porEstado {
 		lines = append(lines, fmt.Sprintf("%s: %d", estado, count))
 	}
 	lines = append(lines, fmt.Sprintf("Total: %d", total))
+	if len(montos) > 0 {
+		lines = append(lines, "", "Montos por tipo de entrada")
+		for _, m := range montos {
+			lines = append(lines, fmt.Sprintf("%s (%d): %s %s, pagado %s, pendiente %s", m.TipoEntrada, m.Inscripciones,
+				m.Total.StringFixed(2), m.Moneda, m.Pagado.StringFixed(2), m.Pendiente.StringFixed(2)))
+		}
+	}
 
 	pdf := buildSimplePDF(lines)
 	w.Header().Set("Content-Type", "application/pdf")
@@ -603,49 +731,57 @@ func writePDFReport(w http.ResponseWriter, porEstado map[string]int, total int)
 	_, _ = w.Write(pdf)
 }
 
+// buildSimplePDF sets lines one below the other on letter pages, wrapping
+// the long ones and starting a new page when one fills up.
 func buildSimplePDF(lines []string) []byte {
-	content := "BT /F1 12 Tf 72 720 Td 16 TL"
-	for _, line := range lines {
-		safe := encodePDFText(line)
-		content += fmt.Sprintf(" (%s) Tj T*", safe)
-	}
-	content += " ET"
-
-	objects := []string{
-		"1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj",
-		"2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj",
-		"3 0 obj << /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >> endobj",
-		fmt.Sprintf("4 0 obj << /Length %d >> stream\n%s\nendstream endobj", len(content), content),
-		"5 0 obj << /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >> endobj",
-	}
-
-	var xref strings.Builder
-	xref.WriteString("xref\n0 6\n0000000000 65535 f \n")
+	doc, _, _ := layoutLines(lines)
+	return doc.Bytes()
+}
 
-	var body strings.Builder
-	body.WriteString("%PDF-1.4\n")
-	offsets := []int{0}
-	for _, obj := range objects {
-		offsets = append(offsets, body.Len())
-		body.WriteString(obj + "\n")
-	}
+const (
+	pdfMargin  = 72.0
+	pdfLeading = 16.0
+)
 
-	for i := 1; i < len(offsets); i++ {
-		xref.WriteString(fmt.Sprintf("%010d 00000 n \n", offsets[i]))
+// layoutLines does the work of buildSimplePDF and returns the last page with
+// the baseline the next line would take, so more can be drawn below.
+func layoutLines(lines []string) (*pdf.Document, *pdf.Page, float64) {
+	doc := pdf.New(pdf.Letter)
+	page := doc.AddPage()
+	width := pdf.Letter.Width - 2*pdfMargin
+	y := pdfMargin
+	for _, line := range lines {
+		for _, wrapped := range pdf.Wrap(pdf.Helvetica, 12, width, line) {
+			if y > pdf.Letter.Height-pdfMargin {
+				page = doc.AddPage()
+				y = pdfMargin
+			}
+			page.Text(pdfMargin, y, pdf.Helvetica, 12, pdf.AlignLeft, wrapped)
+			y += pdfLeading
+		}
 	}
-
-	startXref := body.Len()
-	trailer := fmt.Sprintf("trailer << /Size 6 /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", startXref)
-
-	final := body.String() + xref.String() + trailer
-	return []byte(final)
+	return doc, page, y
 }
 
-func encodePDFText(value string) string {
-	encoded, err := charmap.Windows1252.NewEncoder().String(value)
+// drawTicket draws the QR code of a ticket below y, or on a new page when
+// it does not fit, with the payload under it in case it cannot be scanned.
+// The text stays two lines away so scanners find the quiet zone around the
+// code.
+func drawTicket(doc *pdf.Document, page *pdf.Page, y float64, payload string) error {
+	const side = 160.0
+	code, err := qr.Encode([]byte(payload))
 	if err != nil {
-		encoded = value
+		return err
 	}
-	replacer := strings.NewReplacer("\\", "\\\\", "(", "\\(", ")", "\\)")
-	return replacer.Replace(encoded)
+	if y+3*pdfLeading+side+2*pdfLeading > pdf.Letter.Height-pdfMargin {
+		page = doc.AddPage()
+		y = pdfMargin
+	}
+	y += pdfLeading
+	page.Text(pdfMargin, y, pdf.HelveticaBold, 12, pdf.AlignLeft, "Entrada: presenta este código en el acceso")
+	y += 2 * pdfLeading
+
+	page.Grid(pdfMargin, y, side, code.Size(), code.Dark)
+	page.Text(pdfMargin, y+side+2*pdfLeading, pdf.Helvetica, 8, pdf.AlignLeft, payload)
+	return nil
 }
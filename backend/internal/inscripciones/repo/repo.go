@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"project/backend/internal/inscripciones/validation"
 	"project/backend/prisma/db"
 )
 
@@ -29,6 +30,23 @@ type InscripcionRow struct {
 	FechaInscripcion string  `json:"fecha_inscripcion"`
 	FechaLimitePago  string  `json:"fecha_limite_pago"`
 	Estado           string  `json:"estado"`
+	PosicionEspera   *int    `json:"posicion_espera"`
+	EstadoPago       bool    `json:"estado_pago"`
+	TipoEntrada      *string `json:"tipo_entrada"`
+	Monto            *string `json:"monto"`
+	Moneda           *string `json:"moneda"`
+	Codigo           *string `json:"codigo"`
+	Descuento        *string `json:"descuento"`
+}
+
+// MontoRow sums the amounts due by currency and ticket type. Amounts are
+// read as text to keep their exact decimal value.
+type MontoRow struct {
+	Moneda        string `json:"moneda"`
+	TipoEntrada   string `json:"tipo_entrada"`
+	Inscripciones int    `json:"inscripciones"`
+	Total         string `json:"total"`
+	Pagado        string `json:"pagado"`
 }
 
 type HistorialRow struct {
@@ -73,24 +91,22 @@ func (r *Repository) FindEventoByID(ctx context.Context, id int) (*db.EventoMode
 	return r.client.Evento.FindUnique(db.Evento.IDEvento.Equals(id)).Exec(ctx)
 }
 
-func (r *Repository) FindUsuarioByID(ctx context.Context, id int) (*db.UsuarioModel, error) {
-	return r.client.Usuario.FindUnique(db.Usuario.IDUsuario.Equals(id)).Exec(ctx)
-}
-
-func (r *Repository) CreateInscripcion(ctx context.Context, eventoID, usuarioID int, nombre, email, afiliacion, comprobante, estado string) (int, error) {
-	query := `INSERT INTO "Inscripcion" ("id_evento", "id_usuario", "nombre_participante", "email", "afiliacion", "comprobante_pago", "fecha_inscripcion", "estado", "createdAt", "updatedAt")
-		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NOW(), $7, NOW(), NOW())
-		RETURNING "id_inscripcion"`
+func (r *Repository) FindEstadoEvento(ctx context.Context, id int) (string, error) {
+	query := `SELECT "estado" FROM "Evento" WHERE "id_evento" = $1`
 	var rows []struct {
-		ID int `json:"id_inscripcion"`
+		Estado string `json:"estado"`
 	}
-	if err := r.client.Prisma.Raw.QueryRaw(query, eventoID, usuarioID, strings.TrimSpace(nombre), strings.TrimSpace(email), strings.TrimSpace(afiliacion), strings.TrimSpace(comprobante), estado).Exec(ctx, &rows); err != nil {
-		return 0, err
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return "", err
 	}
 	if len(rows) == 0 {
-		return 0, fmt.Errorf("no rows")
+		return "", db.ErrNotFound
 	}
-	return rows[0].ID, nil
+	return rows[0].Estado, nil
+}
+
+func (r *Repository) FindUsuarioByID(ctx context.Context, id int) (*db.UsuarioModel, error) {
+	return r.client.Usuario.FindUnique(db.Usuario.IDUsuario.Equals(id)).Exec(ctx)
 }
 
 func (r *Repository) FindInscripcionByEventoUsuario(ctx context.Context, eventoID, usuarioID int) (int, error) {
@@ -111,9 +127,12 @@ func (r *Repository) ListInscripciones(ctx context.Context, filters map[string]i
 	query := `SELECT i."id_inscripcion", i."id_evento", e."nombre" AS "evento_nombre", i."id_usuario", i."nombre_participante", i."email", i."afiliacion", i."comprobante_pago",
 		to_char(i."fecha_inscripcion", 'DD/MM/YYYY') AS "fecha_inscripcion",
 		to_char(e."fecha_cierre_inscripcion", 'DD/MM/YYYY') AS "fecha_limite_pago",
-		i."estado"
+		i."estado", i."posicion_espera", i."estado_pago", t."nombre" AS "tipo_entrada", i."monto"::text AS "monto", i."moneda",
+		p."codigo", i."descuento"::text AS "descuento"
 		FROM "Inscripcion" i
 		JOIN "Evento" e ON e."id_evento" = i."id_evento"
+		LEFT JOIN "TipoEntrada" t ON t."id_tipo_entrada" = i."id_tipo_entrada"
+		LEFT JOIN "CodigoPromocional" p ON p."id_codigo" = i."id_codigo"
 		WHERE 1=1`
 
 	params := make([]interface{}, 0)
@@ -156,10 +175,20 @@ func (r *Repository) ListInscripciones(ctx context.Context, filters map[string]i
 	return rows, nil
 }
 
-func (r *Repository) UpdateEstado(ctx context.Context, inscripcionID int, estado string) error {
-	query := `UPDATE "Inscripcion" SET "estado" = $1, "updatedAt" = NOW() WHERE "id_inscripcion" = $2`
-	_, err := r.client.Prisma.Raw.ExecuteRaw(query, estado, inscripcionID).Exec(ctx)
-	return err
+// UpdateEstado moves the inscription to estado only while it is still in
+// anterior, so a change decided on a stale status is not applied. It
+// reports whether the row was updated.
+func (r *Repository) UpdateEstado(ctx context.Context, inscripcionID int, anterior, estado string) (bool, error) {
+	query := `UPDATE "Inscripcion" SET "estado" = $1, "updatedAt" = NOW()
+		WHERE "id_inscripcion" = $2 AND "estado" = $3
+		RETURNING "id_inscripcion"`
+	var rows []struct {
+		IDInscripcion int `json:"id_inscripcion"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, estado, inscripcionID, anterior).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
 }
 
 func (r *Repository) InsertHistorial(ctx context.Context, inscripcionID int, anterior, nuevo, nota, actor string) error {
@@ -229,9 +258,52 @@ func (r *Repository) ListNotificaciones(ctx context.Context, usuarioID int) ([]N
 }
 
 func (r *Repository) ReportePorEstado(ctx context.Context, filters map[string]interface{}) (map[string]int, int, error) {
+	conds, params := reportConditions(filters)
 	query := `SELECT i."estado" AS "estado", COUNT(*)::int AS "total"
 		FROM "Inscripcion" i
-		WHERE 1=1`
+		WHERE 1=1` + conds + ` GROUP BY i."estado"`
+
+	var rows []struct {
+		Estado string `json:"estado"`
+		Total  int    `json:"total"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return nil, 0, err
+	}
+
+	result := map[string]int{}
+	total := 0
+	for _, row := range rows {
+		result[row.Estado] = row.Total
+		total += row.Total
+	}
+	return result, total, nil
+}
+
+// ReporteMontos totals the amounts of the inscriptions with a ticket type,
+// by currency and type. Rejected and cancelled inscriptions owe nothing and
+// are left out.
+func (r *Repository) ReporteMontos(ctx context.Context, filters map[string]interface{}) ([]MontoRow, error) {
+	conds, params := reportConditions(filters)
+	query := `SELECT i."moneda", t."nombre" AS "tipo_entrada", COUNT(*)::int AS "inscripciones",
+		SUM(i."monto")::text AS "total",
+		COALESCE(SUM(i."monto") FILTER (WHERE i."estado_pago"), 0)::text AS "pagado"
+		FROM "Inscripcion" i
+		JOIN "TipoEntrada" t ON t."id_tipo_entrada" = i."id_tipo_entrada"
+		WHERE i."monto" IS NOT NULL AND i."estado" NOT IN ` + validation.EstadosLiberados + conds + `
+		GROUP BY i."moneda", t."nombre"
+		ORDER BY i."moneda", t."nombre"`
+	var rows []MontoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// reportConditions turns the report filters into SQL conditions on the
+// inscription alias i.
+func reportConditions(filters map[string]interface{}) (string, []interface{}) {
+	query := ""
 	params := make([]interface{}, 0)
 	addCond := func(format string, value interface{}) {
 		params = append(params, value)
@@ -249,23 +321,7 @@ func (r *Repository) ReportePorEstado(ctx context.Context, filters map[string]in
 	if value, ok := filters["hasta"].(time.Time); ok && !value.IsZero() {
 		addCond(" AND i.\"fecha_inscripcion\" <= $%d", value)
 	}
-	query += " GROUP BY i.\"estado\""
-
-	var rows []struct {
-		Estado string `json:"estado"`
-		Total  int    `json:"total"`
-	}
-	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
-		return nil, 0, err
-	}
-
-	result := map[string]int{}
-	total := 0
-	for _, row := range rows {
-		result[row.Estado] = row.Total
-		total += row.Total
-	}
-	return result, total, nil
+	return query, params
 }
 
 func (r *Repository) CreateReporteProgramado(ctx context.Context, idEvento *int, estado, frecuencia, formato, creadoPor string) (ReporteProgramadoRow, error) {
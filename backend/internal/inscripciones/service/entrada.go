@@ -0,0 +1,59 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+
+	"project/backend/internal/asistencia/ticket"
+	"project/backend/internal/inscripciones/repo"
+	"project/backend/internal/shared/qr"
+)
+
+// enviarEntrada sends an approved attendee their ticket: the signed payload
+// written out in an in-app notification and, when conEmail is set, in an
+// email with the QR code attached, for staff to type in if the code cannot
+// be scanned. A failure is logged and the approval stands; the ticket is
+// also in the inscription's receipt.
+func (s *Service) enviarEntrada(ctx context.Context, row repo.InscripcionRow, conEmail bool) {
+	payload, err := ticket.Firmar(row.IDInscripcion, row.IDEvento)
+	if err != nil {
+		fmt.Println("[Inscripciones] Error firmando la entrada de la inscripción", row.IDInscripcion, ":", err)
+		return
+	}
+
+	asunto, mensaje := buildTicketEmail(row.Nombre, row.EventoNombre, payload)
+	_ = s.repo.InsertNotificacion(ctx, row.IDUsuario, &row.IDInscripcion, asunto, mensaje)
+	if !conEmail {
+		return
+	}
+	imagen, err := entradaPNG(payload)
+	if err != nil {
+		fmt.Println("[Inscripciones] Error generando el QR de la inscripción", row.IDInscripcion, ":", err)
+		return
+	}
+	if err := sendEmailWithAttachment(row.Email, asunto, mensaje, "entrada.png", "image/png", imagen); err != nil {
+		fmt.Println("[Inscripciones] Error enviando la entrada de la inscripción", row.IDInscripcion, ":", err)
+	}
+}
+
+func buildTicketEmail(nombre, evento, payload string) (string, string) {
+	asunto := "Tu entrada para " + evento
+	msg := "Hola " + nombre + ",\n\nTu inscripción al evento '" + evento + "' fue aprobada. Adjuntamos tu entrada: " +
+		"presenta el código QR en el acceso. También la encontrarás en tu comprobante de inscripción.\n\n" +
+		"Código de la entrada: " + payload + "\n\nGracias."
+	return asunto, msg
+}
+
+func entradaPNG(payload string) ([]byte, error) {
+	code, err := qr.Encode([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code.Image(8)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -2,16 +2,26 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"mime"
 	"net/smtp"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	entradassrv "project/backend/internal/entradas/service"
+	"project/backend/internal/events/domain"
 	"project/backend/internal/inscripciones/dto"
 	"project/backend/internal/inscripciones/repo"
 	"project/backend/internal/inscripciones/validation"
+	waitlistrepo "project/backend/internal/waitlist/repo"
+	waitlistsrv "project/backend/internal/waitlist/service"
 	"project/backend/prisma/db"
+
+	"github.com/shopspring/decimal"
 )
 
 var (
@@ -21,16 +31,21 @@ var (
 	ErrInscripcionExists   = errors.New("ya existe una inscripción para este evento")
 	ErrInscripcionNotFound = errors.New("inscripción no encontrada")
 	ErrEstadoInvalido      = errors.New("estado de inscripción inválido")
+	ErrEnListaEspera       = errors.New("la inscripción está en lista de espera y solo avanza cuando se libera un cupo")
+	ErrInscripcionLiberada = errors.New("la inscripción liberó su cupo; el participante debe inscribirse de nuevo")
+	ErrEstadoCambiado      = errors.New("la inscripción cambió de estado; vuelve a intentarlo")
 	ErrPreferenciasInvalid = errors.New("preferencias inválidas")
 	ErrDB                  = errors.New("db error")
 )
 
 type Service struct {
-	repo *repo.Repository
+	repo     *repo.Repository
+	waitlist *waitlistsrv.Service
+	entradas *entradassrv.Service
 }
 
-func New(repository *repo.Repository) *Service {
-	return &Service{repo: repository}
+func New(repository *repo.Repository, waitlist *waitlistsrv.Service, entradas *entradassrv.Service) *Service {
+	return &Service{repo: repository, waitlist: waitlist, entradas: entradas}
 }
 
 func (s *Service) CreateInscripcion(ctx context.Context, req dto.CreateInscripcionRequest) (int, error) {
@@ -47,6 +62,14 @@ func (s *Service) CreateInscripcion(ctx context.Context, req dto.CreateInscripci
 		return 0, ErrEventoCerrado
 	}
 
+	estado, err := s.repo.FindEstadoEvento(ctx, req.IDEvento)
+	if err != nil {
+		return 0, ErrDB
+	}
+	if !domain.AdmiteInscripciones(estado) {
+		return 0, ErrEventoCerrado
+	}
+
 	if _, err := s.repo.FindUsuarioByID(ctx, req.IDUsuario); err != nil {
 		if errors.Is(err, db.ErrNotFound) {
 			return 0, ErrUsuarioNotFound
@@ -60,12 +83,50 @@ func (s *Service) CreateInscripcion(ctx context.Context, req dto.CreateInscripci
 		return 0, ErrDB
 	}
 
-	id, err := s.repo.CreateInscripcion(ctx, req.IDEvento, req.IDUsuario, req.NombreParticipante, req.Email, req.Afiliacion, req.ComprobantePago, "Pendiente")
+	// Errors from the ticket types are returned as they are so the handler
+	// can tell the participant why the ticket was refused.
+	cotizacion, err := s.entradas.Cotizar(ctx, req.IDEvento, req.IDTipoEntrada, req.Codigo, req.IDUsuario, now)
 	if err != nil {
+		return 0, err
+	}
+
+	reserva, err := s.waitlist.Reservar(ctx, waitlistrepo.NuevaInscripcion{
+		EventoID:        req.IDEvento,
+		UsuarioID:       req.IDUsuario,
+		Nombre:          req.NombreParticipante,
+		Email:           req.Email,
+		Afiliacion:      req.Afiliacion,
+		ComprobantePago: req.ComprobantePago,
+		EstadoPago:      cotizacion.SinCosto(),
+		TipoEntradaID:   cotizacion.IDTipoEntrada,
+		Monto:           cotizacion.Monto,
+		Moneda:          cotizacion.Moneda,
+		CodigoID:        cotizacion.IDCodigo,
+		Descuento:       cotizacion.Descuento,
+	})
+	if err != nil {
+		if errors.Is(err, waitlistsrv.ErrEventoNotFound) {
+			return 0, ErrEventoNotFound
+		}
+		if errors.Is(err, waitlistsrv.ErrEntradaAgotada) {
+			return 0, entradassrv.ErrAgotada
+		}
+		if errors.Is(err, waitlistsrv.ErrCodigoAgotado) {
+			return 0, entradassrv.ErrCodigoAgotado
+		}
 		return 0, ErrDB
 	}
+	id := reserva.IDInscripcion
+
+	if reserva.EnEspera() {
+		asunto, mensaje := buildWaitlistEmail(req.NombreParticipante, evento.Nombre, reserva.PosicionEspera)
+		_ = s.repo.InsertHistorial(ctx, id, "", reserva.Estado, "Evento sin cupos disponibles", "system")
+		_ = s.repo.InsertNotificacion(ctx, req.IDUsuario, &id, asunto, mensaje)
+		_ = sendEmail(req.Email, asunto, mensaje)
+		return id, nil
+	}
 
-	_ = s.repo.InsertHistorial(ctx, id, "", "Pendiente", "Confirmada", "system")
+	_ = s.repo.InsertHistorial(ctx, id, "", reserva.Estado, "Confirmada", "system")
 	_ = s.repo.InsertNotificacion(ctx, req.IDUsuario, &id, "Confirmación de inscripción", "Tu inscripción fue registrada correctamente")
 	_ = sendConfirmationEmail(req.Email, req.NombreParticipante, evento.Nombre)
 
@@ -78,7 +139,44 @@ func sendConfirmationEmail(to, nombre, evento string) error {
 	return sendEmail(to, subject, body)
 }
 
+func buildWaitlistEmail(nombre, evento string, posicion int) (string, string) {
+	asunto := "Inscripción en lista de espera"
+	msg := "Hola " + nombre + ",\n\nEl evento '" + evento + "' alcanzó su capacidad. Tu inscripción quedó en la posición " +
+		strconv.Itoa(posicion) + " de la lista de espera y te avisaremos si se libera un cupo.\n\nGracias."
+	return asunto, msg
+}
+
 func sendEmail(to, subject, body string) error {
+	return sendMessage(to, "Subject: "+subject+"\r\n\r\n"+body)
+}
+
+// sendEmailWithAttachment sends body as text with one file attached.
+func sendEmailWithAttachment(to, subject, body, filename, contentType string, data []byte) error {
+	boundary := "=_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: multipart/mixed; boundary=\"" + boundary + "\"\r\n\r\n")
+	b.WriteString("--" + boundary + "\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(body + "\r\n")
+	b.WriteString("--" + boundary + "\r\n")
+	b.WriteString("Content-Type: " + contentType + "; name=\"" + filename + "\"\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	b.WriteString("Content-Disposition: attachment; filename=\"" + filename + "\"\r\n\r\n")
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	b.WriteString("--" + boundary + "--\r\n")
+	return sendMessage(to, b.String())
+}
+
+// sendMessage sends content, the headers that follow To and then the body,
+// through SMTP_HOST. Nothing is sent when no server is configured.
+func sendMessage(to, content string) error {
 	host := strings.TrimSpace(os.Getenv("SMTP_HOST"))
 	if host == "" {
 		return nil
@@ -96,8 +194,7 @@ func sendEmail(to, subject, body string) error {
 
 	msg := "From: " + from + "\r\n" +
 		"To: " + to + "\r\n" +
-		"Subject: " + subject + "\r\n\r\n" +
-		body
+		content
 
 	addr := host + ":" + port
 	if user == "" {
@@ -148,23 +245,77 @@ func (s *Service) UpdateEstado(ctx context.Context, req dto.UpdateEstadoRequest)
 
 	actual := rows[0].Estado
 	newStatus := validation.NormalizeStatus(req.Estado)
-	if err := s.repo.UpdateEstado(ctx, req.IDInscripcion, newStatus); err != nil {
+	if err := validarCambioManual(actual, newStatus); err != nil {
+		return err
+	}
+	actualizado, err := s.repo.UpdateEstado(ctx, req.IDInscripcion, actual, newStatus)
+	if err != nil {
 		return ErrDB
 	}
+	if !actualizado {
+		return ErrEstadoCambiado
+	}
 
 	_ = s.repo.InsertHistorial(ctx, req.IDInscripcion, actual, newStatus, req.Nota, req.Actor)
 
 	pref, err := s.repo.GetPreferencias(ctx, rows[0].IDUsuario)
-	if err == nil && pref != nil && shouldSendStatusEmail(*pref) {
+	conEmail := err == nil && pref != nil && shouldSendStatusEmail(*pref)
+
+	// Approval sends the ticket in place of the usual status message. The
+	// attendee needs it to get in, so it always reaches their notifications;
+	// the email follows the preferences like any status email.
+	if newStatus == validation.StatusAprobado && actual != newStatus {
+		s.enviarEntrada(ctx, rows[0], conEmail)
+	} else if conEmail {
 		fecha := time.Now().Format("02/01/2006")
 		asunto, mensaje := buildStatusEmail(rows[0].Nombre, rows[0].EventoNombre, newStatus, fecha, req.Nota)
 		_ = s.repo.InsertNotificacion(ctx, rows[0].IDUsuario, &req.IDInscripcion, asunto, mensaje)
 		_ = sendEmail(rows[0].Email, asunto, mensaje)
 	}
 
+	if liberaCupo(actual, newStatus) {
+		s.promoverListaEspera(ctx, rows[0].IDEvento)
+	}
+
+	return nil
+}
+
+// validarCambioManual checks that an inscription can be moved from actual
+// to nuevo by hand. Seats are only taken through the waitlist service, which
+// checks capacity under a lock: waitlisted inscriptions can only be rejected
+// or cancelled by hand, and released ones cannot become active again.
+func validarCambioManual(actual, nuevo string) error {
+	if validation.IsReleasingStatus(nuevo) {
+		return nil
+	}
+	if validation.NormalizeStatus(actual) == validation.StatusEnEspera {
+		return ErrEnListaEspera
+	}
+	if validation.IsReleasingStatus(actual) {
+		return ErrInscripcionLiberada
+	}
 	return nil
 }
 
+// liberaCupo reports whether moving from actual to nuevo frees a seat that a
+// waitlisted participant can take.
+func liberaCupo(actual, nuevo string) bool {
+	if !validation.IsReleasingStatus(nuevo) {
+		return false
+	}
+	return actual != validation.StatusEnEspera && !validation.IsReleasingStatus(actual)
+}
+
+func (s *Service) promoverListaEspera(ctx context.Context, eventoID int) {
+	evento, err := s.repo.FindEventoByID(ctx, eventoID)
+	if err != nil {
+		return
+	}
+	if _, err := s.waitlist.PromoverSiguientes(ctx, evento); err != nil {
+		fmt.Println("[Inscripciones] Error promoviendo lista de espera del evento", eventoID, ":", err)
+	}
+}
+
 func (s *Service) Historial(ctx context.Context, inscripcionID int) ([]repo.HistorialRow, error) {
 	rows, err := s.repo.ListHistorial(ctx, inscripcionID)
 	if err != nil {
@@ -221,6 +372,34 @@ func (s *Service) Reporte(ctx context.Context, filters map[string]interface{}) (
 	return result, total, nil
 }
 
+// ReporteMontos totals the amounts due by currency and ticket type.
+func (s *Service) ReporteMontos(ctx context.Context, filters map[string]interface{}) ([]dto.MontoReporteResponse, error) {
+	rows, err := s.repo.ReporteMontos(ctx, filters)
+	if err != nil {
+		return nil, ErrDB
+	}
+	res := make([]dto.MontoReporteResponse, 0, len(rows))
+	for _, row := range rows {
+		total, err := decimal.NewFromString(row.Total)
+		if err != nil {
+			return nil, ErrDB
+		}
+		pagado, err := decimal.NewFromString(row.Pagado)
+		if err != nil {
+			return nil, ErrDB
+		}
+		res = append(res, dto.MontoReporteResponse{
+			Moneda:        row.Moneda,
+			TipoEntrada:   row.TipoEntrada,
+			Inscripciones: row.Inscripciones,
+			Total:         total,
+			Pagado:        pagado,
+			Pendiente:     total.Sub(pagado),
+		})
+	}
+	return res, nil
+}
+
 func (s *Service) CrearReporteProgramado(ctx context.Context, req dto.ReporteProgramadoRequest) (repo.ReporteProgramadoRow, error) {
 	if req.Frecuencia == "" || req.Formato == "" {
 		return repo.ReporteProgramadoRow{}, ErrPreferenciasInvalid
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"bytes"
+	"image/png"
 	"strings"
 	"testing"
 
@@ -31,3 +33,82 @@ func TestBuildStatusEmail(t *testing.T) {
 		t.Fatal("body should include details")
 	}
 }
+
+func TestLiberaCupo(t *testing.T) {
+	cases := []struct {
+		actual string
+		nuevo  string
+		want   bool
+	}{
+		{"Pendiente", "Rechazado", true},
+		{"Aprobado", "cancelado", true},
+		{"Pagado", "Aprobado", false},
+		{"En espera", "Cancelado", false},
+		{"Rechazado", "Cancelado", false},
+	}
+	for _, c := range cases {
+		if got := liberaCupo(c.actual, c.nuevo); got != c.want {
+			t.Fatalf("liberaCupo(%q, %q) = %v, want %v", c.actual, c.nuevo, got, c.want)
+		}
+	}
+}
+
+func TestValidarCambioManual(t *testing.T) {
+	cases := []struct {
+		actual string
+		nuevo  string
+		want   error
+	}{
+		{"Pendiente", "Aprobado", nil},
+		{"Aprobado", "Cancelado", nil},
+		{"Rechazado", "Pendiente", ErrInscripcionLiberada},
+		{"Cancelado", "Aprobado", ErrInscripcionLiberada},
+		{"Expirado", "Pagado", ErrInscripcionLiberada},
+		{"Rechazado", "Cancelado", nil},
+		{"En espera", "Aprobado", ErrEnListaEspera},
+		{"En espera", "Pagado", ErrEnListaEspera},
+		{"En espera", "Pendiente", ErrEnListaEspera},
+		{"En espera", "Cancelado", nil},
+		{"en espera", "rechazado", nil},
+	}
+	for _, c := range cases {
+		if got := validarCambioManual(c.actual, c.nuevo); got != c.want {
+			t.Fatalf("validarCambioManual(%q, %q) = %v, want %v", c.actual, c.nuevo, got, c.want)
+		}
+	}
+}
+
+func TestBuildWaitlistEmail(t *testing.T) {
+	subject, body := buildWaitlistEmail("Mauricio", "Evento X", 3)
+	if !strings.Contains(subject, "lista de espera") {
+		t.Fatal("subject should mention the waitlist")
+	}
+	if !strings.Contains(body, "Evento X") || !strings.Contains(body, "posición 3") {
+		t.Fatal("body should include event and position")
+	}
+}
+
+func TestBuildTicketEmail(t *testing.T) {
+	asunto, msg := buildTicketEmail("Ana", "Congreso", "INS1.4.2.abc")
+	if asunto != "Tu entrada para Congreso" {
+		t.Fatalf("unexpected subject: %s", asunto)
+	}
+	if !strings.Contains(msg, "Hola Ana") || !strings.Contains(msg, "INS1.4.2.abc") {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
+
+func TestEntradaPNG(t *testing.T) {
+	data, err := entradaPNG("INS1.4.2.abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Version 1 is 21 modules, plus a quiet zone of 4 on each side.
+	if w := img.Bounds().Dx(); w != (21+8)*8 {
+		t.Fatalf("unexpected width %d", w)
+	}
+}
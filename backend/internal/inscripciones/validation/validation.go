@@ -38,13 +38,44 @@ func ParseDate(value string, loc *time.Location) (time.Time, error) {
 	return time.ParseInLocation("02/01/2006", strings.TrimSpace(value), loc)
 }
 
+const (
+	StatusAprobado  = "Aprobado"
+	StatusEnEspera  = "En espera"
+	StatusRechazado = "Rechazado"
+	StatusCancelado = "Cancelado"
+	StatusExpirado  = "Expirado"
+)
+
+// EstadosLiberados is the SQL list of the statuses whose inscriptions no
+// longer hold a seat or a ticket, for use after NOT IN. Waitlisted
+// inscriptions are not in it: they keep the ticket they chose.
+const EstadosLiberados = `('` + StatusRechazado + `', '` + StatusCancelado + `', '` + StatusExpirado + `')`
+
 func NormalizeStatus(value string) string {
-	return strings.Title(strings.ToLower(strings.TrimSpace(value)))
+	normalized := strings.Title(strings.ToLower(strings.TrimSpace(value)))
+	if strings.EqualFold(normalized, StatusEnEspera) {
+		return StatusEnEspera
+	}
+	return normalized
 }
 
+// IsAllowedStatus reports whether an inscription can be moved to value by
+// hand. "En espera" is managed by the waitlist and is not accepted here.
 func IsAllowedStatus(value string) bool {
 	switch NormalizeStatus(value) {
-	case "Pendiente", "Pagado", "Aprobado", "Rechazado":
+	case "Pendiente", "Pagado", StatusAprobado, StatusRechazado, StatusCancelado:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsReleasingStatus reports whether an inscription in this status gives its
+// seat back to the event. Expirado is set when an event is archived with
+// the inscription still unpaid.
+func IsReleasingStatus(value string) bool {
+	switch NormalizeStatus(value) {
+	case StatusRechazado, StatusCancelado, StatusExpirado:
 		return true
 	default:
 		return false
@@ -6,8 +6,17 @@ const (
 	MsgCierreInscripciones   = "¡Última oportunidad! Las inscripciones para el evento '%s' cierran el %s. ¡No te quedes fuera!"
 	MsgRecordatorioEvento    = "Recuerda que el evento '%s' al que te inscribiste inicia el %s."
 	MsgRecordatorioPago      = "Tienes un pago pendiente para el evento '%s', que inicia el %s. Por favor, regulariza tu situación para asegurar tu participación."
+	MsgRecordatorioPagoMonto = "Tienes un pago pendiente de %s %s para el evento '%s', que inicia el %s. Por favor, regulariza tu situación para asegurar tu participación."
 	MsgAperturaInscripciones = "¡Ya puedes inscribirte al evento '%s'! Las inscripciones están abiertas hasta el %s."
 	MsgCancelacionEvento     = "Lamentamos informarte que el evento '%s' ha sido cancelado. Si ya te habías inscrito, recibirás un reembolso completo. Disculpa las molestias."
+	MsgSolicitudRol          = "El usuario '%s' ha solicitado el rol '%s'. Revisa la solicitud para aprobarla o rechazarla."
+	MsgRolAprobado           = "Tu solicitud del rol '%s' fue aprobada. El rol estará vigente %s."
+	MsgRolRechazado          = "Tu solicitud del rol '%s' fue rechazada: %s."
+	MsgRolExpirado           = "Tu asignación del rol '%s' ha vencido."
+	MsgListaEspera           = "El evento '%s' alcanzó su capacidad. Quedaste en la posición %d de la lista de espera y te avisaremos si se libera un cupo."
+	MsgCupoLiberado          = "¡Se liberó un cupo en el evento '%s'! Tu inscripción salió de la lista de espera y ahora está pendiente de confirmación."
+	MsgReprogramacionEvento  = "El evento '%s' fue reprogramado: ahora inicia el %s y finaliza el %s.%s Confirma o cancela tu asistencia en %s. Si cancelas y ya pagaste, recibirás un reembolso."
+	MsgEncuestaEvento        = "¡Gracias por asistir al evento '%s'! Cuéntanos qué te pareció respondiendo la encuesta en %s."
 )
 
 var NotificationTitles = map[string]string{
@@ -18,6 +27,14 @@ var NotificationTitles = map[string]string{
 	NotificationTypeRecordatorioPago:      "Recordatorio de pago",
 	NotificationTypeAperturaInscripciones: "Apertura de inscripciones",
 	NotificationTypeCancelacionEvento:     "Cancelación de evento",
+	NotificationTypeSolicitudRol:          "Solicitud de rol",
+	NotificationTypeRolAprobado:           "Rol aprobado",
+	NotificationTypeRolRechazado:          "Rol rechazado",
+	NotificationTypeRolExpirado:           "Rol vencido",
+	NotificationTypeListaEspera:           "Lista de espera",
+	NotificationTypeCupoLiberado:          "Cupo liberado",
+	NotificationTypeReprogramacionEvento:  "Reprogramación de evento",
+	NotificationTypeEncuestaEvento:        "Encuesta del evento",
 }
 
 func GetNotificationTitle(tipo string) string {
@@ -8,4 +8,12 @@ const (
 	NotificationTypeRecordatorioPago      = "recordatorio_pago"
 	NotificationTypeAperturaInscripciones = "apertura_inscripciones"
 	NotificationTypeCancelacionEvento     = "cancelacion_evento"
+	NotificationTypeSolicitudRol          = "solicitud_rol"
+	NotificationTypeRolAprobado           = "rol_aprobado"
+	NotificationTypeRolRechazado          = "rol_rechazado"
+	NotificationTypeRolExpirado           = "rol_expirado"
+	NotificationTypeListaEspera           = "lista_espera"
+	NotificationTypeCupoLiberado          = "cupo_liberado"
+	NotificationTypeReprogramacionEvento  = "reprogramacion_evento"
+	NotificationTypeEncuestaEvento        = "encuesta_evento"
 )
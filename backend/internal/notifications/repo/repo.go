@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"project/backend/internal/notifications/dto"
 	"project/backend/prisma/db"
@@ -14,6 +15,13 @@ type NotificationRepository interface {
 	MarkAsRead(ctx context.Context, idNotificacion int, leida bool) error
 	ExistsCierreInscripcionToday(ctx context.Context, userID int, eventID int) (bool, error)
 	ExistsNotificationToday(ctx context.Context, userID int, eventID int, tipo string) (bool, error)
+	CreateParaTodosLote(ctx context.Context, tipo string, mensajes []MensajeEvento) (int, error)
+}
+
+// MensajeEvento is the text of a notification about one event.
+type MensajeEvento struct {
+	IDEvento int    `json:"id_evento"`
+	Mensaje  string `json:"mensaje"`
 }
 
 type notificationRepository struct {
@@ -102,3 +110,24 @@ func (r *notificationRepository) ExistsNotificationToday(ctx context.Context, us
 	}
 	return notif != nil, nil
 }
+
+// CreateParaTodosLote notifies every user of each message in a single
+// statement and returns how many notifications were created.
+func (r *notificationRepository) CreateParaTodosLote(ctx context.Context, tipo string, mensajes []MensajeEvento) (int, error) {
+	if len(mensajes) == 0 {
+		return 0, nil
+	}
+	payload, err := json.Marshal(mensajes)
+	if err != nil {
+		return 0, err
+	}
+	query := `INSERT INTO "Notificacion" ("id_usuario", "id_evento", "tipo", "mensaje")
+		SELECT u."id_usuario", m."id_evento", $2::text, m."mensaje"
+		FROM "Usuario" u
+		CROSS JOIN jsonb_to_recordset($1::jsonb) AS m("id_evento" int, "mensaje" text)`
+	count, err := r.client.Prisma.Raw.ExecuteRaw(query, string(payload), tipo).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return count.Count, nil
+}
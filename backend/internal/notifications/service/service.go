@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"project/backend/internal/events/domain"
 	eventrepo "project/backend/internal/events/repo"
 	"project/backend/internal/notifications/dto"
 	"project/backend/internal/notifications/repo"
@@ -18,7 +19,8 @@ type NotificationService interface {
 	NotificarCierreInscripciones(ctx context.Context, eventosRepo *eventrepo.Repository, inscripcionesRepo *registrationrepo.Repository) error
 	NotificarRecordatorioEvento(ctx context.Context, eventosRepo *eventrepo.Repository, inscripcionesRepo *registrationrepo.Repository) error
 	NotificarPagoPendiente(ctx context.Context, eventosRepo *eventrepo.Repository, inscripcionesRepo *registrationrepo.Repository) error
-	NotificarAperturaInscripciones(ctx context.Context, evento *db.EventoModel, usuariosRepo *registrationrepo.Repository) error
+	NotificarAperturaInscripciones(ctx context.Context, evento *db.EventoModel, zona string, usuariosRepo *registrationrepo.Repository) error
+	NotificarAperturaInscripcionesLote(ctx context.Context, eventos []eventrepo.EstadoRow) error
 	NotificarCancelacionEvento(ctx context.Context, evento *db.EventoModel, inscripcionesRepo *registrationrepo.Repository) error
 }
 
@@ -68,7 +70,7 @@ func (s *notificationService) NotificarCierreInscripciones(ctx context.Context,
 			mensaje := fmt.Sprintf(
 				dto.MsgCierreInscripciones,
 				evento.Nombre,
-				evento.FechaCierreInscripcion.Format("02/01/2006"),
+				domain.FormatoConZona(evento.FechaCierreInscripcion, evento.ZonaHoraria),
 			)
 			_, notifErr := s.CreateNotification(ctx, dto.CreateNotificationRequest{
 				UserID:  usuario.IDUsuario,
@@ -107,7 +109,7 @@ func (s *notificationService) NotificarRecordatorioEvento(ctx context.Context, e
 			mensaje := fmt.Sprintf(
 				dto.MsgRecordatorioEvento,
 				evento.Nombre,
-				evento.FechaInicio.Format("02/01/2006"),
+				domain.FormatoConZona(evento.FechaInicio, evento.ZonaHoraria),
 			)
 			_, notifErr := s.CreateNotification(ctx, dto.CreateNotificationRequest{
 				UserID:  inscripcion.IDUsuario,
@@ -125,7 +127,7 @@ func (s *notificationService) NotificarRecordatorioEvento(ctx context.Context, e
 	return nil
 }
 func (s *notificationService) NotificarPagoPendiente(ctx context.Context, eventosRepo *eventrepo.Repository, inscripcionesRepo *registrationrepo.Repository) error {
-	inscripciones, err := inscripcionesRepo.FindAll(ctx)
+	inscripciones, err := inscripcionesRepo.FindPagosPendientes(ctx)
 	if err != nil {
 		fmt.Println("[PagoPendiente] Error obteniendo inscripciones:", err)
 		return err
@@ -133,35 +135,41 @@ func (s *notificationService) NotificarPagoPendiente(ctx context.Context, evento
 	now := time.Now().UTC()
 	count := 0
 	for _, insc := range inscripciones {
-		if !insc.EstadoPago {
-			evento, err := eventosRepo.FindByID(ctx, insc.IDEvento)
+		evento, err := eventosRepo.FindByID(ctx, insc.IDEvento)
+		if err != nil {
+			fmt.Println("[PagoPendiente] Error obteniendo evento", insc.IDEvento, ":", err)
+			continue
+		}
+		diasRestantes := evento.FechaInicio.Sub(now).Hours() / 24
+		if diasRestantes <= 5 && diasRestantes >= 0 {
+			exists, err := s.repo.ExistsNotificationToday(ctx, insc.IDUsuario, evento.IDEvento, dto.NotificationTypeRecordatorioPago)
 			if err != nil {
-				fmt.Println("[PagoPendiente] Error obteniendo evento", insc.IDEvento, ":", err)
+				fmt.Println("Error verificando notificación existente de pago pendiente para usuario", insc.IDUsuario, ":", err)
+				continue
+			}
+			if exists {
 				continue
 			}
-			diasRestantes := evento.FechaInicio.Sub(now).Hours() / 24
-			if diasRestantes <= 5 && diasRestantes >= 0 {
-				exists, err := s.repo.ExistsNotificationToday(ctx, insc.IDUsuario, evento.IDEvento, dto.NotificationTypeRecordatorioPago)
-				if err != nil {
-					fmt.Println("Error verificando notificación existente de pago pendiente para usuario", insc.IDUsuario, ":", err)
-					continue
-				}
-				if exists {
-					continue
-				}
-				mensaje := fmt.Sprintf(dto.MsgRecordatorioPago, evento.Nombre, evento.FechaInicio.Format("02/01/2006"))
-				_, notifErr := s.CreateNotification(ctx, dto.CreateNotificationRequest{
-					UserID:  insc.IDUsuario,
-					EventID: &evento.IDEvento,
-					Type:    dto.NotificationTypeRecordatorioPago,
-					Message: mensaje,
-				})
-				if notifErr != nil {
-					fmt.Println("[PagoPendiente] Error creando notificación de pago pendiente para usuario", insc.IDUsuario, ":", notifErr)
-				} else {
-					fmt.Printf("[PagoPendiente] Notificación de pago pendiente creada para usuario %d en evento %d\n", insc.IDUsuario, evento.IDEvento)
-					count++
-				}
+			zona, err := eventosRepo.FindZonaHoraria(ctx, evento.IDEvento)
+			if err != nil {
+				zona = domain.ZonaHorariaPredeterminada
+			}
+			inicio := domain.FormatoConZona(evento.FechaInicio, zona)
+			mensaje := fmt.Sprintf(dto.MsgRecordatorioPago, evento.Nombre, inicio)
+			if insc.Monto != nil && insc.Moneda != nil {
+				mensaje = fmt.Sprintf(dto.MsgRecordatorioPagoMonto, *insc.Monto, *insc.Moneda, evento.Nombre, inicio)
+			}
+			_, notifErr := s.CreateNotification(ctx, dto.CreateNotificationRequest{
+				UserID:  insc.IDUsuario,
+				EventID: &evento.IDEvento,
+				Type:    dto.NotificationTypeRecordatorioPago,
+				Message: mensaje,
+			})
+			if notifErr != nil {
+				fmt.Println("[PagoPendiente] Error creando notificación de pago pendiente para usuario", insc.IDUsuario, ":", notifErr)
+			} else {
+				fmt.Printf("[PagoPendiente] Notificación de pago pendiente creada para usuario %d en evento %d\n", insc.IDUsuario, evento.IDEvento)
+				count++
 			}
 		}
 	}
@@ -169,7 +177,9 @@ func (s *notificationService) NotificarPagoPendiente(ctx context.Context, evento
 	return nil
 }
 
-func (s *notificationService) NotificarAperturaInscripciones(ctx context.Context, evento *db.EventoModel, usuariosRepo *registrationrepo.Repository) error {
+// NotificarAperturaInscripciones announces a published event; zona is the
+// event's time zone, used to show the closing date.
+func (s *notificationService) NotificarAperturaInscripciones(ctx context.Context, evento *db.EventoModel, zona string, usuariosRepo *registrationrepo.Repository) error {
 	usuarios, err := usuariosRepo.FindAllUsuarios(ctx)
 	if err != nil {
 		fmt.Println("[AperturaInscripciones] Error obteniendo usuarios:", err)
@@ -185,7 +195,7 @@ func (s *notificationService) NotificarAperturaInscripciones(ctx context.Context
 		if exists {
 			continue
 		}
-		mensaje := fmt.Sprintf(dto.MsgAperturaInscripciones, evento.Nombre, evento.FechaCierreInscripcion.Format("02/01/2006"))
+		mensaje := fmt.Sprintf(dto.MsgAperturaInscripciones, evento.Nombre, domain.FormatoConZona(evento.FechaCierreInscripcion, zona))
 		_, notifErr := s.CreateNotification(ctx, dto.CreateNotificationRequest{
 			UserID:  usuario.IDUsuario,
 			EventID: &evento.IDEvento,
@@ -203,6 +213,26 @@ func (s *notificationService) NotificarAperturaInscripciones(ctx context.Context
 	return nil
 }
 
+// NotificarAperturaInscripcionesLote announces several events published at
+// once, such as a bulk import, creating every notification in one batch.
+// The events are new, so no user can have been notified about them yet.
+func (s *notificationService) NotificarAperturaInscripcionesLote(ctx context.Context, eventos []eventrepo.EstadoRow) error {
+	mensajes := make([]repo.MensajeEvento, 0, len(eventos))
+	for _, evento := range eventos {
+		mensajes = append(mensajes, repo.MensajeEvento{
+			IDEvento: evento.IDEvento,
+			Mensaje:  fmt.Sprintf(dto.MsgAperturaInscripciones, evento.Nombre, domain.FormatoConZona(evento.FechaCierreInscripcion, evento.ZonaHoraria)),
+		})
+	}
+	count, err := s.repo.CreateParaTodosLote(ctx, dto.NotificationTypeAperturaInscripciones, mensajes)
+	if err != nil {
+		fmt.Println("[AperturaInscripciones] Error creando notificaciones en lote:", err)
+		return err
+	}
+	fmt.Printf("[AperturaInscripciones] Total notificaciones de apertura creadas en lote: %d\n", count)
+	return nil
+}
+
 func (s *notificationService) NotificarCancelacionEvento(ctx context.Context, evento *db.EventoModel, inscripcionesRepo *registrationrepo.Repository) error {
 	inscripciones, err := inscripcionesRepo.FindByEventoID(ctx, evento.IDEvento)
 	if err != nil {
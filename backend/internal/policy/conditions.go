@@ -0,0 +1,91 @@
+package policy
+
+import "reflect"
+
+type Condition func(req Request) bool
+
+func Authenticated() Condition {
+	return func(req Request) bool {
+		return req.Subject.Authenticated()
+	}
+}
+
+// IsOwner holds when the authenticated subject owns the resource.
+func IsOwner() Condition {
+	return func(req Request) bool {
+		return req.Subject.Authenticated() && req.Subject.UserID == req.Resource.OwnerID
+	}
+}
+
+// IsAssigned holds when the authenticated subject is one of the resource assignees.
+func IsAssigned() Condition {
+	return func(req Request) bool {
+		if !req.Subject.Authenticated() {
+			return false
+		}
+		for _, id := range req.Resource.AssigneeIDs {
+			if id == req.Subject.UserID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func SubjectAttr(key string, value any) Condition {
+	return func(req Request) bool {
+		return attrEquals(req.Subject.Attributes, key, value)
+	}
+}
+
+func ResourceAttr(key string, value any) Condition {
+	return func(req Request) bool {
+		return attrEquals(req.Resource.Attributes, key, value)
+	}
+}
+
+// EventAttr never holds when the request has no event.
+func EventAttr(key string, value any) Condition {
+	return func(req Request) bool {
+		if req.Event == nil {
+			return false
+		}
+		return attrEquals(req.Event.Attributes, key, value)
+	}
+}
+
+func All(conditions ...Condition) Condition {
+	return func(req Request) bool {
+		for _, condition := range conditions {
+			if !condition(req) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func Any(conditions ...Condition) Condition {
+	return func(req Request) bool {
+		for _, condition := range conditions {
+			if condition(req) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func Not(condition Condition) Condition {
+	return func(req Request) bool {
+		return !condition(req)
+	}
+}
+
+func attrEquals(attrs map[string]any, key string, value any) bool {
+	current, ok := attrs[key]
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(current, value)
+}
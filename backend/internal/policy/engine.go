@@ -0,0 +1,55 @@
+package policy
+
+// Rule allows Action on ResourceType for subjects holding any of Roles (or
+// any subject when Roles is empty) as long as When holds.
+type Rule struct {
+	Name         string
+	Action       Action
+	ResourceType string
+	Roles        []string
+	When         Condition
+}
+
+// Engine evaluates attribute rules. It is meant to run after the RBAC check
+// has failed to grant blanket access: callers with the management permission
+// never reach it, everyone else needs a matching rule. Anything not covered by
+// a rule is denied.
+type Engine struct {
+	rules []Rule
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Default returns an engine loaded with the application rules.
+func Default() *Engine {
+	return NewEngine(DefaultRules()...)
+}
+
+func (e *Engine) Evaluate(req Request) Decision {
+	for _, rule := range e.rules {
+		if !rule.appliesTo(req) {
+			continue
+		}
+		if rule.When == nil || rule.When(req) {
+			return Decision{Allowed: true, Rule: rule.Name}
+		}
+	}
+	return Decision{Allowed: false}
+}
+
+func (r Rule) appliesTo(req Request) bool {
+	if r.Action != req.Action || r.ResourceType != req.Resource.Type {
+		return false
+	}
+	if len(r.Roles) == 0 {
+		return true
+	}
+	for _, role := range r.Roles {
+		if req.Subject.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
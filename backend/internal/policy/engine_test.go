@@ -0,0 +1,181 @@
+package policy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authservice "project/backend/internal/auth/service"
+)
+
+func TestEngineEvaluateDefaultRules(t *testing.T) {
+	engine := Default()
+
+	participant := Subject{UserID: 7, Roles: []string{"PARTICIPANTE"}}
+	ponente := Subject{UserID: 9, Roles: []string{"PONENTE"}}
+	anonymous := Subject{Roles: []string{"PONENTE"}}
+
+	cases := []struct {
+		name    string
+		req     Request
+		allowed bool
+		rule    string
+	}{
+		{
+			name:    "participant reads own inscriptions",
+			req:     Request{Subject: participant, Action: ActionRead, Resource: Resource{Type: ResourceInscripcion, OwnerID: 7}},
+			allowed: true,
+			rule:    RuleInscripcionesPropias,
+		},
+		{
+			name: "participant reads someone else's inscriptions",
+			req:  Request{Subject: participant, Action: ActionRead, Resource: Resource{Type: ResourceInscripcion, OwnerID: 8}},
+		},
+		{
+			name: "anonymous caller cannot claim ownership",
+			req:  Request{Subject: Subject{}, Action: ActionRead, Resource: Resource{Type: ResourceInscripcion, OwnerID: 0}},
+		},
+		{
+			name: "read rule does not cover updates",
+			req:  Request{Subject: participant, Action: ActionUpdate, Resource: Resource{Type: ResourceInscripcion, OwnerID: 7}},
+		},
+		{
+			name:    "ponente edits assigned session",
+			req:     Request{Subject: ponente, Action: ActionUpdate, Resource: Resource{Type: ResourceSesion, AssigneeIDs: []int{3, 9}}, Event: &Event{Attributes: map[string]any{"cancelado": false}}},
+			allowed: true,
+			rule:    RuleSesionesAsignadas,
+		},
+		{
+			name: "ponente edits unassigned session",
+			req:  Request{Subject: ponente, Action: ActionUpdate, Resource: Resource{Type: ResourceSesion, AssigneeIDs: []int{3}}},
+		},
+		{
+			name: "ponente edits assigned session of cancelled event",
+			req:  Request{Subject: ponente, Action: ActionUpdate, Resource: Resource{Type: ResourceSesion, AssigneeIDs: []int{9}}, Event: &Event{Attributes: map[string]any{"cancelado": true}}},
+		},
+		{
+			name: "ponente edits cancelled session",
+			req:  Request{Subject: ponente, Action: ActionUpdate, Resource: Resource{Type: ResourceSesion, AssigneeIDs: []int{9}, Attributes: map[string]any{"cancelado": true}}},
+		},
+		{
+			name: "assigned user without ponente role",
+			req:  Request{Subject: Subject{UserID: 9, Roles: []string{"PARTICIPANTE"}}, Action: ActionUpdate, Resource: Resource{Type: ResourceSesion, AssigneeIDs: []int{9}}},
+		},
+		{
+			name: "anonymous ponente header is not enough",
+			req:  Request{Subject: anonymous, Action: ActionUpdate, Resource: Resource{Type: ResourceSesion, AssigneeIDs: []int{0}}},
+		},
+		{
+			name: "unknown resource is denied",
+			req:  Request{Subject: participant, Action: ActionRead, Resource: Resource{Type: "evento", OwnerID: 7}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := engine.Evaluate(tc.req)
+			if got.Allowed != tc.allowed {
+				t.Fatalf("expected allowed %v, got %v", tc.allowed, got.Allowed)
+			}
+			if got.Rule != tc.rule {
+				t.Fatalf("expected rule %q, got %q", tc.rule, got.Rule)
+			}
+		})
+	}
+}
+
+func TestConditions(t *testing.T) {
+	req := Request{
+		Subject:  Subject{UserID: 1, Attributes: map[string]any{"afiliacion": "UCV"}},
+		Resource: Resource{OwnerID: 1, Attributes: map[string]any{"estado": "Pagado"}},
+		Event:    &Event{Attributes: map[string]any{"cancelado": false}},
+	}
+
+	cases := []struct {
+		name      string
+		condition Condition
+		expected  bool
+	}{
+		{"subject attribute match", SubjectAttr("afiliacion", "UCV"), true},
+		{"subject attribute mismatch", SubjectAttr("afiliacion", "USB"), false},
+		{"missing attribute", SubjectAttr("pais", "VE"), false},
+		{"resource attribute", ResourceAttr("estado", "Pagado"), true},
+		{"event attribute", EventAttr("cancelado", false), true},
+		{"all", All(IsOwner(), ResourceAttr("estado", "Pagado")), true},
+		{"all with failing branch", All(IsOwner(), ResourceAttr("estado", "Pendiente")), false},
+		{"any", Any(ResourceAttr("estado", "Pendiente"), IsOwner()), true},
+		{"not", Not(IsAssigned()), true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.condition(req); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+
+	if EventAttr("cancelado", false)(Request{}) {
+		t.Fatalf("expected event condition to fail without event")
+	}
+}
+
+func TestSubjectFromRequest(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	token, err := authservice.CreateJWT(5, "ana@example.com", []string{"PONENTE", "PARTICIPANTE"})
+	if err != nil {
+		t.Fatalf("unexpected error creating token: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		header map[string]string
+		userID int
+		roles  []string
+	}{
+		{"bearer token", map[string]string{"Authorization": "Bearer " + token, "X-Role": "ADMIN"}, 5, []string{"PONENTE", "PARTICIPANTE"}},
+		{"invalid token falls back to header", map[string]string{"Authorization": "Bearer nope", "X-Role": "ORGANIZADOR, PONENTE"}, 0, []string{"ORGANIZADOR", "PONENTE"}},
+		{"no identity", map[string]string{}, 0, []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+			subject := SubjectFromRequest(req)
+			if subject.UserID != tc.userID {
+				t.Fatalf("expected user %d, got %d", tc.userID, subject.UserID)
+			}
+			if len(subject.Roles) != len(tc.roles) {
+				t.Fatalf("expected roles %v, got %v", tc.roles, subject.Roles)
+			}
+			for i := range tc.roles {
+				if subject.Roles[i] != tc.roles[i] {
+					t.Fatalf("expected roles %v, got %v", tc.roles, subject.Roles)
+				}
+			}
+		})
+	}
+}
+
+func TestSubjectIdentified(t *testing.T) {
+	cases := []struct {
+		name    string
+		subject Subject
+		want    bool
+	}{
+		{"user", Subject{UserID: 7}, true},
+		{"roles only", Subject{Roles: []string{"ORGANIZADOR"}}, true},
+		{"nothing", Subject{Roles: []string{}}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.subject.Identified(); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
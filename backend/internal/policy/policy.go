@@ -0,0 +1,70 @@
+package policy
+
+import "strings"
+
+type Action string
+
+const (
+	ActionRead   Action = "read"
+	ActionUpdate Action = "update"
+)
+
+const (
+	ResourceInscripcion = "inscripcion"
+	ResourceSesion      = "sesion"
+)
+
+// Subject is the caller the decision is made for. UserID is zero when the
+// request carried no verified identity.
+type Subject struct {
+	UserID     int
+	Roles      []string
+	Attributes map[string]any
+}
+
+// Resource describes the object being accessed. OwnerID and AssigneeIDs back
+// the ownership conditions; anything else goes in Attributes.
+type Resource struct {
+	Type        string
+	ID          int
+	OwnerID     int
+	AssigneeIDs []int
+	Attributes  map[string]any
+}
+
+// Event carries the attributes of the event the resource belongs to, if any.
+type Event struct {
+	ID         int
+	Attributes map[string]any
+}
+
+type Request struct {
+	Subject  Subject
+	Action   Action
+	Resource Resource
+	Event    *Event
+}
+
+type Decision struct {
+	Allowed bool
+	Rule    string
+}
+
+func (s Subject) Authenticated() bool {
+	return s.UserID > 0
+}
+
+// Identified reports whether the subject carries a user or at least one
+// role. Requests without either are answered 401.
+func (s Subject) Identified() bool {
+	return s.Authenticated() || len(s.Roles) > 0
+}
+
+func (s Subject) HasRole(name string) bool {
+	for _, role := range s.Roles {
+		if strings.EqualFold(strings.TrimSpace(role), strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
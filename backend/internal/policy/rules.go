@@ -0,0 +1,32 @@
+package policy
+
+const (
+	RuleInscripcionesPropias = "inscripciones.leer_propias"
+	RuleSesionesAsignadas    = "sesiones.editar_asignadas"
+)
+
+// DefaultRules are the ownership rules applied on top of RBAC:
+//   - any participant may read their own inscriptions;
+//   - a PONENTE may edit a session only when assigned to it and the event
+//     has not been cancelled.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:         RuleInscripcionesPropias,
+			Action:       ActionRead,
+			ResourceType: ResourceInscripcion,
+			When:         IsOwner(),
+		},
+		{
+			Name:         RuleSesionesAsignadas,
+			Action:       ActionUpdate,
+			ResourceType: ResourceSesion,
+			Roles:        []string{"PONENTE"},
+			When: All(
+				IsAssigned(),
+				Not(ResourceAttr("cancelado", true)),
+				Not(EventAttr("cancelado", true)),
+			),
+		},
+	}
+}
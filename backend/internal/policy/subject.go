@@ -0,0 +1,30 @@
+package policy
+
+import (
+	"net/http"
+	"strings"
+
+	authservice "project/backend/internal/auth/service"
+)
+
+const roleHeaderKey = "X-Role"
+
+// SubjectFromRequest builds the subject from the bearer token when present.
+// Without a valid token the subject is anonymous and only carries the roles
+// declared in the X-Role header, which is enough for the RBAC check but never
+// satisfies an ownership condition.
+func SubjectFromRequest(r *http.Request) Subject {
+	if raw := strings.TrimSpace(r.Header.Get("Authorization")); len(raw) > 7 && strings.EqualFold(raw[:7], "Bearer ") {
+		if claims, err := authservice.ParseJWT(strings.TrimSpace(raw[7:])); err == nil {
+			return Subject{UserID: claims.UserID, Roles: claims.Roles}
+		}
+	}
+
+	roles := []string{}
+	for _, name := range strings.Split(r.Header.Get(roleHeaderKey), ",") {
+		if trimmed := strings.TrimSpace(name); trimmed != "" {
+			roles = append(roles, trimmed)
+		}
+	}
+	return Subject{Roles: roles}
+}
@@ -8,4 +8,5 @@ type EventFilters struct {
 	CityTerm    string
 	FromDate    *time.Time
 	ToDate      *time.Time
+	Archivados  bool
 }
@@ -1,10 +1,12 @@
 package dto
 
 type CreateInscripcionRequest struct {
-	EventoID    int    `json:"id_evento"`
-	UsuarioID   int    `json:"id_usuario"`
-	EstadoPago  bool   `json:"estado_pago"`
-	Comprobante string `json:"comprobante"`
+	EventoID      int    `json:"id_evento"`
+	UsuarioID     int    `json:"id_usuario"`
+	EstadoPago    bool   `json:"estado_pago"`
+	Comprobante   string `json:"comprobante"`
+	TipoEntradaID *int   `json:"id_tipo_entrada"`
+	Codigo        string `json:"codigo"`
 }
 
 type UpdatePagoRequest struct {
@@ -7,4 +7,5 @@ type InscripcionResponse struct {
 	Fecha       string `json:"fecha"`
 	EstadoPago  bool   `json:"estado_pago"`
 	Comprobante string `json:"comprobante"`
+	Estado      string `json:"estado"`
 }
@@ -9,13 +9,18 @@ import (
 	"strings"
 	"time"
 
+	entradasrepo "project/backend/internal/entradas/repo"
+	entradassrv "project/backend/internal/entradas/service"
+	"project/backend/internal/events/domain"
 	eventdto "project/backend/internal/events/dto"
+	eventrepo "project/backend/internal/events/repo"
 	notificationservice "project/backend/internal/notifications/service"
 	"project/backend/internal/registrations/dto"
 	"project/backend/internal/registrations/repo"
 	"project/backend/internal/registrations/service"
 	"project/backend/internal/registrations/validation"
 	"project/backend/internal/shared/httperror"
+	waitlistsrv "project/backend/internal/waitlist/service"
 	"project/backend/prisma/db"
 )
 
@@ -26,7 +31,7 @@ type Handler struct {
 func New(client *db.PrismaClient) http.Handler {
 	repository := repo.New(client)
 	notificationSvc := notificationservice.NewNotificationServiceFromClient(client)
-	return &Handler{svc: service.New(repository, notificationSvc)}
+	return &Handler{svc: service.New(repository, notificationSvc, waitlistsrv.New(client), entradassrv.New(entradasrepo.New(client)))}
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -70,6 +75,13 @@ func (h *Handler) createInscripcion(w http.ResponseWriter, r *http.Request) {
 			httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
 		case errors.Is(err, service.ErrYaInscrito):
 			httperror.WriteJSON(w, http.StatusConflict, err.Error())
+		case errors.Is(err, entradassrv.ErrNotFound), errors.Is(err, entradassrv.ErrCodigoNotFound):
+			httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, entradassrv.ErrRequerida), errors.Is(err, entradassrv.ErrNoDisponible), errors.Is(err, entradassrv.ErrSoloPonentes),
+			errors.Is(err, entradassrv.ErrCodigoVencido), errors.Is(err, entradassrv.ErrCodigoNoAplica):
+			httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, entradassrv.ErrAgotada), errors.Is(err, entradassrv.ErrCodigoAgotado):
+			httperror.WriteJSON(w, http.StatusConflict, err.Error())
 		default:
 			httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
 		}
@@ -83,6 +95,7 @@ func (h *Handler) createInscripcion(w http.ResponseWriter, r *http.Request) {
 		Fecha:       created.Fecha.Format("02/01/2006 15:04"),
 		EstadoPago:  created.EstadoPago,
 		Comprobante: created.Comprobante,
+		Estado:      created.Estado,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -117,14 +130,14 @@ func (h *Handler) listInscripciones(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventos, err := h.svc.GetAllEventos(ctx)
+	eventos, err := h.svc.GetAllEventos(ctx, filters.Archivados)
 	if err != nil {
 		httperror.WriteJSON(w, http.StatusInternalServerError, "db error eventos")
 		return
 	}
 
 	// Filtrar eventos cancelados
-	eventosFiltrados := make([]db.EventoModel, 0, len(eventos))
+	eventosFiltrados := make([]eventrepo.EventoLocal, 0, len(eventos))
 	for _, ev := range eventos {
 		if !ev.Cancelado {
 			eventosFiltrados = append(eventosFiltrados, ev)
@@ -140,21 +153,25 @@ func (h *Handler) listInscripciones(w http.ResponseWriter, r *http.Request) {
 	eventosInscritos := make([]eventdto.EventoResponse, 0)
 	eventosDisponibles := make([]eventdto.EventoResponse, 0)
 	for _, ev := range eventosFiltrados {
-		if !h.svc.MatchesEventFilters(ev, filters) {
+		if !h.svc.MatchesEventFilters(ev.EventoModel, filters) {
 			continue
 		}
 
 		_, inscrito := inscritosMap[ev.IDEvento]
-		abierto := ev.InscripcionesAbiertasManual && now.Before(ev.FechaCierreInscripcion) && now.Before(ev.FechaInicio)
+		fechas := domain.Fechas{
+			Inicio: ev.FechaInicio,
+			Fin:    ev.FechaFin,
+			Cierre: ev.FechaCierreInscripcion,
+		}
+		abierto := domain.InscripcionesAbiertas(ev.InscripcionesAbiertasManual, fechas, now)
 		er := eventdto.EventoResponse{
-			ID:                     ev.IDEvento,
-			Nombre:                 ev.Nombre,
-			FechaInicio:            ev.FechaInicio.Format("02/01/2006"),
-			FechaFin:               ev.FechaFin.Format("02/01/2006"),
-			FechaCierreInscripcion: ev.FechaCierreInscripcion.Format("02/01/2006"),
-			InscripcionesAbiertas:  abierto,
-			Ubicacion:              ev.Ubicacion,
+			ID:                    ev.IDEvento,
+			Nombre:                ev.Nombre,
+			InscripcionesAbiertas: abierto,
+			Ubicacion:             ev.Ubicacion,
+			Archivado:             ev.Archivado,
 		}
+		er.SetFechas(fechas, ev.ZonaHoraria)
 		if inscrito {
 			eventosInscritos = append(eventosInscritos, er)
 		} else if abierto {
@@ -221,6 +238,7 @@ func (h *Handler) updatePago(w http.ResponseWriter, r *http.Request) {
 		Fecha:       updated.Fecha.Format("02/01/2006 15:04"),
 		EstadoPago:  updated.EstadoPago,
 		Comprobante: updated.Comprobante,
+		Estado:      updated.Estado,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
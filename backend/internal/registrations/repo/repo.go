@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 
+	eventrepo "project/backend/internal/events/repo"
 	"project/backend/prisma/db"
 )
 
@@ -65,17 +66,28 @@ func (r *Repository) FindAll(ctx context.Context) ([]db.InscripcionModel, error)
 	return r.client.Inscripcion.FindMany().Exec(ctx)
 }
 
-func (r *Repository) Create(ctx context.Context, eventoID, usuarioID int, estadoPago bool, comprobante string) (*db.InscripcionModel, error) {
-	return r.client.Inscripcion.CreateOne(
-		db.Inscripcion.Evento.Link(
-			db.Evento.IDEvento.Equals(eventoID),
-		),
-		db.Inscripcion.Usuario.Link(
-			db.Usuario.IDUsuario.Equals(usuarioID),
-		),
-		db.Inscripcion.EstadoPago.Set(estadoPago),
-		db.Inscripcion.Comprobante.Set(comprobante),
-	).Exec(ctx)
+// PagoPendienteRow is an unpaid inscription with the amount it owes. Monto
+// and Moneda are nil for inscriptions made without a ticket type.
+type PagoPendienteRow struct {
+	IDInscripcion int     `json:"id_inscripcion"`
+	IDEvento      int     `json:"id_evento"`
+	IDUsuario     int     `json:"id_usuario"`
+	Monto         *string `json:"monto"`
+	Moneda        *string `json:"moneda"`
+}
+
+// FindPagosPendientes returns the unpaid inscriptions that still owe
+// something; free tickets owe nothing.
+func (r *Repository) FindPagosPendientes(ctx context.Context) ([]PagoPendienteRow, error) {
+	query := `SELECT "id_inscripcion", "id_evento", "id_usuario", "monto"::text AS "monto", "moneda"
+		FROM "Inscripcion"
+		WHERE NOT "estado_pago" AND ("monto" IS NULL OR "monto" > 0)
+		ORDER BY "id_inscripcion"`
+	var rows []PagoPendienteRow
+	if err := r.client.Prisma.Raw.QueryRaw(query).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
 }
 
 func (r *Repository) UpdatePago(ctx context.Context, inscripcionID int, estadoPago bool, comprobante string) (*db.InscripcionModel, error) {
@@ -87,8 +99,43 @@ func (r *Repository) UpdatePago(ctx context.Context, inscripcionID int, estadoPa
 	).Exec(ctx)
 }
 
-func (r *Repository) GetAllEventos(ctx context.Context) ([]db.EventoModel, error) {
-	return r.client.Evento.FindMany().Exec(ctx)
+// GetAllEventos lists every event participants can see; drafts stay hidden
+// until they are published and archived events unless incluirArchivados.
+func (r *Repository) GetAllEventos(ctx context.Context, incluirArchivados bool) ([]eventrepo.EventoLocal, error) {
+	query := `SELECT * FROM "Evento" WHERE "estado" <> 'Borrador' AND (NOT "archivado" OR $1::boolean)`
+	var eventos []eventrepo.EventoLocal
+	if err := r.client.Prisma.Raw.QueryRaw(query, incluirArchivados).Exec(ctx, &eventos); err != nil {
+		return nil, err
+	}
+	return eventos, nil
+}
+
+func (r *Repository) FindEstadoEvento(ctx context.Context, id int) (string, error) {
+	query := `SELECT "estado" FROM "Evento" WHERE "id_evento" = $1`
+	var rows []struct {
+		Estado string `json:"estado"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", db.ErrNotFound
+	}
+	return rows[0].Estado, nil
+}
+
+func (r *Repository) FindZonaHorariaEvento(ctx context.Context, id int) (string, error) {
+	query := `SELECT "zona_horaria" FROM "Evento" WHERE "id_evento" = $1`
+	var rows []struct {
+		ZonaHoraria string `json:"zona_horaria"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", db.ErrNotFound
+	}
+	return rows[0].ZonaHoraria, nil
 }
 
 func (r *Repository) FindUsuariosNoInscritosEnEvento(ctx context.Context, eventoID int) ([]db.UsuarioModel, error) {
@@ -7,10 +7,15 @@ import (
 	"strings"
 	"time"
 
+	entradassrv "project/backend/internal/entradas/service"
+	"project/backend/internal/events/domain"
+	eventrepo "project/backend/internal/events/repo"
 	notificationdto "project/backend/internal/notifications/dto"
 	notificationsrv "project/backend/internal/notifications/service"
 	"project/backend/internal/registrations/dto"
 	"project/backend/internal/registrations/repo"
+	waitlistrepo "project/backend/internal/waitlist/repo"
+	waitlistsrv "project/backend/internal/waitlist/service"
 	"project/backend/prisma/db"
 )
 
@@ -27,12 +32,16 @@ var (
 type Service struct {
 	repo                *repo.Repository
 	notificationService notificationsrv.NotificationService
+	waitlist            *waitlistsrv.Service
+	entradas            *entradassrv.Service
 }
 
-func New(repository *repo.Repository, notificationService notificationsrv.NotificationService) *Service {
+func New(repository *repo.Repository, notificationService notificationsrv.NotificationService, waitlist *waitlistsrv.Service, entradas *entradassrv.Service) *Service {
 	return &Service{
 		repo:                repository,
 		notificationService: notificationService,
+		waitlist:            waitlist,
+		entradas:            entradas,
 	}
 }
 
@@ -47,7 +56,21 @@ func (s *Service) CreateInscripcion(ctx context.Context, req dto.CreateInscripci
 	}
 
 	// Check if inscripciones are open
-	if !isInscripcionesAbiertas(evento, now) {
+	fechas := domain.Fechas{
+		Inicio: evento.FechaInicio,
+		Fin:    evento.FechaFin,
+		Cierre: evento.FechaCierreInscripcion,
+	}
+	if !domain.InscripcionesAbiertas(evento.InscripcionesAbiertasManual, fechas, now) {
+		return nil, ErrInscripcionesCerradas
+	}
+
+	// Only published events accept inscriptions
+	estado, err := s.repo.FindEstadoEvento(ctx, req.EventoID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	if !domain.AdmiteInscripciones(estado) {
 		return nil, ErrInscripcionesCerradas
 	}
 
@@ -69,24 +92,64 @@ func (s *Service) CreateInscripcion(ctx context.Context, req dto.CreateInscripci
 		return nil, ErrYaInscrito
 	}
 
-	created, err := s.repo.Create(ctx, req.EventoID, req.UsuarioID, req.EstadoPago, req.Comprobante)
+	// Errors from the ticket types are returned as they are so the handler
+	// can tell the participant why the ticket was refused.
+	cotizacion, err := s.entradas.Cotizar(ctx, req.EventoID, req.TipoEntradaID, req.Codigo, req.UsuarioID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	reserva, err := s.waitlist.Reservar(ctx, waitlistrepo.NuevaInscripcion{
+		EventoID:      req.EventoID,
+		UsuarioID:     req.UsuarioID,
+		EstadoPago:    req.EstadoPago || cotizacion.SinCosto(),
+		Comprobante:   req.Comprobante,
+		TipoEntradaID: cotizacion.IDTipoEntrada,
+		Monto:         cotizacion.Monto,
+		Moneda:        cotizacion.Moneda,
+		CodigoID:      cotizacion.IDCodigo,
+		Descuento:     cotizacion.Descuento,
+	})
+	if err != nil {
+		if errors.Is(err, waitlistsrv.ErrEventoNotFound) {
+			return nil, ErrEventoNotFound
+		}
+		if errors.Is(err, waitlistsrv.ErrEntradaAgotada) {
+			return nil, entradassrv.ErrAgotada
+		}
+		if errors.Is(err, waitlistsrv.ErrCodigoAgotado) {
+			return nil, entradassrv.ErrCodigoAgotado
+		}
+		return nil, ErrDB
+	}
+
+	created, err := s.repo.FindByID(ctx, reserva.IDInscripcion)
 	if err != nil {
 		return nil, ErrDB
 	}
 
+	zona, err := s.repo.FindZonaHorariaEvento(ctx, req.EventoID)
+	if err != nil {
+		zona = domain.ZonaHorariaPredeterminada
+	}
+	tipo := notificationdto.NotificationTypeInscripcion
 	mensaje := fmt.Sprintf(
 		notificationdto.MsgInscripcionExitosa,
 		evento.Nombre,
-		evento.FechaInicio.Format("02/01/2006"),
-		evento.FechaFin.Format("02/01/2006"),
+		domain.FormatoConZona(evento.FechaInicio, zona),
+		domain.FormatoConZona(evento.FechaFin, zona),
 	)
+	if reserva.EnEspera() {
+		tipo = notificationdto.NotificationTypeListaEspera
+		mensaje = fmt.Sprintf(notificationdto.MsgListaEspera, evento.Nombre, reserva.PosicionEspera)
+	}
 
 	var notifErr error
 	for i := 1; i <= 3; i++ {
 		_, notifErr = s.notificationService.CreateNotification(ctx, notificationdto.CreateNotificationRequest{
 			UserID:  req.UsuarioID,
 			EventID: &req.EventoID,
-			Type:    notificationdto.NotificationTypeInscripcion,
+			Type:    tipo,
 			Message: mensaje,
 		})
 		if notifErr == nil {
@@ -149,21 +212,8 @@ func (s *Service) UpdatePago(ctx context.Context, inscripcionID int, estadoPago
 	return updated, nil
 }
 
-func isInscripcionesAbiertas(evento *db.EventoModel, now time.Time) bool {
-	if !evento.InscripcionesAbiertasManual {
-		return false
-	}
-	if !now.Before(evento.FechaCierreInscripcion) {
-		return false
-	}
-	if !now.Before(evento.FechaInicio) {
-		return false
-	}
-	return true
-}
-
-func (s *Service) GetAllEventos(ctx context.Context) ([]db.EventoModel, error) {
-	return s.repo.GetAllEventos(ctx)
+func (s *Service) GetAllEventos(ctx context.Context, incluirArchivados bool) ([]eventrepo.EventoLocal, error) {
+	return s.repo.GetAllEventos(ctx, incluirArchivados)
 }
 
 func (s *Service) MatchesEventFilters(ev db.EventoModel, filters dto.EventFilters) bool {
@@ -75,5 +75,6 @@ func ParseEventFilters(r *http.Request) (dto.EventFilters, error) {
 		CityTerm:    cityTerm,
 		FromDate:    fromDate,
 		ToDate:      toDate,
+		Archivados:  r.URL.Query().Get("archivados") == "true",
 	}, nil
 }
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func sampleDocument() Document {
+	doc := Document{
+		Version: CurrentVersion,
+		Permissions: []PermissionEntry{
+			{Name: "gestionar", Resource: "events.management"},
+			{Name: "inscribir", Resource: "events.inscription"},
+		},
+		Roles: []RoleEntry{
+			{Name: "ORGANIZADOR", Description: "Organiza eventos", Permissions: []string{"gestionar::events.management"}},
+			{Name: "PARTICIPANTE", Description: "Participa", Permissions: []string{"inscribir::events.inscription"}},
+		},
+	}
+	doc.Normalize()
+	return doc
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			doc := sampleDocument()
+			data, err := Encode(doc, format)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			decoded, err := Decode(data, format)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !reflect.DeepEqual(doc, decoded) {
+				t.Fatalf("expected %+v, got %+v", doc, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeValidation(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{"unsupported version", `{"version":2,"permissions":[],"roles":[]}`, ErrUnsupportedVersion},
+		{"undeclared permission", `{"version":1,"permissions":[],"roles":[{"name":"X","permissions":["a::b"]}]}`, ErrInvalidDocument},
+		{"role without name", `{"version":1,"permissions":[],"roles":[{"name":" "}]}`, ErrInvalidDocument},
+		{"malformed", `{"version":`, ErrInvalidDocument},
+		{"valid", `{"version":1,"permissions":[{"name":"a","resource":"b"}],"roles":[{"name":"X","permissions":["a::b","a::b"]}]}`, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode([]byte(tc.body), FormatJSON)
+			if tc.err == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	protected := map[string]bool{"ADMIN": true}
+
+	current := sampleDocument()
+	current.Permissions = append(current.Permissions, PermissionEntry{Name: "obsoleto"})
+	current.Roles = append(current.Roles,
+		RoleEntry{Name: "ADMIN", Description: "Administrador", Permissions: []string{}},
+		RoleEntry{Name: "TEMPORAL", Description: "Temporal", Permissions: []string{"obsoleto"}},
+	)
+	current.Normalize()
+
+	desired := sampleDocument()
+	desired.Permissions = append(desired.Permissions, PermissionEntry{Name: "gestionar", Resource: "roles.manage"})
+	desired.Roles[0].Description = "Organiza y gestiona eventos"
+	desired.Roles[0].Permissions = []string{"gestionar::roles.manage"}
+	desired.Roles = append(desired.Roles, RoleEntry{Name: "PONENTE", Description: "Ponente", Permissions: []string{"inscribir::events.inscription"}})
+	desired.Normalize()
+
+	cases := []struct {
+		name    string
+		current Document
+		desired Document
+		prune   bool
+		changes []Change
+		skipped []Change
+	}{
+		{
+			name:    "identical documents produce no changes",
+			current: sampleDocument(),
+			desired: sampleDocument(),
+			changes: []Change{},
+			skipped: []Change{},
+		},
+		{
+			name:    "without prune only creates and updates",
+			current: current,
+			desired: desired,
+			changes: []Change{
+				{Action: ChangeCreate, Entity: EntityPermission, Key: "gestionar::roles.manage"},
+				{Action: ChangeUpdate, Entity: EntityRole, Key: "ORGANIZADOR", Before: "Organiza eventos", After: "Organiza y gestiona eventos"},
+				{Action: ChangeCreate, Entity: EntityRolePermission, Key: "ORGANIZADOR", Target: "gestionar::roles.manage"},
+				{Action: ChangeDelete, Entity: EntityRolePermission, Key: "ORGANIZADOR", Target: "gestionar::events.management"},
+				{Action: ChangeCreate, Entity: EntityRole, Key: "PONENTE", After: "Ponente"},
+				{Action: ChangeCreate, Entity: EntityRolePermission, Key: "PONENTE", Target: "inscribir::events.inscription"},
+			},
+			skipped: []Change{},
+		},
+		{
+			name:    "prune deletes missing entries but keeps protected roles",
+			current: current,
+			desired: desired,
+			prune:   true,
+			changes: []Change{
+				{Action: ChangeCreate, Entity: EntityPermission, Key: "gestionar::roles.manage"},
+				{Action: ChangeUpdate, Entity: EntityRole, Key: "ORGANIZADOR", Before: "Organiza eventos", After: "Organiza y gestiona eventos"},
+				{Action: ChangeCreate, Entity: EntityRolePermission, Key: "ORGANIZADOR", Target: "gestionar::roles.manage"},
+				{Action: ChangeDelete, Entity: EntityRolePermission, Key: "ORGANIZADOR", Target: "gestionar::events.management"},
+				{Action: ChangeCreate, Entity: EntityRole, Key: "PONENTE", After: "Ponente"},
+				{Action: ChangeCreate, Entity: EntityRolePermission, Key: "PONENTE", Target: "inscribir::events.inscription"},
+				{Action: ChangeDelete, Entity: EntityRole, Key: "TEMPORAL", Before: "Temporal"},
+				{Action: ChangeDelete, Entity: EntityPermission, Key: "obsoleto"},
+			},
+			skipped: []Change{
+				{Action: ChangeDelete, Entity: EntityRole, Key: "ADMIN", Before: "Administrador"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := Diff(tc.current, tc.desired, tc.prune, protected)
+			if !reflect.DeepEqual(plan.Changes, tc.changes) {
+				t.Fatalf("expected changes %+v, got %+v", tc.changes, plan.Changes)
+			}
+			if !reflect.DeepEqual(plan.Skipped, tc.skipped) {
+				t.Fatalf("expected skipped %+v, got %+v", tc.skipped, plan.Skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the document format version written by Export. Import
+// rejects any other version.
+const CurrentVersion = 1
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("versión de configuración no soportada")
+	ErrInvalidDocument    = errors.New("documento de configuración inválido")
+)
+
+// Document is the portable description of the roles and permissions setup.
+// Role permissions reference permissions by their stored key, "name::resource"
+// (or just "name" when the permission has no resource).
+type Document struct {
+	Version     int               `json:"version" yaml:"version"`
+	ExportedAt  string            `json:"exported_at,omitempty" yaml:"exported_at,omitempty"`
+	Permissions []PermissionEntry `json:"permissions" yaml:"permissions"`
+	Roles       []RoleEntry       `json:"roles" yaml:"roles"`
+}
+
+type PermissionEntry struct {
+	Name     string `json:"name" yaml:"name"`
+	Resource string `json:"resource,omitempty" yaml:"resource,omitempty"`
+}
+
+type RoleEntry struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description" yaml:"description"`
+	Permissions []string `json:"permissions" yaml:"permissions"`
+}
+
+func (p PermissionEntry) Key() string {
+	name := strings.TrimSpace(p.Name)
+	resource := strings.TrimSpace(p.Resource)
+	if resource == "" {
+		return name
+	}
+	return name + "::" + resource
+}
+
+func PermissionFromKey(key string) PermissionEntry {
+	parts := strings.SplitN(key, "::", 2)
+	if len(parts) == 2 {
+		return PermissionEntry{Name: parts[0], Resource: parts[1]}
+	}
+	return PermissionEntry{Name: key}
+}
+
+// Normalize trims names, drops duplicate entries and sorts everything so two
+// documents describing the same setup serialize identically.
+func (d *Document) Normalize() {
+	seenPermissions := map[string]struct{}{}
+	permissions := make([]PermissionEntry, 0, len(d.Permissions))
+	for _, permission := range d.Permissions {
+		entry := PermissionEntry{Name: strings.TrimSpace(permission.Name), Resource: strings.TrimSpace(permission.Resource)}
+		if _, ok := seenPermissions[entry.Key()]; ok {
+			continue
+		}
+		seenPermissions[entry.Key()] = struct{}{}
+		permissions = append(permissions, entry)
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i].Key() < permissions[j].Key() })
+	d.Permissions = permissions
+
+	seenRoles := map[string]struct{}{}
+	roles := make([]RoleEntry, 0, len(d.Roles))
+	for _, role := range d.Roles {
+		name := strings.TrimSpace(role.Name)
+		if _, ok := seenRoles[name]; ok {
+			continue
+		}
+		seenRoles[name] = struct{}{}
+		roles = append(roles, RoleEntry{
+			Name:        name,
+			Description: strings.TrimSpace(role.Description),
+			Permissions: uniqueSorted(role.Permissions),
+		})
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	d.Roles = roles
+}
+
+// Validate checks the version and that every role permission is declared in
+// the permissions list.
+func (d Document) Validate() error {
+	if d.Version != CurrentVersion {
+		return ErrUnsupportedVersion
+	}
+	declared := map[string]struct{}{}
+	for _, permission := range d.Permissions {
+		if permission.Name == "" {
+			return fmt.Errorf("%w: permiso sin nombre", ErrInvalidDocument)
+		}
+		declared[permission.Key()] = struct{}{}
+	}
+	for _, role := range d.Roles {
+		if role.Name == "" {
+			return fmt.Errorf("%w: rol sin nombre", ErrInvalidDocument)
+		}
+		for _, key := range role.Permissions {
+			if _, ok := declared[key]; !ok {
+				return fmt.Errorf("%w: el rol '%s' referencia el permiso no declarado '%s'", ErrInvalidDocument, role.Name, key)
+			}
+		}
+	}
+	return nil
+}
+
+func Encode(doc Document, format string) ([]byte, error) {
+	if format == FormatYAML {
+		return yaml.Marshal(doc)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func Decode(data []byte, format string) (Document, error) {
+	var doc Document
+	var err error
+	if format == FormatYAML {
+		err = yaml.Unmarshal(data, &doc)
+	} else {
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return Document{}, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	doc.Normalize()
+	if err := doc.Validate(); err != nil {
+		return Document{}, err
+	}
+	return doc, nil
+}
+
+func newDocument(now time.Time) Document {
+	return Document{Version: CurrentVersion, ExportedAt: now.UTC().Format(time.RFC3339)}
+}
+
+func uniqueSorted(values []string) []string {
+	seen := map[string]struct{}{}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		result = append(result, trimmed)
+	}
+	sort.Strings(result)
+	return result
+}
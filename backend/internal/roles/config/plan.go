@@ -0,0 +1,124 @@
+package config
+
+import "strings"
+
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+const (
+	EntityPermission     = "permission"
+	EntityRole           = "role"
+	EntityRolePermission = "role_permission"
+)
+
+// Change is one step needed to make the database match the document. For
+// role permissions Key is the role name and Target the permission key.
+type Change struct {
+	Action string `json:"action" yaml:"action"`
+	Entity string `json:"entity" yaml:"entity"`
+	Key    string `json:"key" yaml:"key"`
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+	Before string `json:"before,omitempty" yaml:"before,omitempty"`
+	After  string `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+type Plan struct {
+	Changes []Change `json:"changes" yaml:"changes"`
+	// Skipped lists deletions that prune would perform but that are unsafe,
+	// such as removing ADMIN or a role still assigned to users.
+	Skipped []Change `json:"skipped" yaml:"skipped"`
+}
+
+func (p Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Diff computes the changes that turn current into desired. Permissions and
+// roles missing from desired are only deleted when prune is set; the
+// permission list of a role present in both documents always ends up matching
+// desired exactly. protectedRoles are never deleted.
+func Diff(current, desired Document, prune bool, protectedRoles map[string]bool) Plan {
+	plan := Plan{Changes: []Change{}, Skipped: []Change{}}
+
+	currentPermissions := map[string]struct{}{}
+	for _, permission := range current.Permissions {
+		currentPermissions[permission.Key()] = struct{}{}
+	}
+	desiredPermissions := map[string]struct{}{}
+	for _, permission := range desired.Permissions {
+		key := permission.Key()
+		desiredPermissions[key] = struct{}{}
+		if _, ok := currentPermissions[key]; !ok {
+			plan.Changes = append(plan.Changes, Change{Action: ChangeCreate, Entity: EntityPermission, Key: key})
+		}
+	}
+
+	currentRoles := map[string]RoleEntry{}
+	for _, role := range current.Roles {
+		currentRoles[role.Name] = role
+	}
+	desiredRoles := map[string]struct{}{}
+	for _, role := range desired.Roles {
+		desiredRoles[role.Name] = struct{}{}
+		existing, ok := currentRoles[role.Name]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Action: ChangeCreate, Entity: EntityRole, Key: role.Name, After: role.Description})
+			for _, key := range role.Permissions {
+				plan.Changes = append(plan.Changes, Change{Action: ChangeCreate, Entity: EntityRolePermission, Key: role.Name, Target: key})
+			}
+			continue
+		}
+		if existing.Description != role.Description {
+			plan.Changes = append(plan.Changes, Change{Action: ChangeUpdate, Entity: EntityRole, Key: role.Name, Before: existing.Description, After: role.Description})
+		}
+
+		existingLinks := toSet(existing.Permissions)
+		desiredLinks := toSet(role.Permissions)
+		for _, key := range role.Permissions {
+			if _, ok := existingLinks[key]; !ok {
+				plan.Changes = append(plan.Changes, Change{Action: ChangeCreate, Entity: EntityRolePermission, Key: role.Name, Target: key})
+			}
+		}
+		for _, key := range existing.Permissions {
+			if _, ok := desiredLinks[key]; !ok {
+				plan.Changes = append(plan.Changes, Change{Action: ChangeDelete, Entity: EntityRolePermission, Key: role.Name, Target: key})
+			}
+		}
+	}
+
+	if !prune {
+		return plan
+	}
+
+	for _, role := range current.Roles {
+		if _, ok := desiredRoles[role.Name]; ok {
+			continue
+		}
+		change := Change{Action: ChangeDelete, Entity: EntityRole, Key: role.Name, Before: role.Description}
+		if protectedRoles[strings.ToUpper(role.Name)] {
+			plan.Skipped = append(plan.Skipped, change)
+			continue
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	for _, permission := range current.Permissions {
+		key := permission.Key()
+		if _, ok := desiredPermissions[key]; ok {
+			continue
+		}
+		plan.Changes = append(plan.Changes, Change{Action: ChangeDelete, Entity: EntityPermission, Key: key})
+	}
+
+	return plan
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
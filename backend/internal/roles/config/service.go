@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"project/backend/prisma/db"
+)
+
+type Service struct {
+	client *db.PrismaClient
+}
+
+func NewService(client *db.PrismaClient) *Service {
+	return &Service{client: client}
+}
+
+type snapshot struct {
+	doc           Document
+	roleIDs       map[string]int
+	permissionIDs map[string]int
+	assignedRoles map[string]bool
+}
+
+func (s *Service) Export(ctx context.Context) (Document, error) {
+	snap, err := s.load(ctx)
+	if err != nil {
+		return Document{}, err
+	}
+	return snap.doc, nil
+}
+
+// Import makes the database match doc and returns the plan it applied. With
+// dryRun nothing is written. Re-importing the same document yields an empty
+// plan.
+func (s *Service) Import(ctx context.Context, doc Document, dryRun, prune bool) (Plan, error) {
+	snap, err := s.load(ctx)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	protected := map[string]bool{"ADMIN": true}
+	for name := range snap.assignedRoles {
+		protected[strings.ToUpper(name)] = true
+	}
+	plan := Diff(snap.doc, doc, prune, protected)
+	if dryRun || plan.Empty() {
+		return plan, nil
+	}
+
+	if err := s.apply(ctx, snap, plan); err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// apply runs the whole plan in one transaction so a failing change leaves the
+// configuration untouched. New rows are linked by their unique names because
+// their ids are not known until the transaction commits.
+func (s *Service) apply(ctx context.Context, snap *snapshot, plan Plan) error {
+	ops := []db.PrismaTransaction{}
+
+	// Creations first so new roles can link new permissions, deletions last so
+	// links are removed before the rows they reference.
+	for _, change := range plan.Changes {
+		if change.Action != ChangeCreate {
+			continue
+		}
+		switch change.Entity {
+		case EntityPermission:
+			ops = append(ops, s.client.Permisos.CreateOne(
+				db.Permisos.NombrePermiso.Set(change.Key),
+			).Tx())
+		case EntityRole:
+			ops = append(ops, s.client.Roles.CreateOne(
+				db.Roles.NombreRol.Set(change.Key),
+				db.Roles.Descripcion.Set(change.After),
+			).Tx())
+		}
+	}
+
+	for _, change := range plan.Changes {
+		switch {
+		case change.Action == ChangeUpdate && change.Entity == EntityRole:
+			ops = append(ops, s.client.Roles.FindUnique(
+				db.Roles.NombreRol.Equals(change.Key),
+			).Update(
+				db.Roles.Descripcion.Set(change.After),
+			).Tx())
+		case change.Action == ChangeCreate && change.Entity == EntityRolePermission:
+			ops = append(ops, s.client.RolePermisos.CreateOne(
+				db.RolePermisos.Rol.Link(db.Roles.NombreRol.Equals(change.Key)),
+				db.RolePermisos.Permiso.Link(db.Permisos.NombrePermiso.Equals(change.Target)),
+			).Tx())
+		case change.Action == ChangeDelete && change.Entity == EntityRolePermission:
+			ops = append(ops, s.client.RolePermisos.FindMany(
+				db.RolePermisos.IDRol.Equals(snap.roleIDs[change.Key]),
+				db.RolePermisos.IDPermiso.Equals(snap.permissionIDs[change.Target]),
+			).Delete().Tx())
+		}
+	}
+
+	for _, change := range plan.Changes {
+		if change.Action != ChangeDelete {
+			continue
+		}
+		switch change.Entity {
+		case EntityRole:
+			roleID := snap.roleIDs[change.Key]
+			ops = append(ops,
+				s.client.RolePermisos.FindMany(db.RolePermisos.IDRol.Equals(roleID)).Delete().Tx(),
+				s.client.Roles.FindUnique(db.Roles.IDRol.Equals(roleID)).Delete().Tx(),
+			)
+		case EntityPermission:
+			permissionID := snap.permissionIDs[change.Key]
+			ops = append(ops,
+				s.client.RolePermisos.FindMany(db.RolePermisos.IDPermiso.Equals(permissionID)).Delete().Tx(),
+				s.client.Permisos.FindUnique(db.Permisos.IDPermiso.Equals(permissionID)).Delete().Tx(),
+			)
+		}
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+	return s.client.Prisma.Transaction(ops...).Exec(ctx)
+}
+
+func (s *Service) load(ctx context.Context) (*snapshot, error) {
+	permissions, err := s.client.Permisos.FindMany().Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.client.Roles.FindMany().With(
+		db.Roles.RolePermisos.Fetch().With(db.RolePermisos.Permiso.Fetch()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.client.UsuarioRoles.FindMany().With(db.UsuarioRoles.Rol.Fetch()).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &snapshot{
+		doc:           newDocument(time.Now()),
+		roleIDs:       map[string]int{},
+		permissionIDs: map[string]int{},
+		assignedRoles: map[string]bool{},
+	}
+	for _, permission := range permissions {
+		snap.permissionIDs[permission.NombrePermiso] = permission.IDPermiso
+		snap.doc.Permissions = append(snap.doc.Permissions, PermissionFromKey(permission.NombrePermiso))
+	}
+	for _, role := range roles {
+		snap.roleIDs[role.NombreRol] = role.IDRol
+		entry := RoleEntry{Name: role.NombreRol, Description: role.Descripcion, Permissions: []string{}}
+		for _, link := range role.RelationsRoles.RolePermisos {
+			if link.RelationsRolePermisos.Permiso != nil {
+				entry.Permissions = append(entry.Permissions, link.RelationsRolePermisos.Permiso.NombrePermiso)
+			}
+		}
+		snap.doc.Roles = append(snap.doc.Roles, entry)
+	}
+	for _, assignment := range assignments {
+		if assignment.RelationsUsuarioRoles.Rol != nil {
+			snap.assignedRoles[assignment.RelationsUsuarioRoles.Rol.NombreRol] = true
+		}
+	}
+	snap.doc.Normalize()
+	return snap, nil
+}
@@ -0,0 +1,41 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	notificationsrepo "project/backend/internal/notifications/repo"
+	roles "project/backend/internal/roles/service"
+	"project/backend/prisma/db"
+
+	"github.com/robfig/cron/v3"
+)
+
+func StartExpiracionRolesScheduler(prismaClient *db.PrismaClient) {
+	requestService := roles.NewRoleRequestService(prismaClient)
+	jobExecutionRepo := notificationsrepo.NewJobExecutionRepository(prismaClient)
+	jobName := "expiracion_roles"
+
+	runExpiracion(requestService, jobExecutionRepo, jobName)
+
+	c := cron.New()
+	c.AddFunc("@every 5m", func() {
+		runExpiracion(requestService, jobExecutionRepo, jobName)
+	})
+	c.Start()
+}
+
+func runExpiracion(requestService *roles.RoleRequestService, jobExecutionRepo *notificationsrepo.JobExecutionRepository, jobName string) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	expired, err := requestService.ExpirarAsignaciones(ctx, now)
+	if err != nil {
+		log.Println("[Roles] Error al expirar asignaciones de rol:", err)
+		return
+	}
+	if expired > 0 {
+		log.Println("[Roles] Asignaciones de rol expiradas:", expired)
+	}
+	_ = jobExecutionRepo.UpsertLastRun(ctx, jobName, now)
+}
@@ -0,0 +1,31 @@
+package dto
+
+// CreateSolicitudRolRequest is filed for the authenticated caller; IDUsuario
+// is set by the handler and never read from the body.
+type CreateSolicitudRolRequest struct {
+	IDUsuario  int    `json:"-"`
+	Rol        string `json:"rol"`
+	Motivo     string `json:"motivo"`
+	ValidFrom  string `json:"valid_from"`
+	ValidUntil string `json:"valid_until"`
+}
+
+type ResolverSolicitudRolRequest struct {
+	RevisadoPor int    `json:"revisado_por"`
+	Nota        string `json:"nota"`
+}
+
+type SolicitudRolResponse struct {
+	IDSolicitud   int     `json:"id_solicitud"`
+	IDUsuario     int     `json:"id_usuario"`
+	UsuarioNombre string  `json:"usuario_nombre"`
+	IDRol         int     `json:"id_rol"`
+	Rol           string  `json:"rol"`
+	Estado        string  `json:"estado"`
+	Motivo        string  `json:"motivo"`
+	ValidFrom     *string `json:"valid_from,omitempty"`
+	ValidUntil    *string `json:"valid_until,omitempty"`
+	RevisadoPor   *int    `json:"revisado_por,omitempty"`
+	Nota          *string `json:"nota,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	rolesconfig "project/backend/internal/roles/config"
+	"project/backend/internal/shared/response"
+)
+
+const maxConfigSize = 1 << 20
+
+// GET /api/roles/export?format=json|yaml
+func (h *Handler) exportConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.WriteError(w, http.StatusMethodNotAllowed, response.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if !h.authorizeRolesManage(ctx, w, r) {
+		return
+	}
+
+	doc, err := h.config.Export(ctx)
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, response.ErrDatabase)
+		return
+	}
+
+	format := configFormat(r.URL.Query().Get("format"), "")
+	data, err := rolesconfig.Encode(doc, format)
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, response.ErrInternalServer)
+		return
+	}
+
+	contentType := "application/json"
+	if format == rolesconfig.FormatYAML {
+		contentType = "application/yaml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=roles-config."+format)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// POST /api/roles/import?dry_run=true&prune=true
+// The body is a document produced by export, as JSON or YAML.
+func (h *Handler) importConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.WriteError(w, http.StatusMethodNotAllowed, response.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	if !h.authorizeRolesManage(ctx, w, r) {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxConfigSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.WriteJSON(w, http.StatusRequestEntityTooLarge, response.ErrInvalidJSON, map[string]any{"error": "el documento supera el tamaño máximo permitido"})
+			return
+		}
+		response.WriteError(w, http.StatusBadRequest, response.ErrInvalidJSON)
+		return
+	}
+
+	doc, err := rolesconfig.Decode(body, configFormat(r.URL.Query().Get("format"), r.Header.Get("Content-Type")))
+	if err != nil {
+		if errors.Is(err, rolesconfig.ErrUnsupportedVersion) || errors.Is(err, rolesconfig.ErrInvalidDocument) {
+			response.WriteJSON(w, http.StatusBadRequest, response.ErrInvalidJSON, map[string]any{"error": err.Error()})
+			return
+		}
+		response.WriteError(w, http.StatusBadRequest, response.ErrInvalidJSON)
+		return
+	}
+
+	dryRun := queryBool(r, "dry_run")
+	plan, err := h.config.Import(ctx, doc, dryRun, queryBool(r, "prune"))
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, response.ErrDatabase)
+		return
+	}
+
+	response.WriteSuccess(w, http.StatusOK, response.SuccessGeneral, map[string]any{
+		"dry_run": dryRun,
+		"changes": plan.Changes,
+		"skipped": plan.Skipped,
+	})
+}
+
+func configFormat(query, contentType string) string {
+	value := strings.ToLower(strings.TrimSpace(query))
+	if value == "" {
+		value = strings.ToLower(contentType)
+	}
+	if strings.Contains(value, "yaml") || value == "yml" {
+		return rolesconfig.FormatYAML
+	}
+	return rolesconfig.FormatJSON
+}
+
+func queryBool(r *http.Request, key string) bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(r.URL.Query().Get(key)))
+	return err == nil && value
+}
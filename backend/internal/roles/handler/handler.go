@@ -8,12 +8,17 @@ import (
 	"strings"
 	"time"
 
+	rolesconfig "project/backend/internal/roles/config"
+	roles "project/backend/internal/roles/service"
 	"project/backend/internal/shared/response"
 	"project/backend/prisma/db"
 )
 
 type Handler struct {
-	client *db.PrismaClient
+	client      *db.PrismaClient
+	roleService roles.UserRoleService
+	requests    *roles.RoleRequestService
+	config      *rolesconfig.Service
 }
 
 type rolePayload struct {
@@ -26,7 +31,12 @@ type rolePermissionsPayload struct {
 }
 
 func New(client *db.PrismaClient) http.Handler {
-	return &Handler{client: client}
+	return &Handler{
+		client:      client,
+		roleService: roles.NewUserRoleService(client),
+		requests:    roles.NewRoleRequestService(client),
+		config:      rolesconfig.NewService(client),
+	}
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -48,6 +58,21 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if segments[2] == "requests" {
+		h.serveRoleRequests(w, r, segments[3:])
+		return
+	}
+
+	if len(segments) == 3 && segments[2] == "export" {
+		h.exportConfig(w, r)
+		return
+	}
+
+	if len(segments) == 3 && segments[2] == "import" {
+		h.importConfig(w, r)
+		return
+	}
+
 	if len(segments) == 3 {
 		roleID, ok := parseID(segments[2])
 		if !ok {
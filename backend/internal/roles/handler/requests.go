@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"project/backend/internal/policy"
+	"project/backend/internal/roles/dto"
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/shared/response"
+)
+
+// serveRoleRequests handles /api/roles/requests[/{id}/approve|reject].
+func (h *Handler) serveRoleRequests(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			h.listRoleRequests(w, r)
+		case http.MethodPost:
+			h.createRoleRequest(w, r)
+		default:
+			response.WriteError(w, http.StatusMethodNotAllowed, response.ErrMethodNotAllowed)
+		}
+		return
+	}
+
+	if len(rest) == 2 && (rest[1] == "approve" || rest[1] == "reject") {
+		if r.Method != http.MethodPost {
+			response.WriteError(w, http.StatusMethodNotAllowed, response.ErrMethodNotAllowed)
+			return
+		}
+		requestID, ok := parseID(rest[0])
+		if !ok {
+			response.WriteError(w, http.StatusBadRequest, response.ErrMissingFields)
+			return
+		}
+		h.resolveRoleRequest(w, r, requestID, rest[1] == "approve")
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+func (h *Handler) listRoleRequests(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	userID := 0
+	if raw := strings.TrimSpace(r.URL.Query().Get("id_usuario")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			response.WriteError(w, http.StatusBadRequest, response.ErrMissingFields)
+			return
+		}
+		userID = parsed
+	}
+
+	// Signed-in users may list their own requests; anything else requires roles.manage.
+	subject := policy.SubjectFromRequest(r)
+	propias := userID != 0 && subject.Authenticated() && userID == subject.UserID
+	if !propias && !h.authorizeRolesManage(ctx, w, r) {
+		return
+	}
+
+	items, err := h.requests.ListSolicitudes(ctx, r.URL.Query().Get("estado"), userID)
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, response.ErrDatabase)
+		return
+	}
+
+	response.WriteSuccess(w, http.StatusOK, response.SuccessGeneral, map[string]any{
+		"requests": items,
+	})
+}
+
+func (h *Handler) createRoleRequest(w http.ResponseWriter, r *http.Request) {
+	var payload dto.CreateSolicitudRolRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		response.WriteError(w, http.StatusBadRequest, response.ErrInvalidJSON)
+		return
+	}
+
+	// Requests are always filed for the caller, never for the id in the body.
+	subject := policy.SubjectFromRequest(r)
+	if !subject.Authenticated() {
+		response.WriteError(w, http.StatusUnauthorized, response.ErrUnauthenticated)
+		return
+	}
+	payload.IDUsuario = subject.UserID
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	created, err := h.requests.CreateSolicitud(ctx, payload)
+	if err != nil {
+		writeRoleRequestError(w, err)
+		return
+	}
+
+	response.WriteSuccess(w, http.StatusCreated, response.SuccessGeneral, map[string]any{
+		"request": created,
+	})
+}
+
+func (h *Handler) resolveRoleRequest(w http.ResponseWriter, r *http.Request, requestID int, approve bool) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if !h.authorizeRolesManage(ctx, w, r) {
+		return
+	}
+
+	var payload dto.ResolverSolicitudRolRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			response.WriteError(w, http.StatusBadRequest, response.ErrInvalidJSON)
+			return
+		}
+	}
+
+	var (
+		resolved *dto.SolicitudRolResponse
+		err      error
+	)
+	if approve {
+		resolved, err = h.requests.AprobarSolicitud(ctx, requestID, payload)
+	} else {
+		resolved, err = h.requests.RechazarSolicitud(ctx, requestID, payload)
+	}
+	if err != nil {
+		writeRoleRequestError(w, err)
+		return
+	}
+
+	response.WriteSuccess(w, http.StatusOK, response.SuccessGeneral, map[string]any{
+		"request": resolved,
+	})
+}
+
+// authorizeRolesManage writes the error response itself and reports whether
+// the caller may continue.
+func (h *Handler) authorizeRolesManage(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+	subject := policy.SubjectFromRequest(r)
+	if len(subject.Roles) == 0 {
+		response.WriteError(w, http.StatusForbidden, response.ErrForbidden)
+		return false
+	}
+
+	allowed, err := roles.AuthorizeSubject(ctx, h.roleService, subject, "roles.manage")
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, response.ErrDatabase)
+		return false
+	}
+	if !allowed {
+		response.WriteError(w, http.StatusForbidden, response.ErrForbidden)
+		return false
+	}
+	return true
+}
+
+func writeRoleRequestError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, roles.ErrSolicitudInvalida), errors.Is(err, roles.ErrVigenciaInvalida):
+		response.WriteError(w, http.StatusBadRequest, response.ErrMissingFields)
+	case errors.Is(err, roles.ErrRolNotFound):
+		response.WriteError(w, http.StatusNotFound, response.ErrRoleInvalid)
+	case errors.Is(err, roles.ErrSolicitudNotFound):
+		response.WriteError(w, http.StatusNotFound, response.ErrNotFound)
+	case errors.Is(err, roles.ErrSolicitudDuplicada), errors.Is(err, roles.ErrSolicitudResuelta):
+		response.WriteError(w, http.StatusConflict, response.ErrConflict)
+	default:
+		response.WriteError(w, http.StatusInternalServerError, response.ErrDatabase)
+	}
+}
@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"project/backend/prisma/db"
+)
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+type SolicitudRolRow struct {
+	IDSolicitud   int        `json:"id_solicitud"`
+	IDUsuario     int        `json:"id_usuario"`
+	UsuarioNombre string     `json:"usuario_nombre"`
+	IDRol         int        `json:"id_rol"`
+	RolNombre     string     `json:"rol_nombre"`
+	Estado        string     `json:"estado"`
+	Motivo        string     `json:"motivo"`
+	ValidFrom     *time.Time `json:"valid_from"`
+	ValidUntil    *time.Time `json:"valid_until"`
+	RevisadoPor   *int       `json:"revisado_por"`
+	Nota          *string    `json:"nota"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+type RoleGrantRow struct {
+	IDUsuario  int        `json:"id_usuario"`
+	IDRol      int        `json:"id_rol"`
+	RolNombre  string     `json:"rol_nombre"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+}
+
+const solicitudSelect = `SELECT s."id_solicitud", s."id_usuario", u."nombre" AS "usuario_nombre", s."id_rol", r."nombre_rol" AS "rol_nombre",
+		s."estado", s."motivo", s."valid_from", s."valid_until", s."revisado_por", s."nota", s."createdAt"
+		FROM "SolicitudRol" s
+		JOIN "Usuario" u ON u."id_usuario" = s."id_usuario"
+		JOIN "Roles" r ON r."id_rol" = s."id_rol"`
+
+// GrantRole assigns a role to a user, replacing the validity window when the
+// grant already exists. Nil bounds mean the grant is open on that side.
+func (r *Repository) GrantRole(ctx context.Context, userID, roleID int, validFrom, validUntil *time.Time) error {
+	query := `INSERT INTO "UsuarioRoles" ("id_usuario", "id_rol", "valid_from", "valid_until")
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ("id_usuario", "id_rol") DO UPDATE SET "valid_from" = EXCLUDED."valid_from", "valid_until" = EXCLUDED."valid_until"`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, userID, roleID, validFrom, validUntil).Exec(ctx)
+	return err
+}
+
+// ExpireGrants deletes the grants whose validity window ended by now and
+// returns them. The condition is checked by the delete itself, so a grant
+// renewed after it was due is kept.
+func (r *Repository) ExpireGrants(ctx context.Context, now time.Time) ([]RoleGrantRow, error) {
+	query := `WITH "vencidas" AS (
+			DELETE FROM "UsuarioRoles"
+			WHERE "valid_until" IS NOT NULL AND "valid_until" <= $1
+			RETURNING "id_usuario", "id_rol", "valid_from", "valid_until"
+		)
+		SELECT v."id_usuario", v."id_rol", r."nombre_rol" AS "rol_nombre", v."valid_from", v."valid_until"
+		FROM "vencidas" v
+		JOIN "Roles" r ON r."id_rol" = v."id_rol"`
+	var rows []RoleGrantRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, now).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListActiveRoleNames returns the roles of the user whose validity window
+// includes the current time.
+func (r *Repository) ListActiveRoleNames(ctx context.Context, userID int) ([]string, error) {
+	query := `SELECT r."nombre_rol"
+		FROM "UsuarioRoles" ur
+		JOIN "Roles" r ON r."id_rol" = ur."id_rol"
+		WHERE ur."id_usuario" = $1
+			AND (ur."valid_from" IS NULL OR ur."valid_from" <= NOW())
+			AND (ur."valid_until" IS NULL OR ur."valid_until" > NOW())`
+	var rows []struct {
+		NombreRol string `json:"nombre_rol"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, userID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.NombreRol)
+	}
+	return names, nil
+}
+
+func (r *Repository) ListAdminUserIDs(ctx context.Context) ([]int, error) {
+	query := `SELECT DISTINCT ur."id_usuario"
+		FROM "UsuarioRoles" ur
+		JOIN "Roles" r ON r."id_rol" = ur."id_rol"
+		WHERE UPPER(r."nombre_rol") = 'ADMIN'
+			AND (ur."valid_from" IS NULL OR ur."valid_from" <= NOW())
+			AND (ur."valid_until" IS NULL OR ur."valid_until" > NOW())`
+	var rows []struct {
+		IDUsuario int `json:"id_usuario"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.IDUsuario)
+	}
+	return ids, nil
+}
+
+func (r *Repository) CreateSolicitud(ctx context.Context, userID, roleID int, motivo string, validFrom, validUntil *time.Time) (int, error) {
+	query := `INSERT INTO "SolicitudRol" ("id_usuario", "id_rol", "estado", "motivo", "valid_from", "valid_until", "createdAt", "updatedAt")
+		VALUES ($1, $2, 'Pendiente', $3, $4, $5, NOW(), NOW())
+		RETURNING "id_solicitud"`
+	var rows []struct {
+		ID int `json:"id_solicitud"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, userID, roleID, strings.TrimSpace(motivo), validFrom, validUntil).Exec(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no rows")
+	}
+	return rows[0].ID, nil
+}
+
+func (r *Repository) ExistsPendingSolicitud(ctx context.Context, userID, roleID int) (bool, error) {
+	query := `SELECT "id_solicitud" FROM "SolicitudRol" WHERE "id_usuario" = $1 AND "id_rol" = $2 AND "estado" = 'Pendiente' LIMIT 1`
+	var rows []struct {
+		ID int `json:"id_solicitud"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, userID, roleID).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (r *Repository) FindSolicitudByID(ctx context.Context, id int) (*SolicitudRolRow, error) {
+	query := solicitudSelect + ` WHERE s."id_solicitud" = $1 LIMIT 1`
+	var rows []SolicitudRolRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return &rows[0], nil
+}
+
+func (r *Repository) ListSolicitudes(ctx context.Context, estado string, userID int) ([]SolicitudRolRow, error) {
+	query := solicitudSelect + ` WHERE 1=1`
+	params := make([]interface{}, 0)
+	if strings.TrimSpace(estado) != "" {
+		params = append(params, strings.TrimSpace(estado))
+		query += fmt.Sprintf(` AND s."estado" = $%d`, len(params))
+	}
+	if userID > 0 {
+		params = append(params, userID)
+		query += fmt.Sprintf(` AND s."id_usuario" = $%d`, len(params))
+	}
+	query += ` ORDER BY s."createdAt" DESC`
+
+	var rows []SolicitudRolRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ResolveSolicitud moves a pending request to its final state. It reports
+// false when the request was no longer pending.
+func (r *Repository) ResolveSolicitud(ctx context.Context, id int, estado string, revisadoPor int, nota string) (bool, error) {
+	query := `UPDATE "SolicitudRol"
+		SET "estado" = $2, "revisado_por" = NULLIF($3, 0), "nota" = NULLIF($4, ''), "updatedAt" = NOW()
+		WHERE "id_solicitud" = $1 AND "estado" = 'Pendiente'`
+	count, err := r.client.Prisma.Raw.ExecuteRaw(query, id, estado, revisadoPor, strings.TrimSpace(nota)).Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	return count.Count > 0, nil
+}
+
+// ApproveSolicitud marks a pending request as approved and grants its role
+// for the requested window in the same statement, so a request resolved
+// concurrently never leaves a grant behind. It reports false when the request
+// was no longer pending.
+func (r *Repository) ApproveSolicitud(ctx context.Context, id int, estado string, revisadoPor int, nota string) (bool, error) {
+	query := `WITH "resuelta" AS (
+			UPDATE "SolicitudRol"
+			SET "estado" = $2, "revisado_por" = NULLIF($3, 0), "nota" = NULLIF($4, ''), "updatedAt" = NOW()
+			WHERE "id_solicitud" = $1 AND "estado" = 'Pendiente'
+			RETURNING "id_usuario", "id_rol", "valid_from", "valid_until"
+		)
+		INSERT INTO "UsuarioRoles" ("id_usuario", "id_rol", "valid_from", "valid_until")
+		SELECT "id_usuario", "id_rol", "valid_from", "valid_until" FROM "resuelta"
+		ON CONFLICT ("id_usuario", "id_rol") DO UPDATE SET "valid_from" = EXCLUDED."valid_from", "valid_until" = EXCLUDED."valid_until"
+		RETURNING "id_usuario"`
+	var rows []struct {
+		IDUsuario int `json:"id_usuario"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, id, estado, revisadoPor, strings.TrimSpace(nota)).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
@@ -0,0 +1,41 @@
+package roles
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"project/backend/internal/policy"
+	"project/backend/internal/shared/httperror"
+)
+
+const eventsManagementKey = "events.management"
+
+// CanManageEvents reports whether the caller of r may manage events. A
+// missing role service or a failed lookup denies access.
+func CanManageEvents(ctx context.Context, svc UserRoleService, r *http.Request) bool {
+	if svc == nil {
+		return false
+	}
+	allowed, err := AuthorizeSubject(ctx, svc, policy.SubjectFromRequest(r), eventsManagementKey)
+	return err == nil && allowed
+}
+
+// AuthorizeEventsManagement reports whether the caller of r may manage
+// events. Otherwise it writes the error response, with forbidden as the
+// message of a 403, and the handler must stop.
+func AuthorizeEventsManagement(w http.ResponseWriter, r *http.Request, svc UserRoleService, forbidden string) bool {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	allowed, err := AuthorizeSubject(ctx, svc, policy.SubjectFromRequest(r), eventsManagementKey)
+	if err != nil {
+		httperror.WriteJSON(w, http.StatusInternalServerError, "error verificando permisos")
+		return false
+	}
+	if !allowed {
+		httperror.WriteJSON(w, http.StatusForbidden, forbidden)
+		return false
+	}
+	return true
+}
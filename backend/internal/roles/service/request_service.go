@@ -0,0 +1,269 @@
+package roles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	notificationdto "project/backend/internal/notifications/dto"
+	notificationsrv "project/backend/internal/notifications/service"
+	"project/backend/internal/roles/dto"
+	rolesrepo "project/backend/internal/roles/repo"
+	"project/backend/prisma/db"
+)
+
+const (
+	SolicitudPendiente = "Pendiente"
+	SolicitudAprobada  = "Aprobada"
+	SolicitudRechazada = "Rechazada"
+)
+
+var (
+	ErrSolicitudNotFound  = errors.New("solicitud de rol no encontrada")
+	ErrSolicitudResuelta  = errors.New("la solicitud de rol ya fue resuelta")
+	ErrSolicitudDuplicada = errors.New("ya existe una solicitud pendiente para este rol")
+	ErrSolicitudInvalida  = errors.New("solicitud de rol inválida")
+	ErrRolNotFound        = errors.New("rol no encontrado")
+	ErrVigenciaInvalida   = errors.New("la vigencia del rol es inválida")
+	ErrRequestDB          = errors.New("db error")
+)
+
+type RoleRequestService struct {
+	client        *db.PrismaClient
+	repo          *rolesrepo.Repository
+	notifications notificationsrv.NotificationService
+}
+
+func NewRoleRequestService(client *db.PrismaClient) *RoleRequestService {
+	return &RoleRequestService{
+		client:        client,
+		repo:          rolesrepo.New(client),
+		notifications: notificationsrv.NewNotificationServiceFromClient(client),
+	}
+}
+
+func (s *RoleRequestService) CreateSolicitud(ctx context.Context, req dto.CreateSolicitudRolRequest) (*dto.SolicitudRolResponse, error) {
+	roleName := strings.TrimSpace(req.Rol)
+	if req.IDUsuario <= 0 || roleName == "" {
+		return nil, ErrSolicitudInvalida
+	}
+	validFrom, validUntil, err := ParseVigencia(req.ValidFrom, req.ValidUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.client.Roles.FindUnique(db.Roles.NombreRol.Equals(roleName)).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrRolNotFound
+		}
+		return nil, ErrRequestDB
+	}
+	if isAdminRoleName(role.NombreRol) {
+		return nil, ErrSolicitudInvalida
+	}
+
+	exists, err := s.repo.ExistsPendingSolicitud(ctx, req.IDUsuario, role.IDRol)
+	if err != nil {
+		return nil, ErrRequestDB
+	}
+	if exists {
+		return nil, ErrSolicitudDuplicada
+	}
+
+	id, err := s.repo.CreateSolicitud(ctx, req.IDUsuario, role.IDRol, req.Motivo, validFrom, validUntil)
+	if err != nil {
+		return nil, ErrRequestDB
+	}
+
+	solicitud, err := s.repo.FindSolicitudByID(ctx, id)
+	if err != nil {
+		return nil, ErrRequestDB
+	}
+
+	admins, err := s.repo.ListAdminUserIDs(ctx)
+	if err == nil {
+		mensaje := fmt.Sprintf(notificationdto.MsgSolicitudRol, solicitud.UsuarioNombre, solicitud.RolNombre)
+		for _, adminID := range admins {
+			_, _ = s.notifications.CreateNotification(ctx, notificationdto.CreateNotificationRequest{
+				UserID:  adminID,
+				Type:    notificationdto.NotificationTypeSolicitudRol,
+				Message: mensaje,
+			})
+		}
+	}
+
+	return toSolicitudResponse(*solicitud), nil
+}
+
+func (s *RoleRequestService) ListSolicitudes(ctx context.Context, estado string, userID int) ([]dto.SolicitudRolResponse, error) {
+	rows, err := s.repo.ListSolicitudes(ctx, estado, userID)
+	if err != nil {
+		return nil, ErrRequestDB
+	}
+	items := make([]dto.SolicitudRolResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, *toSolicitudResponse(row))
+	}
+	return items, nil
+}
+
+// AprobarSolicitud grants the requested role for the requested window and
+// notifies the requester.
+func (s *RoleRequestService) AprobarSolicitud(ctx context.Context, id int, req dto.ResolverSolicitudRolRequest) (*dto.SolicitudRolResponse, error) {
+	solicitud, err := s.findPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.ApproveSolicitud(ctx, id, SolicitudAprobada, req.RevisadoPor, req.Nota)
+	if err != nil {
+		return nil, ErrRequestDB
+	}
+	if !updated {
+		return nil, ErrSolicitudResuelta
+	}
+
+	mensaje := fmt.Sprintf(notificationdto.MsgRolAprobado, solicitud.RolNombre, describeVigencia(solicitud.ValidFrom, solicitud.ValidUntil))
+	s.notifyRequester(ctx, solicitud.IDUsuario, notificationdto.NotificationTypeRolAprobado, mensaje)
+
+	return s.reload(ctx, id)
+}
+
+func (s *RoleRequestService) RechazarSolicitud(ctx context.Context, id int, req dto.ResolverSolicitudRolRequest) (*dto.SolicitudRolResponse, error) {
+	solicitud, err := s.findPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.ResolveSolicitud(ctx, id, SolicitudRechazada, req.RevisadoPor, req.Nota)
+	if err != nil {
+		return nil, ErrRequestDB
+	}
+	if !updated {
+		return nil, ErrSolicitudResuelta
+	}
+
+	nota := strings.TrimSpace(req.Nota)
+	if nota == "" {
+		nota = "sin observaciones"
+	}
+	mensaje := fmt.Sprintf(notificationdto.MsgRolRechazado, solicitud.RolNombre, nota)
+	s.notifyRequester(ctx, solicitud.IDUsuario, notificationdto.NotificationTypeRolRechazado, mensaje)
+
+	return s.reload(ctx, id)
+}
+
+// ExpirarAsignaciones removes role grants whose validity window has ended and
+// notifies the affected users. It returns the number of removed grants.
+func (s *RoleRequestService) ExpirarAsignaciones(ctx context.Context, now time.Time) (int, error) {
+	grants, err := s.repo.ExpireGrants(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, grant := range grants {
+		mensaje := fmt.Sprintf(notificationdto.MsgRolExpirado, grant.RolNombre)
+		s.notifyRequester(ctx, grant.IDUsuario, notificationdto.NotificationTypeRolExpirado, mensaje)
+	}
+	return len(grants), nil
+}
+
+func (s *RoleRequestService) findPending(ctx context.Context, id int) (*rolesrepo.SolicitudRolRow, error) {
+	solicitud, err := s.repo.FindSolicitudByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrSolicitudNotFound
+		}
+		return nil, ErrRequestDB
+	}
+	if solicitud.Estado != SolicitudPendiente {
+		return nil, ErrSolicitudResuelta
+	}
+	return solicitud, nil
+}
+
+func (s *RoleRequestService) reload(ctx context.Context, id int) (*dto.SolicitudRolResponse, error) {
+	solicitud, err := s.repo.FindSolicitudByID(ctx, id)
+	if err != nil {
+		return nil, ErrRequestDB
+	}
+	return toSolicitudResponse(*solicitud), nil
+}
+
+func (s *RoleRequestService) notifyRequester(ctx context.Context, userID int, tipo, mensaje string) {
+	_, _ = s.notifications.CreateNotification(ctx, notificationdto.CreateNotificationRequest{
+		UserID:  userID,
+		Type:    tipo,
+		Message: mensaje,
+	})
+}
+
+// ParseVigencia parses optional RFC3339 bounds for a role grant. Empty values
+// leave the corresponding side of the window open.
+func ParseVigencia(rawFrom, rawUntil string) (*time.Time, *time.Time, error) {
+	var validFrom, validUntil *time.Time
+	if value := strings.TrimSpace(rawFrom); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, nil, ErrVigenciaInvalida
+		}
+		validFrom = &parsed
+	}
+	if value := strings.TrimSpace(rawUntil); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, nil, ErrVigenciaInvalida
+		}
+		validUntil = &parsed
+	}
+	if validUntil != nil {
+		if !validUntil.After(time.Now()) {
+			return nil, nil, ErrVigenciaInvalida
+		}
+		if validFrom != nil && !validUntil.After(*validFrom) {
+			return nil, nil, ErrVigenciaInvalida
+		}
+	}
+	return validFrom, validUntil, nil
+}
+
+func describeVigencia(validFrom, validUntil *time.Time) string {
+	const layout = "02/01/2006 15:04"
+	switch {
+	case validFrom != nil && validUntil != nil:
+		return "del " + validFrom.Format(layout) + " al " + validUntil.Format(layout)
+	case validFrom != nil:
+		return "desde el " + validFrom.Format(layout)
+	case validUntil != nil:
+		return "hasta el " + validUntil.Format(layout)
+	default:
+		return "sin fecha de vencimiento"
+	}
+}
+
+func toSolicitudResponse(row rolesrepo.SolicitudRolRow) *dto.SolicitudRolResponse {
+	resp := &dto.SolicitudRolResponse{
+		IDSolicitud:   row.IDSolicitud,
+		IDUsuario:     row.IDUsuario,
+		UsuarioNombre: row.UsuarioNombre,
+		IDRol:         row.IDRol,
+		Rol:           row.RolNombre,
+		Estado:        row.Estado,
+		Motivo:        row.Motivo,
+		RevisadoPor:   row.RevisadoPor,
+		Nota:          row.Nota,
+		CreatedAt:     row.CreatedAt.Format(time.RFC3339),
+	}
+	if row.ValidFrom != nil {
+		value := row.ValidFrom.Format(time.RFC3339)
+		resp.ValidFrom = &value
+	}
+	if row.ValidUntil != nil {
+		value := row.ValidUntil.Format(time.RFC3339)
+		resp.ValidUntil = &value
+	}
+	return resp
+}
@@ -3,7 +3,10 @@ package roles
 import (
 	"context"
 	"strings"
+	"time"
 
+	"project/backend/internal/policy"
+	rolesrepo "project/backend/internal/roles/repo"
 	"project/backend/prisma/db"
 )
 
@@ -13,6 +16,8 @@ type UserRoleService interface {
 	HasRoleResourcePermission(ctx context.Context, roleID int, resourceKey string) (bool, error)
 	UpdateUserRole(ctx context.Context, userID int, roleID int) error
 	UpdateUserRoles(ctx context.Context, userID int, roleIDs []int) error
+	GrantUserRole(ctx context.Context, userID int, roleID int, validFrom, validUntil *time.Time) error
+	ActiveRoleNames(ctx context.Context, userID int) ([]string, error)
 }
 
 type prismaUserRoleService struct {
@@ -156,3 +161,81 @@ func (s prismaUserRoleService) UpdateUserRoles(ctx context.Context, userID int,
 
 	return nil
 }
+
+// GrantUserRole adds a role to the user without touching their other roles.
+// The grant is only honored between validFrom and validUntil when they are set.
+func (s prismaUserRoleService) GrantUserRole(ctx context.Context, userID int, roleID int, validFrom, validUntil *time.Time) error {
+	if _, err := s.client.Usuario.FindUnique(db.Usuario.IDUsuario.Equals(userID)).Exec(ctx); err != nil {
+		return err
+	}
+	return rolesrepo.New(s.client).GrantRole(ctx, userID, roleID, validFrom, validUntil)
+}
+
+// ActiveRoleNames returns the roles the user holds right now, leaving out
+// grants outside their validity window.
+func (s prismaUserRoleService) ActiveRoleNames(ctx context.Context, userID int) ([]string, error) {
+	return rolesrepo.New(s.client).ListActiveRoleNames(ctx, userID)
+}
+
+// ActiveSubject drops the roles of an authenticated subject that the user
+// no longer holds, so a token issued before a grant expired stops
+// authorizing with it. Subjects without a user only carry the roles
+// declared in X-Role and are returned as they are.
+func ActiveSubject(ctx context.Context, svc UserRoleService, subject policy.Subject) (policy.Subject, error) {
+	if !subject.Authenticated() || len(subject.Roles) == 0 {
+		return subject, nil
+	}
+	vigentes, err := svc.ActiveRoleNames(ctx, subject.UserID)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+	roles := make([]string, 0, len(subject.Roles))
+	for _, name := range subject.Roles {
+		for _, vigente := range vigentes {
+			if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(vigente)) {
+				roles = append(roles, name)
+				break
+			}
+		}
+	}
+	subject.Roles = roles
+	return subject, nil
+}
+
+// AuthorizeSubject reports whether the roles the subject still holds grant
+// access to resourceKey.
+func AuthorizeSubject(ctx context.Context, svc UserRoleService, subject policy.Subject, resourceKey string) (bool, error) {
+	subject, err := ActiveSubject(ctx, svc, subject)
+	if err != nil {
+		return false, err
+	}
+	return AuthorizeRoleNames(ctx, svc, subject.Roles, resourceKey)
+}
+
+// AuthorizeRoleNames reports whether any of the given role names grants access
+// to resourceKey. ADMIN is always allowed and unknown role names are ignored.
+func AuthorizeRoleNames(ctx context.Context, svc UserRoleService, roleNames []string, resourceKey string) (bool, error) {
+	for _, roleName := range roleNames {
+		if isAdminRoleName(roleName) {
+			return true, nil
+		}
+	}
+
+	for _, roleName := range roleNames {
+		id, err := svc.GetRoleIDByName(ctx, roleName)
+		if err != nil {
+			if db.IsErrNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		hasPermission, err := svc.HasRoleResourcePermission(ctx, id, resourceKey)
+		if err != nil {
+			return false, err
+		}
+		if hasPermission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,15 @@
+package dto
+
+// SedeRequest represents the payload to create or update a venue.
+type SedeRequest struct {
+	Nombre    string `json:"nombre"`
+	Direccion string `json:"direccion"`
+	IDCiudad  int    `json:"id_ciudad"`
+	Capacidad *int   `json:"capacidad"`
+}
+
+// SalaRequest represents the payload to create or update a room of a venue.
+type SalaRequest struct {
+	Nombre    string `json:"nombre"`
+	Capacidad *int   `json:"capacidad"`
+}
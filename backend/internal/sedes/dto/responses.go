@@ -0,0 +1,20 @@
+package dto
+
+// SedeResponse represents the response payload for a venue.
+type SedeResponse struct {
+	ID        int    `json:"id_sede"`
+	Nombre    string `json:"nombre"`
+	Direccion string `json:"direccion"`
+	IDCiudad  int    `json:"id_ciudad"`
+	Ciudad    string `json:"ciudad"`
+	Pais      string `json:"pais"`
+	Capacidad *int   `json:"capacidad"`
+}
+
+// SalaResponse represents the response payload for a room of a venue.
+type SalaResponse struct {
+	ID        int    `json:"id_sala"`
+	IDSede    int    `json:"id_sede"`
+	Nombre    string `json:"nombre"`
+	Capacidad *int   `json:"capacidad"`
+}
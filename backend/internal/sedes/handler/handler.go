@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	roles "project/backend/internal/roles/service"
+	"project/backend/internal/sedes/dto"
+	"project/backend/internal/sedes/repo"
+	"project/backend/internal/sedes/service"
+	"project/backend/internal/sedes/validation"
+	"project/backend/internal/shared/httperror"
+	"project/backend/prisma/db"
+)
+
+type Handler struct {
+	svc         *service.Service
+	roleService roles.UserRoleService
+}
+
+func New(client *db.PrismaClient) http.Handler {
+	return &Handler{
+		svc:         service.New(repo.New(client)),
+		roleService: roles.NewUserRoleService(client),
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listSedes(w, r)
+	case http.MethodPost:
+		h.createSede(w, r)
+	case http.MethodPut:
+		h.updateSede(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) listSedes(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	if idStr := r.URL.Query().Get("id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil || id <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+			return
+		}
+		sede, err := h.svc.GetSede(ctx, id)
+		if writeSedeError(w, err) {
+			return
+		}
+		writeJSON(w, toResponse(sede))
+		return
+	}
+
+	ciudadID := 0
+	if raw := r.URL.Query().Get("id_ciudad"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			httperror.WriteJSON(w, http.StatusBadRequest, "id_ciudad inválido")
+			return
+		}
+		ciudadID = parsed
+	}
+
+	sedes, err := h.svc.ListSedes(ctx, ciudadID)
+	if writeSedeError(w, err) {
+		return
+	}
+	res := make([]dto.SedeResponse, 0, len(sedes))
+	for _, sede := range sedes {
+		res = append(res, toResponse(sede))
+	}
+	writeJSON(w, res)
+}
+
+func (h *Handler) createSede(w http.ResponseWriter, r *http.Request) {
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	var req dto.SedeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateSede(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	created, err := h.svc.CreateSede(ctx, req)
+	if writeSedeError(w, err) {
+		return
+	}
+	writeJSON(w, toResponse(created))
+}
+
+func (h *Handler) updateSede(w http.ResponseWriter, r *http.Request) {
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+	var req dto.SedeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateSede(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	updated, err := h.svc.UpdateSede(ctx, id, req)
+	if writeSedeError(w, err) {
+		return
+	}
+	writeJSON(w, toResponse(updated))
+}
+
+// SalasHandler serves /api/sedes/salas, the rooms of a venue. GET and POST
+// take ?id_sede=N to list the rooms or add one; PUT takes ?id=N.
+func (h *Handler) SalasHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listSalas(w, r)
+	case http.MethodPost:
+		h.createSala(w, r)
+	case http.MethodPut:
+		h.updateSala(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) listSalas(w http.ResponseWriter, r *http.Request) {
+	sedeID, err := strconv.Atoi(r.URL.Query().Get("id_sede"))
+	if err != nil || sedeID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_sede inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	salas, err := h.svc.ListSalas(ctx, sedeID)
+	if writeSedeError(w, err) {
+		return
+	}
+	res := make([]dto.SalaResponse, 0, len(salas))
+	for _, sala := range salas {
+		res = append(res, toSalaResponse(sala))
+	}
+	writeJSON(w, res)
+}
+
+func (h *Handler) createSala(w http.ResponseWriter, r *http.Request) {
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	sedeID, err := strconv.Atoi(r.URL.Query().Get("id_sede"))
+	if err != nil || sedeID <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id_sede inválido")
+		return
+	}
+	var req dto.SalaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateSala(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	created, err := h.svc.CreateSala(ctx, sedeID, req)
+	if writeSedeError(w, err) {
+		return
+	}
+	writeJSON(w, toSalaResponse(created))
+}
+
+func (h *Handler) updateSala(w http.ResponseWriter, r *http.Request) {
+	if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+		return
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || id <= 0 {
+		httperror.WriteJSON(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+	var req dto.SalaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, "json inválido")
+		return
+	}
+	if err := validation.ValidateSala(req); err != nil {
+		httperror.WriteJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	updated, err := h.svc.UpdateSala(ctx, id, req)
+	if writeSedeError(w, err) {
+		return
+	}
+	writeJSON(w, toSalaResponse(updated))
+}
+
+// msgSinPermisos answers callers without events.management.
+const msgSinPermisos = "no tienes permisos para gestionar sedes"
+
+func writeSedeError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrCiudadNotFound), errors.Is(err, service.ErrSalaNotFound):
+		httperror.WriteJSON(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrNameExists), errors.Is(err, service.ErrSalaExists):
+		httperror.WriteJSON(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrSalaCapacidad):
+		httperror.WriteJSON(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		httperror.WriteJSON(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
+
+func toResponse(row repo.SedeRow) dto.SedeResponse {
+	return dto.SedeResponse{
+		ID:        row.IDSede,
+		Nombre:    row.Nombre,
+		Direccion: row.Direccion,
+		IDCiudad:  row.IDCiudad,
+		Ciudad:    row.Ciudad,
+		Pais:      row.Pais,
+		Capacidad: row.Capacidad,
+	}
+}
+
+func toSalaResponse(row repo.SalaRow) dto.SalaResponse {
+	return dto.SalaResponse{
+		ID:        row.IDSala,
+		IDSede:    row.IDSede,
+		Nombre:    row.Nombre,
+		Capacidad: row.Capacidad,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"project/backend/prisma/db"
+)
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+type SedeRow struct {
+	IDSede    int    `json:"id_sede"`
+	Nombre    string `json:"nombre"`
+	Direccion string `json:"direccion"`
+	IDCiudad  int    `json:"id_ciudad"`
+	Ciudad    string `json:"ciudad"`
+	Pais      string `json:"pais"`
+	Capacidad *int   `json:"capacidad"`
+}
+
+const sedeSelect = `SELECT s."id_sede", s."nombre", s."direccion", s."id_ciudad", c."nombre" AS "ciudad", p."nombre" AS "pais", s."capacidad"
+		FROM "Sede" s
+		JOIN "Ciudad" c ON c."id_ciudad" = s."id_ciudad"
+		JOIN "Pais" p ON p."id_pais" = c."id_pais"`
+
+func (r *Repository) List(ctx context.Context, ciudadID int) ([]SedeRow, error) {
+	query := sedeSelect
+	params := []interface{}{}
+	if ciudadID > 0 {
+		params = append(params, ciudadID)
+		query += ` WHERE s."id_ciudad" = $1`
+	}
+	query += ` ORDER BY s."nombre"`
+	var rows []SedeRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, params...).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int) (SedeRow, error) {
+	var rows []SedeRow
+	if err := r.client.Prisma.Raw.QueryRaw(sedeSelect+` WHERE s."id_sede" = $1`, id).Exec(ctx, &rows); err != nil {
+		return SedeRow{}, err
+	}
+	if len(rows) == 0 {
+		return SedeRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// ExistsNombre reports whether another venue in the city already uses nombre.
+func (r *Repository) ExistsNombre(ctx context.Context, nombre string, ciudadID, excluirID int) (bool, error) {
+	query := `SELECT "id_sede" FROM "Sede" WHERE lower("nombre") = lower($1) AND "id_ciudad" = $2 AND "id_sede" <> $3 LIMIT 1`
+	var rows []struct {
+		IDSede int `json:"id_sede"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, strings.TrimSpace(nombre), ciudadID, excluirID).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (r *Repository) Create(ctx context.Context, nombre, direccion string, ciudadID int, capacidad *int) (int, error) {
+	query := `INSERT INTO "Sede" ("nombre", "direccion", "id_ciudad", "capacidad", "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING "id_sede"`
+	var rows []struct {
+		IDSede int `json:"id_sede"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, strings.TrimSpace(nombre), strings.TrimSpace(direccion), ciudadID, capacidad).Exec(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no rows")
+	}
+	return rows[0].IDSede, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id int, nombre, direccion string, ciudadID int, capacidad *int) error {
+	query := `UPDATE "Sede" SET "nombre" = $2, "direccion" = $3, "id_ciudad" = $4, "capacidad" = $5, "updatedAt" = NOW()
+		WHERE "id_sede" = $1`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, id, strings.TrimSpace(nombre), strings.TrimSpace(direccion), ciudadID, capacidad).Exec(ctx)
+	return err
+}
+
+func (r *Repository) CiudadExists(ctx context.Context, ciudadID int) (bool, error) {
+	_, err := r.client.Ciudad.FindUnique(db.Ciudad.IDCiudad.Equals(ciudadID)).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+type SalaRow struct {
+	IDSala    int    `json:"id_sala"`
+	IDSede    int    `json:"id_sede"`
+	Nombre    string `json:"nombre"`
+	Capacidad *int   `json:"capacidad"`
+}
+
+const salaSelect = `SELECT "id_sala", "id_sede", "nombre", "capacidad" FROM "Sala"`
+
+func (r *Repository) ListSalas(ctx context.Context, sedeID int) ([]SalaRow, error) {
+	var rows []SalaRow
+	if err := r.client.Prisma.Raw.QueryRaw(salaSelect+` WHERE "id_sede" = $1 ORDER BY "nombre"`, sedeID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *Repository) FindSala(ctx context.Context, id int) (SalaRow, error) {
+	var rows []SalaRow
+	if err := r.client.Prisma.Raw.QueryRaw(salaSelect+` WHERE "id_sala" = $1`, id).Exec(ctx, &rows); err != nil {
+		return SalaRow{}, err
+	}
+	if len(rows) == 0 {
+		return SalaRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// ExistsSala reports whether another room of the venue already uses nombre.
+func (r *Repository) ExistsSala(ctx context.Context, nombre string, sedeID, excluirID int) (bool, error) {
+	query := `SELECT "id_sala" FROM "Sala" WHERE lower("nombre") = lower($1) AND "id_sede" = $2 AND "id_sala" <> $3 LIMIT 1`
+	var rows []struct {
+		IDSala int `json:"id_sala"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, strings.TrimSpace(nombre), sedeID, excluirID).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (r *Repository) CreateSala(ctx context.Context, sedeID int, nombre string, capacidad *int) (int, error) {
+	query := `INSERT INTO "Sala" ("id_sede", "nombre", "capacidad", "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING "id_sala"`
+	var rows []struct {
+		IDSala int `json:"id_sala"`
+	}
+	if err := r.client.Prisma.Raw.QueryRaw(query, sedeID, strings.TrimSpace(nombre), capacidad).Exec(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no rows")
+	}
+	return rows[0].IDSala, nil
+}
+
+func (r *Repository) UpdateSala(ctx context.Context, id int, nombre string, capacidad *int) error {
+	query := `UPDATE "Sala" SET "nombre" = $2, "capacidad" = $3, "updatedAt" = NOW() WHERE "id_sala" = $1`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, id, strings.TrimSpace(nombre), capacidad).Exec(ctx)
+	return err
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"project/backend/internal/sedes/dto"
+	"project/backend/internal/sedes/repo"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrNotFound       = errors.New("sede no encontrada")
+	ErrCiudadNotFound = errors.New("ciudad no encontrada")
+	ErrNameExists     = errors.New("ya existe una sede con ese nombre en la ciudad")
+	ErrSalaNotFound   = errors.New("sala no encontrada")
+	ErrSalaExists     = errors.New("ya existe una sala con ese nombre en la sede")
+	ErrSalaCapacidad  = errors.New("la capacidad de la sala no puede superar la de la sede")
+	ErrDB             = errors.New("db error")
+)
+
+type Service struct {
+	repo *repo.Repository
+}
+
+func New(r *repo.Repository) *Service {
+	return &Service{repo: r}
+}
+
+func (s *Service) ListSedes(ctx context.Context, ciudadID int) ([]repo.SedeRow, error) {
+	rows, err := s.repo.List(ctx, ciudadID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	return rows, nil
+}
+
+func (s *Service) GetSede(ctx context.Context, id int) (repo.SedeRow, error) {
+	row, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.SedeRow{}, ErrNotFound
+		}
+		return repo.SedeRow{}, ErrDB
+	}
+	return row, nil
+}
+
+func (s *Service) CreateSede(ctx context.Context, req dto.SedeRequest) (repo.SedeRow, error) {
+	if err := s.ensureValida(ctx, req, 0); err != nil {
+		return repo.SedeRow{}, err
+	}
+	id, err := s.repo.Create(ctx, req.Nombre, req.Direccion, req.IDCiudad, req.Capacidad)
+	if err != nil {
+		return repo.SedeRow{}, ErrDB
+	}
+	return s.GetSede(ctx, id)
+}
+
+func (s *Service) UpdateSede(ctx context.Context, id int, req dto.SedeRequest) (repo.SedeRow, error) {
+	if _, err := s.GetSede(ctx, id); err != nil {
+		return repo.SedeRow{}, err
+	}
+	if err := s.ensureValida(ctx, req, id); err != nil {
+		return repo.SedeRow{}, err
+	}
+	if err := s.repo.Update(ctx, id, req.Nombre, req.Direccion, req.IDCiudad, req.Capacidad); err != nil {
+		return repo.SedeRow{}, ErrDB
+	}
+	return s.GetSede(ctx, id)
+}
+
+func (s *Service) ensureValida(ctx context.Context, req dto.SedeRequest, excluirID int) error {
+	exists, err := s.repo.CiudadExists(ctx, req.IDCiudad)
+	if err != nil {
+		return ErrDB
+	}
+	if !exists {
+		return ErrCiudadNotFound
+	}
+	taken, err := s.repo.ExistsNombre(ctx, req.Nombre, req.IDCiudad, excluirID)
+	if err != nil {
+		return ErrDB
+	}
+	if taken {
+		return ErrNameExists
+	}
+	return nil
+}
+
+// ListSalas returns the rooms of a venue by name.
+func (s *Service) ListSalas(ctx context.Context, sedeID int) ([]repo.SalaRow, error) {
+	if _, err := s.GetSede(ctx, sedeID); err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListSalas(ctx, sedeID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	return rows, nil
+}
+
+func (s *Service) CreateSala(ctx context.Context, sedeID int, req dto.SalaRequest) (repo.SalaRow, error) {
+	sede, err := s.GetSede(ctx, sedeID)
+	if err != nil {
+		return repo.SalaRow{}, err
+	}
+	if err := s.ensureSalaValida(ctx, sede, req, 0); err != nil {
+		return repo.SalaRow{}, err
+	}
+	id, err := s.repo.CreateSala(ctx, sedeID, req.Nombre, req.Capacidad)
+	if err != nil {
+		return repo.SalaRow{}, ErrDB
+	}
+	return s.getSala(ctx, id)
+}
+
+func (s *Service) UpdateSala(ctx context.Context, id int, req dto.SalaRequest) (repo.SalaRow, error) {
+	sala, err := s.getSala(ctx, id)
+	if err != nil {
+		return repo.SalaRow{}, err
+	}
+	sede, err := s.GetSede(ctx, sala.IDSede)
+	if err != nil {
+		return repo.SalaRow{}, err
+	}
+	if err := s.ensureSalaValida(ctx, sede, req, id); err != nil {
+		return repo.SalaRow{}, err
+	}
+	if err := s.repo.UpdateSala(ctx, id, req.Nombre, req.Capacidad); err != nil {
+		return repo.SalaRow{}, ErrDB
+	}
+	return s.getSala(ctx, id)
+}
+
+func (s *Service) getSala(ctx context.Context, id int) (repo.SalaRow, error) {
+	row, err := s.repo.FindSala(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return repo.SalaRow{}, ErrSalaNotFound
+		}
+		return repo.SalaRow{}, ErrDB
+	}
+	return row, nil
+}
+
+// ensureSalaValida checks that the room fits in its venue and that its name
+// is free there.
+func (s *Service) ensureSalaValida(ctx context.Context, sede repo.SedeRow, req dto.SalaRequest, excluirID int) error {
+	if req.Capacidad != nil && sede.Capacidad != nil && *req.Capacidad > *sede.Capacidad {
+		return ErrSalaCapacidad
+	}
+	taken, err := s.repo.ExistsSala(ctx, req.Nombre, sede.IDSede, excluirID)
+	if err != nil {
+		return ErrDB
+	}
+	if taken {
+		return ErrSalaExists
+	}
+	return nil
+}
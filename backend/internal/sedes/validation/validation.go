@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+
+	"project/backend/internal/sedes/dto"
+)
+
+func ValidateSede(req dto.SedeRequest) error {
+	nombre := strings.TrimSpace(req.Nombre)
+	if len(nombre) < 3 || len(nombre) > 150 {
+		return errors.New("El nombre de la sede debe tener entre 3 y 150 caracteres.")
+	}
+	direccion := strings.TrimSpace(req.Direccion)
+	if len(direccion) < 5 || len(direccion) > 200 {
+		return errors.New("La dirección de la sede debe tener entre 5 y 200 caracteres.")
+	}
+	if req.IDCiudad <= 0 {
+		return errors.New("id_ciudad es requerido")
+	}
+	if req.Capacidad != nil && *req.Capacidad < 1 {
+		return errors.New("La capacidad de la sede debe ser mayor a 0.")
+	}
+	return nil
+}
+
+func ValidateSala(req dto.SalaRequest) error {
+	nombre := strings.TrimSpace(req.Nombre)
+	if len(nombre) < 1 || len(nombre) > 100 {
+		return errors.New("El nombre de la sala debe tener entre 1 y 100 caracteres.")
+	}
+	if req.Capacidad != nil && *req.Capacidad < 1 {
+		return errors.New("La capacidad de la sala debe ser mayor a 0.")
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"testing"
+
+	"project/backend/internal/sedes/dto"
+)
+
+func TestValidateSede(t *testing.T) {
+	capacidad := 300
+	cero := 0
+	cases := []struct {
+		name    string
+		req     dto.SedeRequest
+		wantErr bool
+	}{
+		{"valida", dto.SedeRequest{Nombre: "Aula Magna", Direccion: "Av. Principal, Los Chaguaramos", IDCiudad: 1, Capacidad: &capacidad}, false},
+		{"sin capacidad", dto.SedeRequest{Nombre: "Aula Magna", Direccion: "Av. Principal, Los Chaguaramos", IDCiudad: 1}, false},
+		{"nombre corto", dto.SedeRequest{Nombre: "AM", Direccion: "Av. Principal", IDCiudad: 1}, true},
+		{"sin direccion", dto.SedeRequest{Nombre: "Aula Magna", IDCiudad: 1}, true},
+		{"sin ciudad", dto.SedeRequest{Nombre: "Aula Magna", Direccion: "Av. Principal"}, true},
+		{"capacidad cero", dto.SedeRequest{Nombre: "Aula Magna", Direccion: "Av. Principal", IDCiudad: 1, Capacidad: &cero}, true},
+	}
+
+	for _, c := range cases {
+		err := ValidateSede(c.req)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
+
+func TestValidateSala(t *testing.T) {
+	capacidad := 80
+	cero := 0
+	cases := []struct {
+		name    string
+		req     dto.SalaRequest
+		wantErr bool
+	}{
+		{"valida", dto.SalaRequest{Nombre: "Sala A", Capacidad: &capacidad}, false},
+		{"sin capacidad", dto.SalaRequest{Nombre: "Auditorio"}, false},
+		{"sin nombre", dto.SalaRequest{Nombre: "  ", Capacidad: &capacidad}, true},
+		{"capacidad cero", dto.SalaRequest{Nombre: "Sala A", Capacidad: &cero}, true},
+	}
+
+	for _, c := range cases {
+		err := ValidateSala(c.req)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
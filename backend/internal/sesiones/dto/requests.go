@@ -1,15 +1,23 @@
 package dto
 
+import "encoding/json"
+
 // CreateSesionRequest represents the payload to create a new session.
+// IDSala must be a room of the event's venue and IDTrack a track of the
+// event; both are optional.
 type CreateSesionRequest struct {
 	Titulo      string `json:"titulo"`
 	Descripcion string `json:"descripcion"`
 	FechaInicio string `json:"fecha_inicio"`
 	FechaFin    string `json:"fecha_fin"`
 	Ubicacion   string `json:"ubicacion"`
+	IDSala      *int   `json:"id_sala"`
+	IDTrack     *int   `json:"id_track"`
 }
 
 // UpdateSesionRequest represents the payload to update an existing session.
+// Leaving out id_sala or id_track keeps the current room or track; sending
+// them as null takes the session out of it.
 type UpdateSesionRequest struct {
 	IDSesion    int    `json:"id_sesion"`
 	Titulo      string `json:"titulo"`
@@ -17,9 +25,51 @@ type UpdateSesionRequest struct {
 	FechaInicio string `json:"fecha_inicio"`
 	FechaFin    string `json:"fecha_fin"`
 	Ubicacion   string `json:"ubicacion"`
+	IDSala      *int   `json:"id_sala"`
+	IDTrack     *int   `json:"id_track"`
+
+	// CambiaSala and CambiaTrack are true when the payload carries id_sala
+	// or id_track.
+	CambiaSala  bool `json:"-"`
+	CambiaTrack bool `json:"-"`
+}
+
+func (r *UpdateSesionRequest) UnmarshalJSON(data []byte) error {
+	type plano UpdateSesionRequest
+	var campos map[string]json.RawMessage
+	if err := json.Unmarshal(data, &campos); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, (*plano)(r)); err != nil {
+		return err
+	}
+	_, r.CambiaSala = campos["id_sala"]
+	_, r.CambiaTrack = campos["id_track"]
+	return nil
 }
 
 // AsignarPonentesRequest represents the payload to assign speakers to a session.
 type AsignarPonentesRequest struct {
 	Usuarios []int `json:"usuarios"`
 }
+
+// TrackRequest represents the payload to create or update a track of an
+// event. Color is optional, as #RRGGBB.
+type TrackRequest struct {
+	Nombre string `json:"nombre"`
+	Color  string `json:"color"`
+}
+
+// Ways the agenda can be grouped.
+const (
+	AgruparSala  = "sala"
+	AgruparTrack = "track"
+)
+
+// BloqueoRequest represents the payload a speaker sends to declare a period
+// they cannot present in. Inicio and Fin are RFC 3339 timestamps.
+type BloqueoRequest struct {
+	Inicio string `json:"inicio"`
+	Fin    string `json:"fin"`
+	Motivo string `json:"motivo"`
+}
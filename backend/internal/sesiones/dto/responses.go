@@ -9,6 +9,11 @@ type SesionResponse struct {
 	FechaFin    string            `json:"fecha_fin"`
 	Ubicacion   string            `json:"ubicacion"`
 	EventoID    int               `json:"id_evento"`
+	IDSala      *int              `json:"id_sala"`
+	Sala        string            `json:"sala,omitempty"`
+	IDTrack     *int              `json:"id_track"`
+	Track       string            `json:"track,omitempty"`
+	ColorTrack  string            `json:"color_track,omitempty"`
 	Ponentes    []PonenteResponse `json:"ponentes"`
 }
 
@@ -25,3 +30,57 @@ type PonenteAsignableResponse struct {
 	Nombre    string `json:"nombre"`
 	Email     string `json:"email"`
 }
+
+// TrackResponse represents the response payload for a track of an event.
+type TrackResponse struct {
+	IDTrack  int    `json:"id_track"`
+	IDEvento int    `json:"id_evento"`
+	Nombre   string `json:"nombre"`
+	Color    string `json:"color,omitempty"`
+}
+
+// AgendaGrupo is a column of the agenda grid: the sessions of one room or
+// one track, in schedule order. ID is nil for the sessions without one.
+type AgendaGrupo struct {
+	ID        *int             `json:"id"`
+	Nombre    string           `json:"nombre"`
+	Capacidad *int             `json:"capacidad,omitempty"`
+	Color     string           `json:"color,omitempty"`
+	Sesiones  []SesionResponse `json:"sesiones"`
+}
+
+// ConflictoResponse represents a commitment that keeps a speaker from
+// presenting a session: another session, in this event or another one, or
+// a blackout period. Tipo is "sesion" or "bloqueo" and tells which fields
+// are set.
+type ConflictoResponse struct {
+	IDUsuario int    `json:"id_usuario"`
+	Ponente   string `json:"ponente"`
+	Tipo      string `json:"tipo"`
+	IDSesion  *int   `json:"id_sesion,omitempty"`
+	Sesion    string `json:"sesion,omitempty"`
+	IDEvento  *int   `json:"id_evento,omitempty"`
+	Evento    string `json:"evento,omitempty"`
+	IDBloqueo *int   `json:"id_bloqueo,omitempty"`
+	Motivo    string `json:"motivo,omitempty"`
+	Inicio    string `json:"inicio"`
+	Fin       string `json:"fin"`
+}
+
+// ConflictoPonentesResponse is the body of a 409 when speakers are not
+// available at the session's time.
+type ConflictoPonentesResponse struct {
+	Message    string              `json:"message"`
+	Conflictos []ConflictoResponse `json:"conflictos"`
+}
+
+// BloqueoResponse represents a blackout period of a speaker. Conflictos
+// lists the sessions they are already assigned to within it, which the
+// organizers need to reassign.
+type BloqueoResponse struct {
+	IDBloqueo  int                 `json:"id_bloqueo"`
+	Inicio     string              `json:"inicio"`
+	Fin        string              `json:"fin"`
+	Motivo     string              `json:"motivo,omitempty"`
+	Conflictos []ConflictoResponse `json:"conflictos,omitempty"`
+}
@@ -1,23 +1,105 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"project/backend/internal/policy"
+	roles "project/backend/internal/roles/service"
 	"project/backend/internal/sesiones/dto"
 	"project/backend/internal/sesiones/service"
+	validation "project/backend/internal/sesiones/validation"
 
 	db "project/backend/prisma/db"
 )
 
 type Handler struct {
-	svc *service.Service
+	svc         *service.Service
+	roleService roles.UserRoleService
+	policy      *policy.Engine
 }
 
 func New(prismaClient interface{}) http.Handler {
-	return &Handler{svc: service.New(prismaClient.(*db.PrismaClient))}
+	client := prismaClient.(*db.PrismaClient)
+	return &Handler{
+		svc:         service.New(client),
+		roleService: roles.NewUserRoleService(client),
+		policy:      policy.Default(),
+	}
+}
+
+// authorizeUpdate lets organizers with events.management edit any session and
+// otherwise defers to the ownership rules. It writes the error response
+// itself.
+func (h *Handler) authorizeUpdate(w http.ResponseWriter, r *http.Request, sesionID int) bool {
+	subject := policy.SubjectFromRequest(r)
+	if !subject.Identified() {
+		writeMessage(w, http.StatusUnauthorized, "autenticación requerida")
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	subject, err := roles.ActiveSubject(ctx, h.roleService, subject)
+	if err != nil {
+		writeMessage(w, http.StatusInternalServerError, "error verificando permisos")
+		return false
+	}
+	allowed, err := roles.AuthorizeRoleNames(ctx, h.roleService, subject.Roles, "events.management")
+	if err != nil {
+		writeMessage(w, http.StatusInternalServerError, "error verificando permisos")
+		return false
+	}
+	if allowed {
+		return true
+	}
+
+	acceso, err := h.svc.ObtenerAcceso(ctx, sesionID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeMessage(w, http.StatusNotFound, err.Error())
+			return false
+		}
+		writeMessage(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	decision := h.policy.Evaluate(policy.Request{
+		Subject: subject,
+		Action:  policy.ActionUpdate,
+		Resource: policy.Resource{
+			Type:        policy.ResourceSesion,
+			ID:          sesionID,
+			AssigneeIDs: acceso.PonenteIDs,
+			Attributes:  map[string]any{"cancelado": acceso.SesionCancelada},
+		},
+		Event: &policy.Event{
+			ID:         acceso.EventoID,
+			Attributes: map[string]any{"cancelado": acceso.EventoCancelado},
+		},
+	})
+	if decision.Allowed {
+		return true
+	}
+	if !subject.Authenticated() {
+		writeMessage(w, http.StatusUnauthorized, "autenticación requerida")
+		return false
+	}
+	writeMessage(w, http.StatusForbidden, "no autorizado para editar esta sesión")
+	return false
+}
+
+func writeMessage(w http.ResponseWriter, status int, message string) {
+	data, _ := json.MarshalIndent(map[string]string{"message": message}, "", "  ")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -53,6 +135,14 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.ListarPonentesAsignablesPorQueryHandler(w, r.WithContext(ctx))
 		return
 	}
+	if path == "tracks" {
+		h.TracksHandler(w, r.WithContext(ctx))
+		return
+	}
+	if path == "bloqueos" {
+		h.BloqueosHandler(w, r.WithContext(ctx))
+		return
+	}
 	switch {
 	case r.Method == http.MethodPost && path == "":
 		h.CrearSesionHandler(w, r.WithContext(ctx))
@@ -85,8 +175,14 @@ func (h *Handler) ActualizarSesionPorQueryHandler(w http.ResponseWriter, r *http
 		w.Write(data)
 		return
 	}
+	if !h.authorizeUpdate(w, r, id) {
+		return
+	}
 	req.IDSesion = id
 	resp, err := h.svc.UpdateSesion(r.Context(), id, req)
+	if writeConflicto(w, err) {
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		resp := map[string]string{"message": err.Error()}
@@ -146,6 +242,9 @@ func (h *Handler) AsignarPonentesPorQueryHandler(w http.ResponseWriter, r *http.
 		return
 	}
 	err = h.svc.AsignarPonentes(r.Context(), id, req)
+	if writeConflicto(w, err) {
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		resp := map[string]string{"message": err.Error()}
@@ -299,7 +398,9 @@ func (h *Handler) CrearSesionHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
-// GET /api/sesiones?evento={id}
+// GET /api/sesiones?evento={id}[&sala={id}][&track={id}][&agrupar=sala|track]
+// Without agrupar it returns the sessions in schedule order; with it, the
+// columns of the agenda grid.
 func (h *Handler) ListarSesionesHandler(w http.ResponseWriter, r *http.Request) {
 	eventoIDStr := r.URL.Query().Get("evento")
 	eventoID, err := strconv.Atoi(eventoIDStr)
@@ -308,7 +409,32 @@ func (h *Handler) ListarSesionesHandler(w http.ResponseWriter, r *http.Request)
 		w.Write([]byte("evento inválido"))
 		return
 	}
-	resp, err := h.svc.ListSesiones(r.Context(), eventoID)
+	salaID, err := optionalID(r, "sala")
+	if err != nil {
+		writeMessage(w, http.StatusBadRequest, "sala inválida")
+		return
+	}
+	trackID, err := optionalID(r, "track")
+	if err != nil {
+		writeMessage(w, http.StatusBadRequest, "track inválido")
+		return
+	}
+	if agrupar := r.URL.Query().Get("agrupar"); agrupar != "" {
+		if agrupar != dto.AgruparSala && agrupar != dto.AgruparTrack {
+			writeMessage(w, http.StatusBadRequest, "agrupar debe ser sala o track")
+			return
+		}
+		grupos, err := h.svc.Agenda(r.Context(), eventoID, salaID, trackID, agrupar)
+		if err != nil {
+			writeMessage(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		data, _ := json.MarshalIndent(grupos, "", "  ")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return
+	}
+	resp, err := h.svc.ListSesiones(r.Context(), eventoID, salaID, trackID)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(err.Error()))
@@ -354,8 +480,14 @@ func (h *Handler) ActualizarSesionHandler(w http.ResponseWriter, r *http.Request
 		w.Write([]byte("json inválido"))
 		return
 	}
+	if !h.authorizeUpdate(w, r, id) {
+		return
+	}
 	req.IDSesion = id
 	resp, err := h.svc.UpdateSesion(r.Context(), id, req)
+	if writeConflicto(w, err) {
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte(err.Error()))
@@ -398,6 +530,9 @@ func (h *Handler) AsignarPonentesHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	err = h.svc.AsignarPonentes(r.Context(), id, req)
+	if writeConflicto(w, err) {
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte(err.Error()))
@@ -470,3 +605,205 @@ func (h *Handler) ListarPonentesAsignablesHandler(w http.ResponseWriter, r *http
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(data)
 }
+
+// TracksHandler serves /api/sesiones/tracks. GET ?evento={id} lists the
+// tracks of an event and POST ?evento={id} adds one; PUT and DELETE take
+// ?id_track={id}. Changes require events.management.
+func (h *Handler) TracksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet, http.MethodPost:
+		eventoID, err := strconv.Atoi(r.URL.Query().Get("evento"))
+		if err != nil || eventoID <= 0 {
+			writeMessage(w, http.StatusBadRequest, "evento inválido")
+			return
+		}
+		if r.Method == http.MethodGet {
+			tracks, err := h.svc.ListTracks(ctx, eventoID)
+			if writeTrackError(w, err) {
+				return
+			}
+			writeJSON(w, http.StatusOK, tracks)
+			return
+		}
+		req, ok := decodeTrack(w, r)
+		if !ok || !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+			return
+		}
+		track, err := h.svc.CreateTrack(ctx, eventoID, req)
+		if writeTrackError(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusCreated, track)
+	case http.MethodPut, http.MethodDelete:
+		trackID, err := strconv.Atoi(r.URL.Query().Get("id_track"))
+		if err != nil || trackID <= 0 {
+			writeMessage(w, http.StatusBadRequest, "id_track inválido")
+			return
+		}
+		if r.Method == http.MethodDelete {
+			if !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+				return
+			}
+			if writeTrackError(w, h.svc.DeleteTrack(ctx, trackID)) {
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		req, ok := decodeTrack(w, r)
+		if !ok || !roles.AuthorizeEventsManagement(w, r, h.roleService, msgSinPermisos) {
+			return
+		}
+		track, err := h.svc.UpdateTrack(ctx, trackID, req)
+		if writeTrackError(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusOK, track)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func decodeTrack(w http.ResponseWriter, r *http.Request) (dto.TrackRequest, bool) {
+	var req dto.TrackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMessage(w, http.StatusBadRequest, "json inválido")
+		return req, false
+	}
+	if err := validation.ValidarTrack(req); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+// msgSinPermisos answers callers without events.management.
+const msgSinPermisos = "no tienes permisos para gestionar tracks"
+
+func writeTrackError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrEventoNotFound), errors.Is(err, service.ErrTrackNotFound):
+		writeMessage(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrTrackExiste):
+		writeMessage(w, http.StatusConflict, err.Error())
+	default:
+		writeMessage(w, http.StatusInternalServerError, "db error")
+	}
+	return true
+}
+
+// optionalID reads a positive id from the query string; nil when absent.
+func optionalID(r *http.Request, name string) (*int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return nil, errors.New(name + " inválido")
+	}
+	return &id, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	data, _ := json.MarshalIndent(payload, "", "  ")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeConflicto answers 409 with the commitments that keep the speakers
+// busy when err is a *service.ConflictoError.
+func writeConflicto(w http.ResponseWriter, err error) bool {
+	var conflicto *service.ConflictoError
+	if !errors.As(err, &conflicto) {
+		return false
+	}
+	writeJSON(w, http.StatusConflict, dto.ConflictoPonentesResponse{
+		Message:    conflicto.Error(),
+		Conflictos: conflicto.Conflictos,
+	})
+	return true
+}
+
+// BloqueosHandler serves /api/sesiones/bloqueos, where speakers manage the
+// periods they cannot present in: GET lists the current ones, POST declares
+// one and DELETE ?id_bloqueo={id} removes it. Each speaker only sees their
+// own.
+func (h *Handler) BloqueosHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	usuarioID, ok := h.authorizePonente(w, r)
+	if !ok {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		bloqueos, err := h.svc.ListBloqueos(ctx, usuarioID)
+		if err != nil {
+			writeMessage(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, bloqueos)
+	case http.MethodPost:
+		var req dto.BloqueoRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeMessage(w, http.StatusBadRequest, "json inválido")
+			return
+		}
+		bloqueo, err := h.svc.CrearBloqueo(ctx, usuarioID, req)
+		if err != nil {
+			if errors.Is(err, service.ErrDB) {
+				writeMessage(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			writeMessage(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, bloqueo)
+	case http.MethodDelete:
+		bloqueoID, err := strconv.Atoi(r.URL.Query().Get("id_bloqueo"))
+		if err != nil || bloqueoID <= 0 {
+			writeMessage(w, http.StatusBadRequest, "id_bloqueo inválido")
+			return
+		}
+		if err := h.svc.EliminarBloqueo(ctx, usuarioID, bloqueoID); err != nil {
+			if errors.Is(err, service.ErrBloqueoNotFound) {
+				writeMessage(w, http.StatusNotFound, err.Error())
+				return
+			}
+			writeMessage(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorizePonente requires a signed-in user who currently holds the
+// speaker role and returns their id.
+func (h *Handler) authorizePonente(w http.ResponseWriter, r *http.Request) (int, bool) {
+	subject := policy.SubjectFromRequest(r)
+	if !subject.Authenticated() {
+		writeMessage(w, http.StatusUnauthorized, "autenticación requerida")
+		return 0, false
+	}
+	subject, err := roles.ActiveSubject(r.Context(), h.roleService, subject)
+	if err != nil {
+		writeMessage(w, http.StatusInternalServerError, "error verificando permisos")
+		return 0, false
+	}
+	if !subject.HasRole(validation.RolPonente) {
+		writeMessage(w, http.StatusForbidden, "solo los ponentes pueden declarar bloqueos")
+		return 0, false
+	}
+	return subject.UserID, true
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"project/backend/internal/policy"
+)
+
+func TestActualizarSesionSinCredenciales(t *testing.T) {
+	h := &Handler{policy: policy.Default()}
+
+	t.Run("path", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ActualizarSesionHandler(rec, httptest.NewRequest(http.MethodPut, "/api/sesiones/5", strings.NewReader(`{"titulo":"x"}`)))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ActualizarSesionPorQueryHandler(rec, httptest.NewRequest(http.MethodPut, "/api/sesiones?sesion_id=5", strings.NewReader(`{"titulo":"x"}`)))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"project/backend/prisma/db"
+)
+
+// UbicacionRow is where a session takes place in the agenda: its room and
+// its track, both optional.
+type UbicacionRow struct {
+	IDSesion      int     `json:"id_sesion"`
+	IDSala        *int    `json:"id_sala"`
+	Sala          *string `json:"sala"`
+	CapacidadSala *int    `json:"capacidad_sala"`
+	IDTrack       *int    `json:"id_track"`
+	Track         *string `json:"track"`
+	ColorTrack    *string `json:"color_track"`
+}
+
+type SalaRow struct {
+	IDSala int `json:"id_sala"`
+	IDSede int `json:"id_sede"`
+}
+
+type TrackRow struct {
+	IDTrack  int     `json:"id_track"`
+	IDEvento int     `json:"id_evento"`
+	Nombre   string  `json:"nombre"`
+	Color    *string `json:"color"`
+}
+
+const ubicacionSelect = `SELECT se."id_sesion", se."id_sala", sa."nombre" AS "sala", sa."capacidad" AS "capacidad_sala",
+		se."id_track", t."nombre" AS "track", t."color" AS "color_track"
+		FROM "Sesion" se
+		LEFT JOIN "Sala" sa ON sa."id_sala" = se."id_sala"
+		LEFT JOIN "Track" t ON t."id_track" = se."id_track"`
+
+// ListUbicaciones returns the room and track of every active session of an
+// event.
+func (r *Repository) ListUbicaciones(ctx context.Context, eventoID int) ([]UbicacionRow, error) {
+	query := ubicacionSelect + ` WHERE se."id_evento" = $1::int AND NOT se."cancelado"`
+	var rows []UbicacionRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetUbicacion returns the room and track of a session, or db.ErrNotFound.
+func (r *Repository) GetUbicacion(ctx context.Context, sesionID int) (UbicacionRow, error) {
+	var rows []UbicacionRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(ubicacionSelect+` WHERE se."id_sesion" = $1::int`, sesionID).Exec(ctx, &rows); err != nil {
+		return UbicacionRow{}, err
+	}
+	if len(rows) == 0 {
+		return UbicacionRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// EventoSede returns the venue of an event, nil when it has none.
+func (r *Repository) EventoSede(ctx context.Context, eventoID int) (*int, error) {
+	var rows []struct {
+		IDSede *int `json:"id_sede"`
+	}
+	query := `SELECT "id_sede" FROM "Evento" WHERE "id_evento" = $1::int`
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return rows[0].IDSede, nil
+}
+
+// FindSala returns the room, or db.ErrNotFound.
+func (r *Repository) FindSala(ctx context.Context, id int) (SalaRow, error) {
+	var rows []SalaRow
+	query := `SELECT "id_sala", "id_sede" FROM "Sala" WHERE "id_sala" = $1::int`
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, id).Exec(ctx, &rows); err != nil {
+		return SalaRow{}, err
+	}
+	if len(rows) == 0 {
+		return SalaRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+const trackSelect = `SELECT "id_track", "id_evento", "nombre", "color" FROM "Track"`
+
+// ListTracks returns the tracks of an event by name.
+func (r *Repository) ListTracks(ctx context.Context, eventoID int) ([]TrackRow, error) {
+	var rows []TrackRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(trackSelect+` WHERE "id_evento" = $1::int ORDER BY "nombre"`, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindTrack returns the track, or db.ErrNotFound.
+func (r *Repository) FindTrack(ctx context.Context, id int) (TrackRow, error) {
+	var rows []TrackRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(trackSelect+` WHERE "id_track" = $1::int`, id).Exec(ctx, &rows); err != nil {
+		return TrackRow{}, err
+	}
+	if len(rows) == 0 {
+		return TrackRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// ExistsTrack reports whether another track of the event already uses nombre.
+func (r *Repository) ExistsTrack(ctx context.Context, eventoID int, nombre string, excluirID int) (bool, error) {
+	query := `SELECT "id_track" FROM "Track" WHERE lower("nombre") = lower($2::text) AND "id_evento" = $1::int AND "id_track" <> $3::int LIMIT 1`
+	var rows []struct {
+		IDTrack int `json:"id_track"`
+	}
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, eventoID, strings.TrimSpace(nombre), excluirID).Exec(ctx, &rows); err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (r *Repository) CreateTrack(ctx context.Context, eventoID int, nombre string, color *string) (int, error) {
+	query := `INSERT INTO "Track" ("id_evento", "nombre", "color", "createdAt")
+		VALUES ($1::int, $2::text, $3::text, NOW())
+		RETURNING "id_track"`
+	var rows []struct {
+		IDTrack int `json:"id_track"`
+	}
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, eventoID, strings.TrimSpace(nombre), color).Exec(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no rows")
+	}
+	return rows[0].IDTrack, nil
+}
+
+func (r *Repository) UpdateTrack(ctx context.Context, id int, nombre string, color *string) error {
+	query := `UPDATE "Track" SET "nombre" = $2::text, "color" = $3::text WHERE "id_track" = $1::int`
+	_, err := r.prisma.Prisma.Raw.ExecuteRaw(query, id, strings.TrimSpace(nombre), color).Exec(ctx)
+	return err
+}
+
+// DeleteTrack removes a track. Its sessions stay, without a track.
+func (r *Repository) DeleteTrack(ctx context.Context, id int) error {
+	_, err := r.prisma.Prisma.Raw.ExecuteRaw(`DELETE FROM "Track" WHERE "id_track" = $1::int`, id).Exec(ctx)
+	return err
+}
+
+type SalaEventoRow struct {
+	IDSala    int    `json:"id_sala"`
+	Nombre    string `json:"nombre"`
+	Capacidad *int   `json:"capacidad"`
+}
+
+// ListSalasEvento returns the rooms of the event's venue by name, none when
+// the event has no venue.
+func (r *Repository) ListSalasEvento(ctx context.Context, eventoID int) ([]SalaEventoRow, error) {
+	query := `SELECT sa."id_sala", sa."nombre", sa."capacidad"
+		FROM "Sala" sa
+		JOIN "Evento" e ON e."id_sede" = sa."id_sede"
+		WHERE e."id_evento" = $1::int
+		ORDER BY sa."nombre"`
+	var rows []SalaEventoRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, eventoID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
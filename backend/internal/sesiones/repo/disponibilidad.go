@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"project/backend/prisma/db"
+)
+
+// Kinds of commitment that keep a speaker from presenting a session.
+const (
+	ConflictoSesion  = "sesion"
+	ConflictoBloqueo = "bloqueo"
+)
+
+// ConflictoRow is a commitment of a speaker that overlaps a time range:
+// another session they present, in any event, or a blackout period they
+// declared. Only the fields of its kind are set.
+type ConflictoRow struct {
+	IDUsuario int       `json:"id_usuario"`
+	Ponente   string    `json:"ponente"`
+	Tipo      string    `json:"tipo"`
+	IDSesion  *int      `json:"id_sesion"`
+	Sesion    *string   `json:"sesion"`
+	IDEvento  *int      `json:"id_evento"`
+	Evento    *string   `json:"evento"`
+	IDBloqueo *int      `json:"id_bloqueo"`
+	Motivo    *string   `json:"motivo"`
+	Inicio    time.Time `json:"inicio"`
+	Fin       time.Time `json:"fin"`
+}
+
+type BloqueoRow struct {
+	IDBloqueo int       `json:"id_bloqueo"`
+	IDUsuario int       `json:"id_usuario"`
+	Inicio    time.Time `json:"inicio"`
+	Fin       time.Time `json:"fin"`
+	Motivo    *string   `json:"motivo"`
+}
+
+// ConflictosSQL selects, as ConflictoRow, the commitments that overlap the
+// slots in candidatas: a relation of "id_usuario", "inicio", "fin",
+// "excluir_sesion" and "excluir_evento", the session and event whose own
+// slots do not count (0 for none). Cancelled sessions and sessions of
+// cancelled events do not count, and back-to-back sessions do not overlap.
+// Other packages embed it to run the check in the same transaction as
+// their write.
+func ConflictosSQL(candidatas string) string {
+	return `SELECT sp."id_usuario", u."nombre" AS "ponente", '` + ConflictoSesion + `' AS "tipo",
+			s."id_sesion", s."titulo" AS "sesion", e."id_evento", e."nombre" AS "evento",
+			NULL::int AS "id_bloqueo", NULL::text AS "motivo", s."fecha_inicio" AS "inicio", s."fecha_fin" AS "fin"
+		FROM (` + candidatas + `) c
+		JOIN "SesionPonente" sp ON sp."id_usuario" = c."id_usuario"
+		JOIN "Sesion" s ON s."id_sesion" = sp."id_sesion"
+		JOIN "Evento" e ON e."id_evento" = s."id_evento"
+		JOIN "Usuario" u ON u."id_usuario" = sp."id_usuario"
+		WHERE s."id_sesion" <> c."excluir_sesion" AND s."id_evento" <> c."excluir_evento"
+			AND NOT s."cancelado" AND NOT e."cancelado" AND e."estado" <> 'Cancelado'
+			AND s."fecha_inicio" < c."fin" AND s."fecha_fin" > c."inicio"
+		UNION
+		SELECT b."id_usuario", u."nombre", '` + ConflictoBloqueo + `',
+			NULL::int, NULL::text, NULL::int, NULL::text,
+			b."id_bloqueo", b."motivo", b."inicio", b."fin"
+		FROM (` + candidatas + `) c
+		JOIN "BloqueoPonente" b ON b."id_usuario" = c."id_usuario"
+		JOIN "Usuario" u ON u."id_usuario" = b."id_usuario"
+		WHERE b."inicio" < c."fin" AND b."fin" > c."inicio"
+		ORDER BY "id_usuario", "inicio"`
+}
+
+// BloquearPonentesSQL locks the speakers selected by usuarios, a query of
+// "id_usuario", so concurrent checks of the same speakers wait for each
+// other. It takes no key lock, so rows referencing them can still be
+// inserted.
+func BloquearPonentesSQL(usuarios string) string {
+	return `SELECT "id_usuario" FROM "Usuario" WHERE "id_usuario" IN (` + usuarios + `)
+		ORDER BY "id_usuario" FOR NO KEY UPDATE`
+}
+
+// ListConflictos returns the commitments of the speakers that overlap
+// [inicio, fin), by speaker and start, leaving out excluirSesionID, the
+// session being checked.
+func (r *Repository) ListConflictos(ctx context.Context, usuarios []int, inicio, fin time.Time, excluirSesionID int) ([]ConflictoRow, error) {
+	query := ConflictosSQL(`SELECT u AS "id_usuario", ($2::timestamptz AT TIME ZONE 'UTC') AS "inicio",
+			($3::timestamptz AT TIME ZONE 'UTC') AS "fin", $4::int AS "excluir_sesion", 0 AS "excluir_evento"
+		FROM unnest($1::int[]) AS u`)
+	var rows []ConflictoRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, usuarios, inicio.UTC(), fin.UTC(), excluirSesionID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+const bloqueoSelect = `SELECT "id_bloqueo", "id_usuario", "inicio", "fin", "motivo" FROM "BloqueoPonente"`
+
+// ListBloqueos returns the blackout periods of a speaker that have not
+// ended yet, by start.
+func (r *Repository) ListBloqueos(ctx context.Context, usuarioID int) ([]BloqueoRow, error) {
+	query := bloqueoSelect + ` WHERE "id_usuario" = $1::int AND "fin" > NOW() ORDER BY "inicio", "id_bloqueo"`
+	var rows []BloqueoRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, usuarioID).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindBloqueo returns a blackout period of the speaker, or db.ErrNotFound
+// when it does not exist or belongs to someone else.
+func (r *Repository) FindBloqueo(ctx context.Context, usuarioID, id int) (BloqueoRow, error) {
+	var rows []BloqueoRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(bloqueoSelect+` WHERE "id_bloqueo" = $1::int AND "id_usuario" = $2::int`, id, usuarioID).Exec(ctx, &rows); err != nil {
+		return BloqueoRow{}, err
+	}
+	if len(rows) == 0 {
+		return BloqueoRow{}, db.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+func (r *Repository) CreateBloqueo(ctx context.Context, usuarioID int, inicio, fin time.Time, motivo *string) (BloqueoRow, error) {
+	query := `INSERT INTO "BloqueoPonente" ("id_usuario", "inicio", "fin", "motivo", "createdAt")
+		VALUES ($1::int, ($2::timestamptz AT TIME ZONE 'UTC'), ($3::timestamptz AT TIME ZONE 'UTC'), $4::text, NOW())
+		RETURNING "id_bloqueo", "id_usuario", "inicio", "fin", "motivo"`
+	var rows []BloqueoRow
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, usuarioID, inicio.UTC(), fin.UTC(), motivo).Exec(ctx, &rows); err != nil {
+		return BloqueoRow{}, err
+	}
+	if len(rows) == 0 {
+		return BloqueoRow{}, fmt.Errorf("no rows")
+	}
+	return rows[0], nil
+}
+
+func (r *Repository) DeleteBloqueo(ctx context.Context, usuarioID, id int) error {
+	query := `DELETE FROM "BloqueoPonente" WHERE "id_bloqueo" = $1::int AND "id_usuario" = $2::int`
+	_, err := r.prisma.Prisma.Raw.ExecuteRaw(query, id, usuarioID).Exec(ctx)
+	return err
+}
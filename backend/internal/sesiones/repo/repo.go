@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"fmt"
 	"project/backend/prisma/db"
 	"time"
 )
@@ -14,7 +15,9 @@ func NewRepository(prismaClient *db.PrismaClient) *Repository {
 	return &Repository{prisma: prismaClient}
 }
 
-func (r *Repository) CreateSesion(ctx context.Context, eventoID int, titulo, descripcion, fechaInicio, fechaFin, ubicacion string) (int, error) {
+// CreateSesion inserts the session together with its optional room and
+// track.
+func (r *Repository) CreateSesion(ctx context.Context, eventoID int, titulo, descripcion, fechaInicio, fechaFin, ubicacion string, salaID, trackID *int) (int, error) {
 	fechaInicioTime, err := time.Parse(time.RFC3339, fechaInicio)
 	if err != nil {
 		return 0, err
@@ -23,18 +26,19 @@ func (r *Repository) CreateSesion(ctx context.Context, eventoID int, titulo, des
 	if err != nil {
 		return 0, err
 	}
-	s, err := r.prisma.Sesion.CreateOne(
-		db.Sesion.Titulo.Set(titulo),
-		db.Sesion.Descripcion.Set(descripcion),
-		db.Sesion.FechaInicio.Set(fechaInicioTime),
-		db.Sesion.FechaFin.Set(fechaFinTime),
-		db.Sesion.Ubicacion.Set(ubicacion),
-		db.Sesion.Evento.Link(db.Evento.IDEvento.Equals(eventoID)),
-	).Exec(ctx)
-	if err != nil {
+	query := `INSERT INTO "Sesion" ("titulo", "descripcion", "fecha_inicio", "fecha_fin", "ubicacion", "id_evento", "id_sala", "id_track", "createdAt")
+		VALUES ($1::text, $2::text, $3::timestamptz AT TIME ZONE 'UTC', $4::timestamptz AT TIME ZONE 'UTC', $5::text, $6::int, $7::int, $8::int, NOW())
+		RETURNING "id_sesion"`
+	var rows []struct {
+		IDSesion int `json:"id_sesion"`
+	}
+	if err := r.prisma.Prisma.Raw.QueryRaw(query, titulo, descripcion, fechaInicioTime, fechaFinTime, ubicacion, eventoID, salaID, trackID).Exec(ctx, &rows); err != nil {
 		return 0, err
 	}
-	return s.IDSesion, nil
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no rows")
+	}
+	return rows[0].IDSesion, nil
 }
 
 func (r *Repository) ListSesiones(ctx context.Context, eventoID int) ([]db.SesionModel, error) {
@@ -51,25 +55,57 @@ func (r *Repository) GetSesionByID(ctx context.Context, sesionID int) (*db.Sesio
 	).Exec(ctx)
 }
 
-func (r *Repository) UpdateSesion(ctx context.Context, sesionID int, titulo, descripcion, fechaInicio, fechaFin, ubicacion string) error {
+// lockEventoSesion keeps the event of session $1 from being rescheduled
+// while a session of it is checked against its speakers' commitments.
+const lockEventoSesion = `SELECT "id_evento" FROM "Evento"
+	WHERE "id_evento" = (SELECT "id_evento" FROM "Sesion" WHERE "id_sesion" = $1::int) FOR KEY SHARE`
+
+// ponentesSesion selects the speakers of session $1.
+const ponentesSesion = `SELECT "id_usuario" FROM "SesionPonente" WHERE "id_sesion" = $1::int`
+
+// UpdateSesion stores the session; a nil salaID or trackID takes it out of
+// its room or track, and neither changes unless cambiaUbicacion is set.
+// When the new times move the session onto a commitment of one of its
+// speakers nothing is stored and the conflicts are returned. The check and
+// the update run in one transaction holding locks on the event, the session
+// and its speakers.
+func (r *Repository) UpdateSesion(ctx context.Context, sesionID int, titulo, descripcion, fechaInicio, fechaFin, ubicacion string, cambiaUbicacion bool, salaID, trackID *int) ([]ConflictoRow, error) {
 	fechaInicioTime, err := time.Parse(time.RFC3339, fechaInicio)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	fechaFinTime, err := time.Parse(time.RFC3339, fechaFin)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = r.prisma.Sesion.FindUnique(
-		db.Sesion.IDSesion.Equals(sesionID),
-	).Update(
-		db.Sesion.Titulo.Set(titulo),
-		db.Sesion.Descripcion.Set(descripcion),
-		db.Sesion.FechaInicio.Set(fechaInicioTime),
-		db.Sesion.FechaFin.Set(fechaFinTime),
-		db.Sesion.Ubicacion.Set(ubicacion),
-	).Exec(ctx)
-	return err
+	lockSesion := `SELECT "id_sesion" FROM "Sesion" WHERE "id_sesion" = $1::int FOR NO KEY UPDATE`
+	candidatas := `SELECT sp."id_usuario", ($2::timestamptz AT TIME ZONE 'UTC') AS "inicio", ($3::timestamptz AT TIME ZONE 'UTC') AS "fin",
+			s."id_sesion" AS "excluir_sesion", 0 AS "excluir_evento"
+		FROM "Sesion" s JOIN "SesionPonente" sp ON sp."id_sesion" = s."id_sesion"
+		WHERE s."id_sesion" = $1::int
+			AND (s."fecha_inicio", s."fecha_fin") IS DISTINCT FROM ($2::timestamptz AT TIME ZONE 'UTC', $3::timestamptz AT TIME ZONE 'UTC')`
+	update := `UPDATE "Sesion" SET "fecha_inicio" = $2::timestamptz AT TIME ZONE 'UTC', "fecha_fin" = $3::timestamptz AT TIME ZONE 'UTC',
+			"titulo" = $4::text, "descripcion" = $5::text, "ubicacion" = $6::text,
+			"id_sala" = CASE WHEN $7::boolean THEN $8::int ELSE "id_sala" END,
+			"id_track" = CASE WHEN $7::boolean THEN $9::int ELSE "id_track" END
+		WHERE "id_sesion" = $1::int AND NOT EXISTS (` + ConflictosSQL(candidatas) + `)`
+
+	conflictos := r.prisma.Prisma.Raw.QueryRaw(ConflictosSQL(candidatas), sesionID, fechaInicioTime, fechaFinTime).Tx()
+	if err := r.prisma.Prisma.Transaction(
+		r.prisma.Prisma.Raw.QueryRaw(lockEventoSesion, sesionID).Tx(),
+		r.prisma.Prisma.Raw.QueryRaw(lockSesion, sesionID).Tx(),
+		r.prisma.Prisma.Raw.QueryRaw(BloquearPonentesSQL(ponentesSesion), sesionID).Tx(),
+		conflictos,
+		r.prisma.Prisma.Raw.ExecuteRaw(update, sesionID, fechaInicioTime, fechaFinTime, titulo, descripcion, ubicacion,
+			cambiaUbicacion, salaID, trackID).Tx(),
+	).Exec(ctx); err != nil {
+		return nil, err
+	}
+	var rows []ConflictoRow
+	if err := conflictos.Into(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
 }
 
 func (r *Repository) DeleteSesion(ctx context.Context, sesionID int) error {
@@ -81,17 +117,36 @@ func (r *Repository) DeleteSesion(ctx context.Context, sesionID int) error {
 	return err
 }
 
-func (r *Repository) AsignarPonentes(ctx context.Context, sesionID int, usuarios []int) error {
-	for _, userID := range usuarios {
-		_, err := r.prisma.SesionPonente.CreateOne(
-			db.SesionPonente.Sesion.Link(db.Sesion.IDSesion.Equals(sesionID)),
-			db.SesionPonente.Usuario.Link(db.Usuario.IDUsuario.Equals(userID)),
-		).Exec(ctx)
-		if err != nil {
-			return err
-		}
+// AsignarPonentes assigns the speakers to the session unless one of them is
+// busy at its time, in which case nothing is assigned and the conflicts are
+// returned. The check and the assignment run in one transaction holding
+// locks on the event, the session and the speakers, so a concurrent
+// assignment or reschedule cannot slip in between.
+func (r *Repository) AsignarPonentes(ctx context.Context, sesionID int, usuarios []int) ([]ConflictoRow, error) {
+	lockSesion := `SELECT "id_sesion" FROM "Sesion" WHERE "id_sesion" = $1::int FOR SHARE`
+	candidatas := `SELECT u AS "id_usuario", s."fecha_inicio" AS "inicio", s."fecha_fin" AS "fin",
+			s."id_sesion" AS "excluir_sesion", 0 AS "excluir_evento"
+		FROM "Sesion" s CROSS JOIN unnest($2::int[]) AS u
+		WHERE s."id_sesion" = $1::int`
+	asignar := `INSERT INTO "SesionPonente" ("id_sesion", "id_usuario")
+		SELECT $1::int, t."id_usuario" FROM unnest($2::int[]) AS t("id_usuario")
+		WHERE NOT EXISTS (` + ConflictosSQL(candidatas) + `)`
+
+	conflictos := r.prisma.Prisma.Raw.QueryRaw(ConflictosSQL(candidatas), sesionID, usuarios).Tx()
+	if err := r.prisma.Prisma.Transaction(
+		r.prisma.Prisma.Raw.QueryRaw(lockEventoSesion, sesionID).Tx(),
+		r.prisma.Prisma.Raw.QueryRaw(lockSesion, sesionID).Tx(),
+		r.prisma.Prisma.Raw.QueryRaw(BloquearPonentesSQL(`SELECT unnest($1::int[])`), usuarios).Tx(),
+		conflictos,
+		r.prisma.Prisma.Raw.ExecuteRaw(asignar, sesionID, usuarios).Tx(),
+	).Exec(ctx); err != nil {
+		return nil, err
+	}
+	var rows []ConflictoRow
+	if err := conflictos.Into(&rows); err != nil {
+		return nil, err
 	}
-	return nil
+	return rows, nil
 }
 
 func (r *Repository) QuitarPonente(ctx context.Context, sesionID int, usuarioID int) error {
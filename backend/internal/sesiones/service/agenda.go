@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"project/backend/internal/sesiones/dto"
+	"project/backend/internal/sesiones/repo"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrEventoNotFound  = errors.New("Evento no encontrado")
+	ErrSalaNotFound    = errors.New("Sala no encontrada")
+	ErrSalaOtraSede    = errors.New("La sala no pertenece a la sede del evento")
+	ErrTrackNotFound   = errors.New("Track no encontrado")
+	ErrTrackOtroEvento = errors.New("El track no pertenece al evento")
+	ErrTrackExiste     = errors.New("Ya existe un track con ese nombre en el evento")
+)
+
+// Agenda returns the sessions of an event grouped by room or by track, as
+// the columns of a grid. Every room of the venue or track of the event gets
+// a column, even an empty one, unless the agenda is filtered; sessions
+// without a room or track go last.
+func (s *Service) Agenda(ctx context.Context, eventoID int, salaID, trackID *int, agrupar string) ([]dto.AgendaGrupo, error) {
+	sesiones, err := s.ListSesiones(ctx, eventoID, salaID, trackID)
+	if err != nil {
+		return nil, err
+	}
+
+	var columnas []dto.AgendaGrupo
+	switch agrupar {
+	case dto.AgruparSala:
+		salas, err := s.repo.ListSalasEvento(ctx, eventoID)
+		if err != nil {
+			return nil, ErrDB
+		}
+		for _, sala := range salas {
+			id := sala.IDSala
+			columnas = append(columnas, dto.AgendaGrupo{ID: &id, Nombre: sala.Nombre, Capacidad: sala.Capacidad})
+		}
+	case dto.AgruparTrack:
+		tracks, err := s.repo.ListTracks(ctx, eventoID)
+		if err != nil {
+			return nil, ErrDB
+		}
+		for _, track := range tracks {
+			columnas = append(columnas, trackGrupo(track))
+		}
+	default:
+		return nil, ErrInvalid
+	}
+	return agruparSesiones(sesiones, columnas, agrupar, salaID == nil && trackID == nil), nil
+}
+
+// agruparSesiones puts each session, already in schedule order, into the
+// column of its room or track. Columns without sessions are dropped unless
+// vacias is set.
+func agruparSesiones(sesiones []dto.SesionResponse, columnas []dto.AgendaGrupo, agrupar string, vacias bool) []dto.AgendaGrupo {
+	sinGrupo := dto.AgendaGrupo{Nombre: "Sin sala"}
+	if agrupar == dto.AgruparTrack {
+		sinGrupo.Nombre = "Sin track"
+	}
+	indice := make(map[int]int, len(columnas))
+	for i, c := range columnas {
+		columnas[i].Sesiones = make([]dto.SesionResponse, 0)
+		indice[*c.ID] = i
+	}
+
+	for _, sesion := range sesiones {
+		id := sesion.IDSala
+		if agrupar == dto.AgruparTrack {
+			id = sesion.IDTrack
+		}
+		if id == nil {
+			sinGrupo.Sesiones = append(sinGrupo.Sesiones, sesion)
+			continue
+		}
+		i, ok := indice[*id]
+		if !ok {
+			// A room left behind when the event changed venue.
+			grupo := dto.AgendaGrupo{ID: id, Nombre: sesion.Sala, Sesiones: make([]dto.SesionResponse, 0)}
+			if agrupar == dto.AgruparTrack {
+				grupo.Nombre = sesion.Track
+			}
+			columnas = append(columnas, grupo)
+			i = len(columnas) - 1
+			indice[*id] = i
+		}
+		columnas[i].Sesiones = append(columnas[i].Sesiones, sesion)
+	}
+
+	grupos := make([]dto.AgendaGrupo, 0, len(columnas)+1)
+	for _, c := range columnas {
+		if len(c.Sesiones) > 0 || vacias {
+			grupos = append(grupos, c)
+		}
+	}
+	if len(sinGrupo.Sesiones) > 0 {
+		grupos = append(grupos, sinGrupo)
+	}
+	return grupos
+}
+
+func (s *Service) ListTracks(ctx context.Context, eventoID int) ([]dto.TrackResponse, error) {
+	if err := s.ensureEvento(ctx, eventoID); err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListTracks(ctx, eventoID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	resp := make([]dto.TrackResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, trackResponse(row))
+	}
+	return resp, nil
+}
+
+func (s *Service) CreateTrack(ctx context.Context, eventoID int, req dto.TrackRequest) (dto.TrackResponse, error) {
+	if err := s.ensureEvento(ctx, eventoID); err != nil {
+		return dto.TrackResponse{}, err
+	}
+	if err := s.ensureTrackLibre(ctx, eventoID, req.Nombre, 0); err != nil {
+		return dto.TrackResponse{}, err
+	}
+	id, err := s.repo.CreateTrack(ctx, eventoID, req.Nombre, color(req.Color))
+	if err != nil {
+		return dto.TrackResponse{}, ErrDB
+	}
+	return s.getTrack(ctx, id)
+}
+
+func (s *Service) UpdateTrack(ctx context.Context, trackID int, req dto.TrackRequest) (dto.TrackResponse, error) {
+	track, err := s.getTrack(ctx, trackID)
+	if err != nil {
+		return dto.TrackResponse{}, err
+	}
+	if err := s.ensureTrackLibre(ctx, track.IDEvento, req.Nombre, trackID); err != nil {
+		return dto.TrackResponse{}, err
+	}
+	if err := s.repo.UpdateTrack(ctx, trackID, req.Nombre, color(req.Color)); err != nil {
+		return dto.TrackResponse{}, ErrDB
+	}
+	return s.getTrack(ctx, trackID)
+}
+
+// DeleteTrack removes a track; its sessions are kept, without a track.
+func (s *Service) DeleteTrack(ctx context.Context, trackID int) error {
+	if _, err := s.getTrack(ctx, trackID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTrack(ctx, trackID); err != nil {
+		return ErrDB
+	}
+	return nil
+}
+
+func (s *Service) getTrack(ctx context.Context, id int) (dto.TrackResponse, error) {
+	row, err := s.repo.FindTrack(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return dto.TrackResponse{}, ErrTrackNotFound
+		}
+		return dto.TrackResponse{}, ErrDB
+	}
+	return trackResponse(row), nil
+}
+
+func (s *Service) ensureTrackLibre(ctx context.Context, eventoID int, nombre string, excluirID int) error {
+	taken, err := s.repo.ExistsTrack(ctx, eventoID, nombre, excluirID)
+	if err != nil {
+		return ErrDB
+	}
+	if taken {
+		return ErrTrackExiste
+	}
+	return nil
+}
+
+func (s *Service) ensureEvento(ctx context.Context, eventoID int) error {
+	if _, err := s.repo.EventoSede(ctx, eventoID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ErrEventoNotFound
+		}
+		return ErrDB
+	}
+	return nil
+}
+
+// validarUbicacion checks that a session's room is one of the event's venue
+// and its track one of the event.
+func (s *Service) validarUbicacion(ctx context.Context, eventoID int, salaID, trackID *int) error {
+	if salaID != nil {
+		sala, err := s.repo.FindSala(ctx, *salaID)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrSalaNotFound
+			}
+			return ErrDB
+		}
+		sedeID, err := s.repo.EventoSede(ctx, eventoID)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrEventoNotFound
+			}
+			return ErrDB
+		}
+		if sedeID == nil || *sedeID != sala.IDSede {
+			return ErrSalaOtraSede
+		}
+	}
+	if trackID != nil {
+		track, err := s.repo.FindTrack(ctx, *trackID)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrTrackNotFound
+			}
+			return ErrDB
+		}
+		if track.IDEvento != eventoID {
+			return ErrTrackOtroEvento
+		}
+	}
+	return nil
+}
+
+// salasPorSesion maps each active session of the event to its room.
+func (s *Service) salasPorSesion(ctx context.Context, eventoID int) (map[int]*int, error) {
+	ubicaciones, err := s.repo.ListUbicaciones(ctx, eventoID)
+	if err != nil {
+		return nil, err
+	}
+	salas := make(map[int]*int, len(ubicaciones))
+	for _, u := range ubicaciones {
+		salas[u.IDSesion] = u.IDSala
+	}
+	return salas, nil
+}
+
+// ubicacion returns the room and track of a session, empty if they cannot
+// be read, the same way a session's speakers are.
+func (s *Service) ubicacion(ctx context.Context, sesionID int) repo.UbicacionRow {
+	u, err := s.repo.GetUbicacion(ctx, sesionID)
+	if err != nil {
+		return repo.UbicacionRow{IDSesion: sesionID}
+	}
+	return u
+}
+
+func mismoID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func color(valor string) *string {
+	valor = strings.ToUpper(strings.TrimSpace(valor))
+	if valor == "" {
+		return nil
+	}
+	return &valor
+}
+
+func trackGrupo(row repo.TrackRow) dto.AgendaGrupo {
+	id := row.IDTrack
+	grupo := dto.AgendaGrupo{ID: &id, Nombre: row.Nombre}
+	if row.Color != nil {
+		grupo.Color = *row.Color
+	}
+	return grupo
+}
+
+func trackResponse(row repo.TrackRow) dto.TrackResponse {
+	resp := dto.TrackResponse{IDTrack: row.IDTrack, IDEvento: row.IDEvento, Nombre: row.Nombre}
+	if row.Color != nil {
+		resp.Color = *row.Color
+	}
+	return resp
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"project/backend/internal/sesiones/dto"
+)
+
+func TestAgruparSesiones(t *testing.T) {
+	salaA, salaB, antigua := 1, 2, 9
+	sesiones := []dto.SesionResponse{
+		{IDSesion: 10, IDSala: &salaA},
+		{IDSesion: 11},
+		{IDSesion: 12, IDSala: &antigua, Sala: "Auditorio anterior"},
+		{IDSesion: 13, IDSala: &salaA},
+	}
+	columnas := func() []dto.AgendaGrupo {
+		return []dto.AgendaGrupo{{ID: &salaA, Nombre: "Sala A"}, {ID: &salaB, Nombre: "Sala B"}}
+	}
+
+	grupos := agruparSesiones(sesiones, columnas(), dto.AgruparSala, true)
+	want := []struct {
+		nombre   string
+		sesiones []int
+	}{
+		{"Sala A", []int{10, 13}},
+		{"Sala B", nil},
+		{"Auditorio anterior", []int{12}},
+		{"Sin sala", []int{11}},
+	}
+	if len(grupos) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(grupos), len(want))
+	}
+	for i, w := range want {
+		if grupos[i].Nombre != w.nombre {
+			t.Errorf("group %d: got %q, want %q", i, grupos[i].Nombre, w.nombre)
+		}
+		if grupos[i].Sesiones == nil {
+			t.Errorf("group %q: sessions must not be nil", w.nombre)
+		}
+		if len(grupos[i].Sesiones) != len(w.sesiones) {
+			t.Fatalf("group %q: got %d sessions, want %d", w.nombre, len(grupos[i].Sesiones), len(w.sesiones))
+		}
+		for j, id := range w.sesiones {
+			if grupos[i].Sesiones[j].IDSesion != id {
+				t.Errorf("group %q: session %d is %d, want %d", w.nombre, j, grupos[i].Sesiones[j].IDSesion, id)
+			}
+		}
+	}
+
+	// A filtered agenda leaves out the empty columns.
+	if grupos := agruparSesiones(sesiones[:1], columnas(), dto.AgruparSala, false); len(grupos) != 1 || grupos[0].Nombre != "Sala A" {
+		t.Fatalf("filtered: got %+v", grupos)
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"project/backend/internal/sesiones/dto"
+	"project/backend/internal/sesiones/repo"
+	validation "project/backend/internal/sesiones/validation"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrPonenteOcupado  = errors.New("Uno o más ponentes no están disponibles en el horario de la sesión")
+	ErrBloqueoNotFound = errors.New("Bloqueo no encontrado")
+)
+
+const formatoFecha = "2006-01-02T15:04:05Z"
+
+// ConflictoError lists the commitments that keep speakers from presenting a
+// session at its time. It matches ErrPonenteOcupado with errors.Is.
+type ConflictoError struct {
+	Conflictos []dto.ConflictoResponse
+}
+
+func (e *ConflictoError) Error() string {
+	return ErrPonenteOcupado.Error()
+}
+
+func (e *ConflictoError) Unwrap() error {
+	return ErrPonenteOcupado
+}
+
+// NewConflictoError returns the error for the commitments in rows, nil when
+// there are none. Callers that move sessions of another package use it to
+// answer with the same conflict body.
+func NewConflictoError(rows []repo.ConflictoRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return &ConflictoError{Conflictos: conflictosResponse(rows)}
+}
+
+// ListBloqueos returns the blackout periods of a speaker that have not
+// ended yet.
+func (s *Service) ListBloqueos(ctx context.Context, usuarioID int) ([]dto.BloqueoResponse, error) {
+	rows, err := s.repo.ListBloqueos(ctx, usuarioID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	resp := make([]dto.BloqueoResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, bloqueoResponse(row))
+	}
+	return resp, nil
+}
+
+// CrearBloqueo records a blackout period for a speaker. Sessions they are
+// already assigned to within it are not touched; they come back in the
+// response so the speaker can warn the organizers.
+func (s *Service) CrearBloqueo(ctx context.Context, usuarioID int, req dto.BloqueoRequest) (dto.BloqueoResponse, error) {
+	inicio, err := time.Parse(time.RFC3339, req.Inicio)
+	if err != nil {
+		return dto.BloqueoResponse{}, errors.New("Fecha de inicio inválida")
+	}
+	fin, err := time.Parse(time.RFC3339, req.Fin)
+	if err != nil {
+		return dto.BloqueoResponse{}, errors.New("Fecha de fin inválida")
+	}
+	if err := validation.ValidarBloqueo(inicio, fin, time.Now(), req.Motivo); err != nil {
+		return dto.BloqueoResponse{}, err
+	}
+	var motivo *string
+	if m := strings.TrimSpace(req.Motivo); m != "" {
+		motivo = &m
+	}
+	row, err := s.repo.CreateBloqueo(ctx, usuarioID, inicio, fin, motivo)
+	if err != nil {
+		return dto.BloqueoResponse{}, ErrDB
+	}
+	resp := bloqueoResponse(row)
+	conflictos, err := s.repo.ListConflictos(ctx, []int{usuarioID}, inicio, fin, 0)
+	if err != nil {
+		return resp, nil
+	}
+	for _, c := range conflictosResponse(conflictos) {
+		if c.Tipo == repo.ConflictoSesion {
+			resp.Conflictos = append(resp.Conflictos, c)
+		}
+	}
+	return resp, nil
+}
+
+func (s *Service) EliminarBloqueo(ctx context.Context, usuarioID, bloqueoID int) error {
+	if _, err := s.repo.FindBloqueo(ctx, usuarioID, bloqueoID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ErrBloqueoNotFound
+		}
+		return ErrDB
+	}
+	if err := s.repo.DeleteBloqueo(ctx, usuarioID, bloqueoID); err != nil {
+		return ErrDB
+	}
+	return nil
+}
+
+func conflictosResponse(rows []repo.ConflictoRow) []dto.ConflictoResponse {
+	resp := make([]dto.ConflictoResponse, 0, len(rows))
+	for _, row := range rows {
+		c := dto.ConflictoResponse{
+			IDUsuario: row.IDUsuario,
+			Ponente:   row.Ponente,
+			Tipo:      row.Tipo,
+			IDSesion:  row.IDSesion,
+			IDEvento:  row.IDEvento,
+			IDBloqueo: row.IDBloqueo,
+			Inicio:    row.Inicio.UTC().Format(formatoFecha),
+			Fin:       row.Fin.UTC().Format(formatoFecha),
+		}
+		if row.Sesion != nil {
+			c.Sesion = *row.Sesion
+		}
+		if row.Evento != nil {
+			c.Evento = *row.Evento
+		}
+		if row.Motivo != nil {
+			c.Motivo = *row.Motivo
+		}
+		resp = append(resp, c)
+	}
+	return resp
+}
+
+func bloqueoResponse(row repo.BloqueoRow) dto.BloqueoResponse {
+	resp := dto.BloqueoResponse{
+		IDBloqueo: row.IDBloqueo,
+		Inicio:    row.Inicio.UTC().Format(formatoFecha),
+		Fin:       row.Fin.UTC().Format(formatoFecha),
+	}
+	if row.Motivo != nil {
+		resp.Motivo = *row.Motivo
+	}
+	return resp
+}
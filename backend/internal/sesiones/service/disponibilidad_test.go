@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"project/backend/internal/sesiones/repo"
+)
+
+func TestConflictosResponse(t *testing.T) {
+	inicio := time.Date(2026, 11, 3, 9, 0, 0, 0, time.UTC)
+	sesionID, eventoID, bloqueoID := 7, 3, 12
+	titulo, evento, motivo := "Keynote", "Congreso de Redes", "Vuelo"
+	rows := []repo.ConflictoRow{
+		{IDUsuario: 5, Ponente: "Ana", Tipo: repo.ConflictoSesion, IDSesion: &sesionID, Sesion: &titulo, IDEvento: &eventoID, Evento: &evento, Inicio: inicio, Fin: inicio.Add(time.Hour)},
+		{IDUsuario: 5, Ponente: "Ana", Tipo: repo.ConflictoBloqueo, IDBloqueo: &bloqueoID, Motivo: &motivo, Inicio: inicio.Add(-time.Hour), Fin: inicio.Add(3 * time.Hour)},
+	}
+
+	got := conflictosResponse(rows)
+	if len(got) != 2 {
+		t.Fatalf("got %d conflicts, want 2", len(got))
+	}
+	if got[0].Sesion != titulo || got[0].Evento != evento || *got[0].IDSesion != sesionID || got[0].IDBloqueo != nil {
+		t.Errorf("session conflict: got %+v", got[0])
+	}
+	if got[0].Inicio != "2026-11-03T09:00:00Z" || got[0].Fin != "2026-11-03T10:00:00Z" {
+		t.Errorf("session conflict times: got %s - %s", got[0].Inicio, got[0].Fin)
+	}
+	if got[1].Motivo != motivo || *got[1].IDBloqueo != bloqueoID || got[1].IDSesion != nil || got[1].Sesion != "" {
+		t.Errorf("blackout conflict: got %+v", got[1])
+	}
+
+	var err error = fmt.Errorf("asignar: %w", &ConflictoError{Conflictos: got})
+	if !errors.Is(err, ErrPonenteOcupado) {
+		t.Fatalf("ConflictoError must match ErrPonenteOcupado")
+	}
+	var conflicto *ConflictoError
+	if !errors.As(err, &conflicto) || len(conflicto.Conflictos) != 2 {
+		t.Fatalf("errors.As: got %+v", conflicto)
+	}
+}
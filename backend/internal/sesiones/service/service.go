@@ -7,6 +7,7 @@ import (
 	"project/backend/internal/sesiones/repo"
 	validation "project/backend/internal/sesiones/validation"
 	"project/backend/prisma/db"
+	"sort"
 	"time"
 )
 
@@ -65,24 +66,31 @@ func (s *Service) CreateSesion(ctx context.Context, eventoID int, req dto.Create
 		println("[CreateSesion] Validación duración falló:", err.Error())
 		return nil, err
 	}
+	if err := s.validarUbicacion(ctx, eventoID, req.IDSala, req.IDTrack); err != nil {
+		return nil, err
+	}
 	println("[CreateSesion] Listando sesiones del evento")
 	sesiones, err := s.repo.ListSesiones(ctx, eventoID)
 	if err != nil {
 		println("[CreateSesion] Error al listar sesiones")
 		return nil, ErrDB
 	}
+	salas, err := s.salasPorSesion(ctx, eventoID)
+	if err != nil {
+		return nil, ErrDB
+	}
 	println("[CreateSesion] Validando título único")
 	if err := validation.ValidarTituloUnico(sesiones, req.Titulo); err != nil {
 		println("[CreateSesion] Validación título único falló:", err.Error())
 		return nil, err
 	}
 	println("[CreateSesion] Validando solapamiento")
-	if err := validation.ValidarSolapamiento(sesiones, fechaInicio, fechaFin); err != nil {
+	if err := validation.ValidarSolapamiento(validation.SesionesEnSala(sesiones, salas, req.IDSala), fechaInicio, fechaFin); err != nil {
 		println("[CreateSesion] Validación solapamiento falló:", err.Error())
 		return nil, err
 	}
 	println("[CreateSesion] Creando sesión en BD")
-	id, err := s.repo.CreateSesion(ctx, eventoID, req.Titulo, req.Descripcion, req.FechaInicio, req.FechaFin, req.Ubicacion)
+	id, err := s.repo.CreateSesion(ctx, eventoID, req.Titulo, req.Descripcion, req.FechaInicio, req.FechaFin, req.Ubicacion, req.IDSala, req.IDTrack)
 	if err != nil {
 		println("[CreateSesion] Error al crear sesión en BD:", err.Error())
 		return nil, ErrDB
@@ -94,17 +102,41 @@ func (s *Service) CreateSesion(ctx context.Context, eventoID int, req dto.Create
 		return nil, ErrDB
 	}
 	println("[CreateSesion] Sesión creada OK")
-	return s.mapSesionToResponse(ctx, sesion), nil
+	return s.mapSesionToResponse(ctx, sesion, s.ubicacion(ctx, id)), nil
 }
 
-func (s *Service) ListSesiones(ctx context.Context, eventoID int) ([]dto.SesionResponse, error) {
+// ListSesiones returns the sessions of an event in schedule order. A non-nil
+// salaID or trackID keeps only the sessions in that room or track.
+func (s *Service) ListSesiones(ctx context.Context, eventoID int, salaID, trackID *int) ([]dto.SesionResponse, error) {
 	sesiones, err := s.repo.ListSesiones(ctx, eventoID)
 	if err != nil {
 		return nil, ErrDB
 	}
+	ubicaciones, err := s.repo.ListUbicaciones(ctx, eventoID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	porSesion := make(map[int]repo.UbicacionRow, len(ubicaciones))
+	for _, u := range ubicaciones {
+		porSesion[u.IDSesion] = u
+	}
+	sort.Slice(sesiones, func(i, j int) bool {
+		if !sesiones[i].FechaInicio.Equal(sesiones[j].FechaInicio) {
+			return sesiones[i].FechaInicio.Before(sesiones[j].FechaInicio)
+		}
+		return sesiones[i].IDSesion < sesiones[j].IDSesion
+	})
+
 	var resp []dto.SesionResponse
 	for _, sesion := range sesiones {
-		resp = append(resp, *s.mapSesionToResponse(ctx, &sesion))
+		u := porSesion[sesion.IDSesion]
+		if salaID != nil && !mismoID(salaID, u.IDSala) {
+			continue
+		}
+		if trackID != nil && !mismoID(trackID, u.IDTrack) {
+			continue
+		}
+		resp = append(resp, *s.mapSesionToResponse(ctx, &sesion, u))
 	}
 	return resp, nil
 }
@@ -114,7 +146,7 @@ func (s *Service) GetSesionByID(ctx context.Context, sesionID int) (*dto.SesionR
 	if err != nil || sesion == nil {
 		return nil, ErrNotFound
 	}
-	return s.mapSesionToResponse(ctx, sesion), nil
+	return s.mapSesionToResponse(ctx, sesion, s.ubicacion(ctx, sesionID)), nil
 }
 
 func (s *Service) UpdateSesion(ctx context.Context, sesionID int, req dto.UpdateSesionRequest) (*dto.SesionResponse, error) {
@@ -150,10 +182,29 @@ func (s *Service) UpdateSesion(ctx context.Context, sesionID int, req dto.Update
 	if err := validation.ValidarDuracion(fechaInicio, fechaFin); err != nil {
 		return nil, err
 	}
+	actual, err := s.repo.GetUbicacion(ctx, sesionID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	salaID, trackID := actual.IDSala, actual.IDTrack
+	var nuevaSala, nuevoTrack *int
+	if req.CambiaSala {
+		salaID, nuevaSala = req.IDSala, req.IDSala
+	}
+	if req.CambiaTrack {
+		trackID, nuevoTrack = req.IDTrack, req.IDTrack
+	}
+	if err := s.validarUbicacion(ctx, sesion.IDEvento, nuevaSala, nuevoTrack); err != nil {
+		return nil, err
+	}
 	sesiones, err := s.repo.ListSesiones(ctx, sesion.IDEvento)
 	if err != nil {
 		return nil, ErrDB
 	}
+	salas, err := s.salasPorSesion(ctx, sesion.IDEvento)
+	if err != nil {
+		return nil, ErrDB
+	}
 	var otrasSesiones []db.SesionModel
 	for _, s := range sesiones {
 		if s.IDSesion != sesionID {
@@ -163,18 +214,22 @@ func (s *Service) UpdateSesion(ctx context.Context, sesionID int, req dto.Update
 	if err := validation.ValidarTituloUnico(otrasSesiones, req.Titulo); err != nil {
 		return nil, err
 	}
-	if err := validation.ValidarSolapamiento(otrasSesiones, fechaInicio, fechaFin); err != nil {
+	if err := validation.ValidarSolapamiento(validation.SesionesEnSala(otrasSesiones, salas, salaID), fechaInicio, fechaFin); err != nil {
 		return nil, err
 	}
-	err = s.repo.UpdateSesion(ctx, sesionID, req.Titulo, req.Descripcion, req.FechaInicio, req.FechaFin, req.Ubicacion)
+	conflictos, err := s.repo.UpdateSesion(ctx, sesionID, req.Titulo, req.Descripcion, req.FechaInicio, req.FechaFin, req.Ubicacion,
+		req.CambiaSala || req.CambiaTrack, salaID, trackID)
 	if err != nil {
 		return nil, ErrDB
 	}
+	if err := NewConflictoError(conflictos); err != nil {
+		return nil, err
+	}
 	sesion, err = s.repo.GetSesionByID(ctx, sesionID)
 	if err != nil || sesion == nil {
 		return nil, ErrDB
 	}
-	return s.mapSesionToResponse(ctx, sesion), nil
+	return s.mapSesionToResponse(ctx, sesion, s.ubicacion(ctx, sesionID)), nil
 }
 
 func (s *Service) DeleteSesion(ctx context.Context, sesionID int) error {
@@ -185,7 +240,7 @@ func (s *Service) DeleteSesion(ctx context.Context, sesionID int) error {
 	return nil
 }
 
-func (svc *Service) mapSesionToResponse(ctx context.Context, sesion *db.SesionModel) *dto.SesionResponse {
+func (svc *Service) mapSesionToResponse(ctx context.Context, sesion *db.SesionModel, ubicacion repo.UbicacionRow) *dto.SesionResponse {
 	if sesion == nil {
 		return nil
 	}
@@ -193,7 +248,7 @@ func (svc *Service) mapSesionToResponse(ctx context.Context, sesion *db.SesionMo
 	if err != nil || ponentes == nil {
 		ponentes = make([]dto.PonenteResponse, 0)
 	}
-	return &dto.SesionResponse{
+	resp := &dto.SesionResponse{
 		IDSesion:    sesion.IDSesion,
 		Titulo:      sesion.Titulo,
 		Descripcion: sesion.Descripcion,
@@ -201,8 +256,20 @@ func (svc *Service) mapSesionToResponse(ctx context.Context, sesion *db.SesionMo
 		FechaFin:    sesion.FechaFin.Format("2006-01-02T15:04:05Z"),
 		Ubicacion:   sesion.Ubicacion,
 		EventoID:    sesion.IDEvento,
+		IDSala:      ubicacion.IDSala,
+		IDTrack:     ubicacion.IDTrack,
 		Ponentes:    ponentes,
 	}
+	if ubicacion.Sala != nil {
+		resp.Sala = *ubicacion.Sala
+	}
+	if ubicacion.Track != nil {
+		resp.Track = *ubicacion.Track
+	}
+	if ubicacion.ColorTrack != nil {
+		resp.ColorTrack = *ubicacion.ColorTrack
+	}
+	return resp
 }
 
 func (s *Service) AsignarPonentes(ctx context.Context, sesionID int, req dto.AsignarPonentesRequest) error {
@@ -237,11 +304,11 @@ func (s *Service) AsignarPonentes(ctx context.Context, sesionID int, req dto.Asi
 	if err := validation.ValidarRolPonente(usuarios); err != nil {
 		return err
 	}
-	err = s.repo.AsignarPonentes(ctx, sesionID, req.Usuarios)
+	conflictos, err := s.repo.AsignarPonentes(ctx, sesionID, req.Usuarios)
 	if err != nil {
 		return ErrDB
 	}
-	return nil
+	return NewConflictoError(conflictos)
 }
 
 func (s *Service) QuitarPonente(ctx context.Context, sesionID int, usuarioID int) error {
@@ -296,3 +363,36 @@ func (s *Service) ListPonentesAsignables(ctx context.Context, sesionID int) ([]d
 	}
 	return resp, nil
 }
+
+// SesionAcceso holds what is needed to evaluate ownership rules on a session.
+type SesionAcceso struct {
+	SesionCancelada bool
+	EventoID        int
+	EventoCancelado bool
+	PonenteIDs      []int
+}
+
+func (s *Service) ObtenerAcceso(ctx context.Context, sesionID int) (*SesionAcceso, error) {
+	sesion, err := s.repo.GetSesionByID(ctx, sesionID)
+	if err != nil || sesion == nil {
+		return nil, ErrNotFound
+	}
+	evento, err := s.repo.Prisma().Evento.FindUnique(db.Evento.IDEvento.Equals(sesion.IDEvento)).Exec(ctx)
+	if err != nil || evento == nil {
+		return nil, ErrDB
+	}
+	ponentes, err := s.repo.ListPonentes(ctx, sesionID)
+	if err != nil {
+		return nil, ErrDB
+	}
+	acceso := &SesionAcceso{
+		SesionCancelada: sesion.Cancelado,
+		EventoID:        evento.IDEvento,
+		EventoCancelado: evento.Cancelado,
+		PonenteIDs:      make([]int, 0, len(ponentes)),
+	}
+	for _, ponente := range ponentes {
+		acceso.PonenteIDs = append(acceso.PonenteIDs, ponente.IDUsuario)
+	}
+	return acceso, nil
+}
@@ -2,13 +2,17 @@ package sesiones
 
 import (
 	"errors"
+	"project/backend/internal/sesiones/dto"
 	"project/backend/prisma/db"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 var (
 	ErrTituloNoUnico   = errors.New("El título de la sesión ya existe para este evento")
-	ErrSolapamiento    = errors.New("Las fechas y horas de la sesión se solapan con otra sesión del evento")
+	ErrSolapamiento    = errors.New("Las fechas y horas de la sesión se solapan con otra sesión del evento en la misma sala")
 	ErrDuracion        = errors.New("La duración de la sesión debe ser entre 30 minutos y 4 horas")
 	ErrEventoIniciado  = errors.New("No se puede modificar la sesión porque el evento ya comenzó")
 	ErrSesionCancelada = errors.New("No se puede modificar una sesión cancelada")
@@ -33,6 +37,20 @@ func ValidarSolapamiento(sesiones []db.SesionModel, fechaInicio, fechaFin time.T
 	return nil
 }
 
+// SesionesEnSala returns the sessions held in the room salaID, given the room
+// of each session in salas. Sessions without a room are taken to share one
+// space, so events that do not use rooms still cannot overlap sessions.
+func SesionesEnSala(sesiones []db.SesionModel, salas map[int]*int, salaID *int) []db.SesionModel {
+	var enSala []db.SesionModel
+	for _, s := range sesiones {
+		otra := salas[s.IDSesion]
+		if (otra == nil && salaID == nil) || (otra != nil && salaID != nil && *otra == *salaID) {
+			enSala = append(enSala, s)
+		}
+	}
+	return enSala
+}
+
 func ValidarDuracion(fechaInicio, fechaFin time.Time) error {
 	duracion := fechaFin.Sub(fechaInicio)
 	if duracion < 30*time.Minute || duracion > 4*time.Hour {
@@ -58,6 +76,9 @@ func ValidarSesionNoCancelada(sesion *db.SesionModel) error {
 	return nil
 }
 
+// RolPonente is the role a user needs to be assigned to sessions.
+const RolPonente = "PONENTE"
+
 func ValidarRolPonente(usuarios []db.UsuarioModel) error {
 	for _, u := range usuarios {
 		roles := u.RelationsUsuario.UsuarioRoles
@@ -68,7 +89,7 @@ func ValidarRolPonente(usuarios []db.UsuarioModel) error {
 		tieneRol := false
 		for _, userRole := range roles {
 			role := userRole.RelationsUsuarioRoles.Rol
-			if role != nil && role.NombreRol == "PONENTE" {
+			if role != nil && role.NombreRol == RolPonente {
 				tieneRol = true
 				break
 			}
@@ -91,3 +112,37 @@ func ValidarSesionDentroDeRangoEvento(fechaInicio, fechaFin, eventoInicio, event
 	}
 	return nil
 }
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+func ValidarTrack(req dto.TrackRequest) error {
+	nombre := strings.TrimSpace(req.Nombre)
+	if utf8.RuneCountInString(nombre) < 1 || utf8.RuneCountInString(nombre) > 100 {
+		return errors.New("El nombre del track debe tener entre 1 y 100 caracteres")
+	}
+	if color := strings.TrimSpace(req.Color); color != "" && !colorPattern.MatchString(color) {
+		return errors.New("El color del track debe tener el formato #RRGGBB")
+	}
+	return nil
+}
+
+// MaxDuracionBloqueo bounds a blackout period, so a speaker cannot block
+// themselves out indefinitely by mistake.
+const MaxDuracionBloqueo = 366 * 24 * time.Hour
+
+var ErrBloqueoRango = errors.New("El bloqueo debe terminar después de empezar y no puede durar más de un año")
+
+// ValidarBloqueo checks a blackout period: it must not be over already, must
+// end after it starts and the reason is optional but short.
+func ValidarBloqueo(inicio, fin, ahora time.Time, motivo string) error {
+	if !fin.After(inicio) || fin.Sub(inicio) > MaxDuracionBloqueo {
+		return ErrBloqueoRango
+	}
+	if !fin.After(ahora) {
+		return errors.New("El bloqueo ya terminó")
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(motivo)) > 200 {
+		return errors.New("El motivo del bloqueo no puede superar 200 caracteres")
+	}
+	return nil
+}
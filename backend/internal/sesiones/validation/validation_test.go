@@ -0,0 +1,94 @@
+package sesiones
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"project/backend/internal/sesiones/dto"
+	"project/backend/prisma/db"
+)
+
+func sesion(id int, inicio time.Time, horas int) db.SesionModel {
+	return db.SesionModel{InnerSesion: db.InnerSesion{
+		IDSesion:    id,
+		FechaInicio: inicio,
+		FechaFin:    inicio.Add(time.Duration(horas) * time.Hour),
+	}}
+}
+
+func TestSesionesEnSala(t *testing.T) {
+	inicio := time.Date(2026, 11, 3, 9, 0, 0, 0, time.UTC)
+	salaA, salaB := 1, 2
+	sesiones := []db.SesionModel{sesion(1, inicio, 1), sesion(2, inicio, 1), sesion(3, inicio, 1)}
+	salas := map[int]*int{1: &salaA, 2: &salaB}
+
+	// A parallel session in another room does not overlap.
+	if err := ValidarSolapamiento(SesionesEnSala(sesiones, salas, &salaA), inicio.Add(30*time.Minute), inicio.Add(90*time.Minute)); err != ErrSolapamiento {
+		t.Fatalf("same room: got %v, want ErrSolapamiento", err)
+	}
+	otra := 3
+	if err := ValidarSolapamiento(SesionesEnSala(sesiones, salas, &otra), inicio, inicio.Add(time.Hour)); err != nil {
+		t.Fatalf("other room: unexpected error: %v", err)
+	}
+
+	// Sessions without a room share one space.
+	sinSala := SesionesEnSala(sesiones, salas, nil)
+	if len(sinSala) != 1 || sinSala[0].IDSesion != 3 {
+		t.Fatalf("without room: got %+v, want session 3", sinSala)
+	}
+}
+
+func TestValidarTrack(t *testing.T) {
+	cases := []struct {
+		name    string
+		req     dto.TrackRequest
+		wantErr bool
+	}{
+		{"valido", dto.TrackRequest{Nombre: "Inteligencia artificial", Color: "#1a73e8"}, false},
+		{"sin color", dto.TrackRequest{Nombre: "Redes"}, false},
+		{"sin nombre", dto.TrackRequest{Nombre: "  ", Color: "#1A73E8"}, true},
+		{"color corto", dto.TrackRequest{Nombre: "Redes", Color: "#fff"}, true},
+		{"color sin numeral", dto.TrackRequest{Nombre: "Redes", Color: "1A73E8"}, true},
+	}
+
+	for _, c := range cases {
+		err := ValidarTrack(c.req)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
+
+func TestValidarBloqueo(t *testing.T) {
+	ahora := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	inicio := ahora.Add(48 * time.Hour)
+	cases := []struct {
+		name    string
+		inicio  time.Time
+		fin     time.Time
+		motivo  string
+		wantErr bool
+	}{
+		{"valido", inicio, inicio.Add(8 * time.Hour), "Otro congreso", false},
+		{"en curso", ahora.Add(-time.Hour), ahora.Add(time.Hour), "", false},
+		{"fin antes del inicio", inicio, inicio.Add(-time.Hour), "", true},
+		{"sin duracion", inicio, inicio, "", true},
+		{"ya termino", ahora.Add(-2 * time.Hour), ahora.Add(-time.Hour), "", true},
+		{"demasiado largo", inicio, inicio.Add(MaxDuracionBloqueo + time.Hour), "", true},
+		{"motivo largo", inicio, inicio.Add(time.Hour), strings.Repeat("a", 201), true},
+	}
+
+	for _, c := range cases {
+		err := ValidarBloqueo(c.inicio, c.fin, ahora, c.motivo)
+		if c.wantErr && err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+		if !c.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+	}
+}
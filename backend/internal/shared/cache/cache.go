@@ -0,0 +1,69 @@
+// Package cache keeps values in memory for a short time.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// TTL is a concurrency-safe map whose entries expire ttl after being set.
+// Expired entries are dropped when read or overwritten, and swept
+// whenever the map grows past the size of the last sweep.
+type TTL[K comparable, V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	items   map[K]entrada[V]
+	barrido int
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+type entrada[V any] struct {
+	valor  V
+	expira time.Time
+}
+
+// NewTTL returns an empty cache whose entries live for ttl.
+func NewTTL[K comparable, V any](ttl time.Duration) *TTL[K, V] {
+	return &TTL[K, V]{ttl: ttl, items: map[K]entrada[V]{}, barrido: 64, now: time.Now}
+}
+
+// Get returns the value stored under key, if it has not expired.
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		var cero V
+		return cero, false
+	}
+	if !c.now().Before(e.expira) {
+		delete(c.items, key)
+		var cero V
+		return cero, false
+	}
+	return e.valor, true
+}
+
+// Set stores value under key for the cache's ttl.
+func (c *TTL[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.items) >= c.barrido {
+		for k, e := range c.items {
+			if !now.Before(e.expira) {
+				delete(c.items, k)
+			}
+		}
+		c.barrido = 2*len(c.items) + 64
+	}
+	c.items[key] = entrada[V]{valor: value, expira: now.Add(c.ttl)}
+}
+
+// Delete drops the entry stored under key.
+func (c *TTL[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
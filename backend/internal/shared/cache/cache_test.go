@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLExpira(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := NewTTL[int, string](30 * time.Second)
+	c.now = func() time.Time { return now }
+
+	c.Set(1, "uno")
+	if v, ok := c.Get(1); !ok || v != "uno" {
+		t.Fatalf("expected a fresh entry, got %q %v", v, ok)
+	}
+
+	now = now.Add(29 * time.Second)
+	if _, ok := c.Get(1); !ok {
+		t.Fatal("expected the entry to live until its ttl")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := c.Get(1); ok {
+		t.Fatal("expected the entry to expire after its ttl")
+	}
+
+	c.Set(2, "dos")
+	c.Delete(2)
+	if _, ok := c.Get(2); ok {
+		t.Fatal("expected a deleted entry to be gone")
+	}
+}
+
+func TestTTLBarreVencidas(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := NewTTL[int, int](time.Second)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 64; i++ {
+		c.Set(i, i)
+	}
+	now = now.Add(time.Minute)
+	c.Set(100, 100)
+	if len(c.items) != 1 {
+		t.Fatalf("expected expired entries to be swept, %d left", len(c.items))
+	}
+}
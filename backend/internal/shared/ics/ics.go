@@ -0,0 +1,140 @@
+// Package ics writes iCalendar (RFC 5545) calendars.
+package ics
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// VEVENT statuses.
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+const ContentType = "text/calendar; charset=utf-8"
+
+// Event is a VEVENT. Times are written in UTC so no VTIMEZONE is needed;
+// calendar clients show them in the reader's zone.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Start        time.Time
+	End          time.Time
+	Created      time.Time
+	LastModified time.Time
+	// Sequence must grow every time the event changes in a way attendees
+	// should notice, so clients replace their copy.
+	Sequence int
+	Status   string
+}
+
+// Calendar is a VCALENDAR published with METHOD:PUBLISH.
+type Calendar struct {
+	Name    string
+	Events  []Event
+	Refresh time.Duration
+}
+
+const utcFormat = "20060102T150405Z"
+
+// Encode renders the calendar. stamp is the DTSTAMP of every event, the time
+// the feed was generated.
+func Encode(c Calendar, stamp time.Time) []byte {
+	var b bytes.Buffer
+	line := func(name, value string) {
+		writeFolded(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//project//eventos//ES")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escape(c.Name))
+	}
+	if c.Refresh > 0 {
+		line("REFRESH-INTERVAL;VALUE=DURATION", duration(c.Refresh))
+		line("X-PUBLISHED-TTL", duration(c.Refresh))
+	}
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", formatUTC(stamp))
+		line("DTSTART", formatUTC(e.Start))
+		line("DTEND", formatUTC(e.End))
+		line("SEQUENCE", strconv.Itoa(e.Sequence))
+		if !e.Created.IsZero() {
+			line("CREATED", formatUTC(e.Created))
+		}
+		if !e.LastModified.IsZero() {
+			line("LAST-MODIFIED", formatUTC(e.LastModified))
+		}
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escape(e.Location))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		if e.Status != "" {
+			line("STATUS", e.Status)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(utcFormat)
+}
+
+// duration formats d as an RFC 5545 duration with whole minutes.
+func duration(d time.Duration) string {
+	return "PT" + strconv.Itoa(int(d.Minutes())) + "M"
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// escape encodes a TEXT value.
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// writeFolded writes a content line ending in CRLF, folding it so no line
+// exceeds 75 octets without splitting a UTF-8 sequence.
+func writeFolded(b *bytes.Buffer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with the space, leaving 74 octets.
+		limit = 74
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,64 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEncode(t *testing.T) {
+	caracas, _ := time.LoadLocation("America/Caracas")
+	stamp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	out := string(Encode(Calendar{
+		Name: "Mi agenda",
+		Events: []Event{{
+			UID:         "evento-7@eventos",
+			Summary:     "Congreso 2027: Salud, Ciencia; Tecnología",
+			Description: "Línea 1\nLínea 2 con \\ barra",
+			Start:       time.Date(2026, 5, 10, 9, 0, 0, 0, caracas),
+			End:         time.Date(2026, 5, 10, 13, 0, 0, 0, caracas),
+			Sequence:    3,
+			Status:      StatusCancelled,
+		}},
+	}, stamp))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"METHOD:PUBLISH\r\n",
+		"X-WR-CALNAME:Mi agenda\r\n",
+		"DTSTAMP:20260301T120000Z\r\n",
+		"DTSTART:20260510T130000Z\r\n",
+		"DTEND:20260510T170000Z\r\n",
+		"SEQUENCE:3\r\n",
+		"STATUS:CANCELLED\r\n",
+		`SUMMARY:Congreso 2027: Salud\, Ciencia\; Tecnología` + "\r\n",
+		`DESCRIPTION:Línea 1\nLínea 2 con \\ barra` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFolding(t *testing.T) {
+	out := string(Encode(Calendar{Events: []Event{{
+		UID:         "x",
+		Summary:     "s",
+		Description: strings.Repeat("ñ", 100),
+	}}}, time.Now()))
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line longer than 75 octets: %q", line)
+		}
+		if !utf8.ValidString(line) {
+			t.Fatalf("folded inside a UTF-8 sequence: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:"+strings.Repeat("ñ", 100)+"\r\n") {
+		t.Fatalf("unfolding does not restore the value:\n%s", out)
+	}
+}
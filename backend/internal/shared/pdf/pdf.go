@@ -0,0 +1,248 @@
+// Package pdf lays out PDF documents with the standard Type 1 fonts: text
+// placed or wrapped to a width, lines and rectangles, on any number of
+// pages. Coordinates are in points from the top-left corner of the page,
+// and a text's y is its baseline.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const ContentType = "application/pdf"
+
+// Size is a page size in points.
+type Size struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	A4     = Size{Width: 595.28, Height: 841.89}
+	Letter = Size{Width: 612, Height: 792}
+)
+
+// Landscape returns the size turned sideways.
+func (s Size) Landscape() Size {
+	return Size{Width: s.Height, Height: s.Width}
+}
+
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	HelveticaOblique
+)
+
+var fontNames = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Document is a PDF being built. Every page has the document's size.
+type Document struct {
+	size  Size
+	pages []*Page
+}
+
+func New(size Size) *Document {
+	return &Document{size: size}
+}
+
+func (d *Document) Size() Size {
+	return d.size
+}
+
+// AddPage appends a blank page and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{height: d.size.Height}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Page collects the drawing operations of one page.
+type Page struct {
+	height  float64
+	content bytes.Buffer
+}
+
+// SetColor sets the color of the text and strokes drawn after it, with
+// components from 0 to 1.
+func (p *Page) SetColor(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%s %s %s rg %s %s %s RG\n", num(r), num(g), num(b), num(r), num(g), num(b))
+}
+
+// Text draws a single line. x is where the line starts, centers or ends
+// depending on align.
+func (p *Page) Text(x, y float64, font Font, size float64, align Align, s string) {
+	switch align {
+	case AlignCenter:
+		x -= TextWidth(font, size, s) / 2
+	case AlignRight:
+		x -= TextWidth(font, size, s)
+	}
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n", int(font)+1, num(size), num(x), num(p.height-y), escape(encode(s)))
+}
+
+// Paragraph wraps s to width starting at the box's left edge x, with the
+// first baseline at y and leading points between baselines. It returns the
+// baseline the next line would take.
+func (p *Page) Paragraph(x, y, width float64, font Font, size, leading float64, align Align, s string) float64 {
+	anchor := x
+	switch align {
+	case AlignCenter:
+		anchor = x + width/2
+	case AlignRight:
+		anchor = x + width
+	}
+	for _, line := range Wrap(font, size, width, s) {
+		p.Text(anchor, y, font, size, align, line)
+		y += leading
+	}
+	return y
+}
+
+// Line draws a straight line lineWidth points thick.
+func (p *Page) Line(x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(lineWidth), num(x1), num(p.height-y1), num(x2), num(p.height-y2))
+}
+
+// Rect draws the outline of a rectangle whose top-left corner is at x, y.
+func (p *Page) Rect(x, y, w, h, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n", num(lineWidth), num(x), num(p.height-y-h), num(w), num(h))
+}
+
+// FillRect paints a rectangle whose top-left corner is at x, y.
+func (p *Page) FillRect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s %s re f\n", num(x), num(p.height-y-h), num(w), num(h))
+}
+
+// Grid paints the dark cells of an n by n grid that fills a side by side
+// square whose top-left corner is at x, y, such as the modules of a QR
+// code.
+func (p *Page) Grid(x, y, side float64, n int, dark func(col, row int) bool) {
+	cell := side / float64(n)
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; col++ {
+			if dark(col, row) {
+				p.FillRect(x+float64(col)*cell, y+float64(row)*cell, cell, cell)
+			}
+		}
+	}
+}
+
+// Bytes renders the document. A document without pages gets one blank
+// page, since readers reject an empty page tree.
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// Objects: catalog, page tree, fonts, then a page and its content
+	// stream per page.
+	firstPage := 3 + len(fontNames)
+	var objects []string
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+2*i))
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)),
+	)
+	var fonts strings.Builder
+	for i, name := range fontNames {
+		objects = append(objects, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i+1, 3+i)
+	}
+	for i, page := range d.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Contents %d 0 R /Resources << /Font << %s>> >> >>",
+				num(d.size.Width), num(d.size.Height), firstPage+2*i+1, fonts.String()),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.content.Len(), page.content.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, 0, len(objects))
+	for i, obj := range objects {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	startXref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer << /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(objects)+1, startXref)
+	return out.Bytes()
+}
+
+// TextWidth is how wide s is in points when set in font at size.
+func TextWidth(font Font, size float64, s string) float64 {
+	total := 0
+	for _, c := range []byte(encode(s)) {
+		total += glyphWidth(font, c)
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap breaks s into lines no wider than width, at spaces and at the line
+// breaks s already has. A word wider than width gets a line of its own.
+func Wrap(font Font, size, width float64, s string) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if TextWidth(font, size, line+" "+word) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// encode converts s to WinAnsi, the encoding the fonts are declared with.
+// Characters outside it become "?".
+func encode(s string) string {
+	var b strings.Builder
+	encoder := charmap.Windows1252.NewEncoder()
+	for _, r := range s {
+		encoded, err := encoder.String(string(r))
+		if err != nil {
+			encoded = "?"
+		}
+		b.WriteString(encoded)
+	}
+	return b.String()
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "(", "\\(", ")", "\\)", "\r", "", "\n", " ").Replace(s)
+}
+
+// num writes a coordinate rounded to hundredths of a point.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
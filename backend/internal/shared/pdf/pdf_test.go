@@ -0,0 +1,103 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTextWidth(t *testing.T) {
+	cases := []struct {
+		font Font
+		text string
+		want float64
+	}{
+		{Helvetica, "Hola", 24.672},
+		{HelveticaBold, "Hola", 26.004},
+		{Helvetica, "Año", 21.348},
+		{Helvetica, "", 0},
+	}
+	for _, c := range cases {
+		if got := TextWidth(c.font, 12, c.text); got < c.want-0.001 || got > c.want+0.001 {
+			t.Fatalf("%q: expected %.3f, got %.3f", c.text, c.want, got)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	texto := "Se certifica que participó en el evento\n\ncon una asistencia completa"
+	lines := Wrap(Helvetica, 12, 150, texto)
+	want := []string{"Se certifica que participó en", "el evento", "", "con una asistencia", "completa"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected %q, got %q", want, lines)
+	}
+	for _, line := range lines {
+		if TextWidth(Helvetica, 12, line) > 150 {
+			t.Fatalf("line %q is wider than the box", line)
+		}
+	}
+
+	largo := Wrap(Helvetica, 12, 10, "supercalifragilístico")
+	if len(largo) != 1 {
+		t.Fatalf("a long word should stay on one line, got %q", largo)
+	}
+}
+
+func TestDocumentBytes(t *testing.T) {
+	doc := New(A4.Landscape())
+	first := doc.AddPage()
+	first.Text(100, 100, HelveticaBold, 24, AlignCenter, "Certificado (prueba)")
+	second := doc.AddPage()
+	second.Paragraph(72, 72, 300, Helvetica, 12, 16, AlignLeft, "Texto con ñ y tildes: á é í ó ú")
+	second.Rect(20, 20, 100, 50, 1)
+	out := doc.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF")) {
+		t.Fatalf("missing header or trailer")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Fatalf("expected two pages")
+	}
+	if !bytes.Contains(out, []byte(`(Certificado \(prueba\))`)) {
+		t.Fatalf("parentheses should be escaped")
+	}
+	if !bytes.Contains(out, []byte("/MediaBox [0 0 841.89 595.28]")) {
+		t.Fatalf("expected a landscape A4 page")
+	}
+
+	// Every entry of the cross-reference table must point at its object.
+	m := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+	if m == nil {
+		t.Fatalf("missing startxref")
+	}
+	start, _ := strconv.Atoi(string(m[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out[start:], -1)
+	if len(entries) == 0 {
+		t.Fatalf("empty cross-reference table")
+	}
+	for i, e := range entries {
+		offset, _ := strconv.Atoi(string(e[1]))
+		if !bytes.HasPrefix(out[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")) {
+			t.Fatalf("xref entry %d does not point at its object", i+1)
+		}
+	}
+}
+
+func TestDocumentWithoutPages(t *testing.T) {
+	if out := New(Letter).Bytes(); !bytes.Contains(out, []byte("/Count 1")) {
+		t.Fatalf("an empty document should get one blank page")
+	}
+}
+
+func TestGrid(t *testing.T) {
+	doc := New(Size{Width: 100, Height: 100})
+	page := doc.AddPage()
+	page.Grid(10, 10, 20, 2, func(col, row int) bool { return col == row })
+	got := page.content.String()
+	want := "10 80 10 10 re f\n20 70 10 10 re f\n"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
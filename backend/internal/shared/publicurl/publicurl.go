@@ -0,0 +1,23 @@
+// Package publicurl builds the absolute links the backend hands out in
+// emails, calendar feeds and certificates.
+package publicurl
+
+import (
+	"os"
+	"strings"
+)
+
+// Base returns the address the backend is reached at from outside, read
+// from PUBLIC_BASE_URL without its trailing slash. Links are never built
+// from the Host header of a request, which the client controls. Without
+// the variable it falls back to localhost on PORT, for development.
+func Base() string {
+	if base := strings.TrimSpace(os.Getenv("PUBLIC_BASE_URL")); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	port := strings.TrimSpace(os.Getenv("PORT"))
+	if port == "" {
+		port = "8080"
+	}
+	return "http://localhost:" + port
+}
@@ -0,0 +1,160 @@
+package qr
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" as 1-M alphanumeric, from the standard's worked example.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	if got := formatBits(0); got != 0b101010000010010 {
+		t.Errorf("format M/0 = %015b", got)
+	}
+	if got := versionBits(7); got != 0x07C94 {
+		t.Errorf("version 7 = %018b", got)
+	}
+}
+
+func TestEncodeSize(t *testing.T) {
+	cases := []struct {
+		n    int
+		size int
+	}{{1, 21}, {14, 21}, {15, 25}, {60, 33}, {154, 53}, {213, 57}}
+	for _, c := range cases {
+		code, err := Encode(bytes.Repeat([]byte("a"), c.n))
+		if err != nil {
+			t.Fatalf("%d bytes: %v", c.n, err)
+		}
+		if code.Size() != c.size {
+			t.Errorf("%d bytes: size %d, want %d", c.n, code.Size(), c.size)
+		}
+	}
+	if _, err := Encode(bytes.Repeat([]byte("a"), 214)); err != ErrTooLong {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, payload := range []string{"INS1.42.7.Zm9vYmFyYmF6cXV4", string(bytes.Repeat([]byte("áé-"), 40))} {
+		code, err := Encode([]byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decode(t, code); got != payload {
+			t.Errorf("decoded %q, want %q", got, payload)
+		}
+	}
+}
+
+// decode reads a symbol back without error correction: the mask from the
+// format bits, the codewords in zigzag order, checking each block's error
+// correction, and the byte-mode segment.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	number := (c.size - 17) / 4
+	v := versions[number]
+
+	read := 0
+	for i := 0; i <= 5; i++ {
+		if c.Dark(8, i) {
+			read |= 1 << i
+		}
+	}
+	mask := -1
+	for m := range masks {
+		if formatBits(m)&0x3F == read {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("unknown format bits %06b", read)
+	}
+
+	clean := newCode(number)
+	clean.drawFunctionPatterns(number)
+	var bits []bool
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.size; vert++ {
+			y := vert
+			if upward {
+				y = c.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !clean.function[y][x] {
+					bits = append(bits, c.Dark(x, y) != masks[mask](x, y))
+				}
+			}
+		}
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i := range codewords {
+		for j := 0; j < 8; j++ {
+			if bits[8*i+j] {
+				codewords[i] |= 1 << (7 - j)
+			}
+		}
+	}
+
+	data := make([][]byte, len(v.blocks))
+	pos := 0
+	for i := 0; i < v.blocks[len(v.blocks)-1]; i++ {
+		for b, n := range v.blocks {
+			if i < n {
+				data[b] = append(data[b], codewords[pos])
+				pos++
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for b := range data {
+			if want := rsRemainder(data[b], rsDivisor(v.ecPerBlock))[i]; codewords[pos] != want {
+				t.Fatalf("block %d: ecc codeword %d is %d, want %d", b, i, codewords[pos], want)
+			}
+			pos++
+		}
+	}
+	stream := bytes.Join(data, nil)
+	if stream[0]>>4 != 0b0100 {
+		t.Fatalf("mode %04b, want byte mode", stream[0]>>4)
+	}
+	countBits := 8
+	if number >= 10 {
+		countBits = 16
+	}
+	var r bitReader
+	r.data = stream
+	r.read(4)
+	n := r.read(countBits)
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = byte(r.read(8))
+	}
+	return string(out)
+}
+
+type bitReader struct {
+	data []byte
+	n    int
+}
+
+func (r *bitReader) read(bits int) int {
+	v := 0
+	for i := 0; i < bits; i++ {
+		v = v<<1 | int(r.data[r.n/8]>>(7-r.n%8)&1)
+		r.n++
+	}
+	return v
+}
@@ -19,6 +19,10 @@ const (
 	ErrUserExists         AppCode = 4006
 	ErrRoleInvalid        AppCode = 4007
 	ErrMissingFields      AppCode = 4008
+	ErrForbidden          AppCode = 4009
+	ErrNotFound           AppCode = 4010
+	ErrConflict           AppCode = 4011
+	ErrUnauthenticated    AppCode = 4012
 
 	// Server Errors (5xxx)
 	ErrInternalServer AppCode = 5000
@@ -42,6 +46,10 @@ var AppMessages = map[AppCode]string{
 	ErrUserExists:         "User already exists or database error",
 	ErrRoleInvalid:        "Invalid role specified",
 	ErrMissingFields:      "Missing required fields",
+	ErrForbidden:          "Not allowed to perform this action",
+	ErrNotFound:           "Resource not found",
+	ErrConflict:           "Resource is in a conflicting state",
+	ErrUnauthenticated:    "Authentication required",
 
 	ErrInternalServer: "Internal server error",
 	ErrDatabase:       "Database operation failed",
@@ -0,0 +1,23 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+)
+
+func SendEventSummaryEmail(ctx context.Context, toEmail, eventName, summary string) error {
+	subject := fmt.Sprintf("Resumen del evento %s", eventName)
+
+	_, err := SendSandboxEmail(ctx, SandboxSendRequest{
+		ToEmail: toEmail,
+		Subject: subject,
+		Text:    summary,
+	})
+
+	// _, err := SendEmail(ctx, SendEmailRequest{
+	// 	ToEmail: toEmail,
+	// 	Subject: subject,
+	// 	Text:    summary,
+	// })
+	return err
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage keeps uploaded files under a key such as "eventos/12/portada.png"
+// and tells where they can be downloaded from.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// Private keeps files that must never be public, such as badge PDFs. They
+// have no URL: only the handlers that check who may read them hand them
+// out.
+type Private interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// Local stores files on disk under Dir and serves them below BaseURL.
+type Local struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewLocalFromEnv reads STORAGE_DIR (default "uploads") and STORAGE_BASE_URL
+// (default "/uploads").
+func NewLocalFromEnv() *Local {
+	dir := strings.TrimSpace(os.Getenv("STORAGE_DIR"))
+	if dir == "" {
+		dir = "uploads"
+	}
+	baseURL := strings.TrimSpace(os.Getenv("STORAGE_BASE_URL"))
+	if baseURL == "" {
+		baseURL = "/uploads"
+	}
+	return &Local{Dir: dir, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// NewPrivateFromEnv reads PRIVATE_STORAGE_DIR (default "private"). Its
+// Handler must not be mounted.
+func NewPrivateFromEnv() *Local {
+	dir := strings.TrimSpace(os.Getenv("PRIVATE_STORAGE_DIR"))
+	if dir == "" {
+		dir = "private"
+	}
+	return &Local{Dir: dir}
+}
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader) error {
+	target, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see half a file.
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	target, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Open returns the stored file; it fails with os.ErrNotExist when there is
+// none under key.
+func (l *Local) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	target, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(target)
+}
+
+func (l *Local) URL(key string) string {
+	return l.BaseURL + "/" + key
+}
+
+// Handler serves the stored files; mount it on BaseURL + "/". Only files
+// are served: directories answer 404 instead of listing their contents.
+func (l *Local) Handler() http.Handler {
+	files := http.FileServer(http.Dir(l.Dir))
+	return http.StripPrefix(l.BaseURL+"/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target, err := l.path(r.URL.Path)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if info, err := os.Stat(target); err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+}
+
+// path maps a key to a file inside Dir, rejecting keys that would escape it.
+func (l *Local) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean == "/" || clean[1:] != key {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(l.Dir, filepath.FromSlash(key)), nil
+}
@@ -37,6 +37,7 @@ const (
 	errUserNotFound           = "User not found"
 	errUpdateRole             = "Error updating role"
 	errUpdateRoles            = "Error updating roles"
+	errInvalidValidity        = "valid_from and valid_until must be RFC3339 dates and valid_until must be in the future"
 )
 
 type UpdateRoleRequest struct {
@@ -49,6 +50,13 @@ type UpdateRolesRequest struct {
 	Roles  []string `json:"roles"`
 }
 
+type GrantRoleRequest struct {
+	UserID     int    `json:"user_id"`
+	Rol        string `json:"rol"`
+	ValidFrom  string `json:"valid_from"`
+	ValidUntil string `json:"valid_until"`
+}
+
 type userRoleItem struct {
 	ID    int      `json:"id"`
 	Name  string   `json:"name"`
@@ -292,6 +300,76 @@ func (h *Handler) UpdateUserRolesHandler(w http.ResponseWriter, r *http.Request)
 	_ = json.NewEncoder(w).Encode(map[string]string{"message": "Roles updated successfully"})
 }
 
+func (h *Handler) GrantUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+
+	roleNames := parseRoleHeader(strings.TrimSpace(r.Header.Get(roleHeaderKey)))
+	if len(roleNames) == 0 {
+		http.Error(w, errForbidden, http.StatusForbidden)
+		return
+	}
+
+	if h.roleService == nil {
+		http.Error(w, errRoleServiceUnavailable, http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	allowed, err := h.authorizeRolesManage(ctx, roleNames)
+	if err != nil {
+		http.Error(w, errQueryPermissions, http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, errForbidden, http.StatusForbidden)
+		return
+	}
+
+	var req GrantRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID <= 0 || strings.TrimSpace(req.Rol) == "" {
+		http.Error(w, errUserIDAndRoleRequired, http.StatusBadRequest)
+		return
+	}
+
+	validFrom, validUntil, err := roles.ParseVigencia(req.ValidFrom, req.ValidUntil)
+	if err != nil {
+		http.Error(w, errInvalidValidity, http.StatusBadRequest)
+		return
+	}
+
+	roleID, err := h.roleService.GetRoleIDByName(ctx, req.Rol)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			http.Error(w, errRoleNotFound, http.StatusNotFound)
+			return
+		}
+		http.Error(w, errQueryRoles, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.roleService.GrantUserRole(ctx, req.UserID, roleID, validFrom, validUntil); err != nil {
+		if db.IsErrNotFound(err) {
+			http.Error(w, errUserNotFound, http.StatusNotFound)
+			return
+		}
+		http.Error(w, errUpdateRole, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": "Role granted successfully"})
+}
+
 func parsePagination(r *http.Request) (int, int) {
 	limit := 10
 	offset := 0
@@ -342,39 +420,5 @@ func parseRoleHeader(roleHeader string) []string {
 }
 
 func (h *Handler) authorizeRolesManage(ctx context.Context, roleNames []string) (bool, error) {
-	for _, roleName := range roleNames {
-		if isAdminRoleName(roleName) {
-			return true, nil
-		}
-	}
-
-	roleIDs := []int{}
-	for _, roleName := range roleNames {
-		id, err := h.roleService.GetRoleIDByName(ctx, roleName)
-		if err != nil {
-			if db.IsErrNotFound(err) {
-				continue
-			}
-			return false, err
-		}
-		roleIDs = append(roleIDs, id)
-	}
-	if len(roleIDs) == 0 {
-		return false, nil
-	}
-
-	for _, id := range roleIDs {
-		hasPermission, err := h.roleService.HasRoleResourcePermission(ctx, id, "roles.manage")
-		if err != nil {
-			return false, err
-		}
-		if hasPermission {
-			return true, nil
-		}
-	}
-	return false, nil
-}
-
-func isAdminRoleName(name string) bool {
-	return strings.EqualFold(strings.TrimSpace(name), "ADMIN")
+	return roles.AuthorizeRoleNames(ctx, h.roleService, roleNames, "roles.manage")
 }
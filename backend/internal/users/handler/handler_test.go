@@ -144,6 +144,42 @@ func TestUpdateUserRoleHandler(t *testing.T) {
     })
 }
 
+func TestGrantUserRoleHandler(t *testing.T) {
+    tests := []struct {
+        name    string
+        method  string
+        role    string
+        body    string
+        service mocks.MockUserRoleService
+        want    int
+    }{
+        {name: "method not allowed", method: http.MethodGet, role: "ADMIN", want: http.StatusMethodNotAllowed},
+        {name: "forbidden without role header", method: http.MethodPut, body: `{"user_id":1,"rol":"PONENTE"}`, want: http.StatusForbidden},
+        {name: "forbidden without role permission", method: http.MethodPut, role: "PONENTE", body: `{"user_id":1,"rol":"PONENTE"}`, service: mocks.MockUserRoleService{RoleID: 1}, want: http.StatusForbidden},
+        {name: "missing fields", method: http.MethodPut, role: "ADMIN", body: `{"user_id":0,"rol":""}`, want: http.StatusBadRequest},
+        {name: "invalid validity format", method: http.MethodPut, role: "ADMIN", body: `{"user_id":1,"rol":"PONENTE","valid_until":"31/12/2099"}`, want: http.StatusBadRequest},
+        {name: "validity already expired", method: http.MethodPut, role: "ADMIN", body: `{"user_id":1,"rol":"PONENTE","valid_until":"2000-01-01T00:00:00Z"}`, want: http.StatusBadRequest},
+        {name: "validity window reversed", method: http.MethodPut, role: "ADMIN", body: `{"user_id":1,"rol":"PONENTE","valid_from":"2099-02-01T00:00:00Z","valid_until":"2099-01-01T00:00:00Z"}`, want: http.StatusBadRequest},
+        {name: "role not found", method: http.MethodPut, role: "ADMIN", body: `{"user_id":1,"rol":"NOPE"}`, service: mocks.MockUserRoleService{GetRoleErr: db.ErrNotFound}, want: http.StatusNotFound},
+        {name: "db error on grant", method: http.MethodPut, role: "ADMIN", body: `{"user_id":1,"rol":"PONENTE"}`, service: mocks.MockUserRoleService{RoleID: 2, UpdateErr: errors.New("boom")}, want: http.StatusInternalServerError},
+        {name: "success", method: http.MethodPut, role: "ADMIN", body: `{"user_id":1,"rol":"PONENTE","valid_from":"2099-01-01T00:00:00Z","valid_until":"2099-01-05T00:00:00Z"}`, service: mocks.MockUserRoleService{RoleID: 2}, want: http.StatusOK},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            req := httptest.NewRequest(tt.method, "/api/user/grant-role", strings.NewReader(tt.body))
+            if tt.role != "" {
+                req.Header.Set("X-Role", tt.role)
+            }
+            rr := httptest.NewRecorder()
+            New(nil, tt.service).GrantUserRoleHandler(rr, req)
+            if rr.Code != tt.want {
+                t.Fatalf("expected %d, got %d", tt.want, rr.Code)
+            }
+        })
+    }
+}
+
 func TestHelloHandler(t *testing.T) {
     req := httptest.NewRequest(http.MethodGet, "/api/hello", nil)
     rr := httptest.NewRecorder()
@@ -1,6 +1,9 @@
 package mocks
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type MockUserRoleService struct {
 	RoleID     int
@@ -8,6 +11,7 @@ type MockUserRoleService struct {
 	UpdateErr  error
 	HasPermission bool
 	HasPermissionErr error
+	ActiveRoles []string
 }
 
 // GetRoleIDsByNames implements [roles.UserRoleService].
@@ -31,3 +35,11 @@ func (m MockUserRoleService) GetRoleIDByName(_ context.Context, _ string) (int,
 func (m MockUserRoleService) UpdateUserRole(_ context.Context, _ int, _ int) error {
 	return m.UpdateErr
 }
+
+func (m MockUserRoleService) GrantUserRole(_ context.Context, _ int, _ int, _ *time.Time, _ *time.Time) error {
+	return m.UpdateErr
+}
+
+func (m MockUserRoleService) ActiveRoleNames(_ context.Context, _ int) ([]string, error) {
+	return m.ActiveRoles, m.GetRoleErr
+}
@@ -55,42 +55,36 @@ func (r *UserRepository) FindRoleByID(ctx context.Context, roleID int) (*db.Role
 	).Exec(ctx)
 }
 
+// activeRolesQuery selects the roles granted to a user whose validity window
+// includes the current time. Grants without bounds never expire.
+const activeRolesQuery = `SELECT r."id_rol", r."nombre_rol", r."descripcion", r."createdAt"
+		FROM "UsuarioRoles" ur
+		JOIN "Roles" r ON r."id_rol" = ur."id_rol"
+		WHERE ur."id_usuario" = $1
+			AND (ur."valid_from" IS NULL OR ur."valid_from" <= NOW())
+			AND (ur."valid_until" IS NULL OR ur."valid_until" > NOW())
+		ORDER BY ur."valid_until" NULLS FIRST, r."id_rol"`
+
 func (r *UserRepository) FindPrimaryRoleByUserID(ctx context.Context, userID int) (*db.RolesModel, error) {
-	roles, err := r.Client.UsuarioRoles.
-		FindMany(db.UsuarioRoles.IDUsuario.Equals(userID)).
-		With(db.UsuarioRoles.Rol.Fetch()).
-		Exec(ctx)
+	roles, err := r.ListRolesByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	if len(roles) == 0 || roles[0].RelationsUsuarioRoles.Rol == nil {
+	if len(roles) == 0 {
 		return nil, db.ErrNotFound
 	}
-	return roles[0].RelationsUsuarioRoles.Rol, nil
+	return &roles[0], nil
 }
 
 func (r *UserRepository) ListRolesByUserID(ctx context.Context, userID int) ([]db.RolesModel, error) {
-	roles, err := r.Client.UsuarioRoles.
-		FindMany(db.UsuarioRoles.IDUsuario.Equals(userID)).
-		With(db.UsuarioRoles.Rol.Fetch()).
-		Exec(ctx)
-	if err != nil {
+	var roles []db.RolesModel
+	if err := r.Client.Prisma.Raw.QueryRaw(activeRolesQuery, userID).Exec(ctx, &roles); err != nil {
 		return nil, err
 	}
 	if len(roles) == 0 {
 		return []db.RolesModel{}, nil
 	}
-
-	roleModels := make([]db.RolesModel, 0, len(roles))
-	for _, userRole := range roles {
-		if userRole.RelationsUsuarioRoles.Rol != nil {
-			roleModels = append(roleModels, *userRole.RelationsUsuarioRoles.Rol)
-		}
-	}
-	if len(roleModels) == 0 {
-		return []db.RolesModel{}, nil
-	}
-	return roleModels, nil
+	return roles, nil
 }
 
 func (r *UserRepository) UpdatePassword(ctx context.Context, email, passwordHash string) (*db.UsuarioModel, error) {
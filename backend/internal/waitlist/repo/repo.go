@@ -0,0 +1,259 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	inscripciones "project/backend/internal/inscripciones/validation"
+	"project/backend/prisma/db"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	EstadoPendiente = "Pendiente"
+	EstadoEnEspera  = "En espera"
+)
+
+// estadosInactivos lists the statuses that do not take a seat of the event:
+// the waitlist plus the released ones.
+var estadosInactivos = `('` + inscripciones.StatusEnEspera + `', ` + strings.TrimPrefix(inscripciones.EstadosLiberados, "(")
+
+// ErrEntradaAgotada is returned by Reservar when the chosen ticket type has
+// no tickets left, and ErrCodigoAgotado when the promo code reached one of
+// its usage limits.
+var (
+	ErrEntradaAgotada = errors.New("entrada agotada")
+	ErrCodigoAgotado  = errors.New("código agotado")
+)
+
+type Repository struct {
+	client *db.PrismaClient
+}
+
+func New(client *db.PrismaClient) *Repository {
+	return &Repository{client: client}
+}
+
+// NuevaInscripcion holds the columns both inscription flows write when a
+// participant signs up. TipoEntradaID and Monto are nil for events that sell
+// no tickets; CodigoID and Descuento are set when a promo code was redeemed.
+type NuevaInscripcion struct {
+	EventoID        int
+	UsuarioID       int
+	Nombre          string
+	Email           string
+	Afiliacion      string
+	ComprobantePago string
+	EstadoPago      bool
+	Comprobante     string
+	TipoEntradaID   *int
+	Monto           *decimal.Decimal
+	Moneda          string
+	CodigoID        *int
+	Descuento       *decimal.Decimal
+}
+
+type ReservaRow struct {
+	IDInscripcion  int    `json:"id_inscripcion"`
+	Estado         string `json:"estado"`
+	PosicionEspera *int   `json:"posicion_espera"`
+}
+
+type PromocionRow struct {
+	IDInscripcion int `json:"id_inscripcion"`
+	IDUsuario     int `json:"id_usuario"`
+}
+
+type CupoRow struct {
+	IDEvento  int  `json:"id_evento"`
+	Capacidad *int `json:"capacidad"`
+	Ocupados  int  `json:"ocupados"`
+	EnEspera  int  `json:"en_espera"`
+}
+
+const lockEvento = `SELECT "id_evento" FROM "Evento" WHERE "id_evento" = $1 FOR UPDATE`
+
+// Reservar inserts the inscription while holding a row lock on the event, so
+// concurrent sign-ups are counted one after another and neither the capacity
+// nor the quantity of the chosen ticket type nor the usage limits of the
+// promo code can be exceeded. Once the event is full the inscription joins
+// the waitlist; once the ticket type is sold out it returns
+// ErrEntradaAgotada, and ErrCodigoAgotado once the code is used up.
+func (r *Repository) Reservar(ctx context.Context, nueva NuevaInscripcion) (ReservaRow, error) {
+	insert := `INSERT INTO "Inscripcion" ("id_evento", "id_usuario", "nombre_participante", "email", "afiliacion", "comprobante_pago",
+		"estado_pago", "comprobante", "id_tipo_entrada", "monto", "moneda", "id_codigo", "descuento",
+		"fecha_inscripcion", "estado", "posicion_espera", "createdAt", "updatedAt")
+		SELECT $1::int, $2::int, $3::text, $4::text, $5::text, NULLIF($6::text, ''), $7::boolean, $8::text,
+			$9::int, $10::numeric, NULLIF($11::text, ''), $12::int, $13::numeric, NOW(),
+			CASE WHEN c."lleno" THEN '` + EstadoEnEspera + `' ELSE '` + EstadoPendiente + `' END,
+			CASE WHEN c."lleno" THEN c."siguiente" ELSE NULL END,
+			NOW(), NOW()
+		FROM (
+			SELECT e."capacidad" IS NOT NULL AND (
+				SELECT COUNT(*) FROM "Inscripcion" i
+				WHERE i."id_evento" = e."id_evento" AND i."estado" NOT IN ` + estadosInactivos + `
+			) >= e."capacidad" AS "lleno",
+			COALESCE((
+				SELECT MAX(i."posicion_espera") FROM "Inscripcion" i
+				WHERE i."id_evento" = e."id_evento" AND i."estado" = '` + EstadoEnEspera + `'
+			), 0) + 1 AS "siguiente",
+			EXISTS (
+				SELECT 1 FROM "TipoEntrada" t
+				WHERE t."id_tipo_entrada" = $9::int AND t."cantidad" IS NOT NULL AND (
+					SELECT COUNT(*) FROM "Inscripcion" i
+					WHERE i."id_tipo_entrada" = t."id_tipo_entrada" AND i."estado" NOT IN ` + inscripciones.EstadosLiberados + `
+				) >= t."cantidad"
+			) AS "agotada",
+			` + codigoAgotado("$12::int", "$2::int") + ` AS "codigo_agotado"
+			FROM "Evento" e
+			WHERE e."id_evento" = $1::int
+		) c
+		WHERE NOT c."agotada" AND NOT c."codigo_agotado"
+		RETURNING "id_inscripcion", "estado", "posicion_espera"`
+
+	lock := r.client.Prisma.Raw.QueryRaw(lockEvento, nueva.EventoID).Tx()
+	created := r.client.Prisma.Raw.QueryRaw(insert,
+		nueva.EventoID,
+		nueva.UsuarioID,
+		strings.TrimSpace(nueva.Nombre),
+		strings.TrimSpace(nueva.Email),
+		strings.TrimSpace(nueva.Afiliacion),
+		strings.TrimSpace(nueva.ComprobantePago),
+		nueva.EstadoPago,
+		nueva.Comprobante,
+		nueva.TipoEntradaID,
+		decimalParam(nueva.Monto),
+		strings.TrimSpace(nueva.Moneda),
+		nueva.CodigoID,
+		decimalParam(nueva.Descuento),
+	).Tx()
+	if err := r.client.Prisma.Transaction(lock, created).Exec(ctx); err != nil {
+		return ReservaRow{}, err
+	}
+
+	var rows []ReservaRow
+	if err := created.Into(&rows); err != nil {
+		return ReservaRow{}, err
+	}
+	if len(rows) == 0 {
+		return ReservaRow{}, r.motivoRechazo(ctx, nueva)
+	}
+	return rows[0], nil
+}
+
+// codigoAgotado is a condition that is true when the promo code given by
+// the codigo parameter reached its total limit, or its per-user limit for
+// the usuario parameter.
+func codigoAgotado(codigo, usuario string) string {
+	return `EXISTS (
+				SELECT 1 FROM "CodigoPromocional" p
+				WHERE p."id_codigo" = ` + codigo + ` AND (
+					(p."usos_maximos" IS NOT NULL AND (
+						SELECT COUNT(*) FROM "Inscripcion" i
+						WHERE i."id_codigo" = p."id_codigo" AND i."estado" NOT IN ` + inscripciones.EstadosLiberados + `
+					) >= p."usos_maximos")
+					OR (p."usos_por_usuario" IS NOT NULL AND (
+						SELECT COUNT(*) FROM "Inscripcion" i
+						WHERE i."id_codigo" = p."id_codigo" AND i."id_usuario" = ` + usuario + ` AND i."estado" NOT IN ` + inscripciones.EstadosLiberados + `
+					) >= p."usos_por_usuario")
+				)
+			)`
+}
+
+// motivoRechazo tells why Reservar inserted nothing. Callers check the event
+// exists first, so with a promo code or a ticket type it was a limit.
+func (r *Repository) motivoRechazo(ctx context.Context, nueva NuevaInscripcion) error {
+	if nueva.CodigoID != nil {
+		query := `SELECT ` + codigoAgotado("$1::int", "$2::int") + ` AS "agotado"`
+		var rows []struct {
+			Agotado bool `json:"agotado"`
+		}
+		if err := r.client.Prisma.Raw.QueryRaw(query, *nueva.CodigoID, nueva.UsuarioID).Exec(ctx, &rows); err != nil {
+			return err
+		}
+		if len(rows) > 0 && rows[0].Agotado {
+			return ErrCodigoAgotado
+		}
+	}
+	if nueva.TipoEntradaID != nil {
+		return ErrEntradaAgotada
+	}
+	return db.ErrNotFound
+}
+
+func decimalParam(value *decimal.Decimal) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.String()
+}
+
+// PromoverDisponibles moves waitlisted inscriptions to Pendiente, in waitlist
+// order, until the free seats of the event are filled. Events without
+// capacity promote everyone; cancelled events promote nobody.
+func (r *Repository) PromoverDisponibles(ctx context.Context, eventoID int) ([]PromocionRow, error) {
+	promote := `WITH "promovidas" AS (
+			UPDATE "Inscripcion" SET "estado" = '` + EstadoPendiente + `', "posicion_espera" = NULL, "updatedAt" = NOW()
+			WHERE "id_inscripcion" IN (
+				SELECT i."id_inscripcion" FROM "Inscripcion" i
+				WHERE i."id_evento" = $1::int AND i."estado" = '` + EstadoEnEspera + `'
+				ORDER BY i."posicion_espera" ASC NULLS LAST, i."id_inscripcion" ASC
+				LIMIT (
+					SELECT CASE
+						WHEN e."cancelado" THEN 0
+						WHEN e."capacidad" IS NULL THEN NULL
+						ELSE GREATEST(e."capacidad" - (
+							SELECT COUNT(*) FROM "Inscripcion" a
+							WHERE a."id_evento" = e."id_evento" AND a."estado" NOT IN ` + estadosInactivos + `
+						), 0)
+					END
+					FROM "Evento" e WHERE e."id_evento" = $1::int
+				)
+			)
+			RETURNING "id_inscripcion", "id_usuario"
+		), "historial" AS (
+			INSERT INTO "InscripcionHistorial" ("id_inscripcion", "estado_anterior", "estado_nuevo", "nota", "actor", "fecha_cambio")
+			SELECT p."id_inscripcion", '` + EstadoEnEspera + `', '` + EstadoPendiente + `', 'Cupo liberado', 'system', NOW() FROM "promovidas" p
+		)
+		SELECT "id_inscripcion", "id_usuario" FROM "promovidas" ORDER BY "id_inscripcion"`
+
+	lock := r.client.Prisma.Raw.QueryRaw(lockEvento, eventoID).Tx()
+	promoted := r.client.Prisma.Raw.QueryRaw(promote, eventoID).Tx()
+	if err := r.client.Prisma.Transaction(lock, promoted).Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	var rows []PromocionRow
+	if err := promoted.Into(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListCupos returns capacity and occupation for the given events.
+func (r *Repository) ListCupos(ctx context.Context, eventoIDs []int) ([]CupoRow, error) {
+	if len(eventoIDs) == 0 {
+		return []CupoRow{}, nil
+	}
+	query := `SELECT e."id_evento", e."capacidad",
+		COUNT(i."id_inscripcion") FILTER (WHERE i."estado" NOT IN ` + estadosInactivos + `)::int AS "ocupados",
+		COUNT(i."id_inscripcion") FILTER (WHERE i."estado" = '` + EstadoEnEspera + `')::int AS "en_espera"
+		FROM "Evento" e
+		LEFT JOIN "Inscripcion" i ON i."id_evento" = e."id_evento"
+		WHERE e."id_evento" = ANY($1::int[])
+		GROUP BY e."id_evento", e."capacidad"`
+	var rows []CupoRow
+	if err := r.client.Prisma.Raw.QueryRaw(query, eventoIDs).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SetCapacidad stores the capacity of an event; nil removes the limit.
+func (r *Repository) SetCapacidad(ctx context.Context, eventoID int, capacidad *int) error {
+	query := `UPDATE "Evento" SET "capacidad" = $2::int WHERE "id_evento" = $1::int`
+	_, err := r.client.Prisma.Raw.ExecuteRaw(query, eventoID, capacidad).Exec(ctx)
+	return err
+}
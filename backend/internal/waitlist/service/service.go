@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	notificationdto "project/backend/internal/notifications/dto"
+	notificationsrv "project/backend/internal/notifications/service"
+	"project/backend/internal/waitlist/repo"
+	"project/backend/prisma/db"
+)
+
+var (
+	ErrEventoNotFound = errors.New("evento no encontrado")
+	ErrEntradaAgotada = errors.New("no quedan entradas de este tipo")
+	ErrCodigoAgotado  = errors.New("el código promocional ya no tiene usos disponibles")
+	ErrDB             = errors.New("db error")
+)
+
+// Reserva is the outcome of a sign-up: either a seat (Pendiente) or a place
+// in the waitlist.
+type Reserva struct {
+	IDInscripcion  int
+	Estado         string
+	PosicionEspera int
+}
+
+func (r Reserva) EnEspera() bool {
+	return r.Estado == repo.EstadoEnEspera
+}
+
+// Cupo summarises the occupation of an event. Disponibles is nil when the
+// event has no capacity limit.
+type Cupo struct {
+	Capacidad   *int
+	Ocupados    int
+	EnEspera    int
+	Disponibles *int
+}
+
+type Service struct {
+	repo                *repo.Repository
+	notificationService notificationsrv.NotificationService
+}
+
+func New(client *db.PrismaClient) *Service {
+	return &Service{
+		repo:                repo.New(client),
+		notificationService: notificationsrv.NewNotificationServiceFromClient(client),
+	}
+}
+
+func (s *Service) Reservar(ctx context.Context, nueva repo.NuevaInscripcion) (Reserva, error) {
+	row, err := s.repo.Reservar(ctx, nueva)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return Reserva{}, ErrEventoNotFound
+		}
+		if errors.Is(err, repo.ErrEntradaAgotada) {
+			return Reserva{}, ErrEntradaAgotada
+		}
+		if errors.Is(err, repo.ErrCodigoAgotado) {
+			return Reserva{}, ErrCodigoAgotado
+		}
+		return Reserva{}, ErrDB
+	}
+	reserva := Reserva{IDInscripcion: row.IDInscripcion, Estado: row.Estado}
+	if row.PosicionEspera != nil {
+		reserva.PosicionEspera = *row.PosicionEspera
+	}
+	return reserva, nil
+}
+
+// PromoverSiguientes fills the free seats of the event from the waitlist and
+// notifies every promoted participant. It returns how many were promoted.
+func (s *Service) PromoverSiguientes(ctx context.Context, evento *db.EventoModel) (int, error) {
+	promovidas, err := s.repo.PromoverDisponibles(ctx, evento.IDEvento)
+	if err != nil {
+		return 0, ErrDB
+	}
+
+	mensaje := fmt.Sprintf(notificationdto.MsgCupoLiberado, evento.Nombre)
+	for _, p := range promovidas {
+		_, notifErr := s.notificationService.CreateNotification(ctx, notificationdto.CreateNotificationRequest{
+			UserID:  p.IDUsuario,
+			EventID: &evento.IDEvento,
+			Type:    notificationdto.NotificationTypeCupoLiberado,
+			Message: mensaje,
+		})
+		if notifErr != nil {
+			fmt.Println("[Waitlist] Error notificando cupo liberado al usuario", p.IDUsuario, ":", notifErr)
+		}
+	}
+	return len(promovidas), nil
+}
+
+// SetCapacidad stores the capacity of an event; nil removes the limit. Call
+// PromoverSiguientes afterwards so a larger capacity reaches the waitlist.
+func (s *Service) SetCapacidad(ctx context.Context, eventoID int, capacidad *int) error {
+	if err := s.repo.SetCapacidad(ctx, eventoID, capacidad); err != nil {
+		return ErrDB
+	}
+	return nil
+}
+
+func (s *Service) Cupos(ctx context.Context, eventoIDs []int) (map[int]Cupo, error) {
+	rows, err := s.repo.ListCupos(ctx, eventoIDs)
+	if err != nil {
+		return nil, ErrDB
+	}
+	cupos := make(map[int]Cupo, len(rows))
+	for _, row := range rows {
+		cupos[row.IDEvento] = buildCupo(row)
+	}
+	return cupos, nil
+}
+
+func buildCupo(row repo.CupoRow) Cupo {
+	cupo := Cupo{Capacidad: row.Capacidad, Ocupados: row.Ocupados, EnEspera: row.EnEspera}
+	if row.Capacidad != nil {
+		disponibles := *row.Capacidad - row.Ocupados
+		if disponibles < 0 {
+			disponibles = 0
+		}
+		cupo.Disponibles = &disponibles
+	}
+	return cupo
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"project/backend/internal/waitlist/repo"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestBuildCupo(t *testing.T) {
+	cupo := buildCupo(repo.CupoRow{IDEvento: 1, Ocupados: 12, EnEspera: 0})
+	if cupo.Disponibles != nil {
+		t.Fatal("expected no availability limit without capacity")
+	}
+
+	cupo = buildCupo(repo.CupoRow{IDEvento: 1, Capacidad: intPtr(10), Ocupados: 4, EnEspera: 0})
+	if cupo.Disponibles == nil || *cupo.Disponibles != 6 {
+		t.Fatalf("expected 6 seats available, got %v", cupo.Disponibles)
+	}
+
+	// Capacity lowered below the current occupation never reports negatives.
+	cupo = buildCupo(repo.CupoRow{IDEvento: 1, Capacidad: intPtr(10), Ocupados: 14, EnEspera: 3})
+	if cupo.Disponibles == nil || *cupo.Disponibles != 0 {
+		t.Fatalf("expected 0 seats available, got %v", cupo.Disponibles)
+	}
+}
+
+func TestReservaEnEspera(t *testing.T) {
+	if (Reserva{Estado: repo.EstadoPendiente}).EnEspera() {
+		t.Fatal("pending reservation should not be waitlisted")
+	}
+	if !(Reserva{Estado: repo.EstadoEnEspera, PosicionEspera: 2}).EnEspera() {
+		t.Fatal("expected waitlisted reservation")
+	}
+}
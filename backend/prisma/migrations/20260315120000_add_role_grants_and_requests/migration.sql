@@ -0,0 +1,35 @@
+-- AlterTable
+ALTER TABLE "UsuarioRoles" ADD COLUMN "valid_from" TIMESTAMP(3),
+ADD COLUMN "valid_until" TIMESTAMP(3);
+
+-- CreateTable
+CREATE TABLE "SolicitudRol" (
+    "id_solicitud" SERIAL NOT NULL,
+    "id_usuario" INTEGER NOT NULL,
+    "id_rol" INTEGER NOT NULL,
+    "estado" TEXT NOT NULL DEFAULT 'Pendiente',
+    "motivo" TEXT NOT NULL DEFAULT '',
+    "valid_from" TIMESTAMP(3),
+    "valid_until" TIMESTAMP(3),
+    "revisado_por" INTEGER,
+    "nota" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "SolicitudRol_pkey" PRIMARY KEY ("id_solicitud")
+);
+
+-- CreateIndex
+CREATE INDEX "UsuarioRoles_valid_until_idx" ON "UsuarioRoles"("valid_until");
+
+-- CreateIndex
+CREATE INDEX "SolicitudRol_id_usuario_idx" ON "SolicitudRol"("id_usuario");
+
+-- CreateIndex
+CREATE INDEX "SolicitudRol_estado_idx" ON "SolicitudRol"("estado");
+
+-- AddForeignKey
+ALTER TABLE "SolicitudRol" ADD CONSTRAINT "SolicitudRol_id_usuario_fkey" FOREIGN KEY ("id_usuario") REFERENCES "Usuario"("id_usuario") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "SolicitudRol" ADD CONSTRAINT "SolicitudRol_id_rol_fkey" FOREIGN KEY ("id_rol") REFERENCES "Roles"("id_rol") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,8 @@
+-- AlterTable
+ALTER TABLE "Evento" ADD COLUMN "capacidad" INTEGER;
+
+-- AlterTable
+ALTER TABLE "Inscripcion" ADD COLUMN "posicion_espera" INTEGER;
+
+-- CreateIndex
+CREATE INDEX "Inscripcion_id_evento_estado_idx" ON "Inscripcion"("id_evento", "estado");
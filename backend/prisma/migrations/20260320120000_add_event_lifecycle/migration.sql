@@ -0,0 +1,33 @@
+-- AlterTable
+ALTER TABLE "Evento" ADD COLUMN "estado" TEXT NOT NULL DEFAULT 'Borrador';
+
+-- Existing events were visible as soon as they were saved, so they start
+-- out published (or further along, according to their dates).
+UPDATE "Evento" SET "estado" = CASE
+    WHEN "cancelado" THEN 'Cancelado'
+    WHEN "fecha_fin" < NOW() THEN 'Finalizado'
+    WHEN "fecha_inicio" <= NOW() THEN 'En curso'
+    ELSE 'Publicado'
+END;
+
+-- CreateTable
+CREATE TABLE "EventoEstadoHistorial" (
+    "id_historial" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "estado_anterior" TEXT NOT NULL,
+    "estado_nuevo" TEXT NOT NULL,
+    "nota" TEXT,
+    "actor" TEXT,
+    "fecha_cambio" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "EventoEstadoHistorial_pkey" PRIMARY KEY ("id_historial")
+);
+
+-- CreateIndex
+CREATE INDEX "Evento_estado_idx" ON "Evento"("estado");
+
+-- CreateIndex
+CREATE INDEX "EventoEstadoHistorial_id_evento_idx" ON "EventoEstadoHistorial"("id_evento");
+
+-- AddForeignKey
+ALTER TABLE "EventoEstadoHistorial" ADD CONSTRAINT "EventoEstadoHistorial_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE RESTRICT ON UPDATE CASCADE;
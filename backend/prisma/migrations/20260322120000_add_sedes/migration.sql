@@ -0,0 +1,27 @@
+-- CreateTable
+CREATE TABLE "Sede" (
+    "id_sede" SERIAL NOT NULL,
+    "nombre" TEXT NOT NULL,
+    "direccion" TEXT NOT NULL,
+    "id_ciudad" INTEGER NOT NULL,
+    "capacidad" INTEGER,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "Sede_pkey" PRIMARY KEY ("id_sede")
+);
+
+-- AlterTable
+ALTER TABLE "Evento" ADD COLUMN "id_sede" INTEGER;
+
+-- CreateIndex
+CREATE UNIQUE INDEX "Sede_nombre_id_ciudad_key" ON "Sede"("nombre", "id_ciudad");
+
+-- CreateIndex
+CREATE INDEX "Evento_id_sede_fecha_inicio_idx" ON "Evento"("id_sede", "fecha_inicio");
+
+-- AddForeignKey
+ALTER TABLE "Sede" ADD CONSTRAINT "Sede_id_ciudad_fkey" FOREIGN KEY ("id_ciudad") REFERENCES "Ciudad"("id_ciudad") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Evento" ADD CONSTRAINT "Evento_id_sede_fkey" FOREIGN KEY ("id_sede") REFERENCES "Sede"("id_sede") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,4 @@
+-- AlterTable
+-- Event dates are instants; "zona_horaria" is the IANA zone they are shown
+-- and scheduled in. Existing events were entered in the default zone.
+ALTER TABLE "Evento" ADD COLUMN "zona_horaria" TEXT NOT NULL DEFAULT 'America/Caracas';
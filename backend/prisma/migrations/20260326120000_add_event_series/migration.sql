@@ -0,0 +1,20 @@
+-- CreateTable
+CREATE TABLE "EventoSerie" (
+    "id_serie" SERIAL NOT NULL,
+    "nombre" TEXT NOT NULL,
+    "regla" TEXT NOT NULL,
+    "excepciones" TEXT[],
+    "zona_horaria" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "EventoSerie_pkey" PRIMARY KEY ("id_serie")
+);
+
+-- AlterTable
+ALTER TABLE "Evento" ADD COLUMN "id_serie" INTEGER;
+
+-- CreateIndex
+CREATE INDEX "Evento_id_serie_fecha_inicio_idx" ON "Evento"("id_serie", "fecha_inicio");
+
+-- AddForeignKey
+ALTER TABLE "Evento" ADD CONSTRAINT "Evento_id_serie_fkey" FOREIGN KEY ("id_serie") REFERENCES "EventoSerie"("id_serie") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,15 @@
+-- AlterTable
+ALTER TABLE "Evento" ADD COLUMN "descripcion" TEXT NOT NULL DEFAULT '',
+ADD COLUMN "categorias" TEXT[] NOT NULL DEFAULT ARRAY[]::TEXT[];
+
+-- CreateIndex
+CREATE INDEX "Evento_categorias_idx" ON "Evento" USING GIN ("categorias");
+
+-- Full-text index over name, description and location. It is an expression
+-- index rather than a column so raw SELECT * queries keep working; catalog
+-- search must use the exact same expression to hit it.
+CREATE INDEX "Evento_busqueda_idx" ON "Evento" USING GIN ((
+    setweight(to_tsvector('spanish', "nombre"), 'A') ||
+    setweight(to_tsvector('spanish', "descripcion"), 'B') ||
+    setweight(to_tsvector('spanish', "ubicacion"), 'C')
+));
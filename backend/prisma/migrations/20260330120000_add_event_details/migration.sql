@@ -0,0 +1,7 @@
+-- AlterTable
+ALTER TABLE "Evento" ADD COLUMN "portada_url" TEXT,
+ADD COLUMN "portada_clave" TEXT,
+ADD COLUMN "organizador_nombre" TEXT,
+ADD COLUMN "organizador_email" TEXT,
+ADD COLUMN "organizador_telefono" TEXT,
+ADD COLUMN "enlaces" JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,55 @@
+-- AlterTable
+ALTER TABLE "Evento" ADD COLUMN "secuencia" INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN "actualizado_en" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- AlterTable
+ALTER TABLE "Sesion" ADD COLUMN "secuencia" INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN "actualizado_en" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- CreateTable
+CREATE TABLE "CalendarioToken" (
+    "id_usuario" INTEGER NOT NULL,
+    "token_hash" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "CalendarioToken_pkey" PRIMARY KEY ("id_usuario")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "CalendarioToken_token_hash_key" ON "CalendarioToken"("token_hash");
+
+-- AddForeignKey
+ALTER TABLE "CalendarioToken" ADD CONSTRAINT "CalendarioToken_id_usuario_fkey" FOREIGN KEY ("id_usuario") REFERENCES "Usuario"("id_usuario") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- iCalendar clients only replace their copy of an event when SEQUENCE grows.
+-- Events and sessions are updated from several places, so the counter is
+-- kept by the database whenever something attendees see changes.
+CREATE FUNCTION "evento_secuencia"() RETURNS trigger AS $$
+BEGIN
+    IF (NEW."nombre", NEW."descripcion", NEW."ubicacion", NEW."fecha_inicio", NEW."fecha_fin", NEW."id_sede", NEW."cancelado")
+        IS DISTINCT FROM
+       (OLD."nombre", OLD."descripcion", OLD."ubicacion", OLD."fecha_inicio", OLD."fecha_fin", OLD."id_sede", OLD."cancelado") THEN
+        NEW."secuencia" := OLD."secuencia" + 1;
+        NEW."actualizado_en" := CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "Evento_secuencia" BEFORE UPDATE ON "Evento"
+    FOR EACH ROW EXECUTE FUNCTION "evento_secuencia"();
+
+CREATE FUNCTION "sesion_secuencia"() RETURNS trigger AS $$
+BEGIN
+    IF (NEW."titulo", NEW."descripcion", NEW."ubicacion", NEW."fecha_inicio", NEW."fecha_fin", NEW."cancelado")
+        IS DISTINCT FROM
+       (OLD."titulo", OLD."descripcion", OLD."ubicacion", OLD."fecha_inicio", OLD."fecha_fin", OLD."cancelado") THEN
+        NEW."secuencia" := OLD."secuencia" + 1;
+        NEW."actualizado_en" := CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "Sesion_secuencia" BEFORE UPDATE ON "Sesion"
+    FOR EACH ROW EXECUTE FUNCTION "sesion_secuencia"();
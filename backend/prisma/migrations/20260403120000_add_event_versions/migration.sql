@@ -0,0 +1,20 @@
+-- CreateTable
+CREATE TABLE "EventoVersion" (
+    "id_version" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "version" INTEGER NOT NULL,
+    "accion" TEXT NOT NULL,
+    "actor" TEXT,
+    "nota" TEXT,
+    "snapshot" JSONB NOT NULL,
+    "cambios" JSONB NOT NULL DEFAULT '[]',
+    "fecha" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "EventoVersion_pkey" PRIMARY KEY ("id_version")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "EventoVersion_id_evento_version_key" ON "EventoVersion"("id_evento", "version");
+
+-- AddForeignKey
+ALTER TABLE "EventoVersion" ADD CONSTRAINT "EventoVersion_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,37 @@
+-- CreateTable
+CREATE TABLE "TipoEntrada" (
+    "id_tipo_entrada" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "nombre" TEXT NOT NULL,
+    "descripcion" TEXT NOT NULL DEFAULT '',
+    "precio" DECIMAL(12,2) NOT NULL,
+    "moneda" TEXT NOT NULL,
+    "cantidad" INTEGER,
+    "venta_desde" TIMESTAMP(3),
+    "venta_hasta" TIMESTAMP(3),
+    "precio_anticipado" DECIMAL(12,2),
+    "anticipado_hasta" TIMESTAMP(3),
+    "solo_ponentes" BOOLEAN NOT NULL DEFAULT false,
+    "activo" BOOLEAN NOT NULL DEFAULT true,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "TipoEntrada_pkey" PRIMARY KEY ("id_tipo_entrada")
+);
+
+-- AlterTable
+ALTER TABLE "Inscripcion" ADD COLUMN "id_tipo_entrada" INTEGER,
+ADD COLUMN "monto" DECIMAL(12,2),
+ADD COLUMN "moneda" TEXT;
+
+-- CreateIndex
+CREATE UNIQUE INDEX "TipoEntrada_id_evento_nombre_key" ON "TipoEntrada"("id_evento", "nombre");
+
+-- CreateIndex
+CREATE INDEX "Inscripcion_id_tipo_entrada_idx" ON "Inscripcion"("id_tipo_entrada");
+
+-- AddForeignKey
+ALTER TABLE "TipoEntrada" ADD CONSTRAINT "TipoEntrada_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Inscripcion" ADD CONSTRAINT "Inscripcion_id_tipo_entrada_fkey" FOREIGN KEY ("id_tipo_entrada") REFERENCES "TipoEntrada"("id_tipo_entrada") ON DELETE SET NULL ON UPDATE CASCADE;
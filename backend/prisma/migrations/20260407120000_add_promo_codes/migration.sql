@@ -0,0 +1,39 @@
+-- CreateTable
+CREATE TABLE "CodigoPromocional" (
+    "id_codigo" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "codigo" TEXT NOT NULL,
+    "tipo_descuento" TEXT NOT NULL,
+    "valor" DECIMAL(12,2) NOT NULL,
+    "moneda" TEXT,
+    "usos_maximos" INTEGER,
+    "usos_por_usuario" INTEGER,
+    "valido_desde" TIMESTAMP(3),
+    "valido_hasta" TIMESTAMP(3),
+    "tipos_entrada" INTEGER[] NOT NULL DEFAULT ARRAY[]::INTEGER[],
+    "lote" TEXT,
+    "activo" BOOLEAN NOT NULL DEFAULT true,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "CodigoPromocional_pkey" PRIMARY KEY ("id_codigo")
+);
+
+-- AlterTable
+ALTER TABLE "Inscripcion" ADD COLUMN "id_codigo" INTEGER,
+ADD COLUMN "descuento" DECIMAL(12,2);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "CodigoPromocional_id_evento_codigo_key" ON "CodigoPromocional"("id_evento", "codigo");
+
+-- CreateIndex
+CREATE INDEX "CodigoPromocional_id_evento_lote_idx" ON "CodigoPromocional"("id_evento", "lote");
+
+-- CreateIndex
+CREATE INDEX "Inscripcion_id_codigo_idx" ON "Inscripcion"("id_codigo");
+
+-- AddForeignKey
+ALTER TABLE "CodigoPromocional" ADD CONSTRAINT "CodigoPromocional_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Inscripcion" ADD CONSTRAINT "Inscripcion_id_codigo_fkey" FOREIGN KEY ("id_codigo") REFERENCES "CodigoPromocional"("id_codigo") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,50 @@
+-- CreateTable
+CREATE TABLE "EventoReprogramacion" (
+    "id_reprogramacion" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "fecha_inicio_anterior" TIMESTAMP(3) NOT NULL,
+    "fecha_fin_anterior" TIMESTAMP(3) NOT NULL,
+    "fecha_inicio" TIMESTAMP(3) NOT NULL,
+    "fecha_fin" TIMESTAMP(3) NOT NULL,
+    "motivo" TEXT,
+    "actor" TEXT,
+    "fecha" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "EventoReprogramacion_pkey" PRIMARY KEY ("id_reprogramacion")
+);
+
+-- CreateTable
+CREATE TABLE "ReprogramacionRespuesta" (
+    "id_respuesta" SERIAL NOT NULL,
+    "id_reprogramacion" INTEGER NOT NULL,
+    "id_inscripcion" INTEGER NOT NULL,
+    "token_hash" TEXT NOT NULL,
+    "respuesta" TEXT NOT NULL DEFAULT 'Pendiente',
+    "fecha_respuesta" TIMESTAMP(3),
+
+    CONSTRAINT "ReprogramacionRespuesta_pkey" PRIMARY KEY ("id_respuesta")
+);
+
+-- AlterTable
+ALTER TABLE "Inscripcion" ADD COLUMN "reembolso_pendiente" BOOLEAN NOT NULL DEFAULT false;
+
+-- CreateIndex
+CREATE INDEX "EventoReprogramacion_id_evento_idx" ON "EventoReprogramacion"("id_evento");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "ReprogramacionRespuesta_token_hash_key" ON "ReprogramacionRespuesta"("token_hash");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "ReprogramacionRespuesta_id_reprogramacion_id_inscripcion_key" ON "ReprogramacionRespuesta"("id_reprogramacion", "id_inscripcion");
+
+-- CreateIndex
+CREATE INDEX "ReprogramacionRespuesta_id_inscripcion_idx" ON "ReprogramacionRespuesta"("id_inscripcion");
+
+-- AddForeignKey
+ALTER TABLE "EventoReprogramacion" ADD CONSTRAINT "EventoReprogramacion_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "ReprogramacionRespuesta" ADD CONSTRAINT "ReprogramacionRespuesta_id_reprogramacion_fkey" FOREIGN KEY ("id_reprogramacion") REFERENCES "EventoReprogramacion"("id_reprogramacion") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "ReprogramacionRespuesta" ADD CONSTRAINT "ReprogramacionRespuesta_id_inscripcion_fkey" FOREIGN KEY ("id_inscripcion") REFERENCES "Inscripcion"("id_inscripcion") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,6 @@
+-- AlterTable
+ALTER TABLE "Evento" ADD COLUMN "archivado" BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN "archivado_en" TIMESTAMP(3);
+
+-- CreateIndex
+CREATE INDEX "Evento_estado_archivado_idx" ON "Evento"("estado", "archivado");
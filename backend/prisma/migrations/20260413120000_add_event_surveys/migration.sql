@@ -0,0 +1,112 @@
+-- CreateTable
+CREATE TABLE "Encuesta" (
+    "id_encuesta" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "titulo" TEXT NOT NULL,
+    "descripcion" TEXT NOT NULL DEFAULT '',
+    "anonima" BOOLEAN NOT NULL DEFAULT false,
+    "activa" BOOLEAN NOT NULL DEFAULT true,
+    "enlace" TEXT NOT NULL DEFAULT '',
+    "enviada_en" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "Encuesta_pkey" PRIMARY KEY ("id_encuesta")
+);
+
+-- CreateTable
+CREATE TABLE "EncuestaPregunta" (
+    "id_pregunta" SERIAL NOT NULL,
+    "id_encuesta" INTEGER NOT NULL,
+    "orden" INTEGER NOT NULL,
+    "tipo" TEXT NOT NULL,
+    "enunciado" TEXT NOT NULL,
+    "opciones" TEXT[] DEFAULT ARRAY[]::TEXT[],
+    "obligatoria" BOOLEAN NOT NULL DEFAULT true,
+
+    CONSTRAINT "EncuestaPregunta_pkey" PRIMARY KEY ("id_pregunta")
+);
+
+-- CreateTable
+CREATE TABLE "EncuestaInvitacion" (
+    "id_invitacion" SERIAL NOT NULL,
+    "id_encuesta" INTEGER NOT NULL,
+    "id_inscripcion" INTEGER NOT NULL,
+    "token_hash" TEXT NOT NULL,
+    "respondida" BOOLEAN NOT NULL DEFAULT false,
+
+    CONSTRAINT "EncuestaInvitacion_pkey" PRIMARY KEY ("id_invitacion")
+);
+
+-- CreateTable
+CREATE TABLE "EncuestaRespuesta" (
+    "id_respuesta" SERIAL NOT NULL,
+    "id_encuesta" INTEGER NOT NULL,
+    "id_inscripcion" INTEGER,
+    "fecha" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "EncuestaRespuesta_pkey" PRIMARY KEY ("id_respuesta")
+);
+
+-- CreateTable
+CREATE TABLE "EncuestaRespuestaItem" (
+    "id_item" SERIAL NOT NULL,
+    "id_respuesta" INTEGER NOT NULL,
+    "id_pregunta" INTEGER NOT NULL,
+    "valor" INTEGER,
+    "opciones" TEXT[] DEFAULT ARRAY[]::TEXT[],
+    "texto" TEXT,
+
+    CONSTRAINT "EncuestaRespuestaItem_pkey" PRIMARY KEY ("id_item")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "Encuesta_id_evento_key" ON "Encuesta"("id_evento");
+
+-- CreateIndex
+CREATE INDEX "Encuesta_activa_enviada_en_idx" ON "Encuesta"("activa", "enviada_en");
+
+-- CreateIndex
+CREATE INDEX "EncuestaPregunta_id_encuesta_idx" ON "EncuestaPregunta"("id_encuesta");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "EncuestaInvitacion_token_hash_key" ON "EncuestaInvitacion"("token_hash");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "EncuestaInvitacion_id_encuesta_id_inscripcion_key" ON "EncuestaInvitacion"("id_encuesta", "id_inscripcion");
+
+-- CreateIndex
+CREATE INDEX "EncuestaInvitacion_id_inscripcion_idx" ON "EncuestaInvitacion"("id_inscripcion");
+
+-- CreateIndex
+CREATE INDEX "EncuestaRespuesta_id_encuesta_idx" ON "EncuestaRespuesta"("id_encuesta");
+
+-- CreateIndex
+CREATE INDEX "EncuestaRespuestaItem_id_respuesta_idx" ON "EncuestaRespuestaItem"("id_respuesta");
+
+-- CreateIndex
+CREATE INDEX "EncuestaRespuestaItem_id_pregunta_idx" ON "EncuestaRespuestaItem"("id_pregunta");
+
+-- AddForeignKey
+ALTER TABLE "Encuesta" ADD CONSTRAINT "Encuesta_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EncuestaPregunta" ADD CONSTRAINT "EncuestaPregunta_id_encuesta_fkey" FOREIGN KEY ("id_encuesta") REFERENCES "Encuesta"("id_encuesta") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EncuestaInvitacion" ADD CONSTRAINT "EncuestaInvitacion_id_encuesta_fkey" FOREIGN KEY ("id_encuesta") REFERENCES "Encuesta"("id_encuesta") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EncuestaInvitacion" ADD CONSTRAINT "EncuestaInvitacion_id_inscripcion_fkey" FOREIGN KEY ("id_inscripcion") REFERENCES "Inscripcion"("id_inscripcion") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EncuestaRespuesta" ADD CONSTRAINT "EncuestaRespuesta_id_encuesta_fkey" FOREIGN KEY ("id_encuesta") REFERENCES "Encuesta"("id_encuesta") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EncuestaRespuesta" ADD CONSTRAINT "EncuestaRespuesta_id_inscripcion_fkey" FOREIGN KEY ("id_inscripcion") REFERENCES "Inscripcion"("id_inscripcion") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EncuestaRespuestaItem" ADD CONSTRAINT "EncuestaRespuestaItem_id_respuesta_fkey" FOREIGN KEY ("id_respuesta") REFERENCES "EncuestaRespuesta"("id_respuesta") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EncuestaRespuestaItem" ADD CONSTRAINT "EncuestaRespuestaItem_id_pregunta_fkey" FOREIGN KEY ("id_pregunta") REFERENCES "EncuestaPregunta"("id_pregunta") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,49 @@
+-- CreateTable
+CREATE TABLE "PlantillaCertificado" (
+    "id_plantilla" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "titulo" TEXT NOT NULL,
+    "texto" TEXT NOT NULL,
+    "firmas" JSONB NOT NULL DEFAULT '[]',
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "PlantillaCertificado_pkey" PRIMARY KEY ("id_plantilla")
+);
+
+-- CreateTable
+CREATE TABLE "Certificado" (
+    "id_certificado" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "id_inscripcion" INTEGER NOT NULL,
+    "codigo" TEXT NOT NULL,
+    "participante" TEXT NOT NULL,
+    "nombre_evento" TEXT NOT NULL,
+    "titulo" TEXT NOT NULL,
+    "texto" TEXT NOT NULL,
+    "firmas" JSONB NOT NULL DEFAULT '[]',
+    "emitido_en" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "Certificado_pkey" PRIMARY KEY ("id_certificado")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "PlantillaCertificado_id_evento_key" ON "PlantillaCertificado"("id_evento");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "Certificado_id_inscripcion_key" ON "Certificado"("id_inscripcion");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "Certificado_codigo_key" ON "Certificado"("codigo");
+
+-- CreateIndex
+CREATE INDEX "Certificado_id_evento_idx" ON "Certificado"("id_evento");
+
+-- AddForeignKey
+ALTER TABLE "PlantillaCertificado" ADD CONSTRAINT "PlantillaCertificado_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Certificado" ADD CONSTRAINT "Certificado_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Certificado" ADD CONSTRAINT "Certificado_id_inscripcion_fkey" FOREIGN KEY ("id_inscripcion") REFERENCES "Inscripcion"("id_inscripcion") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,55 @@
+-- CreateTable
+CREATE TABLE "Asistencia" (
+    "id_asistencia" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "id_inscripcion" INTEGER NOT NULL,
+    "registrado_en" TIMESTAMP(3) NOT NULL,
+    "id_operador" INTEGER,
+    "sin_conexion" BOOLEAN NOT NULL DEFAULT false,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "Asistencia_pkey" PRIMARY KEY ("id_asistencia")
+);
+
+-- CreateTable
+CREATE TABLE "AsistenciaSesion" (
+    "id_asistencia_sesion" SERIAL NOT NULL,
+    "id_sesion" INTEGER NOT NULL,
+    "id_inscripcion" INTEGER NOT NULL,
+    "registrado_en" TIMESTAMP(3) NOT NULL,
+    "id_operador" INTEGER,
+    "sin_conexion" BOOLEAN NOT NULL DEFAULT false,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "AsistenciaSesion_pkey" PRIMARY KEY ("id_asistencia_sesion")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "Asistencia_id_inscripcion_key" ON "Asistencia"("id_inscripcion");
+
+-- CreateIndex
+CREATE INDEX "Asistencia_id_evento_idx" ON "Asistencia"("id_evento");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "AsistenciaSesion_id_sesion_id_inscripcion_key" ON "AsistenciaSesion"("id_sesion", "id_inscripcion");
+
+-- CreateIndex
+CREATE INDEX "AsistenciaSesion_id_inscripcion_idx" ON "AsistenciaSesion"("id_inscripcion");
+
+-- AddForeignKey
+ALTER TABLE "Asistencia" ADD CONSTRAINT "Asistencia_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Asistencia" ADD CONSTRAINT "Asistencia_id_inscripcion_fkey" FOREIGN KEY ("id_inscripcion") REFERENCES "Inscripcion"("id_inscripcion") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Asistencia" ADD CONSTRAINT "Asistencia_id_operador_fkey" FOREIGN KEY ("id_operador") REFERENCES "Usuario"("id_usuario") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AsistenciaSesion" ADD CONSTRAINT "AsistenciaSesion_id_sesion_fkey" FOREIGN KEY ("id_sesion") REFERENCES "Sesion"("id_sesion") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AsistenciaSesion" ADD CONSTRAINT "AsistenciaSesion_id_inscripcion_fkey" FOREIGN KEY ("id_inscripcion") REFERENCES "Inscripcion"("id_inscripcion") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AsistenciaSesion" ADD CONSTRAINT "AsistenciaSesion_id_operador_fkey" FOREIGN KEY ("id_operador") REFERENCES "Usuario"("id_usuario") ON DELETE SET NULL ON UPDATE CASCADE;
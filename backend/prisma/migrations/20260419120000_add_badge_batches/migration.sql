@@ -0,0 +1,32 @@
+-- CreateTable
+CREATE TABLE "LoteGafetes" (
+    "id_lote" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "estado_inscripcion" TEXT,
+    "q" TEXT,
+    "desde" TIMESTAMP(3),
+    "hasta" TIMESTAMP(3),
+    "estado" TEXT NOT NULL DEFAULT 'pendiente',
+    "total" INTEGER NOT NULL DEFAULT 0,
+    "archivo_clave" TEXT,
+    "archivo_url" TEXT,
+    "error" TEXT,
+    "creado_por" INTEGER,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "iniciado_en" TIMESTAMP(3),
+    "terminado_en" TIMESTAMP(3),
+
+    CONSTRAINT "LoteGafetes_pkey" PRIMARY KEY ("id_lote")
+);
+
+-- CreateIndex
+CREATE INDEX "LoteGafetes_id_evento_idx" ON "LoteGafetes"("id_evento");
+
+-- CreateIndex
+CREATE INDEX "LoteGafetes_estado_idx" ON "LoteGafetes"("estado");
+
+-- AddForeignKey
+ALTER TABLE "LoteGafetes" ADD CONSTRAINT "LoteGafetes_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "LoteGafetes" ADD CONSTRAINT "LoteGafetes_creado_por_fkey" FOREIGN KEY ("creado_por") REFERENCES "Usuario"("id_usuario") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,50 @@
+-- CreateTable
+CREATE TABLE "Sala" (
+    "id_sala" SERIAL NOT NULL,
+    "id_sede" INTEGER NOT NULL,
+    "nombre" TEXT NOT NULL,
+    "capacidad" INTEGER,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "Sala_pkey" PRIMARY KEY ("id_sala")
+);
+
+-- CreateTable
+CREATE TABLE "Track" (
+    "id_track" SERIAL NOT NULL,
+    "id_evento" INTEGER NOT NULL,
+    "nombre" TEXT NOT NULL,
+    "color" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "Track_pkey" PRIMARY KEY ("id_track")
+);
+
+-- AlterTable
+ALTER TABLE "Sesion" ADD COLUMN "id_sala" INTEGER,
+ADD COLUMN "id_track" INTEGER;
+
+-- CreateIndex
+CREATE UNIQUE INDEX "Sala_id_sede_nombre_key" ON "Sala"("id_sede", "nombre");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "Track_id_evento_nombre_key" ON "Track"("id_evento", "nombre");
+
+-- CreateIndex
+CREATE INDEX "Sesion_id_sala_fecha_inicio_idx" ON "Sesion"("id_sala", "fecha_inicio");
+
+-- CreateIndex
+CREATE INDEX "Sesion_id_track_idx" ON "Sesion"("id_track");
+
+-- AddForeignKey
+ALTER TABLE "Sala" ADD CONSTRAINT "Sala_id_sede_fkey" FOREIGN KEY ("id_sede") REFERENCES "Sede"("id_sede") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Track" ADD CONSTRAINT "Track_id_evento_fkey" FOREIGN KEY ("id_evento") REFERENCES "Evento"("id_evento") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Sesion" ADD CONSTRAINT "Sesion_id_sala_fkey" FOREIGN KEY ("id_sala") REFERENCES "Sala"("id_sala") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Sesion" ADD CONSTRAINT "Sesion_id_track_fkey" FOREIGN KEY ("id_track") REFERENCES "Track"("id_track") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,17 @@
+-- CreateTable
+CREATE TABLE "BloqueoPonente" (
+    "id_bloqueo" SERIAL NOT NULL,
+    "id_usuario" INTEGER NOT NULL,
+    "inicio" TIMESTAMP(3) NOT NULL,
+    "fin" TIMESTAMP(3) NOT NULL,
+    "motivo" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "BloqueoPonente_pkey" PRIMARY KEY ("id_bloqueo")
+);
+
+-- CreateIndex
+CREATE INDEX "BloqueoPonente_id_usuario_inicio_idx" ON "BloqueoPonente"("id_usuario", "inicio");
+
+-- AddForeignKey
+ALTER TABLE "BloqueoPonente" ADD CONSTRAINT "BloqueoPonente_id_usuario_fkey" FOREIGN KEY ("id_usuario") REFERENCES "Usuario"("id_usuario") ON DELETE CASCADE ON UPDATE CASCADE;